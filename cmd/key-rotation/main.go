@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/larwef/ki/internal/rotating"
+	"golang.org/x/crypto/ssh/terminal"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Starts a key rotation on a running server, waits for it to finish and verifies that no secret values remain encrypted with
+// retired keys. The server has to be restarted with the new key added to its key file first. Exits with 1 if the rotation
+// fails or secret values remain encrypted with retired keys.
+func main() {
+	url := flag.String("url", "http://localhost:8080", "Address of the crud API")
+	username := flag.String("username", "admin", "Admin username")
+	verifyOnly := flag.Bool("verify", false, "Only verify, without starting a key rotation")
+	interval := flag.Duration("interval", time.Second, "How often progress is checked")
+	flag.Parse()
+
+	fmt.Print("Enter Password: ")
+	password, err := terminal.ReadPassword(0)
+	if err != nil {
+		log.Fatalf("Error getting password input: %v", err)
+	}
+	fmt.Println()
+
+	c := &client{url: strings.TrimSuffix(*url, "/"), username: *username, password: string(password)}
+
+	if !*verifyOnly {
+		var progress rotating.Progress
+		if err := c.do(http.MethodPost, "/keys/rotation", http.StatusAccepted, &progress); err != nil {
+			log.Fatalf("Error starting key rotation: %v", err)
+		}
+		fmt.Printf("Rotating to key %s\n", progress.Key)
+
+		for progress.State == rotating.Running {
+			time.Sleep(*interval)
+			if err := c.do(http.MethodGet, "/keys/rotation", http.StatusOK, &progress); err != nil {
+				log.Fatalf("Error getting key rotation progress: %v", err)
+			}
+			fmt.Printf("%d/%d configs rotated, %d values rewrapped\n", progress.Rotated, progress.Configs, progress.Rewrapped)
+		}
+
+		if progress.State != rotating.Done {
+			fmt.Printf("Key rotation %s: %s\n", progress.State, progress.Error)
+			os.Exit(1)
+		}
+	}
+
+	var v rotating.Verification
+	if err := c.do(http.MethodGet, "/keys/verification", http.StatusOK, &v); err != nil {
+		log.Fatalf("Error verifying keys: %v", err)
+	}
+
+	for _, r := range v.Retired {
+		fmt.Printf("%s %s/%s %s: %d values encrypted with retired keys\n", r.Resource, r.Group, r.ID, r.Environment, r.Values)
+	}
+
+	if !v.Verified {
+		os.Exit(1)
+	}
+	fmt.Printf("Verified, every secret value is encrypted with key %s\n", v.Key)
+}
+
+type client struct {
+	url      string
+	username string
+	password string
+}
+
+// do sends a request without a body and decodes the response into v
+func (c *client) do(method string, path string, expected int, v interface{}) error {
+	req, err := http.NewRequest(method, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expected {
+		var p struct {
+			Detail string `json:"detail"`
+		}
+		json.NewDecoder(res.Body).Decode(&p)
+		return fmt.Errorf("%s %s", res.Status, p.Detail)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/sealed"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const usage = `Usage: sealing <command> [flags]
+
+Commands:
+  keygen   Generate a recipient key pair
+  encrypt  Seal properties to the recipients of a group and store them as a config
+  decrypt  Read a sealed config and print its properties
+  rekey    Seal configs in a group to the current recipients of the group
+
+Run sealing <command> -h for the flags of a command.
+`
+
+// Encrypts configs on the client side, so the server only stores ciphertext, to the public keys registered as recipients on
+// their group. Recipients decrypt them with their private key, and re-key them when recipients are added or removed.
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "keygen":
+		keygen(args)
+	case "encrypt":
+		encrypt(args)
+	case "decrypt":
+		decrypt(args)
+	case "rekey":
+		rekey(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func keygen(args []string) {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := flags.String("out", "ki-sealing.key", "File the private key is written to")
+	flags.Parse(args)
+
+	public, private, err := sealed.GenerateKey()
+	if err != nil {
+		log.Fatalf("Error generating key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(*out, []byte(private+"\n"), 0600); err != nil {
+		log.Fatalf("Error writing private key: %v", err)
+	}
+
+	fmt.Printf("Private key written to %s\nPublic key: %s\n", *out, public)
+}
+
+func encrypt(args []string) {
+	flags := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	c := clientFlags(flags)
+	group := flags.String("group", "", "Group of the config")
+	id := flags.String("id", "", "Id of the config")
+	name := flags.String("name", "", "Name of the config")
+	file := flags.String("file", "-", "JSON file with the properties, or - for stdin")
+	flags.Parse(args)
+	requireFlags(flags, *group, *id)
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Error opening properties: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	props, err := ioutil.ReadAll(r)
+	if err != nil {
+		log.Fatalf("Error reading properties: %v", err)
+	}
+
+	c.login()
+	recipients := c.recipients(*group)
+
+	sealedProps, err := sealed.Seal(props, recipients)
+	if err != nil {
+		log.Fatalf("Error sealing properties: %v", err)
+	}
+
+	conf := struct {
+		Name       string          `json:"name"`
+		Properties json.RawMessage `json:"properties"`
+	}{*name, sealedProps}
+	if err := c.do(http.MethodPut, configPath(*group, *id), "application/json", conf, http.StatusOK, nil); err != nil {
+		log.Fatalf("Error storing config: %v", err)
+	}
+
+	fmt.Printf("Sealed %s/%s to %d recipients\n", *group, *id, len(recipients))
+}
+
+func decrypt(args []string) {
+	flags := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	c := clientFlags(flags)
+	group := flags.String("group", "", "Group of the config")
+	id := flags.String("id", "", "Id of the config")
+	keyFile := flags.String("key", "ki-sealing.key", "File with the private key")
+	flags.Parse(args)
+	requireFlags(flags, *group, *id)
+
+	key := readKey(*keyFile)
+	c.login()
+
+	var conf listing.Config
+	if err := c.do(http.MethodGet, configPath(*group, *id)+"?raw=true", "", nil, http.StatusOK, &conf); err != nil {
+		log.Fatalf("Error getting config: %v", err)
+	}
+
+	props, err := sealed.Open(conf.Properties, key)
+	if err != nil {
+		log.Fatalf("Error opening config: %v", err)
+	}
+
+	fmt.Println(string(props))
+}
+
+func rekey(args []string) {
+	flags := flag.NewFlagSet("rekey", flag.ExitOnError)
+	c := clientFlags(flags)
+	group := flags.String("group", "", "Group of the configs")
+	id := flags.String("id", "", "Id of a single config to re-key, instead of every sealed config in the group")
+	keyFile := flags.String("key", "ki-sealing.key", "File with the private key of a current recipient")
+	flags.Parse(args)
+	requireFlags(flags, *group)
+
+	key := readKey(*keyFile)
+	c.login()
+	recipients := c.recipients(*group)
+
+	ids := []string{*id}
+	if *id == "" {
+		ids = c.configs(*group)
+	}
+
+	failed := 0
+	for _, id := range ids {
+		var conf listing.Config
+		if err := c.do(http.MethodGet, configPath(*group, id)+"?raw=true", "", nil, http.StatusOK, &conf); err != nil {
+			log.Fatalf("Error getting config: %v", err)
+		}
+
+		if !sealed.IsSealed(conf.Properties) {
+			continue
+		}
+
+		props, err := sealed.Rekey(conf.Properties, key, recipients)
+		if err != nil {
+			fmt.Printf("%s/%s: %v\n", *group, id, err)
+			failed++
+			continue
+		}
+
+		// The revision is tested so changes made since the config was read are not overwritten
+		patch := []map[string]interface{}{
+			{"op": "test", "path": "/revision", "value": conf.Revision},
+			{"op": "replace", "path": "/properties", "value": props},
+		}
+		if err := c.do(http.MethodPatch, configPath(*group, id), "application/json-patch+json", patch, http.StatusOK, nil); err != nil {
+			fmt.Printf("%s/%s: %v\n", *group, id, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("%s/%s: sealed to %d recipients\n", *group, id, len(recipients))
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func requireFlags(flags *flag.FlagSet, values ...string) {
+	for _, v := range values {
+		if v == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+	}
+}
+
+func readKey(path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading private key: %v", err)
+	}
+
+	key := strings.TrimSpace(string(b))
+	if err := sealed.ValidateKey(key); err != nil {
+		log.Fatalf("Invalid private key: %v", err)
+	}
+
+	return key
+}
+
+func configPath(group string, id string) string {
+	return "/config/" + url.PathEscape(group) + "/" + url.PathEscape(id)
+}
+
+type client struct {
+	url      string
+	username string
+	password string
+}
+
+func clientFlags(flags *flag.FlagSet) *client {
+	c := &client{}
+	flags.StringVar(&c.url, "url", "http://localhost:8080", "Address of the crud API")
+	flags.StringVar(&c.username, "username", "admin", "Username")
+	return c
+}
+
+// login reads the password of the user
+func (c *client) login() {
+	fmt.Print("Enter Password: ")
+	password, err := terminal.ReadPassword(0)
+	if err != nil {
+		log.Fatalf("Error getting password input: %v", err)
+	}
+	fmt.Println()
+
+	c.url = strings.TrimSuffix(c.url, "/")
+	c.password = string(password)
+}
+
+// recipients returns the public keys registered as recipients on a group
+func (c *client) recipients(group string) []string {
+	var recipients []string
+	if err := c.do(http.MethodGet, "/recipients/"+url.PathEscape(group), "", nil, http.StatusOK, &recipients); err != nil {
+		log.Fatalf("Error getting recipients of group: %v", err)
+	}
+
+	return recipients
+}
+
+// configs returns the ids of the configs in a group, paging through them
+func (c *client) configs(group string) []string {
+	var ids []string
+	cursor := ""
+	for {
+		var grp listing.Group
+		path := "/config/" + url.PathEscape(group) + "?cursor=" + url.QueryEscape(cursor)
+		if err := c.do(http.MethodGet, path, "", nil, http.StatusOK, &grp); err != nil {
+			log.Fatalf("Error getting group: %v", err)
+		}
+
+		ids = append(ids, grp.Configs...)
+		if cursor = grp.NextCursor; cursor == "" {
+			return ids
+		}
+	}
+}
+
+// do sends a request with body encoded as JSON, unless it is nil, and decodes the response into v, unless it is nil
+func (c *client) do(method string, path string, contentType string, body interface{}, expected int, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, r)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expected {
+		var p struct {
+			Detail string `json:"detail"`
+		}
+		json.NewDecoder(res.Body).Decode(&p)
+		return fmt.Errorf("%s %s", res.Status, p.Detail)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -9,10 +9,13 @@ import (
 	"github.com/larwef/ki/internal/repository"
 	"github.com/larwef/ki/internal/repository/local"
 	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
 	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 	"log"
 	"net/http"
+	"time"
 )
 
 // PersistenceType defines available storage types
@@ -80,6 +83,9 @@ func main() {
 		app.Repository(repo),
 		app.APITypes(apiType),
 		app.Auth(getBasicAuth()),
+		app.Secrets(getKeeper()),
+		app.SecretProviders(getSecretResolver()),
+		app.Signing(getSigner()),
 	}
 
 	app.NewApp(options...).Run()
@@ -121,6 +127,62 @@ func getAutoCertManager() *autocert.Manager {
 	return certManager
 }
 
+// getKeeper loads the key-encryption key secret values are encrypted with. Secret values cannot be written without it.
+func getKeeper() *secret.Keeper {
+	keyFile, _ := config.GetString("secrets.keyFile", false)
+	if keyFile == "" {
+		log.Println("Secrets disabled, no key file")
+		return nil
+	}
+
+	keeper, err := secret.LoadKeyFile(keyFile)
+	if err != nil {
+		log.Fatalf("Error loading secrets key file: %v", err)
+	}
+
+	log.Printf("Secrets enabled with key %s\n", keeper.ID())
+	return keeper
+}
+
+// getSecretResolver registers the providers of secret values kept outside of ki. Placeholders for other providers cannot be
+// resolved.
+func getSecretResolver() *secret.Resolver {
+	// Values are cached for ttl seconds, and not at all if 0
+	ttl, _ := config.GetInt("secrets.providers.ttl", false)
+	resolver := secret.NewResolver(time.Duration(ttl) * time.Second)
+
+	// Files are only read from the directory, and environment variables only if starting with the prefix
+	if dir, _ := config.GetString("secrets.providers.file.dir", false); dir != "" {
+		resolver.Register("file", secret.FileProvider{Dir: dir})
+		log.Printf("Resolving file secrets from %s\n", dir)
+	}
+
+	if envEnabled, err := config.GetBool("secrets.providers.env.enabled", false, false); envEnabled && err == nil {
+		prefix, _ := config.GetString("secrets.providers.env.prefix", false)
+		resolver.Register("env", secret.EnvProvider{Prefix: prefix})
+		log.Printf("Resolving environment secrets starting with %q\n", prefix)
+	}
+
+	return resolver
+}
+
+// getSigner loads the key configs returned are signed with. Configs are not signed without it.
+func getSigner() *signing.Signer {
+	keyFile, _ := config.GetString("signing.keyFile", false)
+	if keyFile == "" {
+		log.Println("Signing disabled, no key file")
+		return nil
+	}
+
+	signer, err := signing.LoadKeyFile(keyFile)
+	if err != nil {
+		log.Fatalf("Error loading signing key file: %v", err)
+	}
+
+	log.Printf("Signing configs with key %s\n", signer.KeyID())
+	return signer
+}
+
 func getBasicAuth() *auth.Basic {
 	var basic *auth.Basic
 	if basiAuthEnabled, err := config.GetBool("auth.basic.enabled", true, false); basiAuthEnabled && err == nil {
@@ -147,6 +209,10 @@ func getBasicAuth() *auth.Basic {
 			Role:         auth.CLIENT,
 		}
 
+		if revealSecrets, err := config.GetBool("auth.client.revealSecrets", false, false); revealSecrets && err == nil {
+			client.Role = client.Role | auth.SECRETS
+		}
+
 		if err := basic.RegisterUser(client); err != nil {
 			log.Printf("Error adding client user to user basic: %v", err)
 		}
@@ -1,12 +1,14 @@
 module github.com/larwef/ki
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/golang/protobuf v1.2.0
 	github.com/google/uuid v1.0.0
 	github.com/pkg/errors v0.8.0
 	golang.org/x/crypto v0.0.0-20181012144002-a92615f3c490
 	golang.org/x/net v0.0.0-20181011144130-49bb7cea24b1
 	golang.org/x/sys v0.0.0-20181011152604-fa43e7bc11ba // indirect
-	google.golang.org/genproto v0.0.0-20181004005441-af9cb2a35e7f // indirect
+	google.golang.org/genproto v0.0.0-20181004005441-af9cb2a35e7f
 	google.golang.org/grpc v1.15.0
+	gopkg.in/yaml.v2 v2.2.1
 )
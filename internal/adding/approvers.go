@@ -0,0 +1,26 @@
+package adding
+
+import "github.com/larwef/ki/internal/domain"
+
+// ErrApprovalRequired is used when writing a config, overlay, schema or defaults directly to a group with approvers, where
+// configs are only changed by applying approved change requests.
+var ErrApprovalRequired = domain.New(domain.FailedPrecondition, "config", "configs in the group are changed by approved change requests")
+
+// checkApprovers checks that configs and what they are validated against can be written directly to a group, which they
+// cannot if the group has approvers and the service does not apply approved change requests
+func (s *service) checkApprovers(groupID string) error {
+	if s.reviewed {
+		return nil
+	}
+
+	approvers, err := s.repo.RetrieveApprovers(groupID)
+	if err != nil {
+		return err
+	}
+
+	if len(approvers) > 0 {
+		return ErrApprovalRequired
+	}
+
+	return nil
+}
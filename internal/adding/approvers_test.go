@@ -0,0 +1,80 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestService_ApprovalRequired(t *testing.T) {
+	repo := memory.NewRepository()
+	service := adding.NewService(repo)
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	test.AssertNotError(t, service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "staging", Properties: []byte(`{"host":"db3"}`)}))
+	test.AssertNotError(t, repo.StoreApprovers("someGroup", []string{"bob"}))
+
+	conf := adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db2"}`)}
+
+	err := service.AddConfig(conf)
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+	test.AssertEqual(t, domain.From(err).Kind, domain.FailedPrecondition)
+
+	err = service.PatchConfig("someGroup", "someId", adding.Patch{Type: adding.MergePatch, Document: []byte(`{"properties":{"host":"db2"}}`)})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	err = service.SetProperty("someGroup", "someId", properties.Pointer{"host"}, []byte(`"db2"`))
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	err = service.RemoveProperty("someGroup", "someId", properties.Pointer{"host"})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	_, err = service.PrepareConfig(conf)
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	_, err = service.Commit([]adding.Operation{{Type: adding.PutConfig, Config: conf}})
+	test.AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrApprovalRequired)
+
+	_, err = service.Commit([]adding.Operation{{Type: adding.DeleteConfig, Config: adding.Config{ID: "someId", Group: "someGroup"}}})
+	test.AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrApprovalRequired)
+
+	// What configs are read and validated with in the group cannot be changed directly either
+	err = service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Properties: []byte(`{"host":"db2"}`)})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	err = service.PromoteOverlay("someGroup", "someId", "staging", "prod")
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	err = service.SetSchema("someGroup", []byte(`{"required":["port"]}`))
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	err = service.SetDefaults("someGroup", &adding.Defaults{Properties: []byte(`{"port":5432}`)})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	_, err = repo.RetrieveOverlay("someGroup", "someId", "prod")
+	test.AssertEqual(t, err, listing.ErrOverlayNotFound)
+
+	// Groups created by a batch have no approvers yet
+	_, err = service.Commit([]adding.Operation{
+		{Type: adding.CreateGroup, Group: adding.Group{ID: "newGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "newGroup"}},
+	})
+	test.AssertNotError(t, err)
+
+	current, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(current.Properties), `{"host":"db1"}`)
+
+	// Approved change requests are applied by a reviewed service
+	reviewed := adding.NewService(repo, adding.Reviewed())
+	_, err = reviewed.Commit([]adding.Operation{{Type: adding.PutConfig, Config: conf, Revision: &current.Revision}})
+	test.AssertNotError(t, err)
+
+	current, err = repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(current.Properties), `{"host":"db2"}`)
+}
@@ -0,0 +1,250 @@
+package adding
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"strings"
+	"time"
+)
+
+// MaxBatchSize is the maximum number of operations in a batch
+const MaxBatchSize = 100
+
+// ErrRevisionMismatch is used when a resource changed by a batch is not at the revision required by the operation.
+var ErrRevisionMismatch = domain.New(domain.FailedPrecondition, "config", "config is not at the required revision")
+
+// errConfigNotFound is used for configs deleted by a batch, and configs in groups created by it
+var errConfigNotFound = domain.New(domain.NotFound, "config", "config not found")
+
+// OperationType is the kind of change an Operation makes
+type OperationType int
+
+const (
+	// CreateGroup creates a group like AddGroup
+	CreateGroup OperationType = iota
+	// PutConfig adds or replaces a config like AddConfig
+	PutConfig
+	// DeleteConfig deletes a config and its overlays
+	DeleteConfig
+)
+
+// Operation is a change to a group or config made by a batch. Group is used by CreateGroup and Config by the config
+// operations, of which DeleteConfig only uses the group and id.
+type Operation struct {
+	Type   OperationType
+	Group  Group
+	Config Config
+	// Revision, if set, is the revision the config has to be at for the operation to be applied. Zero requires the config to
+	// not exist.
+	Revision *int64
+}
+
+// OperationError is used when an operation of a batch cannot be applied, in which case none of the operations are.
+type OperationError struct {
+	// Index is the position of the operation in the batch
+	Index int
+	Err   error
+}
+
+func (o *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", o.Index, o.Err)
+}
+
+// Describe describes the error of the operation, with the fields of its violations prefixed by the operation
+func (o *OperationError) Describe() *domain.Error {
+	e := domain.From(o.Err)
+	field := fmt.Sprintf("operations[%d]", o.Index)
+
+	violations := make([]domain.Violation, len(e.Violations))
+	for i, v := range e.Violations {
+		// Fields can be JSON Pointers into the resource, like /properties/port
+		sep := "."
+		if strings.HasPrefix(v.Field, "/") {
+			sep = ""
+		}
+		violations[i] = domain.Violation{Field: field + sep + v.Field, Description: v.Description}
+	}
+	if len(violations) == 0 {
+		violations = []domain.Violation{{Field: field, Description: e.Message}}
+	}
+
+	return domain.New(e.Kind, e.Resource, fmt.Sprintf("operation %d: %s", o.Index, e.Message), violations...)
+}
+
+// Commit applies the operations of a batch in order, all or none of them. Every operation is validated like the single
+// operation it corresponds to, against the changes made by the operations before it. Returns an OperationError for the
+// first operation that cannot be applied, like when the config is not at the revision it requires. Returns the revision each
+// operation was stored at otherwise.
+func (s *service) Commit(ops []Operation) ([]int64, error) {
+	if len(ops) == 0 {
+		return nil, invalidField("batch", "operations", "at least one operation is required")
+	}
+
+	if len(ops) > MaxBatchSize {
+		return nil, invalidField("batch", "operations", fmt.Sprintf("more than %d operations", MaxBatchSize))
+	}
+
+	b := &batch{Repository: s.repo, groups: make(map[string]Group), configs: make(map[configRef]*Config)}
+	// Operations are validated by a service staging them in the batch instead of storing them
+	tx := &service{repo: b, keeper: s.keeper, reviewed: s.reviewed}
+	now := time.Now()
+
+	for i, op := range ops {
+		b.revision = op.Revision
+
+		var err error
+		switch op.Type {
+		case CreateGroup:
+			if op.Revision != nil {
+				err = invalidField("group", "revision", "only config operations can require a revision")
+				break
+			}
+			err = tx.AddGroup(op.Group)
+		case PutConfig:
+			c := op.Config
+			c.LastModified = now
+			err = tx.AddConfig(c)
+		case DeleteConfig:
+			if err = tx.checkApprovers(op.Config.Group); err == nil {
+				err = b.deleteConfig(op.Config.Group, op.Config.ID)
+			}
+		default:
+			err = invalidField("operation", "type", "unknown operation type")
+		}
+
+		if err != nil {
+			return nil, &OperationError{Index: i, Err: err}
+		}
+	}
+
+	return s.repo.Commit(b.ops)
+}
+
+// PrepareConfig validates a config like AddConfig without adding it, so it is refused for groups with approvers unless the
+// service applies approved change requests. Returns the config as it would be added, with its secret values encrypted, so
+// it can be added later by a batch.
+func (s *service) PrepareConfig(c Config) (Config, error) {
+	b := &batch{Repository: s.repo, groups: make(map[string]Group), configs: make(map[configRef]*Config)}
+	tx := &service{repo: b, keeper: s.keeper, reviewed: s.reviewed}
+	if err := tx.AddConfig(c); err != nil {
+		return Config{}, err
+	}
+
+	return *b.configs[configRef{c.Group, c.ID}], nil
+}
+
+// configRef identifies a config in a batch
+type configRef struct {
+	group string
+	id    string
+}
+
+// batch is a Repository staging the groups and configs stored by the operations of a batch, and reading them back as if
+// they were stored. Only the methods used by AddGroup and AddConfig are staged, and every other method goes to the
+// repository.
+type batch struct {
+	Repository
+	groups map[string]Group
+	// configs holds the staged configs, or nil for configs deleted by the batch
+	configs  map[configRef]*Config
+	ops      []Operation
+	revision *int64
+}
+
+// StoreGroup stages a group. Returns ErrGroupConflict if the group exists or is created by the batch.
+func (b *batch) StoreGroup(g Group) error {
+	if _, err := b.RetrieveSchema(g.ID); !isNotFound(err) {
+		if err != nil {
+			return err
+		}
+		return ErrGroupConflict
+	}
+
+	b.groups[g.ID] = g
+	b.ops = append(b.ops, Operation{Type: CreateGroup, Group: g})
+	return nil
+}
+
+// StoreConfig stages a config
+func (b *batch) StoreConfig(c Config) error {
+	b.configs[configRef{c.Group, c.ID}] = &c
+	b.ops = append(b.ops, Operation{Type: PutConfig, Config: c, Revision: b.revision})
+	return nil
+}
+
+// deleteConfig stages deleting a config. The config and its children are checked when the batch is committed.
+func (b *batch) deleteConfig(groupID string, id string) error {
+	if err := validateID("config", "group", groupID); err != nil {
+		return err
+	}
+
+	if err := validateID("config", "id", id); err != nil {
+		return err
+	}
+
+	if _, err := b.RetrieveAncestor(groupID, id); err != nil {
+		return err
+	}
+
+	b.configs[configRef{groupID, id}] = nil
+	b.ops = append(b.ops, Operation{Type: DeleteConfig, Config: Config{ID: id, Group: groupID}, Revision: b.revision})
+	return nil
+}
+
+// RetrieveSchema retrieves the schema of a staged group or a group in the repository
+func (b *batch) RetrieveSchema(groupID string) (json.RawMessage, error) {
+	if g, ok := b.groups[groupID]; ok {
+		return g.Schema, nil
+	}
+
+	return b.Repository.RetrieveSchema(groupID)
+}
+
+// RetrieveDefaults retrieves the defaults of a staged group or a group in the repository
+func (b *batch) RetrieveDefaults(groupID string) (*Defaults, error) {
+	if g, ok := b.groups[groupID]; ok {
+		return g.Defaults, nil
+	}
+
+	return b.Repository.RetrieveDefaults(groupID)
+}
+
+// RetrieveRecipients retrieves the recipients of a staged group or a group in the repository
+func (b *batch) RetrieveRecipients(groupID string) ([]string, error) {
+	if g, ok := b.groups[groupID]; ok {
+		return g.Recipients, nil
+	}
+
+	return b.Repository.RetrieveRecipients(groupID)
+}
+
+// RetrieveApprovers retrieves the approvers of a group in the repository. Staged groups have none.
+func (b *batch) RetrieveApprovers(groupID string) ([]string, error) {
+	if _, ok := b.groups[groupID]; ok {
+		return nil, nil
+	}
+
+	return b.Repository.RetrieveApprovers(groupID)
+}
+
+// RetrieveAncestor retrieves a staged config or a config in the repository. Configs deleted by the batch are not found.
+func (b *batch) RetrieveAncestor(groupID string, id string) (*Config, error) {
+	if c, ok := b.configs[configRef{groupID, id}]; ok {
+		if c == nil {
+			return nil, errConfigNotFound
+		}
+		return &Config{ID: c.ID, Group: c.Group, Parent: c.Parent, Properties: c.Properties}, nil
+	}
+
+	if _, ok := b.groups[groupID]; ok {
+		return nil, errConfigNotFound
+	}
+
+	return b.Repository.RetrieveAncestor(groupID, id)
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(*domain.Error)
+	return ok && e.Kind == domain.NotFound
+}
@@ -0,0 +1,78 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestService_Commit(t *testing.T) {
+	repo := memory.NewRepository()
+	service := adding.NewService(repo)
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "old", Group: "someGroup"}))
+
+	// Operations see the groups and configs of the operations before them
+	revisions, err := service.Commit([]adding.Operation{
+		{Type: adding.CreateGroup, Group: adding.Group{
+			ID:       "someOtherGroup",
+			Schema:   []byte(`{"required":["host","port"],"properties":{"host":{"type":"string"}}}`),
+			Defaults: &adding.Defaults{Properties: []byte(`{"port":5432}`)},
+		}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "base", Group: "someOtherGroup", Properties: []byte(`{"host":"db1"}`)}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "child", Group: "someOtherGroup", Parent: "base", Properties: []byte(`{}`)}},
+		{Type: adding.DeleteConfig, Config: adding.Config{ID: "old", Group: "someGroup"}},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(revisions), 4)
+
+	conf, err := repo.RetrieveConfig("someOtherGroup", "child")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Parent, "base")
+	test.AssertEqual(t, conf.Revision, revisions[2])
+	test.AssertEqual(t, conf.LastModified.IsZero(), false)
+
+	_, err = repo.RetrieveConfig("someGroup", "old")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	// Nothing is stored when an operation is not valid
+	_, err = service.Commit([]adding.Operation{
+		{Type: adding.PutConfig, Config: adding.Config{ID: "new", Group: "someGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "other", Group: "someOtherGroup", Properties: []byte(`{"host":1}`)}},
+	})
+	test.AssertEqual(t, err.(*adding.OperationError).Index, 1)
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+
+	_, err = service.Commit([]adding.Operation{
+		{Type: adding.PutConfig, Config: adding.Config{ID: "new", Group: "someGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "other", Group: "someOtherGroup"}},
+	})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "operations[1]/properties")
+
+	_, err = repo.RetrieveConfig("someGroup", "new")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	_, err = service.Commit([]adding.Operation{
+		{Type: adding.DeleteConfig, Config: adding.Config{ID: "base", Group: "someOtherGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "orphan", Group: "someOtherGroup", Parent: "base"}},
+	})
+	test.AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrParentNotFound)
+
+	_, err = service.Commit([]adding.Operation{{Type: adding.CreateGroup, Group: adding.Group{ID: "some/Group"}}})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "operations[0].id")
+
+	_, err = service.Commit([]adding.Operation{{Type: adding.CreateGroup, Group: adding.Group{ID: "someGroup"}}})
+	test.AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrGroupConflict)
+
+	// Revisions are checked by the repository
+	revision := int64(1)
+	_, err = service.Commit([]adding.Operation{{Type: adding.PutConfig, Config: adding.Config{ID: "base", Group: "someOtherGroup", Properties: []byte(`{"host":"db2"}`)}, Revision: &revision}})
+	test.AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrRevisionMismatch)
+	test.AssertEqual(t, domain.From(err).Kind, domain.FailedPrecondition)
+
+	_, err = service.Commit(nil)
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "operations")
+}
@@ -5,12 +5,14 @@ import (
 	"time"
 )
 
-// Config represents a config resource to be added
+// Config represents a config resource to be added. A config with a Parent inherits the properties of the parent config in
+// the same group, which in turn inherits the defaults of the group.
 type Config struct {
 	ID           string          `json:"id"`
 	Name         string          `json:"name"`
 	LastModified time.Time       `json:"lastModified"`
 	Version      int             `json:"version"`
 	Group        string          `json:"group"`
+	Parent       string          `json:"parent,omitempty"`
 	Properties   json.RawMessage `json:"properties"`
 }
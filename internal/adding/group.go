@@ -1,7 +1,23 @@
 package adding
 
-// Group represents a group object to be added
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+)
+
+// Group represents a group object to be added. Configs added to the group have to satisfy Schema, a JSON Schema for their
+// properties, if it is set. Configs added to a group with Recipients, public keys, have to be sealed to them on the client side
+// instead.
 type Group struct {
-	ID      string   `json:"id"`
-	Configs []string `json:"configs"`
+	ID         string          `json:"id"`
+	Configs    []string        `json:"configs"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	Defaults   *Defaults       `json:"defaults,omitempty"`
+	Recipients []string        `json:"recipients,omitempty"`
+}
+
+// Defaults are the properties inherited by every config in a group, and the rules for how configs override them
+type Defaults struct {
+	Properties json.RawMessage       `json:"properties"`
+	Merge      properties.MergeRules `json:"merge"`
 }
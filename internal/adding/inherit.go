@@ -0,0 +1,92 @@
+package adding
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/sealed"
+)
+
+// ErrParentNotFound is used when the parent of a config, or one of its ancestors, does not exist in the group.
+var ErrParentNotFound = domain.New(domain.FailedPrecondition, "config", "parent config does not exist in the group")
+
+// ErrInheritanceCycle is used when the parent of a config would make the config inherit from itself.
+var ErrInheritanceCycle = domain.New(domain.FailedPrecondition, "config", "parent would make the config inherit from itself")
+
+// inheritance is what a config inherits: the defaults of its group merged with the properties of its ancestors, and the rules
+// for merging properties onto them
+type inheritance struct {
+	base  interface{}
+	rules properties.MergeRules
+}
+
+// inherited resolves what a config with a parent inherits. Returns ErrParentNotFound if an ancestor does not exist and
+// ErrInheritanceCycle if the config would be its own ancestor.
+func (s *service) inherited(groupID string, id string, parent string) (inheritance, error) {
+	var inh inheritance
+
+	d, err := s.repo.RetrieveDefaults(groupID)
+	if err != nil {
+		return inh, err
+	}
+	if d != nil {
+		inh.rules = d.Merge
+		if inh.base, err = decodeProperties(d.Properties); err != nil {
+			return inh, err
+		}
+	}
+
+	// Ancestors are collected from the parent and up, and merged from the top down
+	var ancestors []json.RawMessage
+	seen := map[string]bool{id: true}
+	for parent != "" {
+		if seen[parent] {
+			return inh, ErrInheritanceCycle
+		}
+		seen[parent] = true
+
+		c, err := s.repo.RetrieveAncestor(groupID, parent)
+		if e, ok := err.(*domain.Error); ok && e.Kind == domain.NotFound {
+			return inh, ErrParentNotFound
+		}
+		if err != nil {
+			return inh, err
+		}
+
+		if sealed.IsSealed(c.Properties) {
+			return inh, ErrSealed
+		}
+
+		ancestors = append(ancestors, c.Properties)
+		parent = c.Parent
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		props, err := decodeProperties(ancestors[i])
+		if err != nil {
+			return inh, err
+		}
+		if props != nil {
+			inh.base = properties.Merge(inh.base, props, inh.rules)
+		}
+	}
+
+	return inh, nil
+}
+
+// effective returns the properties of a config merged onto what it inherits
+func (inh inheritance) effective(c Config) (json.RawMessage, error) {
+	if inh.base == nil {
+		return c.Properties, nil
+	}
+
+	props, err := decodeProperties(c.Properties)
+	if err != nil {
+		return nil, err
+	}
+	if props == nil {
+		return json.Marshal(inh.base)
+	}
+
+	return json.Marshal(properties.Merge(inh.base, props, inh.rules))
+}
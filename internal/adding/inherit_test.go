@@ -0,0 +1,74 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func newInheritTestService(t *testing.T) adding.Service {
+	service := adding.NewService(memory.NewRepository())
+	test.AssertNotError(t, service.AddGroup(adding.Group{
+		ID:       "someGroup",
+		Schema:   []byte(`{"required":["host","port"],"properties":{"port":{"type":"integer"}}}`),
+		Defaults: &adding.Defaults{Properties: []byte(`{"port":5432}`)},
+	}))
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "base", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+
+	return service
+}
+
+func TestService_AddConfig_Inherited(t *testing.T) {
+	service := newInheritTestService(t)
+
+	// The required properties are inherited from the defaults and the parent
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "child", Group: "someGroup", Parent: "base"}))
+
+	err := service.AddConfig(adding.Config{ID: "orphan", Group: "someGroup", Parent: "someOtherId"})
+	test.AssertEqual(t, err, adding.ErrParentNotFound)
+
+	err = service.AddConfig(adding.Config{ID: "orphan", Group: "someGroup"})
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+	test.AssertEqual(t, domain.From(err).Violations[0].Description, `missing required property "host"`)
+
+	// base would inherit from its own child
+	err = service.AddConfig(adding.Config{ID: "base", Group: "someGroup", Parent: "child", Properties: []byte(`{"host":"db2"}`)})
+	test.AssertEqual(t, err, adding.ErrInheritanceCycle)
+
+	err = service.AddConfig(adding.Config{ID: "base", Group: "someGroup", Parent: "base", Properties: []byte(`{"host":"db2"}`)})
+	test.AssertEqual(t, err, adding.ErrInheritanceCycle)
+}
+
+func TestService_UpdateConfig_Inherited(t *testing.T) {
+	service := newInheritTestService(t)
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "child", Group: "someGroup", Parent: "base", Properties: []byte(`{"debug":true}`)}))
+
+	test.AssertNotError(t, service.RemoveProperty("someGroup", "child", properties.Pointer{"debug"}))
+
+	// The inherited port is overridden with a value violating the schema
+	err := service.SetProperty("someGroup", "child", properties.Pointer{"port"}, []byte(`"5433"`))
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "/properties/port")
+
+	err = service.PatchConfig("someGroup", "child", adding.Patch{Type: adding.MergePatch, Document: []byte(`{"parent":null}`)})
+	test.AssertEqual(t, err, adding.InvalidFieldError{Resource: "config", Field: "parent", Reason: "cannot be changed"})
+}
+
+func TestService_SetDefaults(t *testing.T) {
+	service := newInheritTestService(t)
+
+	err := service.SetDefaults("someGroup", &adding.Defaults{Merge: properties.MergeRules{Arrays: "prepend"}})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "defaults.merge")
+
+	err = service.SetDefaults("someGroup", &adding.Defaults{Properties: []byte(`[1]`)})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "defaults.properties")
+
+	test.AssertNotError(t, service.SetDefaults("someGroup", nil))
+
+	err = service.SetDefaults("someOtherGroup", nil)
+	test.AssertEqual(t, err, listing.ErrGroupNotFound)
+}
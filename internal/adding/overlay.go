@@ -0,0 +1,107 @@
+package adding
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/sealed"
+	"time"
+)
+
+// Overlay represents the properties of a config in a named environment, like "staging" or "prod". Reading the config in the
+// environment merges the overlay onto the effective properties of the config.
+type Overlay struct {
+	Group        string          `json:"group"`
+	Config       string          `json:"config"`
+	Environment  string          `json:"environment"`
+	Version      int             `json:"version"`
+	LastModified time.Time       `json:"lastModified"`
+	Properties   json.RawMessage `json:"properties"`
+	// PromotedFrom identifies the overlay this overlay was promoted from, if any
+	PromotedFrom *Promotion `json:"promotedFrom,omitempty"`
+}
+
+// Promotion identifies an overlay at the revision it was promoted to another environment
+type Promotion struct {
+	Environment string `json:"environment"`
+	Revision    int64  `json:"revision"`
+}
+
+// SetOverlay adds or replaces the overlay of a config in an environment if it is valid and the config in the environment
+// satisfies the schema of its group. Changing the config or its ancestors does not re-validate its overlays.
+func (s *service) SetOverlay(o Overlay) error {
+	o.PromotedFrom = nil
+	return s.setOverlay(o)
+}
+
+// PromoteOverlay copies the overlay of a config in one environment to another, replacing the overlay there. The promoted
+// overlay keeps the version and records the environment and revision it was promoted from.
+func (s *service) PromoteOverlay(groupID string, id string, from string, to string) error {
+	if err := validateID("overlay", "environment", to); err != nil {
+		return err
+	}
+
+	if from == to {
+		return invalidField("overlay", "environment", "has to differ from the environment promoted from")
+	}
+
+	src, revision, err := s.repo.RetrieveOverlayForPromotion(groupID, id, from)
+	if err != nil {
+		return err
+	}
+
+	o := *src
+	o.Environment = to
+	o.PromotedFrom = &Promotion{Environment: from, Revision: revision}
+	return s.setOverlay(o)
+}
+
+func (s *service) setOverlay(o Overlay) error {
+	if err := validateOverlay(o); err != nil {
+		return err
+	}
+
+	if err := s.checkApprovers(o.Group); err != nil {
+		return err
+	}
+
+	sch, err := s.groupSchema(o.Group)
+	if err != nil {
+		return err
+	}
+
+	// The config itself and what it inherits are what the overlay is merged onto
+	c, err := s.repo.RetrieveAncestor(o.Group, o.Config)
+	if err != nil {
+		return err
+	}
+
+	if sealed.IsSealed(c.Properties) {
+		return ErrSealed
+	}
+
+	inh, err := s.inherited(o.Group, o.Config, c.Parent)
+	if err != nil {
+		return err
+	}
+
+	if sch != nil {
+		props, err := inh.effective(*c)
+		if err != nil {
+			return err
+		}
+
+		if inh.base, err = decodeProperties(props); err != nil {
+			return err
+		}
+
+		if err := s.validateProperties(Config{Properties: o.Properties}, inh, sch); err != nil {
+			return err
+		}
+	}
+
+	if o.Properties, err = s.seal(o.Properties, sch); err != nil {
+		return err
+	}
+
+	o.LastModified = time.Now()
+	return s.repo.StoreOverlay(o)
+}
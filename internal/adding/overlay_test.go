@@ -0,0 +1,60 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestService_SetOverlay(t *testing.T) {
+	service := newInheritTestService(t)
+
+	test.AssertNotError(t, service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "base", Environment: "prod", Version: 1, Properties: []byte(`{"port":6432}`)}))
+
+	// The overlay merged onto the config has to satisfy the schema
+	err := service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "base", Environment: "prod", Properties: []byte(`{"port":"6432"}`)})
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "/properties/port")
+
+	err = service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "base", Environment: "prod", Properties: []byte(`{"port":`)})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "properties")
+
+	err = service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "base", Environment: "pr/od"})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "environment")
+
+	err = service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someOtherId", Environment: "prod"})
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
+
+func TestService_PromoteOverlay(t *testing.T) {
+	repo := memory.NewRepository()
+	service := adding.NewService(repo)
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	test.AssertNotError(t, service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "staging", Version: 2, Properties: []byte(`{"host":"db2"}`)}))
+
+	test.AssertNotError(t, service.PromoteOverlay("someGroup", "someId", "staging", "prod"))
+
+	staging, err := repo.RetrieveOverlay("someGroup", "someId", "staging")
+	test.AssertNotError(t, err)
+	prod, err := repo.RetrieveOverlay("someGroup", "someId", "prod")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, prod.Version, 2)
+	test.AssertJSONEqual(t, string(prod.Properties), `{"host":"db2"}`)
+	test.AssertEqual(t, *prod.PromotedFrom, listing.Promotion{Environment: "staging", Revision: staging.Revision})
+
+	// Setting the overlay directly clears where it was promoted from
+	test.AssertNotError(t, service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Version: 3}))
+	prod, err = repo.RetrieveOverlay("someGroup", "someId", "prod")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, prod.PromotedFrom == nil, true)
+
+	err = service.PromoteOverlay("someGroup", "someId", "prod", "prod")
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "environment")
+
+	err = service.PromoteOverlay("someGroup", "someId", "dev", "prod")
+	test.AssertEqual(t, err, listing.ErrOverlayNotFound)
+}
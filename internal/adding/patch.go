@@ -0,0 +1,119 @@
+package adding
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"reflect"
+	"time"
+)
+
+// PatchType is the format of a patch
+type PatchType int
+
+const (
+	// MergePatch is a JSON Merge Patch as defined in RFC 7396
+	MergePatch PatchType = iota
+	// JSONPatch is a JSON Patch as defined in RFC 6902
+	JSONPatch
+)
+
+// Patch is a patch to the JSON representation of a config, the same representation configs are listed with. Only name,
+// version and properties can be changed. Test operations of a JSON Patch can test any field, like revision.
+type Patch struct {
+	Type     PatchType
+	Document json.RawMessage
+}
+
+// immutableFields are the fields of the config document that cannot be changed by a patch
+var immutableFields = []string{"id", "group", "parent", "revision", "lastModified"}
+
+// configDocument is the JSON representation of a config patches are applied to
+type configDocument struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	LastModified time.Time       `json:"lastModified"`
+	Version      int             `json:"version"`
+	Revision     int64           `json:"revision"`
+	Group        string          `json:"group"`
+	Parent       string          `json:"parent,omitempty"`
+	Properties   json.RawMessage `json:"properties"`
+}
+
+// apply applies the patch to a config at a revision and returns the patched config
+func (p Patch) apply(c Config, revision int64) (Config, error) {
+	raw, err := json.Marshal(configDocument{
+		ID:           c.ID,
+		Name:         c.Name,
+		LastModified: c.LastModified,
+		Version:      c.Version,
+		Revision:     revision,
+		Group:        c.Group,
+		Parent:       c.Parent,
+		Properties:   c.Properties,
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Config{}, err
+	}
+	// Patches modify the document in place, so the immutable fields are kept to compare with after patching
+	original := make(map[string]interface{}, len(immutableFields))
+	for _, field := range immutableFields {
+		original[field] = doc.(map[string]interface{})[field]
+	}
+
+	switch p.Type {
+	case MergePatch:
+		var patch interface{}
+		if err := json.Unmarshal(p.Document, &patch); err != nil {
+			return Config{}, invalidField("patch", "document", "not valid JSON")
+		}
+		doc = properties.MergePatch(doc, patch)
+	case JSONPatch:
+		var ops []properties.Operation
+		if err := json.Unmarshal(p.Document, &ops); err != nil {
+			return Config{}, invalidField("patch", "document", "not a list of JSON Patch operations")
+		}
+		if doc, err = properties.ApplyPatch(doc, ops); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, invalidField("patch", "type", "unknown patch type")
+	}
+
+	after, ok := doc.(map[string]interface{})
+	if !ok {
+		return Config{}, invalidField("config", "config", "has to be an object")
+	}
+
+	for _, field := range immutableFields {
+		if !reflect.DeepEqual(original[field], after[field]) {
+			return Config{}, invalidField("config", field, "cannot be changed")
+		}
+	}
+
+	if raw, err = json.Marshal(after); err != nil {
+		return Config{}, err
+	}
+
+	var patched configDocument
+	if err := json.Unmarshal(raw, &patched); err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			return Config{}, invalidField("config", typeErr.Field, "has to be of type "+typeErr.Type.String())
+		}
+		return Config{}, invalidField("config", "config", "not a valid config")
+	}
+
+	return Config{
+		ID:           c.ID,
+		Name:         patched.Name,
+		LastModified: c.LastModified,
+		Version:      patched.Version,
+		Group:        c.Group,
+		Parent:       c.Parent,
+		Properties:   patched.Properties,
+	}, nil
+}
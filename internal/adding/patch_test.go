@@ -0,0 +1,139 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func newPatchTestService(t *testing.T) (adding.Service, *memory.Repository) {
+	repository := memory.NewRepository()
+	service := adding.NewService(repository)
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, service.AddConfig(adding.Config{
+		ID:         "someId",
+		Name:       "someName",
+		Group:      "someGroup",
+		Properties: []byte(`{"database":{"host":"db1","port":5432},"debug":true}`),
+	}))
+
+	return service, repository
+}
+
+func TestService_PatchConfig_MergePatch(t *testing.T) {
+	service, repository := newPatchTestService(t)
+
+	err := service.PatchConfig("someGroup", "someId", adding.Patch{
+		Type:     adding.MergePatch,
+		Document: []byte(`{"version":2,"properties":{"database":{"port":5433},"debug":null}}`),
+	})
+	test.AssertNotError(t, err)
+
+	conf, err := repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Name, "someName")
+	test.AssertEqual(t, conf.Version, 2)
+	test.AssertEqual(t, conf.Revision, int64(3))
+	test.AssertJSONEqual(t, string(conf.Properties), `{"database":{"host":"db1","port":5433}}`)
+}
+
+func TestService_PatchConfig_JSONPatch(t *testing.T) {
+	service, repository := newPatchTestService(t)
+
+	err := service.PatchConfig("someGroup", "someId", adding.Patch{
+		Type: adding.JSONPatch,
+		Document: []byte(`[
+			{"op":"test","path":"/revision","value":2},
+			{"op":"replace","path":"/name","value":"someOtherName"},
+			{"op":"move","from":"/properties/database/host","path":"/properties/database/hosts"},
+			{"op":"remove","path":"/properties/debug"}
+		]`),
+	})
+	test.AssertNotError(t, err)
+
+	conf, err := repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Name, "someOtherName")
+	test.AssertJSONEqual(t, string(conf.Properties), `{"database":{"hosts":"db1","port":5432}}`)
+
+	// The revision has changed, so the same patch fails
+	err = service.PatchConfig("someGroup", "someId", adding.Patch{
+		Type:     adding.JSONPatch,
+		Document: []byte(`[{"op":"test","path":"/revision","value":2},{"op":"replace","path":"/name","value":"someName"}]`),
+	})
+	test.AssertEqual(t, err, properties.TestFailedError{Index: 0, Path: "/revision"})
+	test.AssertEqual(t, domain.From(err).Kind, domain.FailedPrecondition)
+
+	conf, err = repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Name, "someOtherName")
+}
+
+func TestService_PatchConfig_Invalid(t *testing.T) {
+	service, _ := newPatchTestService(t)
+
+	tests := map[string]string{
+		"id":       `{"id":"someOtherId"}`,
+		"revision": `{"revision":10}`,
+		"group":    `{"group":null}`,
+		"name":     `{"name":12}`,
+		"config":   `[1]`,
+		"document": `{"name":`,
+	}
+
+	for field, doc := range tests {
+		err := service.PatchConfig("someGroup", "someId", adding.Patch{Type: adding.MergePatch, Document: []byte(doc)})
+		invalidErr, ok := err.(adding.InvalidFieldError)
+		test.AssertEqual(t, ok, true)
+		test.AssertEqual(t, invalidErr.Field, field)
+	}
+
+	err := service.PatchConfig("someGroup", "someId", adding.Patch{Type: adding.JSONPatch, Document: []byte(`[{"op":"remove","path":"/missing"}]`)})
+	_, ok := err.(properties.InvalidPatchError)
+	test.AssertEqual(t, ok, true)
+
+	err = service.PatchConfig("someGroup", "someOtherId", adding.Patch{Type: adding.MergePatch, Document: []byte(`{}`)})
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
+
+func TestService_PatchConfig_SchemaViolation(t *testing.T) {
+	service, _ := newPatchTestService(t)
+	test.AssertNotError(t, service.SetSchema("someGroup", []byte(`{"properties":{"database":{"properties":{"port":{"type":"integer"}}}}}`)))
+
+	err := service.PatchConfig("someGroup", "someId", adding.Patch{
+		Type:     adding.MergePatch,
+		Document: []byte(`{"properties":{"database":{"port":"5432"}}}`),
+	})
+	e := domain.From(err)
+	test.AssertEqual(t, e.Kind, domain.ValidationFailed)
+	test.AssertEqual(t, e.Violations[0].Field, "/properties/database/port")
+}
+
+func TestService_SetAndRemoveProperty(t *testing.T) {
+	service, repository := newPatchTestService(t)
+
+	test.AssertNotError(t, service.SetProperty("someGroup", "someId", properties.Pointer{"database", "pool", "size"}, []byte(`10`)))
+	test.AssertNotError(t, service.RemoveProperty("someGroup", "someId", properties.Pointer{"debug"}))
+
+	conf, err := repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Revision, int64(4))
+	test.AssertJSONEqual(t, string(conf.Properties), `{"database":{"host":"db1","pool":{"size":10},"port":5432}}`)
+
+	err = service.SetProperty("someGroup", "someId", properties.Pointer{"database", "port", "max"}, []byte(`1`))
+	invalidErr, ok := err.(adding.InvalidFieldError)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, invalidErr.Field, "/properties/database/port/max")
+
+	err = service.RemoveProperty("someGroup", "someId", properties.Pointer{"debug"})
+	test.AssertEqual(t, err, properties.ErrPropertyNotFound)
+
+	test.AssertNotError(t, service.RemoveProperty("someGroup", "someId", properties.Pointer{}))
+	conf, err = repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(conf.Properties), 0)
+}
@@ -0,0 +1,88 @@
+package adding
+
+import (
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/sealed"
+)
+
+// ErrNotSealed is used when a config added to a group with recipients is not sealed to them.
+var ErrNotSealed = domain.New(domain.FailedPrecondition, "config", "configs in the group have to be sealed to its recipients")
+
+// ErrNoRecipients is used when a sealed config is added to a group without recipients.
+var ErrNoRecipients = domain.New(domain.FailedPrecondition, "config", "group has no recipients to seal configs to")
+
+// ErrSealed is used when the properties of a sealed config are needed, like to merge an overlay or a child config onto them.
+// The server cannot read them.
+var ErrSealed = domain.New(domain.FailedPrecondition, "config", "properties of the config are sealed")
+
+// SetRecipients replaces the public keys configs in a group are sealed to. An empty list removes them. Existing configs are
+// not sealed or re-keyed, which only their recipients can do.
+func (s *service) SetRecipients(groupID string, recipients []string) error {
+	if err := validateID("group", "id", groupID); err != nil {
+		return err
+	}
+
+	if err := validateRecipients(recipients); err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		recipients = nil
+	}
+
+	return s.repo.StoreRecipients(groupID, recipients)
+}
+
+func validateRecipients(recipients []string) error {
+	seen := make(map[string]bool)
+	for i, r := range recipients {
+		field := fmt.Sprintf("recipients[%d]", i)
+		if err := sealed.ValidateKey(r); err != nil {
+			return invalidField("group", field, err.Error())
+		}
+		if seen[r] {
+			return invalidField("group", field, "given more than once")
+		}
+		seen[r] = true
+	}
+
+	return nil
+}
+
+// checkSealed reports whether a config is sealed, validating the format of its envelope. Configs in a group with recipients
+// have to be sealed to them, and only configs in such groups can be sealed. The envelope is all the server can validate, so
+// sealed configs are not validated against the schema of the group, and they cannot inherit.
+func checkSealed(c Config, recipients []string) (bool, error) {
+	if !sealed.IsSealed(c.Properties) {
+		if len(recipients) > 0 {
+			return false, ErrNotSealed
+		}
+		return false, nil
+	}
+
+	if len(recipients) == 0 {
+		return false, ErrNoRecipients
+	}
+
+	if c.Parent != "" {
+		return false, invalidField("config", "parent", "sealed configs cannot inherit")
+	}
+
+	env, err := sealed.Parse(c.Properties)
+	if err != nil {
+		return false, invalidField("config", "properties", err.Error())
+	}
+
+	registered := make(map[string]bool)
+	for _, r := range recipients {
+		registered[r] = true
+	}
+	for _, r := range env.Recipients {
+		if !registered[r.Key] {
+			return false, invalidField("config", "properties", fmt.Sprintf("sealed to %s, which is not a recipient of the group", r.Key))
+		}
+	}
+
+	return true, nil
+}
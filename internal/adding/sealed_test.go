@@ -0,0 +1,70 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/sealed"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestService_AddConfig_Sealed(t *testing.T) {
+	public, _, err := sealed.GenerateKey()
+	test.AssertNotError(t, err)
+	otherPublic, _, err := sealed.GenerateKey()
+	test.AssertNotError(t, err)
+
+	repo := memory.NewRepository()
+	service := adding.NewService(repo)
+	test.AssertNotError(t, service.AddGroup(adding.Group{
+		ID:         "someGroup",
+		Schema:     []byte(`{"required":["host"]}`),
+		Recipients: []string{public},
+	}))
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someOtherGroup"}))
+
+	props, err := sealed.Seal([]byte(`{"password":"hunter2"}`), []string{public})
+	test.AssertNotError(t, err)
+
+	// Sealed configs are only checked to be sealed to the recipients of the group, not against the schema
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: props}))
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, string(conf.Properties), string(props))
+
+	err = service.AddConfig(adding.Config{ID: "plain", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)})
+	test.AssertEqual(t, err, adding.ErrNotSealed)
+
+	err = service.AddConfig(adding.Config{ID: "someId", Group: "someOtherGroup", Properties: props})
+	test.AssertEqual(t, err, adding.ErrNoRecipients)
+
+	err = service.AddConfig(adding.Config{ID: "child", Group: "someGroup", Parent: "someId", Properties: props})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "parent")
+
+	other, err := sealed.Seal([]byte(`{"password":"hunter2"}`), []string{public, otherPublic})
+	test.AssertNotError(t, err)
+	err = service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: other})
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "properties")
+
+	err = service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"$sealed":{"version":1}}`)})
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	// Updates are checked the same way, and the server cannot change sealed properties
+	err = service.SetProperty("someGroup", "someId", []string{"host"}, []byte(`"db1"`))
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	err = service.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Properties: []byte(`{}`)})
+	test.AssertEqual(t, err, adding.ErrSealed)
+
+	// Sealed configs can be re-keyed once the new recipient is registered
+	test.AssertNotError(t, service.SetRecipients("someGroup", []string{public, otherPublic}))
+	test.AssertNotError(t, service.SetProperty("someGroup", "someId", nil, other))
+
+	err = service.SetRecipients("someGroup", []string{public, public})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "recipients[1]")
+
+	err = service.SetRecipients("someGroup", []string{"not a key"})
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "recipients[0]")
+}
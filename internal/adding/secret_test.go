@@ -0,0 +1,55 @@
+package adding_test
+
+import (
+	"bytes"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/test"
+	"strings"
+	"testing"
+)
+
+func TestService_AddConfig_Secrets(t *testing.T) {
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+
+	repo := memory.NewRepository()
+	service := adding.NewService(repo, adding.Secrets(keeper))
+	test.AssertNotError(t, service.AddGroup(adding.Group{
+		ID:     "someGroup",
+		Schema: []byte(`{"properties":{"password":{"type":"string","minLength":8,"secret":true}}}`),
+	}))
+
+	// Values marked secret by the schema and wrapped as secrets are encrypted at rest
+	test.AssertNotError(t, service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"password":"hunter22","token":{"$secret":"abc"}}`)}))
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	if strings.Contains(string(conf.Properties), "hunter22") || strings.Contains(string(conf.Properties), "abc") {
+		t.Fatalf("Stored properties hold secret values: %s", conf.Properties)
+	}
+
+	props, err := keeper.Open(conf.Properties)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(props), `{"password":"hunter22","token":"abc"}`)
+
+	// Secret values are validated decrypted
+	err = service.AddConfig(adding.Config{ID: "someOtherId", Group: "someGroup", Properties: []byte(`{"password":{"$secret":"short"}}`)})
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+	test.AssertEqual(t, domain.From(err).Violations[0].Field, "/properties/password")
+
+	// Updating other properties keeps the encrypted values
+	test.AssertNotError(t, service.SetProperty("someGroup", "someId", []string{"user"}, []byte(`"admin"`)))
+	conf, err = repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	props, err = keeper.Open(conf.Properties)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(props), `{"user":"admin","password":"hunter22","token":"abc"}`)
+
+	// Secret values cannot be written without a key
+	service = adding.NewService(repo)
+	err = service.AddConfig(adding.Config{ID: "someOtherId", Group: "someGroup", Properties: []byte(`{"password":"hunter22"}`)})
+	test.AssertEqual(t, err, secret.ErrNoKey)
+}
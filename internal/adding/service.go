@@ -1,35 +1,433 @@
 package adding
 
-import "errors"
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/schema"
+	"github.com/larwef/ki/internal/secret"
+	"time"
+)
 
 // ErrGroupConflict is used when a group already exists.
-var ErrGroupConflict = errors.New("group already exists and is not overwritable")
+var ErrGroupConflict = domain.New(domain.AlreadyExists, "group", "group already exists and is not overwritable")
+
+// ErrConfigConflict is used when creating a config that already exists. Adding a config overwrites it, so it is only used by
+// APIs separating creating from updating.
+var ErrConfigConflict = domain.New(domain.AlreadyExists, "config", "config already exists")
 
 // Service provides adding operations
 type Service interface {
 	AddGroup(g Group) error
 	AddConfig(c Config) error
+	SetSchema(groupID string, s json.RawMessage) error
+	SetDefaults(groupID string, d *Defaults) error
+	SetRecipients(groupID string, recipients []string) error
+	PatchConfig(groupID string, id string, p Patch) error
+	SetProperty(groupID string, id string, p properties.Pointer, value json.RawMessage) error
+	RemoveProperty(groupID string, id string, p properties.Pointer) error
+	SetOverlay(o Overlay) error
+	PromoteOverlay(groupID string, id string, from string, to string) error
+	Commit(ops []Operation) ([]int64, error)
+	PrepareConfig(c Config) (Config, error)
 }
 
 // Repository provides access to repository
 type Repository interface {
 	StoreGroup(g Group) error
 	StoreConfig(c Config) error
+	StoreSchema(groupID string, s json.RawMessage) error
+	RetrieveSchema(groupID string) (json.RawMessage, error)
+	StoreDefaults(groupID string, d *Defaults) error
+	// RetrieveDefaults retrieves the defaults of a group. Returns nil if the group has no defaults.
+	RetrieveDefaults(groupID string) (*Defaults, error)
+	StoreRecipients(groupID string, recipients []string) error
+	// RetrieveRecipients retrieves the recipients of a group. Returns nil if the group has none.
+	RetrieveRecipients(groupID string) ([]string, error)
+	// RetrieveApprovers retrieves the approvers of a group. Returns nil if the group has none.
+	RetrieveApprovers(groupID string) ([]string, error)
+	// RetrieveAncestor retrieves a config as it is inherited from by other configs
+	RetrieveAncestor(groupID string, id string) (*Config, error)
+	// UpdateConfig replaces an existing config with the one returned by update, which is given the current config and its
+	// revision. No other changes can be made to the config while update runs, and update cannot use the repository.
+	UpdateConfig(groupID string, id string, update func(c Config, revision int64) (Config, error)) error
+	StoreOverlay(o Overlay) error
+	// RetrieveOverlayForPromotion retrieves an overlay and its revision to be promoted to another environment
+	RetrieveOverlayForPromotion(groupID string, id string, env string) (*Overlay, int64, error)
+	// Commit applies validated operations in order, all or none of them, and no other changes can be made in between.
+	// Returns the revision each operation was stored at, or an OperationError for the first operation that cannot be
+	// applied, like ErrRevisionMismatch.
+	Commit(ops []Operation) ([]int64, error)
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	keeper *secret.Keeper
+	// reviewed lets configs be written to groups with approvers
+	reviewed bool
 }
 
+// Option sets options on the service
+type Option func(*service)
+
+// Secrets returns an Option that sets the Keeper secret values are encrypted with. Without it secret values cannot be written.
+func Secrets(k *secret.Keeper) Option { return func(s *service) { s.keeper = k } }
+
+// Reviewed returns an Option that lets the service write configs to groups with approvers. Only the service applying approved
+// change requests is to have it, as other services refuse such writes with ErrApprovalRequired.
+func Reviewed() Option { return func(s *service) { s.reviewed = true } }
+
 // NewService created a new adding service
-func NewService(r Repository) Service {
-	return &service{repo: r}
+func NewService(r Repository, opts ...Option) Service {
+	s := &service{repo: r}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
 }
 
+// AddGroup adds a group. Returns an InvalidFieldError if the id is not valid.
 func (s *service) AddGroup(g Group) error {
-	return s.repo.StoreGroup(Group{ID: g.ID})
+	if err := validateGroup(g); err != nil {
+		return err
+	}
+
+	if err := checkSchema(g.Schema); err != nil {
+		return err
+	}
+
+	if g.Defaults != nil {
+		var sch *schema.Schema
+		if hasSchema(g.Schema) {
+			sch, _ = schema.Compile(g.Schema)
+		}
+
+		d := *g.Defaults
+		var err error
+		if d.Properties, err = s.seal(d.Properties, sch); err != nil {
+			return err
+		}
+		g.Defaults = &d
+	}
+
+	if len(g.Recipients) == 0 {
+		g.Recipients = nil
+	}
+
+	return s.repo.StoreGroup(Group{ID: g.ID, Schema: g.Schema, Defaults: g.Defaults, Recipients: g.Recipients})
 }
 
+// AddConfig adds a config if it is valid and its properties, merged onto what it inherits, satisfy the schema of its group.
+// Returns an InvalidFieldError if a field is not valid, ErrParentNotFound or ErrInheritanceCycle if the parent is not valid,
+// or an error of kind domain.ValidationFailed with the violations found if the properties do not satisfy the schema. Configs
+// in a group with recipients are only checked to be sealed to them. Returns ErrApprovalRequired if the group has approvers,
+// unless the service is Reviewed.
 func (s *service) AddConfig(c Config) error {
+	if err := validateConfig(c); err != nil {
+		return err
+	}
+
+	if err := s.checkApprovers(c.Group); err != nil {
+		return err
+	}
+
+	recipients, err := s.repo.RetrieveRecipients(c.Group)
+	if err != nil {
+		return err
+	}
+
+	if isSealed, err := checkSealed(c, recipients); err != nil || isSealed {
+		if err != nil {
+			return err
+		}
+		return s.repo.StoreConfig(c)
+	}
+
+	sch, err := s.groupSchema(c.Group)
+	if err != nil {
+		return err
+	}
+
+	inh, err := s.inherited(c.Group, c.ID, c.Parent)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateProperties(c, inh, sch); err != nil {
+		return err
+	}
+
+	if c.Properties, err = s.seal(c.Properties, sch); err != nil {
+		return err
+	}
+
 	return s.repo.StoreConfig(c)
 }
+
+// PatchConfig applies a patch to an existing config. The patch is applied to the config as it is in the repository, and no
+// other changes can be made to the config in between. The patched config is validated like when adding a config. Returns an
+// InvalidFieldError if the patch tries to change other fields than name, version and properties, like the parent, a
+// properties.InvalidPatchError if an operation of a JSON Patch cannot be applied and a properties.TestFailedError if a test
+// operation fails.
+func (s *service) PatchConfig(groupID string, id string, p Patch) error {
+	return s.updateConfig(groupID, id, func(c *Config, revision int64) error {
+		patched, err := p.apply(*c, revision)
+		*c = patched
+		return err
+	})
+}
+
+// SetProperty sets the value of a single property of an existing config. Objects missing along the Pointer are created and
+// the empty Pointer replaces all properties. The config is validated like when adding a config.
+func (s *service) SetProperty(groupID string, id string, p properties.Pointer, value json.RawMessage) error {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return invalidField("property", "value", "not valid JSON")
+	}
+
+	return s.updateConfig(groupID, id, func(c *Config, revision int64) error {
+		doc, err := decodeProperties(c.Properties)
+		if err != nil {
+			return err
+		}
+
+		if doc, err = properties.Set(doc, p, v); err != nil {
+			return invalidField("property", "/properties"+p.String(), "parent is not an object or array, or index is out of range")
+		}
+
+		c.Properties, err = json.Marshal(doc)
+		return err
+	})
+}
+
+// RemoveProperty removes a single property from an existing config. The empty Pointer removes all properties. Returns
+// properties.ErrPropertyNotFound if there is no property at the Pointer.
+func (s *service) RemoveProperty(groupID string, id string, p properties.Pointer) error {
+	return s.updateConfig(groupID, id, func(c *Config, revision int64) error {
+		if len(p) == 0 {
+			c.Properties = nil
+			return nil
+		}
+
+		doc, err := decodeProperties(c.Properties)
+		if err != nil {
+			return err
+		}
+
+		if doc, err = properties.Remove(doc, p); err != nil {
+			return properties.ErrPropertyNotFound
+		}
+
+		c.Properties, err = json.Marshal(doc)
+		return err
+	})
+}
+
+// updateConfig updates an existing config as it is in the repository, so no other changes can be made to the config in
+// between. The updated config is validated like when adding a config.
+func (s *service) updateConfig(groupID string, id string, update func(c *Config, revision int64) error) error {
+	if err := validateID("config", "group", groupID); err != nil {
+		return err
+	}
+
+	if err := validateID("config", "id", id); err != nil {
+		return err
+	}
+
+	if err := s.checkApprovers(groupID); err != nil {
+		return err
+	}
+
+	sch, err := s.groupSchema(groupID)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := s.repo.RetrieveRecipients(groupID)
+	if err != nil {
+		return err
+	}
+
+	// What the config inherits is resolved up front, as the repository cannot be used during the update. Updates do not
+	// change the parent.
+	current, err := s.repo.RetrieveAncestor(groupID, id)
+	if err != nil {
+		return err
+	}
+
+	inh, err := s.inherited(groupID, id, current.Parent)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateConfig(groupID, id, func(c Config, revision int64) (Config, error) {
+		if err := update(&c, revision); err != nil {
+			return Config{}, err
+		}
+		c.LastModified = time.Now()
+
+		if err := validateConfig(c); err != nil {
+			return Config{}, err
+		}
+
+		if isSealed, err := checkSealed(c, recipients); err != nil || isSealed {
+			return c, err
+		}
+
+		if err := s.validateProperties(c, inh, sch); err != nil {
+			return Config{}, err
+		}
+
+		var err error
+		c.Properties, err = s.seal(c.Properties, sch)
+		return c, err
+	})
+}
+
+// decodeProperties decodes the properties of a stored config
+func decodeProperties(raw json.RawMessage) (interface{}, error) {
+	var doc interface{}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// groupSchema returns the compiled schema of a group, or nil if the group has no schema
+func (s *service) groupSchema(groupID string) (*schema.Schema, error) {
+	raw, err := s.repo.RetrieveSchema(groupID)
+	if err != nil || !hasSchema(raw) {
+		return nil, err
+	}
+
+	return schema.Compile(raw)
+}
+
+// validateProperties validates the properties of a config merged onto what it inherits against a schema, if any. Secret
+// values are validated decrypted.
+func (s *service) validateProperties(c Config, inh inheritance, sch *schema.Schema) error {
+	if sch == nil {
+		return nil
+	}
+
+	props, err := inh.effective(c)
+	if err != nil {
+		return err
+	}
+
+	if props, err = s.keeper.Open(props); err != nil {
+		return err
+	}
+
+	if err := sch.Validate(props); err != nil {
+		return describeViolations(err)
+	}
+
+	return nil
+}
+
+// seal encrypts the secret values of properties, which are the values wrapped as secrets and the values the schema marks as
+// secret. Returns secret.ErrNoKey if there are secret values and no Keeper.
+func (s *service) seal(props json.RawMessage, sch *schema.Schema) (json.RawMessage, error) {
+	var paths []properties.Pointer
+	if sch != nil {
+		doc, err := decodeProperties(props)
+		if err != nil {
+			return nil, err
+		}
+		paths = sch.Secrets(doc)
+	}
+
+	return s.keeper.Seal(props, paths)
+}
+
+// SetSchema replaces the schema of a group. An empty or null schema removes it. Existing configs are not validated against
+// the new schema.
+func (s *service) SetSchema(groupID string, sch json.RawMessage) error {
+	if err := validateID("group", "id", groupID); err != nil {
+		return err
+	}
+
+	if err := s.checkApprovers(groupID); err != nil {
+		return err
+	}
+
+	if err := checkSchema(sch); err != nil {
+		return err
+	}
+
+	if !hasSchema(sch) {
+		sch = nil
+	}
+
+	return s.repo.StoreSchema(groupID, sch)
+}
+
+// SetDefaults replaces the defaults of a group. Nil removes them. Existing configs are not validated against the schema with
+// the new defaults.
+func (s *service) SetDefaults(groupID string, d *Defaults) error {
+	if err := validateID("group", "id", groupID); err != nil {
+		return err
+	}
+
+	if err := s.checkApprovers(groupID); err != nil {
+		return err
+	}
+
+	if err := validateDefaults(d); err != nil {
+		return err
+	}
+
+	if d != nil && !hasSchema(d.Properties) && d.Merge == (properties.MergeRules{}) {
+		d = nil
+	}
+
+	if d != nil {
+		sch, err := s.groupSchema(groupID)
+		if err != nil {
+			return err
+		}
+
+		sealed := *d
+		if sealed.Properties, err = s.seal(d.Properties, sch); err != nil {
+			return err
+		}
+		d = &sealed
+	}
+
+	return s.repo.StoreDefaults(groupID, d)
+}
+
+func checkSchema(raw json.RawMessage) error {
+	if !hasSchema(raw) {
+		return nil
+	}
+
+	_, err := schema.Compile(raw)
+	return err
+}
+
+func hasSchema(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
+
+// describeViolations describes a schema validation error as violations of fields within the properties of a config
+func describeViolations(err error) error {
+	validationErr, ok := err.(schema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	violations := make([]domain.Violation, len(validationErr.Violations))
+	for i, v := range validationErr.Violations {
+		violations[i] = domain.Violation{Field: "/properties" + v.Path, Description: v.Message}
+	}
+
+	return domain.New(domain.ValidationFailed, "config", "properties do not satisfy the schema of the group", violations...)
+}
@@ -0,0 +1,147 @@
+package adding
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	// MaxIDLength is the maximum length of group and config ids
+	MaxIDLength = 128
+	// MaxNameLength is the maximum number of characters in the name of a config
+	MaxNameLength = 256
+	// MaxPropertiesSize is the maximum size in bytes of the properties of a config
+	MaxPropertiesSize = 1 << 20
+)
+
+// idPattern is the grammar of group and config ids. An id starts with a letter or digit followed by letters, digits, '.', '_'
+// or '-'. Ids are used as file names by some repositories, so path separators and the relative names "." and ".." are not
+// allowed.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// InvalidFieldError is used when a field of a group or config is not valid.
+type InvalidFieldError struct {
+	// Resource is the type of resource the field belongs to
+	Resource string
+	Field    string
+	Reason   string
+}
+
+func (i InvalidFieldError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", i.Field, i.Reason)
+}
+
+// Describe describes the error as an invalid argument
+func (i InvalidFieldError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, i.Resource, i.Error(), domain.Violation{Field: i.Field, Description: i.Reason})
+}
+
+func validateGroup(g Group) error {
+	if err := validateID("group", "id", g.ID); err != nil {
+		return err
+	}
+
+	if err := validateRecipients(g.Recipients); err != nil {
+		return err
+	}
+
+	return validateDefaults(g.Defaults)
+}
+
+func validateDefaults(d *Defaults) error {
+	if d == nil {
+		return nil
+	}
+
+	if len(d.Properties) > MaxPropertiesSize {
+		return invalidField("group", "defaults.properties", fmt.Sprintf("larger than %d bytes", MaxPropertiesSize))
+	}
+
+	var props interface{}
+	if len(d.Properties) > 0 && json.Unmarshal(d.Properties, &props) != nil {
+		return invalidField("group", "defaults.properties", "not valid JSON")
+	}
+	if _, ok := props.(map[string]interface{}); props != nil && !ok {
+		return invalidField("group", "defaults.properties", "has to be an object")
+	}
+
+	if !d.Merge.Valid() {
+		return invalidField("group", "defaults.merge", "arrays has to be replace or append, and nulls keep or delete")
+	}
+
+	return nil
+}
+
+func validateConfig(c Config) error {
+	if err := validateID("config", "id", c.ID); err != nil {
+		return err
+	}
+
+	if err := validateID("config", "group", c.Group); err != nil {
+		return err
+	}
+
+	if c.Parent != "" {
+		if err := validateID("config", "parent", c.Parent); err != nil {
+			return err
+		}
+	}
+
+	if utf8.RuneCountInString(c.Name) > MaxNameLength {
+		return invalidField("config", "name", fmt.Sprintf("longer than %d characters", MaxNameLength))
+	}
+
+	if len(c.Properties) > MaxPropertiesSize {
+		return invalidField("config", "properties", fmt.Sprintf("larger than %d bytes", MaxPropertiesSize))
+	}
+
+	if len(c.Properties) > 0 && !json.Valid(c.Properties) {
+		return invalidField("config", "properties", "not valid JSON")
+	}
+
+	return nil
+}
+
+func validateOverlay(o Overlay) error {
+	if err := validateID("overlay", "group", o.Group); err != nil {
+		return err
+	}
+
+	if err := validateID("overlay", "config", o.Config); err != nil {
+		return err
+	}
+
+	if err := validateID("overlay", "environment", o.Environment); err != nil {
+		return err
+	}
+
+	if len(o.Properties) > MaxPropertiesSize {
+		return invalidField("overlay", "properties", fmt.Sprintf("larger than %d bytes", MaxPropertiesSize))
+	}
+
+	if len(o.Properties) > 0 && !json.Valid(o.Properties) {
+		return invalidField("overlay", "properties", "not valid JSON")
+	}
+
+	return nil
+}
+
+func validateID(resource, field, id string) error {
+	switch {
+	case id == "":
+		return invalidField(resource, field, "required")
+	case len(id) > MaxIDLength:
+		return invalidField(resource, field, fmt.Sprintf("longer than %d characters", MaxIDLength))
+	case !idPattern.MatchString(id):
+		return invalidField(resource, field, "has to start with a letter or digit and contain only letters, digits, '.', '_' or '-'")
+	}
+
+	return nil
+}
+
+func invalidField(resource, field, reason string) error {
+	return InvalidFieldError{Resource: resource, Field: field, Reason: reason}
+}
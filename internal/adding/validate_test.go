@@ -0,0 +1,46 @@
+package adding_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"strings"
+	"testing"
+)
+
+func TestService_AddGroup_InvalidID(t *testing.T) {
+	service := adding.NewService(memory.NewRepository())
+
+	for _, id := range []string{"", ".", "..", "../someGroup", "some/group", "-someGroup", "some group", strings.Repeat("a", adding.MaxIDLength+1)} {
+		err := service.AddGroup(adding.Group{ID: id})
+		invalidErr, ok := err.(adding.InvalidFieldError)
+		test.AssertEqual(t, ok, true)
+		test.AssertEqual(t, invalidErr.Field, "id")
+	}
+
+	for _, id := range []string{"someGroup", "some.group_1-a", "1", strings.Repeat("a", adding.MaxIDLength)} {
+		test.AssertNotError(t, service.AddGroup(adding.Group{ID: id}))
+	}
+}
+
+func TestService_AddConfig_Invalid(t *testing.T) {
+	service := adding.NewService(memory.NewRepository())
+	test.AssertNotError(t, service.AddGroup(adding.Group{ID: "someGroup"}))
+
+	tests := map[string]adding.Config{
+		"id":         {ID: "../someId", Group: "someGroup"},
+		"group":      {ID: "someId", Group: ""},
+		"name":       {ID: "someId", Group: "someGroup", Name: strings.Repeat("æ", adding.MaxNameLength+1)},
+		"properties": {ID: "someId", Group: "someGroup", Properties: []byte(`{"property1": `)},
+	}
+
+	for field, c := range tests {
+		err := service.AddConfig(c)
+		invalidErr, ok := err.(adding.InvalidFieldError)
+		test.AssertEqual(t, ok, true)
+		test.AssertEqual(t, invalidErr.Field, field)
+	}
+
+	err := service.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Name: strings.Repeat("æ", adding.MaxNameLength)})
+	test.AssertNotError(t, err)
+}
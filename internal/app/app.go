@@ -4,13 +4,21 @@ import (
 	"crypto/tls"
 	"github.com/larwef/ki/internal/adding"
 	"github.com/larwef/ki/internal/config"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
 	"github.com/larwef/ki/internal/http/auth"
 	"github.com/larwef/ki/internal/http/crud"
 	"github.com/larwef/ki/internal/http/grpc"
+	"github.com/larwef/ki/internal/http/grpc/kiv2"
 	"github.com/larwef/ki/internal/listing"
 	"github.com/larwef/ki/internal/repository"
 	"github.com/larwef/ki/internal/repository/local"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/rotating"
 	"github.com/larwef/ki/internal/runner"
+	"github.com/larwef/ki/internal/scheduling"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
 	goGrpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"log"
@@ -39,6 +47,9 @@ type options struct {
 	repository repository.Repository
 	apiType    APIType
 	aut        auth.Auth
+	keeper     *secret.Keeper
+	resolver   *secret.Resolver
+	signer     *signing.Signer
 }
 
 var defaultAppOptions = options{
@@ -60,6 +71,15 @@ func APITypes(at APIType) Option { return func(o *options) { o.apiType = at } }
 // Auth returns an Option that sets authentication implementation to be used.
 func Auth(a auth.Auth) Option { return func(o *options) { o.aut = a } }
 
+// Secrets returns an Option that sets the Keeper secret values are encrypted and decrypted with.
+func Secrets(k *secret.Keeper) Option { return func(o *options) { o.keeper = k } }
+
+// SecretProviders returns an Option that sets the Resolver placeholders for secret values kept outside of ki are resolved with.
+func SecretProviders(r *secret.Resolver) Option { return func(o *options) { o.resolver = r } }
+
+// Signing returns an Option that sets the Signer configs returned are signed with.
+func Signing(s *signing.Signer) Option { return func(o *options) { o.signer = s } }
+
 // NewApp returns a new app object
 func NewApp(opt ...Option) *App {
 	opts := defaultAppOptions
@@ -75,18 +95,34 @@ func NewApp(opt ...Option) *App {
 // Run runs the application. Will start server objects for active APIs. CRUD and GRPC will use the same TLS config and the same
 // persistence. So both can be used at the same time towards different clients and still provide the same resources to both.
 func (a *App) Run() {
-	add := adding.NewService(a.opts.repository)
-	lst := listing.NewService(a.opts.repository)
+	add := adding.NewService(a.opts.repository, adding.Secrets(a.opts.keeper))
+	lst := listing.NewService(a.opts.repository, listing.Secrets(a.opts.keeper), listing.Providers(a.opts.resolver))
+	del := deleting.NewService(a.opts.repository)
+	// Only approved change requests can change configs in groups with approvers
+	rev := reviewing.NewService(a.opts.repository, adding.NewService(a.opts.repository, adding.Secrets(a.opts.keeper), adding.Reviewed()))
+	dif := diffing.NewService(a.opts.repository, lst)
 
 	rnr := runner.NewRunner()
 
+	// Scheduled configs are activated by the scheduler, which picks up the schedule stored in the repository when started
+	sch := scheduling.NewScheduler(a.opts.repository, add)
+	rnr.Add(sch)
+
+	// Rotating keys needs keys, and the job is only run when there are any
+	var rot rotating.Service
+	if a.opts.keeper != nil {
+		job := rotating.NewJob(a.opts.repository, a.opts.keeper)
+		rnr.Add(job)
+		rot = job
+	}
+
 	// CRUD
 	if a.opts.apiType&CRUD != 0 {
 		crudAddress, _ := config.GetString("apiType.crud.address", true)
 		crudServer := &crud.Server{
 			Server: &http.Server{
 				Addr:         crudAddress,
-				Handler:      crud.NewHandler(a.opts.aut, add, lst),
+				Handler:      crud.NewHandler(a.opts.aut, add, lst, rot, rev, sch, dif, a.opts.signer),
 				ReadTimeout:  15 * time.Second,
 				WriteTimeout: 30 * time.Second,
 				IdleTimeout:  60 * time.Second,
@@ -105,7 +141,7 @@ func (a *App) Run() {
 		}
 
 		var opts []goGrpc.ServerOption
-		opts = append(opts, goGrpc.UnaryInterceptor(grpc.InOutLoggingUnaryInterceptor))
+		opts = append(opts, goGrpc.UnaryInterceptor(grpc.AuthenticatingUnaryInterceptor(a.opts.aut, grpc.InOutLoggingUnaryInterceptor)))
 
 		if a.opts.tlsConfig != nil {
 			log.Println("Enabling tls for gRPC")
@@ -113,9 +149,10 @@ func (a *App) Run() {
 		}
 
 		grpcServer := &grpc.Server{
-			Server:   goGrpc.NewServer(opts...),
-			Listener: listener,
-			Handler:  grpc.NewHandler(add, lst),
+			Server:    goGrpc.NewServer(opts...),
+			Listener:  listener,
+			Handler:   grpc.NewHandler(add, lst, a.opts.signer),
+			HandlerV2: kiv2.NewHandler(add, lst, del, rev, sch, dif, a.opts.signer),
 		}
 
 		rnr.Add(grpcServer)
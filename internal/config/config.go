@@ -22,7 +22,7 @@ const (
 type MissingPropertyError string
 
 func (m MissingPropertyError) Error() string {
-	return fmt.Sprintf("Property %q is missing", m)
+	return fmt.Sprintf("Property %q is missing", string(m))
 }
 
 // Predefined Config
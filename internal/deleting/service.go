@@ -0,0 +1,77 @@
+package deleting
+
+import "github.com/larwef/ki/internal/domain"
+
+// ErrGroupNotEmpty is used when deleting a group which still has configs.
+var ErrGroupNotEmpty = domain.New(domain.FailedPrecondition, "group", "group has configs and cannot be deleted")
+
+// ErrConfigHasChildren is used when deleting a config which other configs inherit from.
+var ErrConfigHasChildren = domain.New(domain.FailedPrecondition, "config", "config has children and cannot be deleted")
+
+// ErrApprovalRequired is used when deleting a config or overlay in a group with approvers, where configs are only changed by
+// applying approved change requests.
+var ErrApprovalRequired = domain.New(domain.FailedPrecondition, "config", "configs in the group are changed by approved change requests")
+
+// Service provides deleting operations
+type Service interface {
+	DeleteGroup(id string) error
+	DeleteConfig(groupID string, id string) error
+	DeleteOverlay(groupID string, id string, env string) error
+}
+
+// Repository provides access to repository
+type Repository interface {
+	DeleteGroup(id string) error
+	// DeleteConfig deletes a config together with its overlays
+	DeleteConfig(groupID string, id string) error
+	DeleteOverlay(groupID string, id string, env string) error
+	// RetrieveApprovers retrieves the approvers of a group. Returns nil if the group has none.
+	RetrieveApprovers(groupID string) ([]string, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService created a new deleting service
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// DeleteGroup deletes a group. Returns ErrGroupNotEmpty if the group has configs, which have to be deleted first.
+func (s *service) DeleteGroup(id string) error {
+	return s.repo.DeleteGroup(id)
+}
+
+// DeleteConfig deletes a config and its overlays and removes it from its group. Returns ErrConfigHasChildren if other configs
+// have it as parent, and ErrApprovalRequired if the group has approvers.
+func (s *service) DeleteConfig(groupID string, id string) error {
+	if err := s.checkApprovers(groupID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteConfig(groupID, id)
+}
+
+// DeleteOverlay deletes the overlay of a config in an environment. Returns ErrApprovalRequired if the group has approvers.
+func (s *service) DeleteOverlay(groupID string, id string, env string) error {
+	if err := s.checkApprovers(groupID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteOverlay(groupID, id, env)
+}
+
+// checkApprovers checks that configs can be deleted from a group, which they cannot if the group has approvers
+func (s *service) checkApprovers(groupID string) error {
+	approvers, err := s.repo.RetrieveApprovers(groupID)
+	if err != nil {
+		return err
+	}
+
+	if len(approvers) > 0 {
+		return ErrApprovalRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package deleting_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestService_ApprovalRequired(t *testing.T) {
+	repo := memory.NewRepository()
+	add := adding.NewService(repo)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	test.AssertNotError(t, add.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Properties: []byte(`{"host":"db2"}`)}))
+	test.AssertNotError(t, repo.StoreApprovers("someGroup", []string{"bob"}))
+
+	service := deleting.NewService(repo)
+
+	err := service.DeleteConfig("someGroup", "someId")
+	test.AssertEqual(t, err, deleting.ErrApprovalRequired)
+
+	err = service.DeleteOverlay("someGroup", "someId", "prod")
+	test.AssertEqual(t, err, deleting.ErrApprovalRequired)
+
+	_, err = repo.RetrieveOverlay("someGroup", "someId", "prod")
+	test.AssertNotError(t, err)
+
+	// Without approvers configs are deleted directly
+	test.AssertNotError(t, repo.StoreApprovers("someGroup", nil))
+	test.AssertNotError(t, service.DeleteConfig("someGroup", "someId"))
+
+	_, err = repo.RetrieveConfig("someGroup", "someId")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
@@ -0,0 +1,167 @@
+// Package diffing provides structural diffs between the properties of two versions of a config, or of two configs. Secret
+// values are masked in every diff, and values only show as changed when they are written again.
+package diffing
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/secret"
+	"strconv"
+)
+
+// ErrRevisionNotFound is used when a config was not stored at a revision.
+var ErrRevisionNotFound = domain.New(domain.NotFound, listing.ConfigResource, "config revision not found")
+
+// ErrVersionNotFound is used when a config was never stored with a version.
+var ErrVersionNotFound = domain.New(domain.NotFound, listing.ConfigResource, "config version not found")
+
+// Side is a config compared by a Diff. Properties are the properties compared, with secret values masked.
+type Side struct {
+	Group      string          `json:"group"`
+	ID         string          `json:"id"`
+	Revision   int64           `json:"revision"`
+	Version    int             `json:"version"`
+	Properties json.RawMessage `json:"-"`
+}
+
+// String returns the config and revision of a side, like "someGroup/someId@3"
+func (s Side) String() string {
+	return s.Group + "/" + s.ID + "@" + strconv.FormatInt(s.Revision, 10)
+}
+
+// Diff holds the differences between the properties of two configs, ordered by path
+type Diff struct {
+	From        Side                    `json:"from"`
+	To          Side                    `json:"to"`
+	Differences []properties.Difference `json:"differences"`
+}
+
+// Service provides diffing operations
+type Service interface {
+	DiffVersions(groupID string, id string, from int, to *int) (*Diff, error)
+	DiffRevisions(groupID string, id string, from int64, to int64) (*Diff, error)
+	DiffConfigs(a listing.ConfigRef, b listing.ConfigRef, raw bool) (*Diff, error)
+}
+
+// Repository provides access to repository
+type Repository interface {
+	RetrieveConfig(groupID string, id string) (*listing.Config, error)
+	// RetrieveConfigRevision retrieves a config as it was stored at a revision. Returns ErrRevisionNotFound if the config was
+	// not stored at the revision.
+	RetrieveConfigRevision(groupID string, id string, revision int64) (*listing.Config, error)
+	// RetrieveConfigVersion retrieves a config as it was last stored with a version. Returns ErrVersionNotFound if the config
+	// was never stored with the version.
+	RetrieveConfigVersion(groupID string, id string, version int) (*listing.Config, error)
+}
+
+type service struct {
+	repo    Repository
+	listing listing.Service
+}
+
+// NewService returns a new diffing service reading the effective properties of configs with list
+func NewService(r Repository, list listing.Service) Service {
+	return &service{repo: r, listing: list}
+}
+
+// DiffVersions compares the properties of a config as it was stored with two versions, and a nil to is the current config.
+// Versions are set by whoever stores the config, so a version stored more than once is compared as it was stored last. Only
+// the properties of the config itself are compared, like for DiffRevisions.
+func (s *service) DiffVersions(groupID string, id string, from int, to *int) (*Diff, error) {
+	a, err := s.repo.RetrieveConfigVersion(groupID, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var b *listing.Config
+	if to == nil {
+		b, err = s.repo.RetrieveConfig(groupID, id)
+	} else {
+		b, err = s.repo.RetrieveConfigVersion(groupID, id, *to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return diff(a, b)
+}
+
+// DiffRevisions compares the properties of a config as it was stored at two revisions. The revisions are revisions the
+// config was stored at, and a zero to is the current config. Only the properties of the config itself are compared, as the
+// properties it inherits are not kept for old revisions.
+func (s *service) DiffRevisions(groupID string, id string, from int64, to int64) (*Diff, error) {
+	if from <= 0 {
+		return nil, invalidField("from", "has to be a revision")
+	}
+	if to < 0 {
+		return nil, invalidField("to", "has to be a revision")
+	}
+
+	a, err := s.repo.RetrieveConfigRevision(groupID, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var b *listing.Config
+	if to == 0 {
+		b, err = s.repo.RetrieveConfig(groupID, id)
+	} else {
+		b, err = s.repo.RetrieveConfigRevision(groupID, id, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return diff(a, b)
+}
+
+// DiffConfigs compares the current effective properties of two configs, or their own properties if raw is set. References
+// and secret placeholders are compared unresolved.
+func (s *service) DiffConfigs(a listing.ConfigRef, b listing.ConfigRef, raw bool) (*Diff, error) {
+	get := s.listing.GetConfig
+	if raw {
+		get = s.listing.GetRawConfig
+	}
+
+	from, err := get(a.Group, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := get(b.Group, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff(from, to)
+}
+
+func diff(from *listing.Config, to *listing.Config) (*Diff, error) {
+	differences, err := secret.Diff(from.Properties, to.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	if differences == nil {
+		differences = []properties.Difference{}
+	}
+
+	return &Diff{From: side(from), To: side(to), Differences: differences}, nil
+}
+
+func side(c *listing.Config) Side {
+	return Side{
+		Group:      c.Group,
+		ID:         c.ID,
+		Revision:   c.Revision,
+		Version:    c.Version,
+		Properties: secret.Masked(c.Properties),
+	}
+}
+
+func invalidField(field, reason string) error {
+	return adding.InvalidFieldError{Resource: listing.ConfigResource, Field: field, Reason: reason}
+}
@@ -0,0 +1,120 @@
+package diffing_test
+
+import (
+	"bytes"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/diffing"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func newService(t *testing.T) (adding.Service, diffing.Service) {
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+
+	repo := memory.NewRepository()
+	add := adding.NewService(repo, adding.Secrets(keeper))
+	return add, diffing.NewService(repo, listing.NewService(repo, listing.Secrets(keeper)))
+}
+
+func TestService_DiffRevisions(t *testing.T) {
+	add, service := newService(t)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 1, Properties: []byte(`{"host":"db1","port":5432,"password":{"$secret":"hunter2"}}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"db2","port":5432,"password":{"$secret":"hunter2"},"pool":10}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 3, Properties: []byte(`{"host":"db2","port":5432,"password":{"$secret":"hunter3"},"pool":10}`)}))
+
+	d, err := service.DiffRevisions("someGroup", "someId", 2, 3)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.From.Version, 1)
+	test.AssertEqual(t, d.To.Version, 2)
+	test.AssertEqual(t, d.To.String(), "someGroup/someId@3")
+	test.AssertEqual(t, len(d.Differences), 3)
+	test.AssertEqual(t, d.Differences[0], properties.Difference{Type: properties.Changed, Path: "/host", From: "db1", To: "db2"})
+	// The secret value is encrypted again when the config is written, and never revealed
+	test.AssertEqual(t, d.Differences[1], properties.Difference{Type: properties.Changed, Path: "/password", From: secret.Mask, To: secret.Mask})
+	test.AssertEqual(t, d.Differences[2], properties.Difference{Type: properties.Added, Path: "/pool", To: float64(10)})
+	test.AssertJSONEqual(t, string(d.To.Properties), `{"host":"db2","port":5432,"password":"********","pool":10}`)
+
+	// The current config is compared without to
+	d, err = service.DiffRevisions("someGroup", "someId", 3, 0)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.To.Revision, int64(4))
+	test.AssertEqual(t, len(d.Differences), 1)
+
+	d, err = service.DiffRevisions("someGroup", "someId", 4, 4)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(d.Differences), 0)
+
+	_, err = service.DiffRevisions("someGroup", "someId", 1, 4)
+	test.AssertEqual(t, err, diffing.ErrRevisionNotFound)
+
+	_, err = service.DiffRevisions("someGroup", "someId", 0, 4)
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	_, err = service.DiffRevisions("someGroup", "missingId", 2, 0)
+	test.AssertEqual(t, err, diffing.ErrRevisionNotFound)
+}
+
+func TestService_DiffVersions(t *testing.T) {
+	add, service := newService(t)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 3, Properties: []byte(`{"host":"db1"}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 3, Properties: []byte(`{"host":"db2"}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 7, Properties: []byte(`{"host":"db3"}`)}))
+
+	// A version stored more than once is compared as it was stored last
+	seven := 7
+	d, err := service.DiffVersions("someGroup", "someId", 3, &seven)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.From.Version, 3)
+	test.AssertEqual(t, d.From.Revision, int64(3))
+	test.AssertEqual(t, d.To.Version, 7)
+	test.AssertEqual(t, len(d.Differences), 1)
+	test.AssertEqual(t, d.Differences[0], properties.Difference{Type: properties.Changed, Path: "/host", From: "db2", To: "db3"})
+
+	// The current config is compared without to
+	d, err = service.DiffVersions("someGroup", "someId", 7, nil)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.To.Revision, int64(4))
+	test.AssertEqual(t, len(d.Differences), 0)
+
+	_, err = service.DiffVersions("someGroup", "someId", 5, nil)
+	test.AssertEqual(t, err, diffing.ErrVersionNotFound)
+
+	_, err = service.DiffVersions("someGroup", "missingId", 3, nil)
+	test.AssertEqual(t, err, diffing.ErrVersionNotFound)
+}
+
+func TestService_DiffConfigs(t *testing.T) {
+	add, service := newService(t)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "staging", Defaults: &adding.Defaults{Properties: []byte(`{"replicas":1}`)}}))
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "prod", Defaults: &adding.Defaults{Properties: []byte(`{"replicas":3}`)}}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "api", Group: "staging", Properties: []byte(`{"host":"db.staging","hosts":["a","b"]}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "api", Group: "prod", Properties: []byte(`{"host":"db.prod","hosts":["a"]}`)}))
+
+	staging := listing.ConfigRef{Group: "staging", ID: "api"}
+	prod := listing.ConfigRef{Group: "prod", ID: "api"}
+
+	d, err := service.DiffConfigs(staging, prod, false)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.From.Group, "staging")
+	test.AssertEqual(t, d.To.Group, "prod")
+	test.AssertEqual(t, len(d.Differences), 3)
+	test.AssertEqual(t, d.Differences[0].Path, "/host")
+	test.AssertEqual(t, d.Differences[1], properties.Difference{Type: properties.Removed, Path: "/hosts/1", From: "b"})
+	test.AssertEqual(t, d.Differences[2], properties.Difference{Type: properties.Changed, Path: "/replicas", From: float64(1), To: float64(3)})
+
+	// Inherited properties are left out of raw configs
+	d, err = service.DiffConfigs(staging, prod, true)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(d.Differences), 2)
+
+	_, err = service.DiffConfigs(staging, listing.ConfigRef{Group: "prod", ID: "missing"}, false)
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
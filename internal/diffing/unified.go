@@ -0,0 +1,196 @@
+package diffing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around changed lines in a unified diff
+const context = 3
+
+// edit is a line kept (' '), removed ('-') or added ('+') by a line diff
+type edit struct {
+	kind byte
+	line string
+}
+
+// Unified returns the differences as a unified diff of the properties written as indented JSON with sorted keys, or an empty
+// string if the properties are equal. Secret values are masked, so a changed secret value only shows in Differences.
+func (d *Diff) Unified() (string, error) {
+	a, err := lines(d.From.Properties)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := lines(d.To.Properties)
+	if err != nil {
+		return "", err
+	}
+
+	edits := lineDiff(a, b)
+
+	// aPos and bPos are the number of lines of a and b before each edit
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if e.kind != '+' {
+			aPos[i+1]++
+		}
+		if e.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(edits); {
+		for i < len(edits) && edits[i].kind == ' ' {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+
+		// Changes separated by at most twice the context are shown in the same hunk
+		last := i
+		for j := i; j < len(edits) && j-last <= 2*context; j++ {
+			if edits[j].kind != ' ' {
+				last = j
+			}
+		}
+
+		start, stop := max(i-context, 0), min(last+context+1, len(edits))
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "--- %s\n+++ %s\n", d.From, d.To)
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aPos[start], aPos[stop]), hunkRange(bPos[start], bPos[stop]))
+		for _, e := range edits[start:stop] {
+			buf.WriteByte(e.kind)
+			buf.WriteString(e.line)
+			buf.WriteByte('\n')
+		}
+
+		i = stop
+	}
+
+	return buf.String(), nil
+}
+
+// hunkRange returns the range of lines from start to end in a hunk header, which starts at 1 and leaves out a length of 1
+func hunkRange(start int, end int) string {
+	switch end - start {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, end-start)
+	}
+}
+
+// lines returns properties as lines of indented JSON. Empty properties are an empty object.
+func lines(props json.RawMessage) ([]string, error) {
+	var doc interface{} = map[string]interface{}{}
+	if len(props) > 0 {
+		if err := json.Unmarshal(props, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// lineDiff returns the shortest list of edits turning a into b, found with the Myers algorithm
+func lineDiff(a []string, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+
+	// trace holds v before each round, to find the path back from the end
+	var trace [][]int
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack follows the rounds of lineDiff back from the end of a and b and returns the edits in order
+func backtrack(trace [][]int, a []string, b []string, offset int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{kind: ' ', line: a[x-1]})
+			x, y = x-1, y-1
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{kind: '+', line: b[y-1]})
+			} else {
+				edits = append(edits, edit{kind: '-', line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package diffing
+
+import (
+	"testing"
+)
+
+func TestDiff_Unified(t *testing.T) {
+	tests := []struct {
+		from, to, expected string
+	}{
+		{`{"a":1}`, `{"a":1}`, ``},
+		{`{"a":1,"b":2}`, `{"a":1,"b":3}`, "--- g/a@1\n+++ g/b@2\n@@ -1,4 +1,4 @@\n {\n   \"a\": 1,\n-  \"b\": 2\n+  \"b\": 3\n }\n"},
+		{``, `{"a":"<b>"}`, "--- g/a@1\n+++ g/b@2\n@@ -1 +1,3 @@\n-{}\n+{\n+  \"a\": \"<b>\"\n+}\n"},
+		{
+			`{"a":1,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8,"i":9,"j":10}`,
+			`{"a":0,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8,"i":9,"j":11}`,
+			"--- g/a@1\n+++ g/b@2\n" +
+				"@@ -1,5 +1,5 @@\n {\n-  \"a\": 1,\n+  \"a\": 0,\n   \"b\": 2,\n   \"c\": 3,\n   \"d\": 4,\n" +
+				"@@ -8,5 +8,5 @@\n   \"g\": 7,\n   \"h\": 8,\n   \"i\": 9,\n-  \"j\": 10\n+  \"j\": 11\n }\n",
+		},
+		{
+			`{"a":1,"b":2,"c":3,"d":4,"e":5}`,
+			`{"a":0,"b":2,"c":3,"d":4,"e":6}`,
+			"--- g/a@1\n+++ g/b@2\n" +
+				"@@ -1,7 +1,7 @@\n {\n-  \"a\": 1,\n+  \"a\": 0,\n   \"b\": 2,\n   \"c\": 3,\n   \"d\": 4,\n-  \"e\": 5\n+  \"e\": 6\n }\n",
+		},
+	}
+
+	for _, tc := range tests {
+		d := &Diff{
+			From: Side{Group: "g", ID: "a", Revision: 1, Properties: []byte(tc.from)},
+			To:   Side{Group: "g", ID: "b", Revision: 2, Properties: []byte(tc.to)},
+		}
+
+		res, err := d.Unified()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res != tc.expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", tc.expected, res)
+		}
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		a, b     []string
+		expected string
+	}{
+		{nil, nil, ""},
+		{[]string{"a"}, nil, "-a"},
+		{nil, []string{"a"}, "+a"},
+		{[]string{"a", "b", "c"}, []string{"a", "c"}, " a-b c"},
+		{[]string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"}, "-a-b c+b a b-b a+c"},
+	}
+
+	for _, tc := range tests {
+		var res string
+		for _, e := range lineDiff(tc.a, tc.b) {
+			res += string(e.kind) + e.line
+		}
+		if res != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, res)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package domain provides the error model shared by the service packages. Errors describe what went wrong independent of the
+// API used, and each API maps them to its own status codes and error bodies.
+package domain
+
+// Kind is the category of an error
+type Kind int
+
+const (
+	// Internal is used for unexpected errors, like failing to read from storage. Details are not exposed to callers.
+	Internal Kind = iota
+	// InvalidArgument is used when a request is malformed or has invalid fields
+	InvalidArgument
+	// ValidationFailed is used when a resource is well-formed but does not satisfy the rules set for it, like a schema
+	ValidationFailed
+	// NotFound is used when a resource does not exist
+	NotFound
+	// AlreadyExists is used when creating a resource that already exists
+	AlreadyExists
+	// FailedPrecondition is used when a resource is not in the state required by the operation
+	FailedPrecondition
+	// Unauthenticated is used when the caller could not be identified
+	Unauthenticated
+	// PermissionDenied is used when the caller is not allowed to do the operation
+	PermissionDenied
+)
+
+var kindNames = map[Kind]string{
+	Internal:           "internal",
+	InvalidArgument:    "invalid argument",
+	ValidationFailed:   "validation failed",
+	NotFound:           "not found",
+	AlreadyExists:      "already exists",
+	FailedPrecondition: "failed precondition",
+	Unauthenticated:    "unauthenticated",
+	PermissionDenied:   "permission denied",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Violation describes why a field is invalid. Field is the name of the field, or a JSON Pointer to the field within the
+// resource.
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error is an error of a service package
+type Error struct {
+	Kind Kind
+	// Resource is the type of resource the error concerns, like "group" or "config". Can be empty.
+	Resource   string
+	Message    string
+	Violations []Violation
+}
+
+// New returns a new Error
+func New(kind Kind, resource, message string, violations ...Violation) *Error {
+	return &Error{
+		Kind:       kind,
+		Resource:   resource,
+		Message:    message,
+		Violations: violations,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Describer is implemented by errors of other types that can be described as an Error
+type Describer interface {
+	Describe() *Error
+}
+
+// From returns the Error describing err. Errors that are neither an Error nor a Describer are internal errors.
+func From(err error) *Error {
+	switch e := err.(type) {
+	case *Error:
+		return e
+	case Describer:
+		return e.Describe()
+	}
+
+	return New(Internal, "", "internal error")
+}
@@ -0,0 +1,51 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// nonKeyChars are the characters replaced by "_" in dotenv keys
+	nonKeyChars = regexp.MustCompile(`[^A-Z0-9_]`)
+	// plainValue matches values that can be written without quotes
+	plainValue = regexp.MustCompile(`^[A-Za-z0-9_./:@+,-]*$`)
+)
+
+// encodeDotenv writes a document as dotenv variables. Keys are the paths to the values in upper case joined by "_", like
+// DATABASE_HOSTS_0 for "database.hosts[0]", with other characters than letters and digits replaced by "_". Values are
+// double quoted if needed and null is written as an empty value. Returns an UnsupportedValueError if two values get the same
+// key.
+func encodeDotenv(w io.Writer, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	seen := make(map[string]string)
+	err := flatten(v, func(path []segment, v interface{}) error {
+		keys := make([]string, len(path))
+		for i, s := range path {
+			keys[i] = s.key
+		}
+		key := nonKeyChars.ReplaceAllString(strings.ToUpper(strings.Join(keys, "_")), "_")
+
+		if other, exists := seen[key]; exists {
+			return UnsupportedValueError{Format: "dotenv", Path: pointer(path), Reason: fmt.Sprintf("key %s is also used by %q", key, other)}
+		}
+		seen[key] = pointer(path)
+
+		value := scalarString(v)
+		if !plainValue.MatchString(value) {
+			// Variables are expanded within double quotes by most dotenv implementations
+			value = strings.Replace(quote(value), "$", `\$`, -1)
+		}
+
+		_, err := fmt.Fprintf(bw, "%s=%s\n", key, value)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
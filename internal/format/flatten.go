@@ -0,0 +1,69 @@
+package format
+
+import (
+	"github.com/larwef/ki/internal/properties"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// segment is a part of the path to a value. Index is set for array elements.
+type segment struct {
+	key   string
+	index bool
+}
+
+// flatten calls fn for every scalar in a decoded JSON document with the path to it, visiting object keys in sorted order.
+// Empty objects and arrays are left out.
+func flatten(v interface{}, fn func(path []segment, v interface{}) error) error {
+	return flattenAt(nil, v, fn)
+}
+
+func flattenAt(path []segment, v interface{}, fn func(path []segment, v interface{}) error) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			if err := flattenAt(append(path[:len(path):len(path)], segment{key: k}), val[k], fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range val {
+			if err := flattenAt(append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), child, fn); err != nil {
+				return err
+			}
+		}
+	default:
+		return fn(path, v)
+	}
+
+	return nil
+}
+
+// pointer returns the JSON Pointer to a path, used in errors
+func pointer(path []segment) string {
+	p := properties.Pointer{}
+	for _, s := range path {
+		p = p.Child(s.key)
+	}
+	return p.String()
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// formatNumber formats a JSON number without exponent if it is an integer of reasonable size
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
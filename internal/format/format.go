@@ -0,0 +1,188 @@
+// Package format reads and renders config properties in formats other than JSON. Formats are kept in a Registry and chosen by
+// name, by the media type of a request or by content negotiation on the media types they are served as.
+package format
+
+import (
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSON is the name of the JSON format, which is the default
+const JSON = "json"
+
+// UnsupportedValueError is used when a value cannot be represented in a format, like null in TOML.
+type UnsupportedValueError struct {
+	Format string
+	// Path is a JSON Pointer to the value
+	Path   string
+	Reason string
+}
+
+func (u UnsupportedValueError) Error() string {
+	return fmt.Sprintf("value at %q cannot be represented in %s: %s", u.Path, u.Format, u.Reason)
+}
+
+// Describe describes the error as an invalid argument, which is the case when reading a document
+func (u UnsupportedValueError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, "config", u.Error())
+}
+
+// InvalidDocumentError is used when a document cannot be read, like when it has syntax errors or duplicate keys.
+type InvalidDocumentError struct {
+	Format string
+	Reason string
+}
+
+func (i InvalidDocumentError) Error() string {
+	return fmt.Sprintf("invalid %s document: %s", i.Format, i.Reason)
+}
+
+// Describe describes the error as an invalid argument
+func (i InvalidDocumentError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, "config", i.Error())
+}
+
+// Format is a format properties can be rendered in
+type Format struct {
+	Name string
+	// MediaTypes are the media types the format is negotiated by. The first one is used as content type.
+	MediaTypes []string
+	// Binary is set for formats that are not text, which have no charset
+	Binary bool
+	// Encode writes a decoded JSON document in the format. Returns an UnsupportedValueError if the document has values that
+	// cannot be represented.
+	Encode func(w io.Writer, v interface{}) error
+	// Decode reads a document in the format as a value that can be marshalled to JSON. Returns an InvalidDocumentError or
+	// UnsupportedValueError if the document cannot be read. Nil if the format can only be written.
+	Decode func(r io.Reader) (interface{}, error)
+}
+
+// ContentType returns the content type of responses in the format
+func (f *Format) ContentType() string {
+	if f.Binary {
+		return f.MediaTypes[0]
+	}
+
+	return f.MediaTypes[0] + "; charset=utf-8"
+}
+
+// Registry holds formats by name. The first format registered is the default.
+type Registry struct {
+	formats []*Format
+	byName  map[string]*Format
+}
+
+// NewRegistry returns a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*Format)}
+}
+
+// NewDefaultRegistry returns a Registry with JSON as default, YAML, TOML, Java properties, dotenv, MessagePack and HCL. All
+// but dotenv and HCL can also be read.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(&Format{Name: JSON, MediaTypes: []string{"application/json"}, Encode: encodeJSON, Decode: decodeJSON})
+	r.Register(&Format{Name: "yaml", MediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml"}, Encode: encodeYAML, Decode: decodeYAML})
+	r.Register(&Format{Name: "toml", MediaTypes: []string{"application/toml"}, Encode: encodeTOML, Decode: decodeTOML})
+	r.Register(&Format{Name: "properties", MediaTypes: []string{"text/x-java-properties"}, Encode: encodeProperties, Decode: decodeProperties})
+	r.Register(&Format{Name: "dotenv", MediaTypes: []string{"text/x-dotenv"}, Encode: encodeDotenv})
+	r.Register(&Format{Name: MessagePack, MediaTypes: []string{"application/msgpack", "application/x-msgpack"}, Binary: true, Encode: encodeMsgpack, Decode: decodeMsgpack})
+	r.Register(&Format{Name: "hcl", MediaTypes: []string{"application/hcl", "text/x-hcl"}, Encode: encodeHCL})
+	return r
+}
+
+// Register adds a format, replacing any format with the same name
+func (r *Registry) Register(f *Format) {
+	if old, exists := r.byName[f.Name]; exists {
+		for i := range r.formats {
+			if r.formats[i] == old {
+				r.formats[i] = f
+			}
+		}
+	} else {
+		r.formats = append(r.formats, f)
+	}
+	r.byName[f.Name] = f
+}
+
+// Lookup returns the format with a name
+func (r *Registry) Lookup(name string) (*Format, bool) {
+	f, exists := r.byName[name]
+	return f, exists
+}
+
+// ForMediaType returns the format served as a media type, like the media type of a request body
+func (r *Registry) ForMediaType(mediaType string) (*Format, bool) {
+	for _, f := range r.formats {
+		for _, mt := range f.MediaTypes {
+			if mt == mediaType {
+				return f, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Negotiate returns the format best matching an Accept header, as specified in RFC 7231. Media ranges are tried by
+// descending quality and then by order. An empty header accepts the default format. Returns false if no format is
+// acceptable.
+func (r *Registry) Negotiate(accept string) (*Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		if len(r.formats) == 0 {
+			return nil, false
+		}
+		return r.formats[0], true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, exists := params["q"]; exists {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, mr := range ranges {
+		for _, f := range r.formats {
+			for _, mt := range f.MediaTypes {
+				if matches(mr.mediaType, mt) {
+					return f, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// matches checks if a media type is within a media range like "*/*" or "text/*"
+func matches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+
+	return strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+}
@@ -0,0 +1,268 @@
+package format_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/larwef/ki/internal/format"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+const document = `{
+	"database": {"host": "db1", "port": 5432, "timeout": 2.5, "options": {}},
+	"debug": true,
+	"name": "some \"quoted\" näme",
+	"servers": [{"host": "a", "ports": [80, 443]}, {"host": "b", "ports": []}],
+	"tags": ["x", "y"],
+	"some key": "with $HOME and ${var}",
+	"big": 12345678
+}`
+
+func encode(t *testing.T, name string, doc string) (string, error) {
+	f, ok := format.NewDefaultRegistry().Lookup(name)
+	test.AssertEqual(t, ok, true)
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err := f.Encode(&buf, v)
+	return buf.String(), err
+}
+
+func TestEncode(t *testing.T) {
+	tests := map[string]string{
+		"yaml": `big: 12345678
+database:
+  host: db1
+  options: {}
+  port: 5432
+  timeout: 2.5
+debug: true
+name: some "quoted" näme
+servers:
+- host: a
+  ports:
+  - 80
+  - 443
+- host: b
+  ports: []
+some key: with $HOME and ${var}
+tags:
+- x
+- "y"
+`,
+		"toml": `big = 12345678
+debug = true
+name = "some \"quoted\" näme"
+"some key" = "with $HOME and ${var}"
+tags = ["x", "y"]
+
+[database]
+host = "db1"
+port = 5432
+timeout = 2.5
+
+[database.options]
+
+[[servers]]
+host = "a"
+ports = [80, 443]
+
+[[servers]]
+host = "b"
+ports = []
+`,
+		"properties": `big=12345678
+database.host=db1
+database.port=5432
+database.timeout=2.5
+debug=true
+name=some "quoted" n\u00E4me
+servers[0].host=a
+servers[0].ports[0]=80
+servers[0].ports[1]=443
+servers[1].host=b
+some\ key=with $HOME and ${var}
+tags[0]=x
+tags[1]=y
+`,
+		"dotenv": `BIG=12345678
+DATABASE_HOST=db1
+DATABASE_PORT=5432
+DATABASE_TIMEOUT=2.5
+DEBUG=true
+NAME="some \"quoted\" näme"
+SERVERS_0_HOST=a
+SERVERS_0_PORTS_0=80
+SERVERS_0_PORTS_1=443
+SERVERS_1_HOST=b
+SOME_KEY="with \$HOME and \${var}"
+TAGS_0=x
+TAGS_1=y
+`,
+		"hcl": `big = 12345678
+database {
+  host = "db1"
+  options {
+  }
+  port = 5432
+  timeout = 2.5
+}
+debug = true
+name = "some \"quoted\" näme"
+servers = [{ host = "a", ports = [80, 443] }, { host = "b", ports = [] }]
+"some key" = "with $HOME and $${var}"
+tags = ["x", "y"]
+`,
+	}
+
+	for name, expected := range tests {
+		actual, err := encode(t, name, document)
+		test.AssertNotError(t, err)
+		if actual != expected {
+			t.Errorf("%s: expected:\n%s\nactual:\n%s", name, expected, actual)
+		}
+	}
+}
+
+func TestEncode_Unsupported(t *testing.T) {
+	tests := []struct {
+		format, doc string
+		err         format.UnsupportedValueError
+	}{
+		{"toml", `{"a":{"b":null}}`, format.UnsupportedValueError{Format: "TOML", Path: "/a/b", Reason: "TOML has no null"}},
+		{"toml", `[1]`, format.UnsupportedValueError{Format: "TOML", Path: "", Reason: "the root has to be an object"}},
+		{"hcl", `"a"`, format.UnsupportedValueError{Format: "HCL", Path: "", Reason: "the root has to be an object"}},
+		{"dotenv", `{"a.b":1,"a_b":2}`, format.UnsupportedValueError{Format: "dotenv", Path: "/a_b", Reason: `key A_B is also used by "/a.b"`}},
+	}
+
+	for _, tc := range tests {
+		_, err := encode(t, tc.format, tc.doc)
+		test.AssertEqual(t, err, tc.err)
+	}
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	registry := format.NewDefaultRegistry()
+
+	tests := map[string]string{
+		"":                                      "json",
+		"*/*":                                   "json",
+		"application/yaml":                      "yaml",
+		"text/x-yaml, application/x-yaml":       "yaml",
+		"application/toml;q=0.5, text/*;q=0.8":  "yaml",
+		"text/x-dotenv, application/json;q=0.9": "dotenv",
+		"application/hcl;q=0, application/*":    "json",
+	}
+
+	for accept, expected := range tests {
+		f, ok := registry.Negotiate(accept)
+		test.AssertEqual(t, ok, true)
+		test.AssertEqual(t, f.Name, expected)
+	}
+
+	_, ok := registry.Negotiate("image/png, application/toml;q=0")
+	test.AssertEqual(t, ok, false)
+}
+
+func decode(t *testing.T, name string, doc string) (string, error) {
+	f, ok := format.NewDefaultRegistry().Lookup(name)
+	test.AssertEqual(t, ok, true)
+
+	v, err := f.Decode(bytes.NewBufferString(doc))
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(v)
+	test.AssertNotError(t, err)
+	return string(b), nil
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		format, doc, expected string
+	}{
+		{"yaml", "db:\n  host: db1\n  port: 5432\n  ratio: 0.5\nlist: [1, x, true, null]\n200: ok\nbig: 12345678901234567890\n",
+			`{"200":"ok","big":12345678901234567890,"db":{"host":"db1","port":5432,"ratio":0.5},"list":[1,"x",true,null]}`},
+		{"yaml", "base: &base {a: 1}\nother:\n  <<: *base\n  b: 2\n", `{"base":{"a":1},"other":{"a":1,"b":2}}`},
+		{"yaml", "", `null`},
+		{"toml", "name = \"x\"\n\n[db]\nport = 5432\n\n[[servers]]\nhost = \"a\"\n\n[[servers]]\nhost = \"b\"\n",
+			`{"db":{"port":5432},"name":"x","servers":[{"host":"a"},{"host":"b"}]}`},
+		{"properties", "# comment\n! comment\ndb.host = db1\ndb.port:5432\nservers[1].host=b\nservers[0].host=a\nkey\\ with\\ spaces=multi \\\n    line\nname=n\\u00E4me \\uD83D\\uDE00\nempty\n",
+			`{"db":{"host":"db1","port":"5432"},"empty":"","key with spaces":"multi line","name":"näme 😀","servers":[{"host":"a"},{"host":"b"}]}`},
+		{"json", `{"a":12345678901234567890}`, `{"a":12345678901234567890}`},
+	}
+
+	for _, tc := range tests {
+		actual, err := decode(t, tc.format, tc.doc)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, actual, tc.expected)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		format, doc string
+		err         error
+	}{
+		{"yaml", "a: 1\na: 2\n", format.InvalidDocumentError{Format: "YAML", Reason: `unmarshal errors: line 2: key "a" already set in map`}},
+		{"yaml", "a: 1\n---\nb: 2\n", format.InvalidDocumentError{Format: "YAML", Reason: "only a single document is allowed"}},
+		{"yaml", "a: [1\n", format.InvalidDocumentError{Format: "YAML", Reason: "line 1: did not find expected ',' or ']'"}},
+		{"yaml", "a:\n  ~: x\n", format.UnsupportedValueError{Format: "JSON", Path: "/a", Reason: "JSON keys have to be strings, but found null"}},
+		{"yaml", "a: [.inf]\n", format.UnsupportedValueError{Format: "JSON", Path: "/a/0", Reason: "JSON has no infinite numbers or NaN"}},
+		{"toml", "a = 1\na = 2\n", format.InvalidDocumentError{Format: "TOML", Reason: "Near line 2 (last key parsed 'a'): Key 'a' has already been defined."}},
+		{"toml", "[a]\nb = 1979-05-27T07:32:00Z\n", format.UnsupportedValueError{Format: "JSON", Path: "/a/b", Reason: "JSON has no datetimes, quote the value to store it as a string"}},
+		{"properties", "a=1\na=2\n", format.InvalidDocumentError{Format: "Java properties", Reason: `line 2: key "a" is given more than once`}},
+		{"properties", "a=1\na.b=2\n", format.InvalidDocumentError{Format: "Java properties", Reason: `line 2: key "a.b" conflicts with an earlier key`}},
+		{"properties", "a[0]=1\na.b=2\n", format.InvalidDocumentError{Format: "Java properties", Reason: `line 2: key "a.b" conflicts with an earlier key`}},
+		{"properties", "a..b=1\n", format.InvalidDocumentError{Format: "Java properties", Reason: `line 1: key "a..b" is not a valid path`}},
+		{"properties", "a[1]=1\n", format.InvalidDocumentError{Format: "Java properties", Reason: `array at "/a" has no element 0`}},
+		{"properties", "a=\\u00G0\n", format.InvalidDocumentError{Format: "Java properties", Reason: `line 1: malformed \uXXXX escape "\\u00G0"`}},
+	}
+
+	for _, tc := range tests {
+		_, err := decode(t, tc.format, tc.doc)
+		test.AssertEqual(t, err, tc.err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range []string{"json", "yaml", "toml", "msgpack"} {
+		encoded, err := encode(t, name, document)
+		test.AssertNotError(t, err)
+
+		decoded, err := decode(t, name, encoded)
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, decoded, document)
+	}
+}
+
+func TestEncode_Msgpack(t *testing.T) {
+	actual, err := encode(t, "msgpack", `{"b":[1,-1,200,-200,70000,0.5,null,true],"a":"x"}`)
+	test.AssertNotError(t, err)
+
+	expected := "\x82\xa1a\xa1x\xa1b\x98\x01\xff\xd1\x00\xc8\xd1\xff\x38\xd2\x00\x01\x11\x70\xcb\x3f\xe0\x00\x00\x00\x00\x00\x00\xc0\xc3"
+	test.AssertEqual(t, actual, expected)
+}
+
+func TestDecode_MsgpackInvalid(t *testing.T) {
+	tests := []struct {
+		doc string
+		err error
+	}{
+		{"\x92\x01", format.InvalidDocumentError{Format: "MessagePack", Reason: "unexpected end of document"}},
+		{"\x01\x02", format.InvalidDocumentError{Format: "MessagePack", Reason: "only a single value is allowed"}},
+		{"\xc1", format.InvalidDocumentError{Format: "MessagePack", Reason: "unknown type 0xc1"}},
+		{"\x81\xa1a\xc4\x01\x00", format.UnsupportedValueError{Format: "JSON", Path: "/a", Reason: "JSON has no binary data"}},
+		{"\x81\x01\x02", format.UnsupportedValueError{Format: "JSON", Path: "", Reason: "JSON keys have to be strings, but found 1"}},
+	}
+
+	for _, tc := range tests {
+		_, err := decode(t, "msgpack", tc.doc)
+		test.AssertEqual(t, err, tc.err)
+	}
+}
@@ -0,0 +1,78 @@
+package format
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// encodeHCL writes a document as a subset of HCL: objects become blocks, and arrays and objects within arrays are written
+// inline. The root has to be an object.
+func encodeHCL(w io.Writer, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return UnsupportedValueError{Format: "HCL", Path: "", Reason: "the root has to be an object"}
+	}
+
+	var buf bytes.Buffer
+	writeHCLBody(&buf, obj, "")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeHCLBody(buf *bytes.Buffer, obj map[string]interface{}, indent string) {
+	for _, k := range sortedKeys(obj) {
+		if block, ok := obj[k].(map[string]interface{}); ok {
+			buf.WriteString(indent + hclKey(k) + " {\n")
+			writeHCLBody(buf, block, indent+"  ")
+			buf.WriteString(indent + "}\n")
+		} else {
+			buf.WriteString(indent + hclKey(k) + " = " + hclValue(obj[k]) + "\n")
+		}
+	}
+}
+
+func hclValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		// "${" and "%{" start template sequences in HCL strings
+		s := quote(val)
+		s = strings.Replace(s, "${", "$${", -1)
+		return strings.Replace(s, "%{", "%%{", -1)
+	case []interface{}:
+		elems := make([]string, len(val))
+		for i, elem := range val {
+			elems[i] = hclValue(elem)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case map[string]interface{}:
+		var pairs []string
+		for _, k := range sortedKeys(val) {
+			pairs = append(pairs, hclKey(k)+" = "+hclValue(val[k]))
+		}
+		if len(pairs) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(pairs, ", ") + " }"
+	}
+
+	return scalarString(v)
+}
+
+func hclKey(k string) string {
+	if identifier.MatchString(k) {
+		return k
+	}
+
+	return quote(k)
+}
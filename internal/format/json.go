@@ -0,0 +1,30 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func encodeJSON(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// decodeJSON reads a single JSON value, keeping numbers as they are written
+func decodeJSON(r io.Reader) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, InvalidDocumentError{Format: "JSON", Reason: err.Error()}
+	}
+
+	if dec.More() {
+		return nil, InvalidDocumentError{Format: "JSON", Reason: "only a single value is allowed"}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,311 @@
+package format
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MessagePack is the name of the MessagePack format
+const MessagePack = "msgpack"
+
+// encodeMsgpack writes a document as MessagePack. Integers are written in the smallest integer type holding them, other numbers
+// as 64-bit floats and object keys in sorted order, so equal documents are encoded equally.
+func encodeMsgpack(w io.Writer, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	if err := writeMsgpack(bw, nil, v); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+func writeMsgpack(w *bufio.Writer, path []segment, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		w.WriteByte(0xc0)
+	case bool:
+		if val {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<63 {
+			writeMsgpackInt(w, int64(val))
+		} else {
+			writeMsgpackFloat(w, val)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(val), 10, 64); err == nil {
+			writeMsgpackInt(w, i)
+		} else if u, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			w.WriteByte(0xcf)
+			binary.Write(w, binary.BigEndian, u)
+		} else if f, err := val.Float64(); err == nil {
+			writeMsgpackFloat(w, f)
+		} else {
+			return UnsupportedValueError{Format: "MessagePack", Path: pointer(path), Reason: "the number is out of range"}
+		}
+	case string:
+		writeMsgpackHeader(w, len(val), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		w.WriteString(val)
+	case []interface{}:
+		writeMsgpackHeader(w, len(val), 0x90, 16, 0, 0xdc, 0xdd)
+		for i, elem := range val {
+			if err := writeMsgpack(w, append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), elem); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		writeMsgpackHeader(w, len(val), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range sortedKeys(val) {
+			writeMsgpackHeader(w, len(k), 0xa0, 32, 0xd9, 0xda, 0xdb)
+			w.WriteString(k)
+			if err := writeMsgpack(w, append(path[:len(path):len(path)], segment{key: k}), val[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return UnsupportedValueError{Format: "MessagePack", Path: pointer(path), Reason: fmt.Sprintf("unknown type %T", v)}
+	}
+
+	return nil
+}
+
+func writeMsgpackInt(w *bufio.Writer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		w.WriteByte(byte(i))
+	case i >= -32 && i < 0:
+		w.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		w.WriteByte(0xd0)
+		w.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		w.WriteByte(0xd1)
+		binary.Write(w, binary.BigEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		w.WriteByte(0xd2)
+		binary.Write(w, binary.BigEndian, int32(i))
+	default:
+		w.WriteByte(0xd3)
+		binary.Write(w, binary.BigEndian, i)
+	}
+}
+
+func writeMsgpackFloat(w *bufio.Writer, f float64) {
+	w.WriteByte(0xcb)
+	binary.Write(w, binary.BigEndian, math.Float64bits(f))
+}
+
+// writeMsgpackHeader writes the type and length of a string, array or map. fix is the type of the short form holding lengths
+// below fixLimit, and the others the types with 8, 16 and 32-bit lengths, where 0 means there is none.
+func writeMsgpackHeader(w *bufio.Writer, n int, fix byte, fixLimit int, t8, t16, t32 byte) {
+	switch {
+	case n < fixLimit:
+		w.WriteByte(fix | byte(n))
+	case n <= math.MaxUint8 && t8 != 0:
+		w.WriteByte(t8)
+		w.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(t16)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(t32)
+		binary.Write(w, binary.BigEndian, uint32(n))
+	}
+}
+
+// decodeMsgpack reads a single MessagePack value. Binary data, extension types and keys that are not strings have no JSON
+// representation.
+func decodeMsgpack(r io.Reader) (interface{}, error) {
+	br := bufio.NewReader(r)
+	if _, err := br.Peek(1); err == io.EOF {
+		return nil, nil
+	}
+
+	v, err := readMsgpack(br, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := br.Peek(1); err != io.EOF {
+		return nil, InvalidDocumentError{Format: "MessagePack", Reason: "only a single value is allowed"}
+	}
+
+	return v, nil
+}
+
+func readMsgpack(r *bufio.Reader, path []segment) (interface{}, error) {
+	unsupported := func(reason string) error {
+		return UnsupportedValueError{Format: "JSON", Path: pointer(path), Reason: reason}
+	}
+
+	t, err := r.ReadByte()
+	if err != nil {
+		return nil, truncated(err)
+	}
+
+	switch {
+	case t <= 0x7f:
+		return json.Number(strconv.Itoa(int(t))), nil
+	case t >= 0xe0:
+		return json.Number(strconv.Itoa(int(int8(t)))), nil
+	case t >= 0xa0 && t <= 0xbf:
+		return readMsgpackString(r, path, int(t&0x1f))
+	case t >= 0x90 && t <= 0x9f:
+		return readMsgpackArray(r, path, int(t&0x0f))
+	case t >= 0x80 && t <= 0x8f:
+		return readMsgpackMap(r, path, int(t&0x0f))
+	}
+
+	switch t {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := readMsgpackUint(r, 1<<(t-0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatUint(u, 10)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (t - 0xd0)
+		u, err := readMsgpackUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign extend from the size read
+		shift := uint(64 - 8*size)
+		return json.Number(strconv.FormatInt(int64(u<<shift)>>shift, 10)), nil
+	case 0xca, 0xcb:
+		var f float64
+		if t == 0xca {
+			u, err := readMsgpackUint(r, 4)
+			if err != nil {
+				return nil, err
+			}
+			f = float64(math.Float32frombits(uint32(u)))
+		} else {
+			u, err := readMsgpackUint(r, 8)
+			if err != nil {
+				return nil, err
+			}
+			f = math.Float64frombits(u)
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, unsupported("JSON has no infinite numbers or NaN")
+		}
+		return f, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := readMsgpackUint(r, 1<<(t-0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, path, int(n))
+	case 0xdc, 0xdd:
+		n, err := readMsgpackUint(r, 2<<(t-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackArray(r, path, int(n))
+	case 0xde, 0xdf:
+		n, err := readMsgpackUint(r, 2<<(t-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackMap(r, path, int(n))
+	case 0xc4, 0xc5, 0xc6:
+		return nil, unsupported("JSON has no binary data")
+	case 0xc7, 0xc8, 0xc9, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return nil, unsupported("JSON has no extension types")
+	}
+
+	return nil, InvalidDocumentError{Format: "MessagePack", Reason: fmt.Sprintf("unknown type 0x%02x", t)}
+}
+
+func readMsgpackUint(r *bufio.Reader, size int) (uint64, error) {
+	var u uint64
+	for i := 0; i < size; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, truncated(err)
+		}
+		u = u<<8 | uint64(b)
+	}
+
+	return u, nil
+}
+
+func readMsgpackString(r *bufio.Reader, path []segment, n int) (interface{}, error) {
+	// The length is not trusted to allocate up front, as it is read from the request
+	var b []byte
+	for i := 0; i < n; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, truncated(err)
+		}
+		b = append(b, c)
+	}
+
+	if !utf8.Valid(b) {
+		return nil, UnsupportedValueError{Format: "JSON", Path: pointer(path), Reason: "the string is not valid UTF-8"}
+	}
+
+	return string(b), nil
+}
+
+func readMsgpackArray(r *bufio.Reader, path []segment, n int) (interface{}, error) {
+	arr := []interface{}{}
+	for i := 0; i < n; i++ {
+		elem, err := readMsgpack(r, append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, elem)
+	}
+
+	return arr, nil
+}
+
+func readMsgpackMap(r *bufio.Reader, path []segment, n int) (interface{}, error) {
+	obj := map[string]interface{}{}
+	for i := 0; i < n; i++ {
+		k, err := readMsgpack(r, path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := k.(string)
+		if !ok {
+			return nil, UnsupportedValueError{Format: "JSON", Path: pointer(path), Reason: fmt.Sprintf("JSON keys have to be strings, but found %v", k)}
+		}
+		if _, exists := obj[key]; exists {
+			return nil, InvalidDocumentError{Format: "MessagePack", Reason: fmt.Sprintf("key %q at %q is used more than once", key, pointer(path))}
+		}
+
+		if obj[key], err = readMsgpack(r, append(path[:len(path):len(path)], segment{key: key})); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// truncated makes an InvalidDocumentError of reaching the end of a document before a value is complete
+func truncated(err error) error {
+	if err == io.EOF {
+		return InvalidDocumentError{Format: "MessagePack", Reason: "unexpected end of document"}
+	}
+
+	return err
+}
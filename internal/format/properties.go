@@ -0,0 +1,373 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// encodeProperties writes a document as Java properties. Keys are the paths to the values, with object keys joined by "."
+// and array indices in brackets, like "servers[0].host". Null is written as an empty value. Characters outside ISO 8859-1
+// are escaped as \uXXXX as expected by java.util.Properties.
+func encodeProperties(w io.Writer, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	err := flatten(v, func(path []segment, v interface{}) error {
+		var key strings.Builder
+		for i, s := range path {
+			if s.index {
+				key.WriteString("[" + s.key + "]")
+				continue
+			}
+			if i > 0 {
+				key.WriteByte('.')
+			}
+			key.WriteString(s.key)
+		}
+
+		_, err := fmt.Fprintf(bw, "%s=%s\n", escapeProperty(key.String(), true), escapeProperty(scalarString(v), false))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// escapeProperty escapes a key or value of a Java property
+func escapeProperty(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r == ' ' && (isKey || i == 0):
+			sb.WriteString(`\ `)
+		case strings.ContainsRune("=:#!", r) && (isKey || i == 0):
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			if r1, r2 := utf16.EncodeRune(r); r1 != '\uFFFD' {
+				fmt.Fprintf(&sb, `\u%04X\u%04X`, r1, r2)
+			} else {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+// scalarString formats a scalar value without quotes. Null is the empty string.
+func scalarString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return formatNumber(val)
+	case string:
+		return val
+	}
+
+	return fmt.Sprint(v)
+}
+
+// property is a key and value read from a logical line of Java properties
+type property struct {
+	line       int
+	key, value string
+}
+
+// decodeProperties reads Java properties as written by encodeProperties, where keys like "servers[0].host" are paths into
+// nested objects and arrays. Every value is a string, as properties have no types. Keys may not be given more than once, be
+// both a value and a parent of other keys, or leave gaps in arrays.
+func decodeProperties(r io.Reader) (interface{}, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := parseProperties(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	seen := make(map[string]bool)
+	for _, p := range props {
+		if seen[p.key] {
+			return nil, invalidProperties(p.line, "key %q is given more than once", p.key)
+		}
+		seen[p.key] = true
+
+		path, ok := parsePropertyKey(p.key)
+		if !ok {
+			return nil, invalidProperties(p.line, "key %q is not a valid path", p.key)
+		}
+
+		if !setProperty(doc, path, p.value) {
+			return nil, invalidProperties(p.line, "key %q conflicts with an earlier key", p.key)
+		}
+	}
+
+	return denseArrays(nil, doc)
+}
+
+// parseProperties splits properties into keys and values as done by java.util.Properties
+func parseProperties(s string) ([]property, error) {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.Replace(s, "\r", "\n", -1)
+	lines := strings.Split(s, "\n")
+
+	var props []property
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// A line ending with an odd number of backslashes continues on the next line
+		for continues(line) {
+			line = line[:len(line)-1]
+			if i+1 < len(lines) {
+				i++
+				line += strings.TrimLeft(lines[i], " \t\f")
+			}
+		}
+
+		end := keyEnd(line)
+		key, rest := line[:end], strings.TrimLeft(line[end:], " \t\f")
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+
+		k, err := unescapeProperty(key)
+		if err != nil {
+			return nil, invalidProperties(start, "%s", err.Error())
+		}
+		v, err := unescapeProperty(rest)
+		if err != nil {
+			return nil, invalidProperties(start, "%s", err.Error())
+		}
+		props = append(props, property{line: start, key: k, value: v})
+	}
+
+	return props, nil
+}
+
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// keyEnd returns the index of the first unescaped '=', ':' or whitespace of a line
+func keyEnd(line string) int {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			return i
+		}
+	}
+
+	return len(line)
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.ContainsRune(s, '\\') {
+		return s, nil
+	}
+
+	var units []uint16
+	var sb strings.Builder
+	flush := func() {
+		sb.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			flush()
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if s[i] == 'u' {
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX escape %q", s[i-1:])
+			}
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX escape %q", s[i-1:i+5])
+			}
+			// Characters outside the basic multilingual plane are escaped as two UTF-16 code units
+			units = append(units, uint16(u))
+			i += 4
+			continue
+		}
+
+		flush()
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	flush()
+
+	return sb.String(), nil
+}
+
+// parsePropertyKey splits a key like "servers[0].host" into its path
+func parsePropertyKey(key string) ([]segment, bool) {
+	var path []segment
+	for i := 0; i < len(key); {
+		switch {
+		case key[i] == '[':
+			end := strings.IndexByte(key[i:], ']')
+			if end < 0 || len(path) == 0 {
+				return nil, false
+			}
+			index := key[i+1 : i+end]
+			if n, err := strconv.Atoi(index); err != nil || n < 0 || strconv.Itoa(n) != index {
+				return nil, false
+			}
+			path = append(path, segment{key: index, index: true})
+			i += end + 1
+		case key[i] == '.' && len(path) > 0:
+			i++
+			fallthrough
+		case len(path) == 0:
+			end := strings.IndexAny(key[i:], ".[")
+			if end < 0 {
+				end = len(key) - i
+			}
+			if end == 0 {
+				return nil, false
+			}
+			path = append(path, segment{key: key[i : i+end]})
+			i += end
+		default:
+			return nil, false
+		}
+	}
+
+	return path, len(path) > 0
+}
+
+// setProperty sets a value in doc, where arrays are maps from index to element until they are made dense. Returns false if
+// the value or a parent of it is already set to something else.
+func setProperty(doc map[string]interface{}, path []segment, value string) bool {
+	var parent interface{} = doc
+	for i, s := range path {
+		var child interface{}
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			child = p[s.key]
+		case map[int]interface{}:
+			n, _ := strconv.Atoi(s.key)
+			child = p[n]
+		}
+
+		if i == len(path)-1 {
+			if child != nil {
+				return false
+			}
+			child = value
+		} else if child == nil {
+			if path[i+1].index {
+				child = map[int]interface{}{}
+			} else {
+				child = map[string]interface{}{}
+			}
+		}
+
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if s.index {
+				return false
+			}
+			p[s.key] = child
+		case map[int]interface{}:
+			if !s.index {
+				return false
+			}
+			n, _ := strconv.Atoi(s.key)
+			p[n] = child
+		default:
+			return false
+		}
+		parent = child
+	}
+
+	return true
+}
+
+// denseArrays turns the arrays set by setProperty into slices, checking that no index is missing
+func denseArrays(path []segment, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			var err error
+			if val[k], err = denseArrays(append(path[:len(path):len(path)], segment{key: k}), child); err != nil {
+				return nil, err
+			}
+		}
+	case map[int]interface{}:
+		indices := make([]int, 0, len(val))
+		for n := range val {
+			indices = append(indices, n)
+		}
+		sort.Ints(indices)
+
+		res := make([]interface{}, len(indices))
+		for i, n := range indices {
+			if n != i {
+				return nil, InvalidDocumentError{Format: "Java properties", Reason: fmt.Sprintf("array at %q has no element %d", pointer(path), i)}
+			}
+
+			var err error
+			if res[i], err = denseArrays(append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), val[n]); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	return v, nil
+}
+
+func invalidProperties(line int, format string, args ...interface{}) error {
+	return InvalidDocumentError{Format: "Java properties", Reason: fmt.Sprintf("line %d: ", line) + fmt.Sprintf(format, args...)}
+}
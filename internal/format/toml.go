@@ -0,0 +1,214 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// encodeTOML writes a document as TOML v1.0.0. Objects become tables, arrays of objects become arrays of tables and other
+// arrays are written inline. The root has to be an object, and null cannot be represented.
+func encodeTOML(w io.Writer, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return UnsupportedValueError{Format: "TOML", Path: "", Reason: "the root has to be an object"}
+	}
+
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, nil, obj); err != nil {
+		return err
+	}
+
+	// A root without key/value pairs would start with the blank line separating tables
+	_, err := w.Write(bytes.TrimPrefix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+func writeTOMLTable(buf *bytes.Buffer, path []segment, obj map[string]interface{}) error {
+	keys := sortedKeys(obj)
+
+	// Key/value pairs have to come before sub-tables, or they would belong to the last sub-table
+	for _, k := range keys {
+		if isTable(obj[k]) || isArrayOfTables(obj[k]) {
+			continue
+		}
+
+		value, err := tomlValue(append(path[:len(path):len(path)], segment{key: k}), obj[k])
+		if err != nil {
+			return err
+		}
+		buf.WriteString(tomlKey(k) + " = " + value + "\n")
+	}
+
+	for _, k := range keys {
+		child := append(path[:len(path):len(path)], segment{key: k})
+		switch val := obj[k].(type) {
+		case map[string]interface{}:
+			buf.WriteString("\n[" + tomlPath(child) + "]\n")
+			if err := writeTOMLTable(buf, child, val); err != nil {
+				return err
+			}
+		case []interface{}:
+			if !isArrayOfTables(val) {
+				continue
+			}
+			for i, elem := range val {
+				buf.WriteString("\n[[" + tomlPath(child) + "]]\n")
+				if err := writeTOMLTable(buf, append(child, segment{key: strconv.Itoa(i), index: true}), elem.(map[string]interface{})); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// tomlValue formats a value written inline
+func tomlValue(path []segment, v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", UnsupportedValueError{Format: "TOML", Path: pointer(path), Reason: "TOML has no null"}
+	case bool:
+		return strconv.FormatBool(val), nil
+	case float64:
+		return formatNumber(val), nil
+	case string:
+		return quote(val), nil
+	case []interface{}:
+		elems := make([]string, len(val))
+		for i, elem := range val {
+			var err error
+			if elems[i], err = tomlValue(append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), elem); err != nil {
+				return "", err
+			}
+		}
+		return "[" + strings.Join(elems, ", ") + "]", nil
+	case map[string]interface{}:
+		var pairs []string
+		for _, k := range sortedKeys(val) {
+			value, err := tomlValue(append(path[:len(path):len(path)], segment{key: k}), val[k])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, tomlKey(k)+" = "+value)
+		}
+		if len(pairs) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(pairs, ", ") + " }", nil
+	}
+
+	return "", UnsupportedValueError{Format: "TOML", Path: pointer(path), Reason: "unknown type"}
+}
+
+// tomlPath returns the dotted key of a table. Array indices are left out, as a table header refers to the last element of an
+// array of tables.
+func tomlPath(path []segment) string {
+	var keys []string
+	for _, s := range path {
+		if !s.index {
+			keys = append(keys, tomlKey(s.key))
+		}
+	}
+
+	return strings.Join(keys, ".")
+}
+
+func tomlKey(k string) string {
+	if bareKey.MatchString(k) {
+		return k
+	}
+
+	return quote(k)
+}
+
+func isTable(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+// isArrayOfTables checks if v is a non-empty array of objects
+func isArrayOfTables(v interface{}) bool {
+	arr, ok := v.([]interface{})
+	if !ok || len(arr) == 0 {
+		return false
+	}
+
+	for _, elem := range arr {
+		if !isTable(elem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeTOML reads a TOML document. Datetimes have no JSON representation and have to be quoted to be stored as strings.
+func decodeTOML(r io.Reader) (interface{}, error) {
+	var doc map[string]interface{}
+	if _, err := toml.DecodeReader(r, &doc); err != nil {
+		return nil, InvalidDocumentError{Format: "TOML", Reason: err.Error()}
+	}
+
+	return tomlToJSON(nil, doc)
+}
+
+func tomlToJSON(path []segment, v interface{}) (interface{}, error) {
+	unsupported := func(reason string) error {
+		return UnsupportedValueError{Format: "JSON", Path: pointer(path), Reason: reason}
+	}
+
+	switch val := v.(type) {
+	case bool, string:
+		return val, nil
+	case int64:
+		return json.Number(strconv.FormatInt(val, 10)), nil
+	case float64:
+		if math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, unsupported("JSON has no infinite numbers or NaN")
+		}
+		return val, nil
+	case time.Time:
+		return nil, unsupported("JSON has no datetimes, quote the value to store it as a string")
+	case []map[string]interface{}:
+		elems := make([]interface{}, len(val))
+		for i, elem := range val {
+			elems[i] = elem
+		}
+		return tomlToJSON(path, elems)
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, elem := range val {
+			var err error
+			if res[i], err = tomlToJSON(append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), elem); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, elem := range val {
+			var err error
+			if res[k], err = tomlToJSON(append(path[:len(path):len(path)], segment{key: k}), elem); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	return nil, unsupported(fmt.Sprintf("TOML values of type %T have no JSON representation", v))
+}
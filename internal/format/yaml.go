@@ -0,0 +1,141 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+func encodeYAML(w io.Writer, v interface{}) error {
+	b, err := yaml.Marshal(integers(v))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// integers returns a copy of a decoded JSON document where numbers without a fractional part are integers, so they are not
+// written with an exponent
+func integers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			res[k] = integers(child)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, child := range val {
+			res[i] = integers(child)
+		}
+		return res
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int64(val)
+		}
+	}
+
+	return v
+}
+
+// decodeYAML reads a single YAML document. Duplicate keys are not allowed. Keys that are numbers or booleans are used as
+// strings, while other keys, infinite numbers and binary data that is not UTF-8 have no JSON representation.
+func decodeYAML(r io.Reader) (interface{}, error) {
+	dec := yaml.NewDecoder(r)
+	dec.SetStrict(true)
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, yamlError(err)
+	}
+
+	var next interface{}
+	if err := dec.Decode(&next); err != io.EOF {
+		return nil, InvalidDocumentError{Format: "YAML", Reason: "only a single document is allowed"}
+	}
+
+	return yamlToJSON(nil, v)
+}
+
+// yamlError makes an InvalidDocumentError of the error messages of the YAML decoder, which can span several lines
+func yamlError(err error) error {
+	reason := strings.TrimPrefix(err.Error(), "yaml: ")
+	reason = strings.Replace(reason, ":\n  ", ": ", 1)
+	reason = strings.Replace(reason, "\n  ", ", ", -1)
+
+	return InvalidDocumentError{Format: "YAML", Reason: reason}
+}
+
+func yamlToJSON(path []segment, v interface{}) (interface{}, error) {
+	unsupported := func(reason string) error {
+		return UnsupportedValueError{Format: "JSON", Path: pointer(path), Reason: reason}
+	}
+
+	switch val := v.(type) {
+	case nil, bool:
+		return val, nil
+	case string:
+		if !utf8.ValidString(val) {
+			return nil, unsupported("the string is not valid UTF-8")
+		}
+		return val, nil
+	case int:
+		return json.Number(strconv.Itoa(val)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(val, 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(val, 10)), nil
+	case float64:
+		if math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, unsupported("JSON has no infinite numbers or NaN")
+		}
+		return val, nil
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, elem := range val {
+			var err error
+			if res[i], err = yamlToJSON(append(path[:len(path):len(path)], segment{key: strconv.Itoa(i), index: true}), elem); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, elem := range val {
+			var key string
+			switch kv := k.(type) {
+			case string:
+				key = kv
+			case int, int64, uint64, float64, bool:
+				key = fmt.Sprint(kv)
+			case nil:
+				return nil, unsupported("JSON keys have to be strings, but found null")
+			default:
+				return nil, unsupported(fmt.Sprintf("JSON keys have to be strings, but found %v", k))
+			}
+
+			if _, exists := res[key]; exists {
+				return nil, InvalidDocumentError{Format: "YAML", Reason: fmt.Sprintf("key %q at %q is used more than once", key, pointer(path))}
+			}
+
+			var err error
+			if res[key], err = yamlToJSON(append(path[:len(path):len(path)], segment{key: key}), elem); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	return nil, unsupported(fmt.Sprintf("YAML values of type %T have no JSON representation", v))
+}
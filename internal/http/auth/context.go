@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+)
+
+// userKey is the context key of the authenticated user
+type userKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated user
+func NewContext(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// FromContext returns the authenticated user carried by ctx, if any
+func FromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok
+}
@@ -12,6 +12,9 @@ const (
 	ADMIN Role = 1 << iota
 	// CLIENT role has access to read operations only.
 	CLIENT
+	// SECRETS role can read secret values, which are masked for users without it. Combined with the other roles, like
+	// CLIENT|SECRETS. ADMIN can always read secret values.
+	SECRETS
 )
 
 // ErrUserAlreadyExists is used when trying to register a username which already exists in the pool.
@@ -29,3 +32,8 @@ type User struct {
 	PasswordHash string
 	Role         Role
 }
+
+// CanRevealSecrets reports whether the user can read secret values
+func (u User) CanRevealSecrets() bool {
+	return u.Role&(ADMIN|SECRETS) != 0
+}
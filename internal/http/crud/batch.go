@@ -0,0 +1,23 @@
+package crud
+
+import "github.com/larwef/ki/internal/adding"
+
+// batch is a list of operations applied all or nothing
+type batch struct {
+	Operations []operation `json:"operations"`
+}
+
+// operation is an operation of a batch. Op is one of "createGroup", "putConfig" and "deleteConfig". Revision, if set, is
+// the revision the config has to be at, and zero requires it to not exist.
+type operation struct {
+	Op       string        `json:"op"`
+	Group    adding.Group  `json:"group"`
+	Config   adding.Config `json:"config"`
+	Revision *int64        `json:"revision,omitempty"`
+}
+
+var operationTypes = map[string]adding.OperationType{
+	"createGroup":  adding.CreateGroup,
+	"putConfig":    adding.PutConfig,
+	"deleteConfig": adding.DeleteConfig,
+}
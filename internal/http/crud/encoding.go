@@ -0,0 +1,216 @@
+package crud
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/golang/protobuf/proto"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/format"
+	"github.com/larwef/ki/internal/http/grpc"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// protobufFormat is the name of the format writing groups and configs as the Group and Config messages of the gRPC API
+const protobufFormat = "protobuf"
+
+// resourceFormats are the formats groups and configs are read and written as a whole in. Other formats hold only the
+// properties of a config.
+var resourceFormats = []string{format.JSON, format.MessagePack, protobufFormat}
+
+func newProtobufFormat() *format.Format {
+	return &format.Format{
+		Name:       protobufFormat,
+		MediaTypes: []string{"application/x-protobuf"},
+		Binary:     true,
+		Encode:     encodeProtobuf,
+	}
+}
+
+// encodeProtobuf writes a message of the gRPC API. Documents have no message to be written as.
+func encodeProtobuf(w io.Writer, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return format.UnsupportedValueError{Format: "protobuf", Reason: "only groups and configs can be written as protobuf"}
+	}
+
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func isResourceFormat(f *format.Format) bool {
+	for _, name := range resourceFormats {
+		if f.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resourceRegistry returns the formats groups can be negotiated in
+func (handler *Handler) resourceRegistry() *format.Registry {
+	r := format.NewRegistry()
+	for _, name := range resourceFormats {
+		if f, exists := handler.formats.Lookup(name); exists {
+			r.Register(f)
+		}
+	}
+
+	return r
+}
+
+// requestFormat returns the format of the request body given by its content type. A body without content type is JSON.
+func (handler *Handler) requestFormat(req *http.Request) (*format.Format, bool) {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "" {
+		return handler.formats.Lookup(format.JSON)
+	}
+
+	return handler.formats.ForMediaType(mediaType)
+}
+
+// readResource reads a group or config in a resource format. JSON and MessagePack are read as the JSON representation of
+// the resource, while protobuf is read as the Group or Config message of the gRPC API.
+func readResource(r io.Reader, f *format.Format, v interface{}) error {
+	switch f.Name {
+	case format.JSON:
+		return json.NewDecoder(r).Decode(v)
+	case protobufFormat:
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return fromMessage(b, v)
+	}
+
+	doc, err := f.Decode(r)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// writeResource writes a group or config in a resource format, mirroring readResource
+func writeResource(res http.ResponseWriter, f *format.Format, v interface{}) error {
+	var buf bytes.Buffer
+	switch f.Name {
+	case format.JSON:
+		if err := json.NewEncoder(&buf).Encode(v); err != nil {
+			return err
+		}
+	case protobufFormat:
+		if err := f.Encode(&buf, toMessage(v)); err != nil {
+			return err
+		}
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var doc interface{}
+		if err = dec.Decode(&doc); err != nil {
+			return err
+		}
+
+		if err = f.Encode(&buf, doc); err != nil {
+			return err
+		}
+	}
+
+	res.Header().Set("Content-Type", f.ContentType())
+	_, err := res.Write(buf.Bytes())
+	return err
+}
+
+func toMessage(v interface{}) proto.Message {
+	switch val := v.(type) {
+	case *listing.Group:
+		return &grpc.Group{
+			Id:         val.ID,
+			ConfigIds:  val.Configs,
+			Revision:   val.Revision,
+			NextCursor: val.NextCursor,
+			Schema:     val.Schema,
+			Defaults:   toDefaultsMessage(val.Defaults),
+		}
+	case *listing.Config:
+		return &grpc.Config{
+			Id:           val.ID,
+			Name:         val.Name,
+			LastModified: val.LastModified.Unix(),
+			Version:      int32(val.Version),
+			Group:        val.Group,
+			Properties:   val.Properties,
+			Revision:     val.Revision,
+			Parent:       val.Parent,
+		}
+	}
+
+	return nil
+}
+
+func fromMessage(b []byte, v interface{}) error {
+	switch val := v.(type) {
+	case *adding.Group:
+		var msg grpc.Group
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+		*val = adding.Group{ID: msg.Id, Configs: msg.ConfigIds, Schema: msg.Schema, Defaults: fromDefaultsMessage(msg.Defaults)}
+	case *adding.Config:
+		var msg grpc.Config
+		if err := proto.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+		*val = adding.Config{
+			ID:           msg.Id,
+			Name:         msg.Name,
+			LastModified: time.Unix(msg.LastModified, 0),
+			Version:      int(msg.Version),
+			Group:        msg.Group,
+			Parent:       msg.Parent,
+			Properties:   msg.Properties,
+		}
+	}
+
+	return nil
+}
+
+func toDefaultsMessage(d *listing.Defaults) *grpc.Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &grpc.Defaults{Properties: d.Properties, Arrays: string(d.Merge.Arrays), Nulls: string(d.Merge.Nulls)}
+}
+
+func fromDefaultsMessage(d *grpc.Defaults) *adding.Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &adding.Defaults{
+		Properties: d.Properties,
+		Merge:      properties.MergeRules{Arrays: properties.ArrayMerge(d.Arrays), Nulls: properties.NullMerge(d.Nulls)},
+	}
+}
@@ -1,40 +1,121 @@
 package crud
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/diffing"
+	"github.com/larwef/ki/internal/format"
 	"github.com/larwef/ki/internal/http/auth"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/rotating"
+	"github.com/larwef/ki/internal/scheduling"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
+	"io"
+	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	healthPath = "health"
-	configPath = "config"
+	healthPath     = "health"
+	configPath     = "config"
+	changesPath    = "changes"
+	searchPath     = "search"
+	schemaPath     = "schema"
+	defaultsPath   = "defaults"
+	recipientsPath = "recipients"
+	keysPath       = "keys"
+	batchPath      = "batch"
+	approversPath  = "approvers"
+	wellKnownPath  = ".well-known"
+
+	// changeRequestsPath is the path of change requests, optionally followed by the id of a change request and an action
+	changeRequestsPath = "changerequests"
+	// diffPath is the path comparing two configs, and is appended to the path of a config to compare two of its revisions
+	diffPath = "diff"
+
+	// checkPath is appended to the schema path of a group to check the configs in the group against a schema
+	checkPath = "check"
+	// propertiesPath is appended to the path of a config, optionally followed by a JSON Pointer, to address its properties
+	propertiesPath = "properties"
+	// environmentsPath is appended to the path of a config, optionally followed by an environment, to address its overlays
+	environmentsPath = "environments"
+	// promotePath is appended to the path of an overlay to promote the overlay of another environment to it
+	promotePath = "promote"
+	// dependentsPath is appended to the path of a config to list the configs referencing it
+	dependentsPath = "dependents"
+	// schedulePath is appended to the path of a config, optionally followed by the id of an activation, to schedule versions
+	// of the config and cancel them
+	schedulePath = "schedule"
+	// rotationPath is appended to the keys path to start a key rotation and follow its progress
+	rotationPath = "rotation"
+	// verificationPath is appended to the keys path to verify that no secret values are encrypted with retired keys
+	verificationPath = "verification"
+	// approvePath, rejectPath and applyPath are appended to the path of a change request to review or apply it
+	approvePath = "approve"
+	rejectPath  = "reject"
+	applyPath   = "apply"
 
 	contentType = "application/json; charset=utf-8"
+
+	// Content types of the patch formats accepted by PATCH on a config
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+
+	// unifiedContentType is the content type of diffs written as unified diffs
+	unifiedContentType = "text/x-diff; charset=utf-8"
 )
 
 // Handler handles is the entry point for requests and handles routing and processing.
 type Handler struct {
-	aut     auth.Auth
-	adding  adding.Service
-	listing listing.Service
+	aut        auth.Auth
+	adding     adding.Service
+	listing    listing.Service
+	rotating   rotating.Service
+	reviewing  reviewing.Service
+	scheduling scheduling.Service
+	diffing    diffing.Service
+	signer     *signing.Signer
+	formats    *format.Registry
 }
 
-// NewHandler returns a new Handler object.
-func NewHandler(aut auth.Auth, add adding.Service, list listing.Service) *Handler {
+// NewHandler returns a new Handler object. Configs are signed with sig, unless it is nil.
+func NewHandler(aut auth.Auth, add adding.Service, list listing.Service, rot rotating.Service, rev reviewing.Service, sch scheduling.Service, dif diffing.Service, sig *signing.Signer) *Handler {
 	return &Handler{
-		aut:     aut,
-		adding:  add,
-		listing: list,
+		aut:        aut,
+		adding:     add,
+		listing:    list,
+		rotating:   rot,
+		reviewing:  rev,
+		scheduling: sch,
+		diffing:    dif,
+		signer:     sig,
+		formats:    newRegistry(),
 	}
 }
 
+// newRegistry returns the default formats with protobuf added
+func newRegistry() *format.Registry {
+	r := format.NewDefaultRegistry()
+	r.Register(newProtobufFormat())
+	return r
+}
+
+// Formats returns the registry of formats configs can be retrieved in. Formats registered are negotiated by the Accept header
+// or chosen by name with the format query parameter.
+func (handler *Handler) Formats() *format.Registry {
+	return handler.formats
+}
+
 func (handler *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	newHandlerChain(emptyHandler()).
 		add(inOutLog).
@@ -58,22 +139,78 @@ func (handler *Handler) route(h http.Handler) http.Handler {
 			newHandlerChain(emptyHandler()).
 				add(handler.handleConfig).
 				ServeHTTP(res, req)
+
+		case searchPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleSearch).
+				ServeHTTP(res, req)
+
+		case schemaPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleSchema).
+				ServeHTTP(res, req)
+
+		case defaultsPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleDefaults).
+				ServeHTTP(res, req)
+
+		case recipientsPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleRecipients).
+				ServeHTTP(res, req)
+
+		case changesPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleChanges).
+				ServeHTTP(res, req)
+
+		case keysPath:
+			newHandlerChain(emptyHandler()).
+				add(requireAdmin).
+				add(handler.handleKeys).
+				ServeHTTP(res, req)
+
+		case batchPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleBatch).
+				ServeHTTP(res, req)
+
+		case approversPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleApprovers).
+				ServeHTTP(res, req)
+
+		case changeRequestsPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleChangeRequests).
+				ServeHTTP(res, req)
+
+		case diffPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleDiff).
+				ServeHTTP(res, req)
+
+		case wellKnownPath:
+			newHandlerChain(emptyHandler()).
+				add(handler.handleWellKnown).
+				ServeHTTP(res, req)
 		default:
 			log.Printf("Invalid path %q called\n", req.URL.Path)
-			http.Error(res, "Not Found", http.StatusNotFound)
+			writeProblem(res, http.StatusNotFound, "")
 		}
 	})
 }
 
 func (handler *Handler) authenticate(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		_, err := handler.aut.Authenticate(req.Header.Get("Authorization"))
+		user, err := handler.aut.Authenticate(req.Header.Get("Authorization"))
 		if err != nil {
-			http.Error(res, "Unauthorized", http.StatusUnauthorized)
+			writeProblem(res, http.StatusUnauthorized, "")
 			return
 		}
 
-		h.ServeHTTP(res, req)
+		h.ServeHTTP(res, req.WithContext(auth.NewContext(req.Context(), user)))
 	})
 }
 
@@ -82,7 +219,7 @@ func (handler *Handler) handleHealth(h http.Handler) http.Handler {
 		healthCheck := &health{Status: "OK"}
 		if err := json.NewEncoder(res).Encode(healthCheck); err != nil {
 			log.Printf("Health check failed. Error: %v", err)
-			http.Error(res, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(res, http.StatusInternalServerError, "")
 			return
 		}
 
@@ -90,102 +227,240 @@ func (handler *Handler) handleHealth(h http.Handler) http.Handler {
 	})
 }
 
-func (handler *Handler) handleConfig(h http.Handler) http.Handler {
+func (handler *Handler) handleSearch(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		chain := newHandlerChain(h)
-		_, grpID, confID, remainder := getPathVariables(req.URL.Path)
+		if req.Method != http.MethodGet {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
 
-		if remainder != "/" {
-			log.Printf("Invalid path %q called", req.URL.Path)
-			http.Error(res, "Invalid Path", http.StatusBadRequest)
-		} else if grpID != "" && confID == "" && remainder == "/" {
-			chain.add(handler.handleGroupAction)
-		} else if grpID != "" && confID != "" && remainder == "/" {
-			chain.add(handler.handleConfigAction)
-		} else {
-			log.Printf("Unexpected state when processing path %q", req.URL.Path)
-			http.Error(res, "Internal Server Error", http.StatusInternalServerError)
+		page, ok := getPage(req)
+		if !ok {
+			writeProblem(res, http.StatusBadRequest, "Invalid limit parameter")
+			return
 		}
 
-		chain.ServeHTTP(res, req)
+		if text := req.URL.Query().Get("text"); text != "" {
+			handler.searchText(h, res, req, listing.TextQuery{
+				Text:  text,
+				Group: req.URL.Query().Get("group"),
+				Limit: page.Limit,
+			})
+			return
+		}
+
+		query := listing.Query{
+			Group: req.URL.Query().Get("group"),
+			Page:  page,
+		}
+
+		for _, expr := range req.URL.Query()["q"] {
+			cond, err := listing.ParseCondition(expr)
+			if err != nil {
+				writeServiceError(res, err)
+				return
+			}
+			query.Conditions = append(query.Conditions, cond)
+		}
+
+		confs, err := handler.listing.SearchConfigs(query)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		for i := range confs.Configs {
+			if confs.Configs[i].Properties, err = handler.revealSecrets(res, req, confs.Configs[i].Properties); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+		}
+
+		if err = json.NewEncoder(res).Encode(confs); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
 	})
 }
 
-func (handler *Handler) handleGroupAction(h http.Handler) http.Handler {
+func (handler *Handler) searchText(h http.Handler, res http.ResponseWriter, req *http.Request, query listing.TextQuery) {
+	if len(req.URL.Query()["q"]) > 0 {
+		writeProblem(res, http.StatusBadRequest, "Use either text or q parameters")
+		return
+	}
+
+	matches, err := handler.listing.SearchText(query)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	if err = json.NewEncoder(res).Encode(matches); err != nil {
+		writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+		return
+	}
+
+	h.ServeHTTP(res, req)
+}
+
+func (handler *Handler) handleChanges(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		switch req.Method {
-		case http.MethodPut:
-			newHandlerChain(h).
-				add(handler.storeGroup).
-				add(handler.retrieveGroup).
-				ServeHTTP(res, req)
-			break
-		case http.MethodGet:
-			newHandlerChain(h).
-				add(handler.retrieveGroup).
-				ServeHTTP(res, req)
-			break
+		if req.Method != http.MethodGet {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
+
+		var since int64
+		if s := req.URL.Query().Get("since"); s != "" {
+			var err error
+			if since, err = strconv.ParseInt(s, 10, 64); err != nil || since < 0 {
+				writeProblem(res, http.StatusBadRequest, "Invalid since parameter")
+				return
+			}
+		}
+
+		changes, err := handler.listing.ListChanges(since)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(changes); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// handleKeys handles the key-encryption keys secret values are encrypted with. POST on the rotation path starts rewrapping every
+// secret value with the primary key, GET follows its progress, and GET on the verification path lists the resources with
+// secret values still encrypted with retired keys.
+func (handler *Handler) handleKeys(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, action, _, remainder := getPathVariables(req.URL.Path)
+
+		if remainder != "/" || (action != rotationPath && action != verificationPath) {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		if handler.rotating == nil {
+			writeServiceError(res, secret.ErrNoKey)
+			return
+		}
+
+		switch {
+		case action == rotationPath && req.Method == http.MethodPost:
+			if err := handler.rotating.Rotate(); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+
+			res.WriteHeader(http.StatusAccepted)
+			if err := json.NewEncoder(res).Encode(handler.rotating.Progress()); err != nil {
+				log.Printf("Error writing response: %v", err)
+				return
+			}
+		case action == rotationPath && req.Method == http.MethodGet:
+			if err := json.NewEncoder(res).Encode(handler.rotating.Progress()); err != nil {
+				writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+				return
+			}
+		case action == verificationPath && req.Method == http.MethodGet:
+			v, err := handler.rotating.Verify()
+			if err != nil {
+				writeServiceError(res, err)
+				return
+			}
+
+			if err = json.NewEncoder(res).Encode(v); err != nil {
+				writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+				return
+			}
 		default:
-			http.Error(res, "Method Not Allowed", http.StatusMethodNotAllowed)
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
 		}
+
+		h.ServeHTTP(res, req)
 	})
 }
 
-func (handler *Handler) handleConfigAction(h http.Handler) http.Handler {
+func (handler *Handler) handleSchema(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		switch req.Method {
-		case http.MethodPut:
+		_, grpID, action, remainder := getPathVariables(req.URL.Path)
+
+		if grpID == "" || remainder != "/" || (action != "" && action != checkPath) {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		switch {
+		case action == checkPath && req.Method == http.MethodPost:
 			newHandlerChain(h).
-				add(handler.storeConfig).
-				add(handler.retrieveConfig).
+				add(handler.checkSchema).
 				ServeHTTP(res, req)
-			break
-		case http.MethodGet:
+		case action == "" && req.Method == http.MethodPut:
 			newHandlerChain(h).
-				add(handler.retrieveConfig).
+				add(handler.storeSchema).
+				add(handler.retrieveSchema).
+				ServeHTTP(res, req)
+		case action == "" && req.Method == http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveSchema).
 				ServeHTTP(res, req)
-			break
 		default:
-			http.Error(res, "Method Not Allowed", http.StatusMethodNotAllowed)
+			writeProblem(res, http.StatusMethodNotAllowed, "")
 		}
 	})
 }
 
-func (handler *Handler) storeGroup(h http.Handler) http.Handler {
+func (handler *Handler) storeSchema(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		var grp adding.Group
+		var sch json.RawMessage
 
-		if err := json.NewDecoder(req.Body).Decode(&grp); err != nil {
-			http.Error(res, "Unable to unmarshal request object", http.StatusBadRequest)
+		if err := json.NewDecoder(req.Body).Decode(&sch); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
 			return
 		}
 
 		defer req.Body.Close()
 
-		_, grp.ID, _, _ = getPathVariables(req.URL.Path)
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
 
-		if err := handler.adding.AddGroup(grp); err != nil {
+		if err := handler.adding.SetSchema(grpID, sch); err != nil {
 			writeServiceError(res, err)
 			return
 		}
 
+		// A removed schema has nothing more to return
+		if string(sch) == "null" {
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		h.ServeHTTP(res, req)
 	})
 }
 
-func (handler *Handler) retrieveGroup(h http.Handler) http.Handler {
+func (handler *Handler) retrieveSchema(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		_, grpID, _, _ := getPathVariables(req.URL.Path)
 
-		var conf *listing.Group
-		var err error
-		if conf, err = handler.listing.GetGroup(grpID); err != nil {
+		sch, err := handler.listing.GetSchema(grpID)
+		if err != nil {
 			writeServiceError(res, err)
 			return
 		}
 
-		if err = json.NewEncoder(res).Encode(conf); err != nil {
-			http.Error(res, "Error marshalling response", http.StatusInternalServerError)
+		if _, err = res.Write(sch); err != nil {
+			log.Printf("Error writing response: %v", err)
 			return
 		}
 
@@ -193,42 +468,77 @@ func (handler *Handler) retrieveGroup(h http.Handler) http.Handler {
 	})
 }
 
-func (handler *Handler) storeConfig(h http.Handler) http.Handler {
+func (handler *Handler) handleDefaults(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		var conf adding.Config
+		_, grpID, action, remainder := getPathVariables(req.URL.Path)
+
+		if grpID == "" || action != "" || remainder != "/" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
 
-		if err := json.NewDecoder(req.Body).Decode(&conf); err != nil {
-			http.Error(res, "Unable to unmarshal request object", http.StatusInternalServerError)
+		switch req.Method {
+		case http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeDefaults).
+				add(handler.retrieveDefaults).
+				ServeHTTP(res, req)
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveDefaults).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) storeDefaults(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var d *adding.Defaults
+
+		if err := json.NewDecoder(req.Body).Decode(&d); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
 			return
 		}
 
 		defer req.Body.Close()
 
-		_, conf.Group, conf.ID, _ = getPathVariables(req.URL.Path)
-		conf.LastModified = time.Now()
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
 
-		if err := handler.adding.AddConfig(conf); err != nil {
+		if err := handler.adding.SetDefaults(grpID, d); err != nil {
 			writeServiceError(res, err)
 			return
 		}
 
+		// Removed defaults have nothing more to return
+		if d == nil {
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		h.ServeHTTP(res, req)
 	})
 }
 
-func (handler *Handler) retrieveConfig(h http.Handler) http.Handler {
+func (handler *Handler) retrieveDefaults(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		_, grp, id, _ := getPathVariables(req.URL.Path)
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
 
-		var conf *listing.Config
-		var err error
-		if conf, err = handler.listing.GetConfig(grp, id); err != nil {
+		d, err := handler.listing.GetDefaults(grpID)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if d.Properties, err = handler.revealSecrets(res, req, d.Properties); err != nil {
 			writeServiceError(res, err)
 			return
 		}
 
-		if err = json.NewEncoder(res).Encode(conf); err != nil {
-			http.Error(res, "Error marshalling response", http.StatusInternalServerError)
+		if err = json.NewEncoder(res).Encode(d); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
 			return
 		}
 
@@ -236,34 +546,1437 @@ func (handler *Handler) retrieveConfig(h http.Handler) http.Handler {
 	})
 }
 
-func writeServiceError(res http.ResponseWriter, err error) {
-	log.Printf("Error: %v", err)
-	switch err {
-	case listing.ErrGroupNotFound:
-		fallthrough
-	case listing.ErrConfigNotFound:
-		http.Error(res, err.Error(), http.StatusNotFound)
-	case adding.ErrGroupConflict:
-		http.Error(res, err.Error(), http.StatusConflict)
+// handleDiff compares the configs given by the a and b query parameters, like "staging/api". Their effective properties are
+// compared, unless raw is set.
+func (handler *Handler) handleDiff(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		if grpID != "" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
+
+		var refs [2]listing.ConfigRef
+		for i, param := range []string{"a", "b"} {
+			ref, ok := getConfigRef(req.URL.Query().Get(param))
+			if !ok {
+				writeProblem(res, http.StatusBadRequest, "Invalid "+param+" parameter, expected {group}/{id}")
+				return
+			}
+			refs[i] = ref
+		}
+
+		raw, _ := strconv.ParseBool(req.URL.Query().Get("raw"))
+		d, err := handler.diffing.DiffConfigs(refs[0], refs[1], raw)
+		writeDiff(h, res, req, d, err)
+	})
+}
+
+// writeDiff writes a diff as JSON, or as a unified diff if the format query parameter is "unified", or err if it is set
+func writeDiff(h http.Handler, res http.ResponseWriter, req *http.Request, d *diffing.Diff, err error) {
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	switch req.URL.Query().Get("format") {
+	case "", "json":
+		if err := json.NewEncoder(res).Encode(d); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+	case "unified":
+		text, err := d.Unified()
+		if err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error writing unified diff")
+			return
+		}
+
+		res.Header().Set("Content-Type", unifiedContentType)
+		if _, err := io.WriteString(res, text); err != nil {
+			log.Printf("Error writing response: %v", err)
+			return
+		}
 	default:
-		http.Error(res, "Internal Server Error", http.StatusInternalServerError)
+		writeProblem(res, http.StatusBadRequest, "Invalid format parameter, expected json or unified")
+		return
 	}
+
+	h.ServeHTTP(res, req)
 }
 
-func setCommonHeaders(h http.Handler) http.Handler {
+// handleWellKnown publishes the public key configs are signed with
+func (handler *Handler) handleWellKnown(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		res.Header().Set("Content-Type", contentType)
+		if req.URL.Path != signing.WellKnownPath || handler.signer == nil {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusNotFound, "")
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
+
+		if err := json.NewEncoder(res).Encode(handler.signer.PublicKey()); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
 		h.ServeHTTP(res, req)
 	})
 }
 
-func getPathVariables(url string) (string, string, string, string) {
-	var serivce, grp, id string
-	serivce, url = shiftPath(url)
-	grp, url = shiftPath(url)
-	id, url = shiftPath(url)
+func (handler *Handler) handleRecipients(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, action, remainder := getPathVariables(req.URL.Path)
 
-	return serivce, grp, id, url
+		if grpID == "" || action != "" || remainder != "/" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		switch req.Method {
+		case http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeRecipients).
+				add(handler.retrieveRecipients).
+				ServeHTTP(res, req)
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveRecipients).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) storeRecipients(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var recipients []string
+
+		if err := json.NewDecoder(req.Body).Decode(&recipients); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		defer req.Body.Close()
+
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		if err := handler.adding.SetRecipients(grpID, recipients); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		// Removed recipients have nothing more to return
+		if len(recipients) == 0 {
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) retrieveRecipients(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		recipients, err := handler.listing.GetRecipients(grpID)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(recipients); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// checkSchema checks the configs of a group against the schema in the request body, or the current schema of the group if
+// the body is empty
+func (handler *Handler) checkSchema(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var sch json.RawMessage
+
+		if err := json.NewDecoder(req.Body).Decode(&sch); err != nil && err != io.EOF {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		defer req.Body.Close()
+
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		check, err := handler.listing.CheckSchema(grpID, sch)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(check); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// handleBatch applies the operations of a batch, all or nothing
+func (handler *Handler) handleBatch(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		if grpID != "" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		if req.Method != http.MethodPost {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
+
+		defer req.Body.Close()
+
+		var b batch
+		if err := json.NewDecoder(req.Body).Decode(&b); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		ops := make([]adding.Operation, len(b.Operations))
+		for i, op := range b.Operations {
+			t, ok := operationTypes[op.Op]
+			if !ok {
+				writeProblem(res, http.StatusBadRequest, "Unknown op "+strconv.Quote(op.Op)+" of operation "+strconv.Itoa(i))
+				return
+			}
+
+			ops[i] = adding.Operation{Type: t, Group: op.Group, Config: op.Config, Revision: op.Revision}
+		}
+
+		if _, err := handler.adding.Commit(ops); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (handler *Handler) handleApprovers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, action, remainder := getPathVariables(req.URL.Path)
+
+		if grpID == "" || action != "" || remainder != "/" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		switch req.Method {
+		case http.MethodPut:
+			// Approvers decide who reviews changes, so only admins can set them
+			newHandlerChain(h).
+				add(requireAdmin).
+				add(handler.storeApprovers).
+				add(handler.retrieveApprovers).
+				ServeHTTP(res, req)
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveApprovers).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) storeApprovers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var approvers []string
+
+		if err := json.NewDecoder(req.Body).Decode(&approvers); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		defer req.Body.Close()
+
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		if err := handler.reviewing.SetApprovers(grpID, approvers); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) retrieveApprovers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		approvers, err := handler.reviewing.GetApprovers(grpID)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(approvers); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) handleChangeRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, crID, action, remainder := getPathVariables(req.URL.Path)
+
+		if remainder != "/" || (action != "" && action != approvePath && action != rejectPath && action != applyPath) {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+			return
+		}
+
+		switch {
+		case crID == "" && req.Method == http.MethodGet:
+			handler.listChangeRequests(res, req)
+		case crID == "" && req.Method == http.MethodPost:
+			handler.proposeChange(res, req)
+		case crID != "" && action == "" && req.Method == http.MethodGet:
+			handler.retrieveChangeRequest(res, req)
+		case crID != "" && action != "" && req.Method == http.MethodPost:
+			handler.reviewChangeRequest(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) listChangeRequests(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	crs, err := handler.reviewing.ListChangeRequests(reviewing.Query{Group: q.Get("group"), State: reviewing.State(q.Get("state"))})
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	for i := range crs {
+		if crs[i].Config.Properties, err = handler.revealSecrets(res, req, crs[i].Config.Properties); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+	}
+
+	if err := json.NewEncoder(res).Encode(crs); err != nil {
+		writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+	}
+}
+
+func (handler *Handler) proposeChange(res http.ResponseWriter, req *http.Request) {
+	var p reviewing.Proposal
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+		return
+	}
+
+	defer req.Body.Close()
+
+	user, _ := auth.FromContext(req.Context())
+	cr, err := handler.reviewing.Propose(user.Username, p)
+	handler.writeChangeRequest(res, req, http.StatusCreated, cr, err)
+}
+
+func (handler *Handler) retrieveChangeRequest(res http.ResponseWriter, req *http.Request) {
+	id, ok := getChangeRequestID(res, req)
+	if !ok {
+		return
+	}
+
+	cr, err := handler.reviewing.GetChangeRequest(id)
+	handler.writeChangeRequest(res, req, http.StatusOK, cr, err)
+}
+
+// reviewChangeRequest approves, rejects or applies a change request as the authenticated user. The body is optional, and can
+// hold a comment recorded with the approval or rejection.
+func (handler *Handler) reviewChangeRequest(res http.ResponseWriter, req *http.Request) {
+	id, ok := getChangeRequestID(res, req)
+	if !ok {
+		return
+	}
+
+	defer req.Body.Close()
+
+	var r review
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil && err != io.EOF {
+		writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+		return
+	}
+
+	user, _ := auth.FromContext(req.Context())
+	_, _, action, _ := getPathVariables(req.URL.Path)
+
+	var cr *reviewing.ChangeRequest
+	var err error
+	switch action {
+	case approvePath:
+		cr, err = handler.reviewing.Approve(user.Username, id, r.Comment)
+	case rejectPath:
+		cr, err = handler.reviewing.Reject(user.Username, id, r.Comment)
+	case applyPath:
+		cr, err = handler.reviewing.Apply(user.Username, id)
+	}
+
+	handler.writeChangeRequest(res, req, http.StatusOK, cr, err)
+}
+
+// writeChangeRequest writes a change request with status, or err if it is set
+func (handler *Handler) writeChangeRequest(res http.ResponseWriter, req *http.Request, status int, cr *reviewing.ChangeRequest, err error) {
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	if cr.Config.Properties, err = handler.revealSecrets(res, req, cr.Config.Properties); err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	res.WriteHeader(status)
+	if err := json.NewEncoder(res).Encode(cr); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// getChangeRequestID gets the id of the change request addressed by a request, or writes a problem if it is not valid
+func getChangeRequestID(res http.ResponseWriter, req *http.Request) (int64, bool) {
+	_, crID, _, _ := getPathVariables(req.URL.Path)
+
+	id, err := strconv.ParseInt(crID, 10, 64)
+	if err != nil {
+		writeProblem(res, http.StatusBadRequest, "Invalid change request id")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (handler *Handler) handleConfig(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		chain := newHandlerChain(h)
+		_, grpID, confID, remainder := getPathVariables(req.URL.Path)
+
+		if _, ok := getPropertyPointer(remainder); ok && confID != "" {
+			chain.add(handler.handlePropertyAction)
+		} else if _, _, ok := getOverlayPath(remainder); ok && confID != "" {
+			chain.add(handler.handleOverlayAction)
+		} else if isDependentsPath(remainder) && confID != "" {
+			chain.add(handler.handleDependentsAction)
+		} else if _, ok := getSchedulePath(remainder); ok && confID != "" {
+			chain.add(handler.handleScheduleAction)
+		} else if isDiffPath(remainder) && confID != "" {
+			chain.add(handler.handleRevisionDiffAction)
+		} else if remainder != "/" {
+			log.Printf("Invalid path %q called", req.URL.Path)
+			writeProblem(res, http.StatusBadRequest, "Invalid Path")
+		} else if grpID == "" {
+			chain.add(handler.handleGroupsAction)
+		} else if grpID != "" && confID == "" && remainder == "/" {
+			chain.add(handler.handleGroupAction)
+		} else if grpID != "" && confID != "" && remainder == "/" {
+			chain.add(handler.handleConfigAction)
+		} else {
+			log.Printf("Unexpected state when processing path %q", req.URL.Path)
+			writeProblem(res, http.StatusInternalServerError, "")
+		}
+
+		chain.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) handleGroupsAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.listGroups).
+				ServeHTTP(res, req)
+			break
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) handleGroupAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeGroup).
+				add(handler.retrieveGroup).
+				ServeHTTP(res, req)
+			break
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveGroup).
+				ServeHTTP(res, req)
+			break
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) handleConfigAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeConfig).
+				add(handler.retrieveConfig).
+				ServeHTTP(res, req)
+			break
+		case http.MethodPatch:
+			newHandlerChain(h).
+				add(handler.patchConfig).
+				add(handler.retrieveConfig).
+				ServeHTTP(res, req)
+			break
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveConfig).
+				ServeHTTP(res, req)
+			break
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) handlePropertyAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeProperty).
+				add(handler.retrieveProperty).
+				ServeHTTP(res, req)
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveProperty).
+				ServeHTTP(res, req)
+		case http.MethodDelete:
+			newHandlerChain(h).
+				add(handler.removeProperty).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) handleDependentsAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			newHandlerChain(h).
+				add(handler.listDependents).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) handleScheduleAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _, _, remainder := getPathVariables(req.URL.Path)
+		activation, _ := getSchedulePath(remainder)
+
+		switch {
+		case activation == "" && req.Method == http.MethodGet:
+			handler.retrieveSchedule(res, req)
+		case activation == "" && req.Method == http.MethodPost:
+			handler.scheduleConfig(res, req)
+		case activation != "" && req.Method == http.MethodGet:
+			handler.retrieveActivation(res, req)
+		case activation != "" && req.Method == http.MethodDelete:
+			handler.cancelActivation(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+// handleRevisionDiffAction compares the versions of a config given by the from and to query parameters, or its revisions if
+// the by query parameter is "revision". Without to, from is compared to the current config.
+func (handler *Handler) handleRevisionDiffAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+			return
+		}
+
+		query := req.URL.Query()
+		if query.Get("from") == "" {
+			writeProblem(res, http.StatusBadRequest, "Missing from parameter")
+			return
+		}
+
+		_, grp, id, _ := getPathVariables(req.URL.Path)
+
+		switch query.Get("by") {
+		case "", "version":
+			var versions [2]*int
+			for i, param := range []string{"from", "to"} {
+				if v := query.Get(param); v != "" {
+					version, err := strconv.Atoi(v)
+					if err != nil {
+						writeProblem(res, http.StatusBadRequest, "Invalid "+param+" parameter")
+						return
+					}
+					versions[i] = &version
+				}
+			}
+
+			d, err := handler.diffing.DiffVersions(grp, id, *versions[0], versions[1])
+			writeDiff(h, res, req, d, err)
+		case "revision":
+			var revisions [2]int64
+			for i, param := range []string{"from", "to"} {
+				if v := query.Get(param); v != "" {
+					r, err := strconv.ParseInt(v, 10, 64)
+					if err != nil || r <= 0 {
+						writeProblem(res, http.StatusBadRequest, "Invalid "+param+" parameter")
+						return
+					}
+					revisions[i] = r
+				}
+			}
+
+			d, err := handler.diffing.DiffRevisions(grp, id, revisions[0], revisions[1])
+			writeDiff(h, res, req, d, err)
+		default:
+			writeProblem(res, http.StatusBadRequest, "Invalid by parameter, expected version or revision")
+		}
+	})
+}
+
+func (handler *Handler) handleOverlayAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _, _, remainder := getPathVariables(req.URL.Path)
+		env, action, _ := getOverlayPath(remainder)
+
+		switch {
+		case env == "" && req.Method == http.MethodGet:
+			newHandlerChain(h).
+				add(handler.listOverlays).
+				ServeHTTP(res, req)
+		case env != "" && action == "" && req.Method == http.MethodPut:
+			newHandlerChain(h).
+				add(handler.storeOverlay).
+				add(handler.retrieveOverlay).
+				ServeHTTP(res, req)
+		case env != "" && action == "" && req.Method == http.MethodGet:
+			newHandlerChain(h).
+				add(handler.retrieveOverlay).
+				ServeHTTP(res, req)
+		case env != "" && action == promotePath && req.Method == http.MethodPost:
+			newHandlerChain(h).
+				add(handler.promoteOverlay).
+				add(handler.retrieveOverlay).
+				ServeHTTP(res, req)
+		default:
+			writeProblem(res, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+func (handler *Handler) storeGroup(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		f, ok := handler.requestFormat(req)
+		if !ok || !isResourceFormat(f) {
+			writeProblem(res, http.StatusUnsupportedMediaType, "Groups cannot be read from content type "+strconv.Quote(req.Header.Get("Content-Type")))
+			return
+		}
+
+		defer req.Body.Close()
+
+		var grp adding.Group
+		if err := readResource(req.Body, f, &grp); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		_, grp.ID, _, _ = getPathVariables(req.URL.Path)
+
+		if err := handler.adding.AddGroup(grp); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) listGroups(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		page, ok := getPage(req)
+		if !ok {
+			writeProblem(res, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+
+		query := listing.GroupQuery{
+			Prefix: req.URL.Query().Get("prefix"),
+			Sort:   req.URL.Query().Get("sort"),
+			Page:   page,
+		}
+
+		var grps *listing.GroupPage
+		var err error
+		if grps, err = handler.listing.ListGroups(query); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(grps); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) retrieveGroup(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grpID, _, _ := getPathVariables(req.URL.Path)
+
+		page, ok := getPage(req)
+		if !ok {
+			writeProblem(res, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+
+		var conf *listing.Group
+		var err error
+		if conf, err = handler.listing.GetPagedGroup(grpID, page); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if conf.Defaults != nil {
+			d := *conf.Defaults
+			if d.Properties, err = handler.revealSecrets(res, req, d.Properties); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+			conf.Defaults = &d
+		}
+
+		res.Header().Set("Vary", "Accept")
+		f, status, detail := negotiateFormat(req, handler.resourceRegistry())
+		if f == nil {
+			writeProblem(res, status, detail)
+			return
+		}
+
+		if err = writeResource(res, f, conf); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) storeConfig(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		f, ok := handler.requestFormat(req)
+		if !ok || (f.Decode == nil && !isResourceFormat(f)) {
+			writeProblem(res, http.StatusUnsupportedMediaType, "Configs cannot be read from content type "+strconv.Quote(req.Header.Get("Content-Type")))
+			return
+		}
+
+		defer req.Body.Close()
+
+		// Resource formats hold the full config, while other formats hold only the properties
+		var conf adding.Config
+		if isResourceFormat(f) {
+			if err := readResource(req.Body, f, &conf); err != nil {
+				writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+				return
+			}
+		} else {
+			version, err := strconv.Atoi(req.URL.Query().Get("version"))
+			if err != nil && req.URL.Query().Get("version") != "" {
+				writeProblem(res, http.StatusBadRequest, "Invalid version parameter")
+				return
+			}
+
+			props, err := f.Decode(req.Body)
+			if err != nil {
+				writeServiceError(res, err)
+				return
+			}
+
+			conf = adding.Config{Name: req.URL.Query().Get("name"), Version: version, Parent: req.URL.Query().Get("parent")}
+			if props != nil {
+				if conf.Properties, err = json.Marshal(props); err != nil {
+					writeProblem(res, http.StatusInternalServerError, "Error marshalling properties")
+					return
+				}
+			}
+		}
+
+		_, conf.Group, conf.ID, _ = getPathVariables(req.URL.Path)
+		conf.LastModified = time.Now()
+
+		if err := handler.adding.AddConfig(conf); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// patchConfig applies a JSON Merge Patch or a JSON Patch, as given by the content type, to a config
+func (handler *Handler) patchConfig(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var patch adding.Patch
+
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch mediaType {
+		case mergePatchContentType:
+			patch.Type = adding.MergePatch
+		case jsonPatchContentType:
+			patch.Type = adding.JSONPatch
+		default:
+			res.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+			writeProblem(res, http.StatusUnsupportedMediaType, "Use either "+mergePatchContentType+" or "+jsonPatchContentType)
+			return
+		}
+
+		defer req.Body.Close()
+
+		var err error
+		if patch.Document, err = ioutil.ReadAll(req.Body); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to read request body")
+			return
+		}
+
+		_, grp, id, _ := getPathVariables(req.URL.Path)
+
+		if err := handler.adding.PatchConfig(grp, id, patch); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// storeProperty sets a single property of a config to the JSON value in the request body
+func (handler *Handler) storeProperty(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		value, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to read request body")
+			return
+		}
+
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		p, _ := getPropertyPointer(remainder)
+
+		if err := handler.adding.SetProperty(grp, id, p, value); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) retrieveProperty(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		p, _ := getPropertyPointer(remainder)
+
+		value, err := handler.listing.GetProperty(grp, id, p)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if resolveReferences(req) {
+			ref := listing.ConfigRef{Group: grp, ID: id}
+			if value, err = handler.listing.ResolveReferences(ref, p, value); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+		}
+
+		if value, err = handler.revealSecrets(res, req, value); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if value, err = handler.resolveSecrets(res, req, p, value); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if _, err = res.Write(value); err != nil {
+			log.Printf("Error writing response: %v", err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) removeProperty(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		p, _ := getPropertyPointer(remainder)
+
+		if err := handler.adding.RemoveProperty(grp, id, p); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		// A removed property has nothing more to return
+		res.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (handler *Handler) retrieveConfig(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, _ := getPathVariables(req.URL.Path)
+
+		// Configs are retrieved with their effective properties unless the raw config is asked for, and with the overlay of
+		// an environment merged onto them if one is given
+		get := handler.listing.GetConfig
+		raw, _ := strconv.ParseBool(req.URL.Query().Get("raw"))
+		env := req.URL.Query().Get("env")
+		switch {
+		case raw && env != "":
+			writeProblem(res, http.StatusBadRequest, "Raw configs cannot be retrieved in an environment")
+			return
+		case raw:
+			get = handler.listing.GetRawConfig
+		case env != "":
+			get = func(grp string, id string) (*listing.Config, error) {
+				return handler.listing.GetEnvironmentConfig(grp, id, env)
+			}
+		}
+
+		var conf *listing.Config
+		var err error
+		if conf, err = get(grp, id); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if !raw && resolveReferences(req) {
+			resolved := *conf
+			ref := listing.ConfigRef{Group: grp, ID: id}
+			if resolved.Properties, err = handler.listing.ResolveReferences(ref, nil, conf.Properties); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+			conf = &resolved
+		}
+
+		revealed := *conf
+		if revealed.Properties, err = handler.revealSecrets(res, req, conf.Properties); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+		if !raw {
+			if revealed.Properties, err = handler.resolveSecrets(res, req, nil, revealed.Properties); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+		}
+		conf = &revealed
+
+		if err = handler.sign(res, conf); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error signing config")
+			return
+		}
+
+		res.Header().Set("Vary", "Accept")
+		f, status, detail := negotiateFormat(req, handler.formats)
+		if f == nil {
+			writeProblem(res, status, detail)
+			return
+		}
+
+		// Resource formats are the full config, while other formats render only the properties
+		if isResourceFormat(f) {
+			if err = writeResource(res, f, conf); err != nil {
+				writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+				return
+			}
+
+			h.ServeHTTP(res, req)
+			return
+		}
+
+		var props interface{}
+		if len(conf.Properties) > 0 {
+			if err = json.Unmarshal(conf.Properties, &props); err != nil {
+				writeProblem(res, http.StatusInternalServerError, "Error reading properties")
+				return
+			}
+		}
+
+		var buf bytes.Buffer
+		if err = f.Encode(&buf, props); err != nil {
+			if _, ok := err.(format.UnsupportedValueError); ok {
+				writeProblem(res, http.StatusNotAcceptable, err.Error())
+				return
+			}
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		res.Header().Set("Content-Type", f.ContentType())
+		if _, err = res.Write(buf.Bytes()); err != nil {
+			log.Printf("Error writing response: %v", err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// scheduleConfig schedules the config in the body to be added to the config addressed at activatesAt
+func (handler *Handler) scheduleConfig(res http.ResponseWriter, req *http.Request) {
+	var r scheduling.Request
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+		writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+		return
+	}
+
+	defer req.Body.Close()
+
+	_, grp, id, _ := getPathVariables(req.URL.Path)
+	r.Config.Group = grp
+	r.Config.ID = id
+
+	a, err := handler.scheduling.Schedule(r)
+	handler.writeActivation(res, req, http.StatusCreated, a, err)
+}
+
+func (handler *Handler) retrieveSchedule(res http.ResponseWriter, req *http.Request) {
+	_, grp, id, _ := getPathVariables(req.URL.Path)
+
+	schedule, err := handler.scheduling.GetSchedule(grp, id)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	for i := range schedule.Pending {
+		if schedule.Pending[i].Config.Properties, err = handler.revealSecrets(res, req, schedule.Pending[i].Config.Properties); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+	}
+
+	if err := json.NewEncoder(res).Encode(schedule); err != nil {
+		writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+	}
+}
+
+func (handler *Handler) retrieveActivation(res http.ResponseWriter, req *http.Request) {
+	grp, id, activationID, ok := getActivationID(res, req)
+	if !ok {
+		return
+	}
+
+	a, err := handler.scheduling.GetActivation(grp, id, activationID)
+	handler.writeActivation(res, req, http.StatusOK, a, err)
+}
+
+func (handler *Handler) cancelActivation(res http.ResponseWriter, req *http.Request) {
+	grp, id, activationID, ok := getActivationID(res, req)
+	if !ok {
+		return
+	}
+
+	a, err := handler.scheduling.Cancel(grp, id, activationID)
+	handler.writeActivation(res, req, http.StatusOK, a, err)
+}
+
+// writeActivation writes an activation with status, or err if it is set
+func (handler *Handler) writeActivation(res http.ResponseWriter, req *http.Request, status int, a *scheduling.Activation, err error) {
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	if a.Config.Properties, err = handler.revealSecrets(res, req, a.Config.Properties); err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	res.WriteHeader(status)
+	if err := json.NewEncoder(res).Encode(a); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// getActivationID gets the config and the id of the activation addressed by a request, or writes a problem if the id is not
+// valid
+func getActivationID(res http.ResponseWriter, req *http.Request) (string, string, int64, bool) {
+	_, grp, id, remainder := getPathVariables(req.URL.Path)
+	activation, _ := getSchedulePath(remainder)
+
+	activationID, err := strconv.ParseInt(activation, 10, 64)
+	if err != nil {
+		writeProblem(res, http.StatusBadRequest, "Invalid activation id")
+		return "", "", 0, false
+	}
+
+	return grp, id, activationID, true
+}
+
+func (handler *Handler) storeOverlay(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var o adding.Overlay
+
+		if err := json.NewDecoder(req.Body).Decode(&o); err != nil {
+			writeProblem(res, http.StatusBadRequest, "Unable to unmarshal request object")
+			return
+		}
+
+		defer req.Body.Close()
+
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		o.Group, o.Config = grp, id
+		o.Environment, _, _ = getOverlayPath(remainder)
+
+		if err := handler.adding.SetOverlay(o); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// promoteOverlay promotes the overlay of the environment given by the from query parameter to the environment in the path
+func (handler *Handler) promoteOverlay(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		from := req.URL.Query().Get("from")
+		if from == "" {
+			writeProblem(res, http.StatusBadRequest, "Missing from parameter")
+			return
+		}
+
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		to, _, _ := getOverlayPath(remainder)
+
+		if err := handler.adding.PromoteOverlay(grp, id, from, to); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) retrieveOverlay(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, remainder := getPathVariables(req.URL.Path)
+		env, _, _ := getOverlayPath(remainder)
+
+		o, err := handler.listing.GetOverlay(grp, id, env)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if o.Properties, err = handler.revealSecrets(res, req, o.Properties); err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(o); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// listDependents lists the configs referencing a config, directly or through other configs
+func (handler *Handler) listDependents(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, _ := getPathVariables(req.URL.Path)
+
+		dependents, err := handler.listing.GetDependents(grp, id)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		if err = json.NewEncoder(res).Encode(dependents); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+func (handler *Handler) listOverlays(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, grp, id, _ := getPathVariables(req.URL.Path)
+
+		overlays, err := handler.listing.ListOverlays(grp, id)
+		if err != nil {
+			writeServiceError(res, err)
+			return
+		}
+
+		for i := range overlays {
+			if overlays[i].Properties, err = handler.revealSecrets(res, req, overlays[i].Properties); err != nil {
+				writeServiceError(res, err)
+				return
+			}
+		}
+
+		if err = json.NewEncoder(res).Encode(overlays); err != nil {
+			writeProblem(res, http.StatusInternalServerError, "Error marshalling response")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// negotiateFormat chooses a format of a registry from the format query parameter, or else the Accept header. Returns the
+// status and detail of the problem to write if no format could be chosen.
+func negotiateFormat(req *http.Request, formats *format.Registry) (*format.Format, int, string) {
+	if name := req.URL.Query().Get("format"); name != "" {
+		if f, exists := formats.Lookup(name); exists {
+			return f, 0, ""
+		}
+		return nil, http.StatusBadRequest, "Unknown format " + strconv.Quote(name)
+	}
+
+	f, ok := formats.Negotiate(req.Header.Get("Accept"))
+	if !ok {
+		return nil, http.StatusNotAcceptable, "None of the accepted media types are supported"
+	}
+
+	return f, 0, ""
+}
+
+func setCommonHeaders(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", contentType)
+		h.ServeHTTP(res, req)
+	})
+}
+
+// getPage reads pagination parameters from the query of the request. Returns false if the limit is invalid.
+func getPage(req *http.Request) (listing.Page, bool) {
+	page := listing.Page{Cursor: req.URL.Query().Get("cursor")}
+	if l := req.URL.Query().Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			return page, false
+		}
+		page.Limit = limit
+	}
+
+	return page, true
+}
+
+func getPathVariables(url string) (string, string, string, string) {
+	var serivce, grp, id string
+	serivce, url = shiftPath(url)
+	grp, url = shiftPath(url)
+	id, url = shiftPath(url)
+
+	return serivce, grp, id, url
+}
+
+// getPropertyPointer gets the Pointer to a property from the remainder of a config path, like "/properties/database/host".
+// Keys containing "/" are escaped as "~1" like in a JSON Pointer.
+func getPropertyPointer(remainder string) (properties.Pointer, bool) {
+	head, tail := shiftPath(remainder)
+	if head != propertiesPath {
+		return nil, false
+	}
+
+	if tail == "/" {
+		return properties.Pointer{}, true
+	}
+
+	p, err := properties.ParsePointer(tail)
+	return p, err == nil
+}
+
+// resolveReferences reports whether the references in the properties read by a request are resolved. Only reads resolve
+// references, and they can be left as they are with resolve=false.
+func resolveReferences(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.URL.Query().Get("resolve") != "false"
+}
+
+// sign sets the signature of a config as it is returned on the response, if configs are signed
+func (handler *Handler) sign(res http.ResponseWriter, conf *listing.Config) error {
+	if handler.signer == nil {
+		return nil
+	}
+
+	sig, err := handler.signer.Sign(signing.Config{ID: conf.ID, Group: conf.Group, Version: conf.Version, Properties: conf.Properties})
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set(signing.SignatureHeader, sig)
+	res.Header().Set(signing.KeyIDHeader, handler.signer.KeyID())
+	return nil
+}
+
+// requireAdmin only lets admin users through
+func requireAdmin(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if user, ok := auth.FromContext(req.Context()); !ok || user.Role&auth.ADMIN == 0 {
+			writeProblem(res, http.StatusForbidden, "")
+			return
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// revealSecrets returns properties with their secret values decrypted if the authenticated user can read them, or masked if
+// not. Responses with decrypted secret values are redacted from the log.
+func (handler *Handler) revealSecrets(res http.ResponseWriter, req *http.Request, props json.RawMessage) (json.RawMessage, error) {
+	user, ok := auth.FromContext(req.Context())
+	if !ok || !user.CanRevealSecrets() {
+		return secret.Masked(props), nil
+	}
+
+	if !secret.Encrypted(props) {
+		return props, nil
+	}
+
+	if logger, ok := res.(*responseLoggerWrapper); ok {
+		logger.redact = true
+	}
+
+	return handler.listing.RevealSecrets(props)
+}
+
+// resolveSecrets resolves the placeholders for secret values kept outside of ki in the property at p, if the request resolves
+// references and the authenticated user can read secrets. Placeholders are left as they are otherwise. Responses with resolved
+// secret values are redacted from the log.
+func (handler *Handler) resolveSecrets(res http.ResponseWriter, req *http.Request, p properties.Pointer, props json.RawMessage) (json.RawMessage, error) {
+	if !resolveReferences(req) || !secret.HasPlaceholders(props) {
+		return props, nil
+	}
+
+	if user, ok := auth.FromContext(req.Context()); !ok || !user.CanRevealSecrets() {
+		return props, nil
+	}
+
+	if logger, ok := res.(*responseLoggerWrapper); ok {
+		logger.redact = true
+	}
+
+	return handler.listing.ResolveSecrets(p, props)
+}
+
+// isDependentsPath reports whether a path addresses the dependents of a config, like "/dependents"
+func isDependentsPath(remainder string) bool {
+	head, tail := shiftPath(remainder)
+	return head == dependentsPath && tail == "/"
+}
+
+// isDiffPath reports whether a path addresses the revision diffs of a config, like "/diff"
+func isDiffPath(remainder string) bool {
+	head, tail := shiftPath(remainder)
+	return head == diffPath && tail == "/"
+}
+
+// getConfigRef parses a config given as "{group}/{id}"
+func getConfigRef(s string) (listing.ConfigRef, bool) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return listing.ConfigRef{}, false
+	}
+
+	return listing.ConfigRef{Group: parts[0], ID: parts[1]}, true
+}
+
+// getSchedulePath returns the activation of a path addressing the schedule of a config, like "/schedule" or
+// "/schedule/{activation}". The activation is empty when the schedule itself is addressed.
+func getSchedulePath(remainder string) (string, bool) {
+	head, tail := shiftPath(remainder)
+	if head != schedulePath {
+		return "", false
+	}
+
+	activation, tail := shiftPath(tail)
+	return activation, tail == "/"
+}
+
+// getOverlayPath returns the environment and action of a path addressing the overlays of a config, like "/environments",
+// "/environments/{env}" or "/environments/{env}/promote". The environment is empty when all overlays are addressed.
+func getOverlayPath(remainder string) (string, string, bool) {
+	head, tail := shiftPath(remainder)
+	if head != environmentsPath {
+		return "", "", false
+	}
+
+	if tail == "/" {
+		return "", "", true
+	}
+
+	env, tail := shiftPath(tail)
+	if tail == "/" {
+		return env, "", true
+	}
+
+	action, tail := shiftPath(tail)
+	return env, action, tail == "/" && action == promotePath
 }
 
 // ShiftPath splits off the first component of p, which will be cleaned of
@@ -3,6 +3,7 @@ package crud
 import (
 	"bytes"
 	"github.com/google/uuid"
+	"github.com/larwef/ki/internal/secret"
 	"io"
 	"io/ioutil"
 	"log"
@@ -29,15 +30,19 @@ func newRequestLogger(req *http.Request, breadcrumb string) *requestLoggerWrappe
 	}
 }
 
-// log prints the content of the buffer
+// log prints the content of the buffer with secret values masked. The payloads of writes are omitted.
 func (r *requestLoggerWrapper) log() {
 	reqBytes, err := ioutil.ReadAll(r.buffer)
 	if err != nil {
 		log.Printf("Error logging request: %v\n", err)
 	}
 
+	if isWrite(r.req) {
+		reqBytes = []byte(secret.Omitted)
+	}
+
 	log.Printf("Inbound message:\nBreadcrumb: %s\nHost: %s\nRemoteAddr: %s\nMethod: %s\nProto: %s\nPath: %s\nPayload: %s",
-		r.breadcrumb, r.req.Host, r.req.RemoteAddr, r.req.Method, r.req.Proto, r.req.URL.Path, string(reqBytes))
+		r.breadcrumb, r.req.Host, r.req.RemoteAddr, r.req.Method, r.req.Proto, r.req.URL.Path, string(secret.MaskPayload(reqBytes)))
 }
 
 func (r *requestLoggerWrapper) Read(p []byte) (int, error) {
@@ -49,10 +54,13 @@ func (r *requestLoggerWrapper) Close() error {
 }
 
 // responseLoggerWrapper wraps a http.ResponseWriter. All writes to the http.ResponseWriter gets written to a buffer which can be
-// used to log the content written to it by calling the log function.
+// used to log the content written to it by calling the log function. Payloads are redacted when redact is set, like when
+// secret values have been decrypted for the response, and omitted when omit is set, like for responses to writes.
 type responseLoggerWrapper struct {
 	breadcrumb string
 	status     int
+	redact     bool
+	omit       bool
 	resWriter  http.ResponseWriter
 	writer     io.Writer
 	buffer     io.ReadWriter
@@ -69,13 +77,21 @@ func newResponseLoggerWrapper(responseWriter http.ResponseWriter, breadcrumb str
 	}
 }
 
-// log prints the content of the buffer
+// log prints the content of the buffer with secret values masked
 func (r *responseLoggerWrapper) log() {
 	resBytes, err := ioutil.ReadAll(r.buffer)
 	if err != nil {
 		log.Printf("Error logging request: %v\n", err)
 	}
 
+	resBytes = secret.MaskPayload(resBytes)
+	if r.redact {
+		resBytes = []byte(secret.Redacted)
+	}
+	if r.omit {
+		resBytes = []byte(secret.Omitted)
+	}
+
 	log.Printf("Outbound Response:\nBreadcrumb: %s\nResponse-Code: %d\nHeaders: %v\nPayload: %s",
 		r.breadcrumb, r.status, r.resWriter.Header(), string(resBytes))
 }
@@ -105,6 +121,7 @@ func inOutLog(h http.Handler) http.Handler {
 		requestLogger := newRequestLogger(req, breadCrumb)
 		req.Body = requestLogger
 		resWriter := newResponseLoggerWrapper(res, breadCrumb)
+		resWriter.omit = isWrite(req)
 
 		h.ServeHTTP(resWriter, req)
 
@@ -112,3 +129,8 @@ func inOutLog(h http.Handler) http.Handler {
 		resWriter.log()
 	})
 }
+
+// isWrite reports whether a request can write configs, groups or what they are validated against
+func isWrite(req *http.Request) bool {
+	return req.Method != http.MethodGet && req.Method != http.MethodHead
+}
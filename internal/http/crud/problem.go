@@ -0,0 +1,70 @@
+package crud
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"log"
+	"net/http"
+)
+
+const problemContentType = "application/problem+json; charset=utf-8"
+
+// kindStatus maps the kinds of service errors to HTTP status codes. Unknown kinds are internal server errors.
+var kindStatus = map[domain.Kind]int{
+	domain.InvalidArgument:    http.StatusBadRequest,
+	domain.ValidationFailed:   http.StatusUnprocessableEntity,
+	domain.NotFound:           http.StatusNotFound,
+	domain.AlreadyExists:      http.StatusConflict,
+	domain.FailedPrecondition: http.StatusConflict,
+	domain.Unauthenticated:    http.StatusUnauthorized,
+	domain.PermissionDenied:   http.StatusForbidden,
+}
+
+// problem is a problem details object as defined in RFC 7807. Resource and violations are extension members describing
+// service errors.
+type problem struct {
+	Type       string             `json:"type"`
+	Title      string             `json:"title"`
+	Status     int                `json:"status"`
+	Detail     string             `json:"detail,omitempty"`
+	Resource   string             `json:"resource,omitempty"`
+	Violations []domain.Violation `json:"violations,omitempty"`
+}
+
+// writeProblem writes a problem with the status code and detail. The title is the text of the status code.
+func writeProblem(res http.ResponseWriter, status int, detail string) {
+	writeProblemDetails(res, problem{Status: status, Detail: detail})
+}
+
+// writeServiceError writes a problem describing an error returned by a service. Details of internal errors are logged but
+// not exposed.
+func writeServiceError(res http.ResponseWriter, err error) {
+	log.Printf("Error: %v", err)
+
+	e := domain.From(err)
+	status, ok := kindStatus[e.Kind]
+	if !ok {
+		writeProblem(res, http.StatusInternalServerError, "")
+		return
+	}
+
+	writeProblemDetails(res, problem{
+		Status:     status,
+		Detail:     e.Message,
+		Resource:   e.Resource,
+		Violations: e.Violations,
+	})
+}
+
+func writeProblemDetails(res http.ResponseWriter, p problem) {
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+
+	res.Header().Set("Content-Type", problemContentType)
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	res.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(res).Encode(p); err != nil {
+		log.Printf("Error writing problem: %v", err)
+	}
+}
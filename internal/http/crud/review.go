@@ -0,0 +1,6 @@
+package crud
+
+// review is the optional body when approving, rejecting or applying a change request
+type review struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,290 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: change.proto
+
+package grpc
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Change struct {
+	Revision int64  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Resource string `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Group    string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	Id       string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	// deleted is set when the resource was deleted by the change
+	Deleted bool `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// environment is set for changes to the overlay of a config in that environment
+	Environment          string   `protobuf:"bytes,6,opt,name=environment,proto3" json:"environment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Change) Reset()         { *m = Change{} }
+func (m *Change) String() string { return proto.CompactTextString(m) }
+func (*Change) ProtoMessage()    {}
+func (*Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c013f0fbf0b6ffb, []int{0}
+}
+func (m *Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Change.Unmarshal(m, b)
+}
+func (m *Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Change.Marshal(b, m, deterministic)
+}
+func (m *Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Change.Merge(m, src)
+}
+func (m *Change) XXX_Size() int {
+	return xxx_messageInfo_Change.Size(m)
+}
+func (m *Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Change proto.InternalMessageInfo
+
+func (m *Change) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Change) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *Change) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *Change) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Change) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *Change) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+type ListChangesRequest struct {
+	Since                int64    `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChangesRequest) Reset()         { *m = ListChangesRequest{} }
+func (m *ListChangesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChangesRequest) ProtoMessage()    {}
+func (*ListChangesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c013f0fbf0b6ffb, []int{1}
+}
+func (m *ListChangesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChangesRequest.Unmarshal(m, b)
+}
+func (m *ListChangesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChangesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChangesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChangesRequest.Merge(m, src)
+}
+func (m *ListChangesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChangesRequest.Size(m)
+}
+func (m *ListChangesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChangesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChangesRequest proto.InternalMessageInfo
+
+func (m *ListChangesRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type ListChangesResponse struct {
+	Revision             int64     `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Changes              []*Change `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListChangesResponse) Reset()         { *m = ListChangesResponse{} }
+func (m *ListChangesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChangesResponse) ProtoMessage()    {}
+func (*ListChangesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c013f0fbf0b6ffb, []int{2}
+}
+func (m *ListChangesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChangesResponse.Unmarshal(m, b)
+}
+func (m *ListChangesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChangesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChangesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChangesResponse.Merge(m, src)
+}
+func (m *ListChangesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChangesResponse.Size(m)
+}
+func (m *ListChangesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChangesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChangesResponse proto.InternalMessageInfo
+
+func (m *ListChangesResponse) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ListChangesResponse) GetChanges() []*Change {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Change)(nil), "grpc.Change")
+	proto.RegisterType((*ListChangesRequest)(nil), "grpc.ListChangesRequest")
+	proto.RegisterType((*ListChangesResponse)(nil), "grpc.ListChangesResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ChangeServiceClient is the client API for ChangeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChangeServiceClient interface {
+	ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error)
+}
+
+type changeServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChangeServiceClient(cc *grpc.ClientConn) ChangeServiceClient {
+	return &changeServiceClient{cc}
+}
+
+func (c *changeServiceClient) ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error) {
+	out := new(ListChangesResponse)
+	err := c.cc.Invoke(ctx, "/grpc.ChangeService/ListChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChangeServiceServer is the Handler API for ChangeService service.
+type ChangeServiceServer interface {
+	ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error)
+}
+
+func RegisterChangeServiceServer(s *grpc.Server, srv ChangeServiceServer) {
+	s.RegisterService(&_ChangeService_serviceDesc, srv)
+}
+
+func _ChangeService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.ChangeService/ListChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeServiceServer).ListChanges(ctx, req.(*ListChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ChangeService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc.ChangeService",
+	HandlerType: (*ChangeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListChanges",
+			Handler:    _ChangeService_ListChanges_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "change.proto",
+}
+
+func init() { proto.RegisterFile("change.proto", fileDescriptor_4c013f0fbf0b6ffb) }
+
+var fileDescriptor_4c013f0fbf0b6ffb = []byte{
+	// 246 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0x31, 0x4f, 0xc3, 0x30,
+	0x10, 0x85, 0xe5, 0xb4, 0x4d, 0xcb, 0xa5, 0x30, 0x18, 0x06, 0xd3, 0xc9, 0xca, 0x80, 0x22, 0x86,
+	0x0c, 0xe5, 0x1f, 0xc0, 0xca, 0xe4, 0x4e, 0x8c, 0xe0, 0x9c, 0x82, 0x25, 0xb0, 0x83, 0xcf, 0xc9,
+	0x0f, 0xe2, 0x97, 0xa2, 0xfa, 0x68, 0x55, 0x04, 0x62, 0xfc, 0xde, 0xf3, 0xf9, 0x9e, 0x9f, 0x61,
+	0x6d, 0x5f, 0x9f, 0x7d, 0x8f, 0xed, 0x10, 0x43, 0x0a, 0x72, 0xde, 0xc7, 0xc1, 0xd6, 0x9f, 0x02,
+	0xca, 0x87, 0x2c, 0xcb, 0x0d, 0xac, 0x22, 0x4e, 0x8e, 0x5c, 0xf0, 0x4a, 0x68, 0xd1, 0xcc, 0xcc,
+	0x91, 0xd9, 0xa3, 0x30, 0x46, 0x8b, 0xaa, 0xd0, 0xa2, 0x39, 0x33, 0x47, 0x96, 0x57, 0xb0, 0xe8,
+	0x63, 0x18, 0x07, 0x35, 0xcb, 0x06, 0x83, 0xbc, 0x80, 0xc2, 0x75, 0x6a, 0x9e, 0xa5, 0xc2, 0x75,
+	0x52, 0xc1, 0xb2, 0xc3, 0x37, 0x4c, 0xd8, 0xa9, 0x85, 0x16, 0xcd, 0xca, 0x1c, 0x50, 0x6a, 0xa8,
+	0xd0, 0x4f, 0x2e, 0x06, 0xff, 0x8e, 0x3e, 0xa9, 0x32, 0x8f, 0x9c, 0x4a, 0xf5, 0x2d, 0xc8, 0x47,
+	0x47, 0x89, 0x73, 0x92, 0xc1, 0x8f, 0x11, 0x29, 0xed, 0xf7, 0x92, 0xf3, 0x16, 0xbf, 0xc3, 0x32,
+	0xd4, 0x4f, 0x70, 0xf9, 0xe3, 0x2c, 0x0d, 0xc1, 0xd3, 0xff, 0x8f, 0xbb, 0x81, 0x25, 0x37, 0x43,
+	0xaa, 0xd0, 0xb3, 0xa6, 0xda, 0xae, 0xdb, 0x7d, 0x37, 0x2d, 0xdf, 0x61, 0x0e, 0xe6, 0x76, 0x07,
+	0xe7, 0x2c, 0xed, 0x30, 0x4e, 0xce, 0xa2, 0xbc, 0x87, 0xea, 0x64, 0x97, 0x54, 0x3c, 0xf6, 0x3b,
+	0xea, 0xe6, 0xfa, 0x0f, 0x87, 0x83, 0xbd, 0x94, 0xf9, 0x37, 0xee, 0xbe, 0x06, 0x00, 0xc5, 0xc6,
+	0x02, 0xc5, 0x9d, 0x01, 0x00, 0x00,
+}
@@ -26,12 +26,18 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Config struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	LastModified         int64    `protobuf:"varint,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
-	Version              int32    `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
-	Group                string   `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
-	Properties           []byte   `protobuf:"bytes,6,opt,name=properties,proto3" json:"properties,omitempty"`
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	LastModified int64  `protobuf:"varint,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Version      int32  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Group        string `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
+	// properties are the effective properties of the config, unless retrieved raw.
+	Properties []byte `protobuf:"bytes,6,opt,name=properties,proto3" json:"properties,omitempty"`
+	Revision   int64  `protobuf:"varint,7,opt,name=revision,proto3" json:"revision,omitempty"`
+	// parent is the id of the config in the same group the config inherits properties from, if set.
+	Parent string `protobuf:"bytes,8,opt,name=parent,proto3" json:"parent,omitempty"`
+	// environment is the environment whose overlay is merged onto the properties, if retrieved in one.
+	Environment          string   `protobuf:"bytes,9,opt,name=environment,proto3" json:"environment,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -103,11 +109,33 @@ func (m *Config) GetProperties() []byte {
 	return nil
 }
 
+func (m *Config) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Config) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *Config) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
 type StoreConfigRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Group                string   `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
 	Properties           []byte   `protobuf:"bytes,6,opt,name=properties,proto3" json:"properties,omitempty"`
+	Parent               string   `protobuf:"bytes,7,opt,name=parent,proto3" json:"parent,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -165,9 +193,22 @@ func (m *StoreConfigRequest) GetProperties() []byte {
 	return nil
 }
 
+func (m *StoreConfigRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+// RetrieveConfigRequest retrieves a config with its effective properties, or only its own properties if raw is set. If
+// environment is set, the overlay of that environment is merged onto the effective properties. Raw and environment cannot
+// be combined. References to the properties of other configs are resolved unless unresolved or raw is set.
 type RetrieveConfigRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	GroupId              string   `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Raw                  bool     `protobuf:"varint,3,opt,name=raw,proto3" json:"raw,omitempty"`
+	Environment          string   `protobuf:"bytes,4,opt,name=environment,proto3" json:"environment,omitempty"`
+	Unresolved           bool     `protobuf:"varint,5,opt,name=unresolved,proto3" json:"unresolved,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -211,10 +252,403 @@ func (m *RetrieveConfigRequest) GetGroupId() string {
 	return ""
 }
 
+func (m *RetrieveConfigRequest) GetRaw() bool {
+	if m != nil {
+		return m.Raw
+	}
+	return false
+}
+
+func (m *RetrieveConfigRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *RetrieveConfigRequest) GetUnresolved() bool {
+	if m != nil {
+		return m.Unresolved
+	}
+	return false
+}
+
+// Condition is a predicate on the property value at path. Operator is one of eq, ne, gt, gte, lt, lte, exists and regex. Value
+// is JSON encoded.
+type Condition struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Operator             string   `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value                []byte   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Condition) Reset()         { *m = Condition{} }
+func (m *Condition) String() string { return proto.CompactTextString(m) }
+func (*Condition) ProtoMessage()    {}
+func (*Condition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{3}
+}
+func (m *Condition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Condition.Unmarshal(m, b)
+}
+func (m *Condition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Condition.Marshal(b, m, deterministic)
+}
+func (m *Condition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Condition.Merge(m, src)
+}
+func (m *Condition) XXX_Size() int {
+	return xxx_messageInfo_Condition.Size(m)
+}
+func (m *Condition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Condition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Condition proto.InternalMessageInfo
+
+func (m *Condition) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Condition) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *Condition) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type SearchConfigsRequest struct {
+	Group                string       `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Conditions           []*Condition `protobuf:"bytes,2,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	Cursor               string       `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32        `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SearchConfigsRequest) Reset()         { *m = SearchConfigsRequest{} }
+func (m *SearchConfigsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchConfigsRequest) ProtoMessage()    {}
+func (*SearchConfigsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{4}
+}
+func (m *SearchConfigsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchConfigsRequest.Unmarshal(m, b)
+}
+func (m *SearchConfigsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchConfigsRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchConfigsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchConfigsRequest.Merge(m, src)
+}
+func (m *SearchConfigsRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchConfigsRequest.Size(m)
+}
+func (m *SearchConfigsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchConfigsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchConfigsRequest proto.InternalMessageInfo
+
+func (m *SearchConfigsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *SearchConfigsRequest) GetConditions() []*Condition {
+	if m != nil {
+		return m.Conditions
+	}
+	return nil
+}
+
+func (m *SearchConfigsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *SearchConfigsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type SearchConfigsResponse struct {
+	Configs              []*Config `protobuf:"bytes,1,rep,name=configs,proto3" json:"configs,omitempty"`
+	NextCursor           string    `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SearchConfigsResponse) Reset()         { *m = SearchConfigsResponse{} }
+func (m *SearchConfigsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchConfigsResponse) ProtoMessage()    {}
+func (*SearchConfigsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{5}
+}
+func (m *SearchConfigsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchConfigsResponse.Unmarshal(m, b)
+}
+func (m *SearchConfigsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchConfigsResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchConfigsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchConfigsResponse.Merge(m, src)
+}
+func (m *SearchConfigsResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchConfigsResponse.Size(m)
+}
+func (m *SearchConfigsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchConfigsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchConfigsResponse proto.InternalMessageInfo
+
+func (m *SearchConfigsResponse) GetConfigs() []*Config {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *SearchConfigsResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type SearchTextRequest struct {
+	Text                 string   `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Group                string   `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchTextRequest) Reset()         { *m = SearchTextRequest{} }
+func (m *SearchTextRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchTextRequest) ProtoMessage()    {}
+func (*SearchTextRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{6}
+}
+func (m *SearchTextRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchTextRequest.Unmarshal(m, b)
+}
+func (m *SearchTextRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchTextRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchTextRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTextRequest.Merge(m, src)
+}
+func (m *SearchTextRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchTextRequest.Size(m)
+}
+func (m *SearchTextRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTextRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTextRequest proto.InternalMessageInfo
+
+func (m *SearchTextRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *SearchTextRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *SearchTextRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// Snippet is a piece of the field matched with the matches highlighted by <em> tags.
+type Snippet struct {
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Snippet) Reset()         { *m = Snippet{} }
+func (m *Snippet) String() string { return proto.CompactTextString(m) }
+func (*Snippet) ProtoMessage()    {}
+func (*Snippet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{7}
+}
+func (m *Snippet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snippet.Unmarshal(m, b)
+}
+func (m *Snippet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snippet.Marshal(b, m, deterministic)
+}
+func (m *Snippet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snippet.Merge(m, src)
+}
+func (m *Snippet) XXX_Size() int {
+	return xxx_messageInfo_Snippet.Size(m)
+}
+func (m *Snippet) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snippet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Snippet proto.InternalMessageInfo
+
+func (m *Snippet) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *Snippet) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type TextMatch struct {
+	Group                string     `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Score                float64    `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	Snippets             []*Snippet `protobuf:"bytes,4,rep,name=snippets,proto3" json:"snippets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *TextMatch) Reset()         { *m = TextMatch{} }
+func (m *TextMatch) String() string { return proto.CompactTextString(m) }
+func (*TextMatch) ProtoMessage()    {}
+func (*TextMatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{8}
+}
+func (m *TextMatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TextMatch.Unmarshal(m, b)
+}
+func (m *TextMatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TextMatch.Marshal(b, m, deterministic)
+}
+func (m *TextMatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TextMatch.Merge(m, src)
+}
+func (m *TextMatch) XXX_Size() int {
+	return xxx_messageInfo_TextMatch.Size(m)
+}
+func (m *TextMatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_TextMatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TextMatch proto.InternalMessageInfo
+
+func (m *TextMatch) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *TextMatch) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TextMatch) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *TextMatch) GetSnippets() []*Snippet {
+	if m != nil {
+		return m.Snippets
+	}
+	return nil
+}
+
+type SearchTextResponse struct {
+	Matches              []*TextMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SearchTextResponse) Reset()         { *m = SearchTextResponse{} }
+func (m *SearchTextResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchTextResponse) ProtoMessage()    {}
+func (*SearchTextResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eaf2c85e69e9ea4, []int{9}
+}
+func (m *SearchTextResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchTextResponse.Unmarshal(m, b)
+}
+func (m *SearchTextResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchTextResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchTextResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTextResponse.Merge(m, src)
+}
+func (m *SearchTextResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchTextResponse.Size(m)
+}
+func (m *SearchTextResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTextResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTextResponse proto.InternalMessageInfo
+
+func (m *SearchTextResponse) GetMatches() []*TextMatch {
+	if m != nil {
+		return m.Matches
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "grpc.Config")
 	proto.RegisterType((*StoreConfigRequest)(nil), "grpc.StoreConfigRequest")
 	proto.RegisterType((*RetrieveConfigRequest)(nil), "grpc.RetrieveConfigRequest")
+	proto.RegisterType((*Condition)(nil), "grpc.Condition")
+	proto.RegisterType((*SearchConfigsRequest)(nil), "grpc.SearchConfigsRequest")
+	proto.RegisterType((*SearchConfigsResponse)(nil), "grpc.SearchConfigsResponse")
+	proto.RegisterType((*SearchTextRequest)(nil), "grpc.SearchTextRequest")
+	proto.RegisterType((*Snippet)(nil), "grpc.Snippet")
+	proto.RegisterType((*TextMatch)(nil), "grpc.TextMatch")
+	proto.RegisterType((*SearchTextResponse)(nil), "grpc.SearchTextResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -231,6 +665,8 @@ const _ = grpc.SupportPackageIsVersion4
 type ConfigServiceClient interface {
 	StoreConfig(ctx context.Context, in *StoreConfigRequest, opts ...grpc.CallOption) (*Config, error)
 	RetrieveConfig(ctx context.Context, in *RetrieveConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	SearchConfigs(ctx context.Context, in *SearchConfigsRequest, opts ...grpc.CallOption) (*SearchConfigsResponse, error)
+	SearchText(ctx context.Context, in *SearchTextRequest, opts ...grpc.CallOption) (*SearchTextResponse, error)
 }
 
 type configServiceClient struct {
@@ -259,10 +695,30 @@ func (c *configServiceClient) RetrieveConfig(ctx context.Context, in *RetrieveCo
 	return out, nil
 }
 
+func (c *configServiceClient) SearchConfigs(ctx context.Context, in *SearchConfigsRequest, opts ...grpc.CallOption) (*SearchConfigsResponse, error) {
+	out := new(SearchConfigsResponse)
+	err := c.cc.Invoke(ctx, "/grpc.ConfigService/SearchConfigs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) SearchText(ctx context.Context, in *SearchTextRequest, opts ...grpc.CallOption) (*SearchTextResponse, error) {
+	out := new(SearchTextResponse)
+	err := c.cc.Invoke(ctx, "/grpc.ConfigService/SearchText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConfigServiceServer is the Handler API for ConfigService service.
 type ConfigServiceServer interface {
 	StoreConfig(context.Context, *StoreConfigRequest) (*Config, error)
 	RetrieveConfig(context.Context, *RetrieveConfigRequest) (*Config, error)
+	SearchConfigs(context.Context, *SearchConfigsRequest) (*SearchConfigsResponse, error)
+	SearchText(context.Context, *SearchTextRequest) (*SearchTextResponse, error)
 }
 
 func RegisterConfigServiceServer(s *grpc.Server, srv ConfigServiceServer) {
@@ -305,6 +761,42 @@ func _ConfigService_RetrieveConfig_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConfigService_SearchConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).SearchConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.ConfigService/SearchConfigs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).SearchConfigs(ctx, req.(*SearchConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_SearchText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).SearchText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.ConfigService/SearchText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).SearchText(ctx, req.(*SearchTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ConfigService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "grpc.ConfigService",
 	HandlerType: (*ConfigServiceServer)(nil),
@@ -317,6 +809,14 @@ var _ConfigService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RetrieveConfig",
 			Handler:    _ConfigService_RetrieveConfig_Handler,
 		},
+		{
+			MethodName: "SearchConfigs",
+			Handler:    _ConfigService_SearchConfigs_Handler,
+		},
+		{
+			MethodName: "SearchText",
+			Handler:    _ConfigService_SearchText_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "config.proto",
@@ -325,22 +825,45 @@ var _ConfigService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("config.proto", fileDescriptor_3eaf2c85e69e9ea4) }
 
 var fileDescriptor_3eaf2c85e69e9ea4 = []byte{
-	// 267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x91, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x86, 0xe5, 0x36, 0x49, 0xe1, 0x48, 0x3b, 0x9c, 0x40, 0x32, 0x20, 0xa1, 0xa8, 0x2c, 0x99,
-	0x32, 0x80, 0x98, 0xd8, 0x60, 0x62, 0x60, 0x71, 0x1f, 0xa0, 0x2a, 0xf1, 0x35, 0x3a, 0x89, 0xc6,
-	0xc6, 0x71, 0xf3, 0x02, 0x3c, 0x09, 0x6f, 0x8a, 0x6a, 0x07, 0xa9, 0x15, 0x48, 0x20, 0x36, 0xff,
-	0xdf, 0xf9, 0xee, 0xff, 0xcf, 0x86, 0xbc, 0x36, 0xed, 0x9a, 0x9b, 0xca, 0x3a, 0xe3, 0x0d, 0x26,
-	0x8d, 0xb3, 0xf5, 0xfc, 0x43, 0x40, 0xf6, 0x18, 0x30, 0xce, 0x60, 0xc4, 0x5a, 0x8a, 0x42, 0x94,
-	0xc7, 0x6a, 0xc4, 0x1a, 0x11, 0x92, 0x76, 0xb5, 0x21, 0x39, 0x0a, 0x24, 0x9c, 0xf1, 0x1a, 0xa6,
-	0xaf, 0xab, 0xce, 0x2f, 0x37, 0x46, 0xf3, 0x9a, 0x49, 0xcb, 0x71, 0x21, 0xca, 0xb1, 0xca, 0x77,
-	0xf0, 0x79, 0x60, 0x28, 0x61, 0xd2, 0x93, 0xeb, 0xd8, 0xb4, 0x32, 0x29, 0x44, 0x99, 0xaa, 0x2f,
-	0x89, 0xa7, 0x90, 0x36, 0xce, 0x6c, 0xad, 0x4c, 0xc3, 0xcc, 0x28, 0xf0, 0x0a, 0xc0, 0x3a, 0x63,
-	0xc9, 0x79, 0xa6, 0x4e, 0x66, 0x85, 0x28, 0x73, 0xb5, 0x47, 0xe6, 0x2d, 0xe0, 0xc2, 0x1b, 0x47,
-	0x31, 0xa7, 0xa2, 0xb7, 0x2d, 0x75, 0xfe, 0x4f, 0x71, 0xff, 0xe7, 0xf7, 0x00, 0x67, 0x8a, 0xbc,
-	0x63, 0xea, 0x7f, 0xb1, 0x3c, 0x87, 0xa3, 0x30, 0x71, 0xc9, 0x7a, 0xb0, 0x9d, 0x04, 0xfd, 0xa4,
-	0x6f, 0xde, 0x05, 0x4c, 0x63, 0xf3, 0x82, 0x5c, 0xcf, 0x35, 0xe1, 0x1d, 0x9c, 0xec, 0x6d, 0x81,
-	0xb2, 0xda, 0xbd, 0x7f, 0xf5, 0x7d, 0xb1, 0x8b, 0x3c, 0x56, 0x86, 0x7b, 0xf7, 0x30, 0x3b, 0x0c,
-	0x83, 0x97, 0xb1, 0xfe, 0x63, 0xc4, 0xc3, 0xe6, 0x97, 0x2c, 0x7c, 0xf5, 0xed, 0x67, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x74, 0x42, 0x34, 0xe7, 0xfa, 0x01, 0x00, 0x00,
+	// 631 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4d, 0x6f, 0xd3, 0x4c,
+	0x10, 0x96, 0x1d, 0x27, 0x4e, 0x26, 0x49, 0xdf, 0x97, 0x51, 0x0b, 0x26, 0x95, 0x20, 0x32, 0x12,
+	0x4a, 0x2f, 0x45, 0x6a, 0xc5, 0x89, 0x43, 0x0f, 0xbd, 0xc0, 0xa1, 0x07, 0x36, 0xdc, 0x8b, 0xb1,
+	0xa7, 0xed, 0x4a, 0x89, 0xd7, 0xec, 0x6e, 0x4c, 0x7f, 0x01, 0x17, 0x24, 0x8e, 0xfc, 0x55, 0xae,
+	0x68, 0x3f, 0xec, 0xb8, 0x6d, 0x10, 0x88, 0xdb, 0x3e, 0x33, 0xf1, 0x3e, 0x1f, 0x33, 0x1b, 0x98,
+	0xe4, 0xa2, 0xbc, 0xe2, 0xd7, 0xc7, 0x95, 0x14, 0x5a, 0x60, 0x74, 0x2d, 0xab, 0x3c, 0xfd, 0x19,
+	0xc0, 0xe0, 0xdc, 0x96, 0x71, 0x0f, 0x42, 0x5e, 0x24, 0xc1, 0x3c, 0x58, 0x8c, 0x58, 0xc8, 0x0b,
+	0x44, 0x88, 0xca, 0x6c, 0x4d, 0x49, 0x68, 0x2b, 0xf6, 0x8c, 0x2f, 0x60, 0xba, 0xca, 0x94, 0xbe,
+	0x5c, 0x8b, 0x82, 0x5f, 0x71, 0x2a, 0x92, 0xde, 0x3c, 0x58, 0xf4, 0xd8, 0xc4, 0x14, 0x2f, 0x7c,
+	0x0d, 0x13, 0x88, 0x6b, 0x92, 0x8a, 0x8b, 0x32, 0x89, 0xe6, 0xc1, 0xa2, 0xcf, 0x1a, 0x88, 0xfb,
+	0xd0, 0xbf, 0x96, 0x62, 0x53, 0x25, 0x7d, 0x7b, 0xa7, 0x03, 0xf8, 0x0c, 0xa0, 0x92, 0xa2, 0x22,
+	0xa9, 0x39, 0xa9, 0x64, 0x30, 0x0f, 0x16, 0x13, 0xd6, 0xa9, 0xe0, 0x0c, 0x86, 0x92, 0x6a, 0x6e,
+	0x2f, 0x8c, 0x2d, 0x5f, 0x8b, 0xf1, 0x31, 0x0c, 0xaa, 0x4c, 0x52, 0xa9, 0x93, 0xa1, 0xbd, 0xd2,
+	0x23, 0x9c, 0xc3, 0x98, 0xca, 0x9a, 0x4b, 0x51, 0xae, 0x4d, 0x73, 0x64, 0x9b, 0xdd, 0x52, 0xfa,
+	0x35, 0x00, 0x5c, 0x6a, 0x21, 0xc9, 0xd9, 0x67, 0xf4, 0x79, 0x43, 0x4a, 0xff, 0x55, 0x0a, 0xff,
+	0x66, 0x63, 0x2b, 0x35, 0xee, 0x4a, 0x4d, 0x7f, 0x04, 0x70, 0xc0, 0x48, 0x4b, 0x4e, 0xf5, 0x1f,
+	0xb4, 0x3c, 0x85, 0xa1, 0xa5, 0xba, 0xe4, 0x85, 0xd7, 0x13, 0x5b, 0xfc, 0xae, 0xc0, 0xff, 0xa1,
+	0x27, 0xb3, 0x2f, 0x76, 0x1c, 0x43, 0x66, 0x8e, 0xf7, 0x13, 0x88, 0x1e, 0x24, 0x60, 0x04, 0x6f,
+	0x4a, 0x49, 0x4a, 0xac, 0x6a, 0x2a, 0xac, 0x97, 0x21, 0xeb, 0x54, 0xd2, 0xf7, 0x30, 0x3a, 0x17,
+	0x65, 0xc1, 0xb5, 0x09, 0x1a, 0x21, 0xaa, 0x32, 0x7d, 0xe3, 0xd5, 0xd8, 0xb3, 0x19, 0x8c, 0x71,
+	0x97, 0x69, 0x21, 0xbd, 0x9e, 0x16, 0x9b, 0x8c, 0xea, 0x6c, 0xb5, 0x21, 0x2b, 0x69, 0xc2, 0x1c,
+	0x48, 0xbf, 0x05, 0xb0, 0xbf, 0xa4, 0x4c, 0xe6, 0x37, 0xce, 0xa9, 0x6a, 0xac, 0xb6, 0x91, 0x06,
+	0xdd, 0x48, 0x5f, 0x01, 0xe4, 0x8d, 0x02, 0x95, 0x84, 0xf3, 0xde, 0x62, 0x7c, 0xf2, 0xdf, 0xb1,
+	0x59, 0xdc, 0xe3, 0x56, 0x19, 0xeb, 0xfc, 0xc4, 0x64, 0x9c, 0x6f, 0xa4, 0x12, 0xd2, 0xd2, 0x8e,
+	0x98, 0x47, 0xe6, 0xfa, 0x15, 0x5f, 0x73, 0xed, 0x17, 0xd2, 0x81, 0xf4, 0x23, 0x1c, 0xdc, 0x13,
+	0xa3, 0x2a, 0x51, 0x2a, 0xc2, 0x97, 0x10, 0xbb, 0xb7, 0xa2, 0x92, 0xc0, 0x92, 0x4e, 0x5a, 0x52,
+	0x33, 0x9e, 0xa6, 0x89, 0xcf, 0x61, 0x5c, 0xd2, 0xad, 0xbe, 0xf4, 0x9c, 0x2e, 0x03, 0x30, 0xa5,
+	0x73, 0x5b, 0x49, 0x97, 0xf0, 0xc8, 0x31, 0x7c, 0xa0, 0x5b, 0xdd, 0x78, 0x45, 0x88, 0x34, 0xdd,
+	0xea, 0x26, 0x4a, 0x73, 0xde, 0xfa, 0x0f, 0xbb, 0xfe, 0x5b, 0xd9, 0xbd, 0xae, 0xec, 0x53, 0x88,
+	0x97, 0x25, 0xaf, 0x2a, 0xb2, 0x9f, 0x5d, 0x71, 0x5a, 0x35, 0x4b, 0xe2, 0x40, 0x4b, 0x10, 0x6e,
+	0x09, 0x52, 0x09, 0x23, 0xa3, 0xe1, 0x22, 0xd3, 0xf9, 0xcd, 0x6f, 0xd2, 0x76, 0xeb, 0x16, 0xb6,
+	0xeb, 0xb6, 0x0f, 0x7d, 0x95, 0x0b, 0xe9, 0x46, 0x18, 0x30, 0x07, 0xf0, 0x08, 0x86, 0xca, 0xb1,
+	0xab, 0x24, 0xb2, 0xe1, 0x4c, 0x5d, 0x38, 0x5e, 0x13, 0x6b, 0xdb, 0xe9, 0x19, 0x60, 0xd7, 0xbd,
+	0x0f, 0xf7, 0x08, 0xe2, 0xb5, 0x51, 0x41, 0x4d, 0xb8, 0x7e, 0xa2, 0xad, 0x3c, 0xd6, 0xf4, 0x4f,
+	0xbe, 0x87, 0x30, 0x75, 0x99, 0x2f, 0x49, 0xd6, 0x3c, 0x27, 0x7c, 0x0d, 0xe3, 0xce, 0xa3, 0xc5,
+	0xc4, 0x53, 0x3f, 0x78, 0xc7, 0xb3, 0x3b, 0x13, 0xc3, 0x37, 0xb0, 0x77, 0xf7, 0x89, 0xe1, 0xa1,
+	0xeb, 0xef, 0x7c, 0x78, 0xf7, 0x3e, 0x7e, 0x0b, 0xd3, 0x3b, 0x6b, 0x82, 0x33, 0xcf, 0xba, 0x63,
+	0x91, 0x67, 0x87, 0x3b, 0x7b, 0xde, 0xfa, 0x19, 0xc0, 0x36, 0x10, 0x7c, 0xd2, 0xfd, 0x69, 0x67,
+	0x41, 0x66, 0xc9, 0xc3, 0x86, 0xbb, 0xe0, 0xd3, 0xc0, 0xfe, 0x77, 0x9f, 0xfe, 0x1a, 0x00, 0x9a,
+	0x81, 0xea, 0x6c, 0xcb, 0x05, 0x00, 0x00,
 }
@@ -26,11 +26,16 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Group struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ConfigIds            []string `protobuf:"bytes,2,rep,name=config_ids,json=configIds,proto3" json:"config_ids,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Id         string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ConfigIds  []string `protobuf:"bytes,2,rep,name=config_ids,json=configIds,proto3" json:"config_ids,omitempty"`
+	Revision   int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	NextCursor string   `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// schema is the JSON Schema the properties of configs in the group have to satisfy, if set.
+	Schema               []byte    `protobuf:"bytes,5,opt,name=schema,proto3" json:"schema,omitempty"`
+	Defaults             *Defaults `protobuf:"bytes,6,opt,name=defaults,proto3" json:"defaults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *Group) Reset()         { *m = Group{} }
@@ -71,18 +76,158 @@ func (m *Group) GetConfigIds() []string {
 	return nil
 }
 
-type StoreGroupRequest struct {
+func (m *Group) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Group) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *Group) GetSchema() []byte {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+func (m *Group) GetDefaults() *Defaults {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
+// Defaults are the properties configs in a group inherit. Properties are JSON encoded. Arrays is one of replace and append,
+// and nulls one of keep and delete.
+type Defaults struct {
+	Properties           []byte   `protobuf:"bytes,1,opt,name=properties,proto3" json:"properties,omitempty"`
+	Arrays               string   `protobuf:"bytes,2,opt,name=arrays,proto3" json:"arrays,omitempty"`
+	Nulls                string   `protobuf:"bytes,3,opt,name=nulls,proto3" json:"nulls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Defaults) Reset()         { *m = Defaults{} }
+func (m *Defaults) String() string { return proto.CompactTextString(m) }
+func (*Defaults) ProtoMessage()    {}
+func (*Defaults) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{1}
+}
+func (m *Defaults) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Defaults.Unmarshal(m, b)
+}
+func (m *Defaults) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Defaults.Marshal(b, m, deterministic)
+}
+func (m *Defaults) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Defaults.Merge(m, src)
+}
+func (m *Defaults) XXX_Size() int {
+	return xxx_messageInfo_Defaults.Size(m)
+}
+func (m *Defaults) XXX_DiscardUnknown() {
+	xxx_messageInfo_Defaults.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Defaults proto.InternalMessageInfo
+
+func (m *Defaults) GetProperties() []byte {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func (m *Defaults) GetArrays() string {
+	if m != nil {
+		return m.Arrays
+	}
+	return ""
+}
+
+func (m *Defaults) GetNulls() string {
+	if m != nil {
+		return m.Nulls
+	}
+	return ""
+}
+
+type GroupSummary struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision             int64    `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	ConfigCount          int32    `protobuf:"varint,3,opt,name=config_count,json=configCount,proto3" json:"config_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *GroupSummary) Reset()         { *m = GroupSummary{} }
+func (m *GroupSummary) String() string { return proto.CompactTextString(m) }
+func (*GroupSummary) ProtoMessage()    {}
+func (*GroupSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{2}
+}
+func (m *GroupSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupSummary.Unmarshal(m, b)
+}
+func (m *GroupSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupSummary.Marshal(b, m, deterministic)
+}
+func (m *GroupSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupSummary.Merge(m, src)
+}
+func (m *GroupSummary) XXX_Size() int {
+	return xxx_messageInfo_GroupSummary.Size(m)
+}
+func (m *GroupSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupSummary proto.InternalMessageInfo
+
+func (m *GroupSummary) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GroupSummary) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *GroupSummary) GetConfigCount() int32 {
+	if m != nil {
+		return m.ConfigCount
+	}
+	return 0
+}
+
+type StoreGroupRequest struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Schema               []byte    `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	Defaults             *Defaults `protobuf:"bytes,3,opt,name=defaults,proto3" json:"defaults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
 func (m *StoreGroupRequest) Reset()         { *m = StoreGroupRequest{} }
 func (m *StoreGroupRequest) String() string { return proto.CompactTextString(m) }
 func (*StoreGroupRequest) ProtoMessage()    {}
 func (*StoreGroupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e10f4c9b19ad8eee, []int{1}
+	return fileDescriptor_e10f4c9b19ad8eee, []int{3}
 }
 func (m *StoreGroupRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StoreGroupRequest.Unmarshal(m, b)
@@ -109,8 +254,24 @@ func (m *StoreGroupRequest) GetId() string {
 	return ""
 }
 
+func (m *StoreGroupRequest) GetSchema() []byte {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+func (m *StoreGroupRequest) GetDefaults() *Defaults {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
 type RetrieveGroupRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -120,7 +281,7 @@ func (m *RetrieveGroupRequest) Reset()         { *m = RetrieveGroupRequest{} }
 func (m *RetrieveGroupRequest) String() string { return proto.CompactTextString(m) }
 func (*RetrieveGroupRequest) ProtoMessage()    {}
 func (*RetrieveGroupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e10f4c9b19ad8eee, []int{2}
+	return fileDescriptor_e10f4c9b19ad8eee, []int{4}
 }
 func (m *RetrieveGroupRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RetrieveGroupRequest.Unmarshal(m, b)
@@ -147,10 +308,437 @@ func (m *RetrieveGroupRequest) GetId() string {
 	return ""
 }
 
+func (m *RetrieveGroupRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *RetrieveGroupRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListGroupsRequest struct {
+	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Sort                 string   `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupsRequest) Reset()         { *m = ListGroupsRequest{} }
+func (m *ListGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsRequest) ProtoMessage()    {}
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{5}
+}
+func (m *ListGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsRequest.Unmarshal(m, b)
+}
+func (m *ListGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsRequest.Merge(m, src)
+}
+func (m *ListGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsRequest.Size(m)
+}
+func (m *ListGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsRequest proto.InternalMessageInfo
+
+func (m *ListGroupsRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListGroupsRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *ListGroupsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListGroupsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListGroupsResponse struct {
+	Groups               []*GroupSummary `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	NextCursor           string          `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListGroupsResponse) Reset()         { *m = ListGroupsResponse{} }
+func (m *ListGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsResponse) ProtoMessage()    {}
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{6}
+}
+func (m *ListGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsResponse.Merge(m, src)
+}
+func (m *ListGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsResponse.Size(m)
+}
+func (m *ListGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsResponse proto.InternalMessageInfo
+
+func (m *ListGroupsResponse) GetGroups() []*GroupSummary {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *ListGroupsResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+// StoreSchemaRequest replaces the schema of a group. An empty schema removes it.
+type StoreSchemaRequest struct {
+	GroupId              string   `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Schema               []byte   `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreSchemaRequest) Reset()         { *m = StoreSchemaRequest{} }
+func (m *StoreSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreSchemaRequest) ProtoMessage()    {}
+func (*StoreSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{7}
+}
+func (m *StoreSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreSchemaRequest.Unmarshal(m, b)
+}
+func (m *StoreSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreSchemaRequest.Merge(m, src)
+}
+func (m *StoreSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreSchemaRequest.Size(m)
+}
+func (m *StoreSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreSchemaRequest proto.InternalMessageInfo
+
+func (m *StoreSchemaRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *StoreSchemaRequest) GetSchema() []byte {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+// StoreDefaultsRequest replaces the defaults of a group. Empty defaults remove them.
+type StoreDefaultsRequest struct {
+	GroupId              string    `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Defaults             *Defaults `protobuf:"bytes,2,opt,name=defaults,proto3" json:"defaults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *StoreDefaultsRequest) Reset()         { *m = StoreDefaultsRequest{} }
+func (m *StoreDefaultsRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreDefaultsRequest) ProtoMessage()    {}
+func (*StoreDefaultsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{8}
+}
+func (m *StoreDefaultsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreDefaultsRequest.Unmarshal(m, b)
+}
+func (m *StoreDefaultsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreDefaultsRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreDefaultsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreDefaultsRequest.Merge(m, src)
+}
+func (m *StoreDefaultsRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreDefaultsRequest.Size(m)
+}
+func (m *StoreDefaultsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreDefaultsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreDefaultsRequest proto.InternalMessageInfo
+
+func (m *StoreDefaultsRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *StoreDefaultsRequest) GetDefaults() *Defaults {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
+// CheckSchemaRequest checks the configs of a group against schema, or the current schema of the group if empty.
+type CheckSchemaRequest struct {
+	GroupId              string   `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Schema               []byte   `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckSchemaRequest) Reset()         { *m = CheckSchemaRequest{} }
+func (m *CheckSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckSchemaRequest) ProtoMessage()    {}
+func (*CheckSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{9}
+}
+func (m *CheckSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckSchemaRequest.Unmarshal(m, b)
+}
+func (m *CheckSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckSchemaRequest.Merge(m, src)
+}
+func (m *CheckSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckSchemaRequest.Size(m)
+}
+func (m *CheckSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckSchemaRequest proto.InternalMessageInfo
+
+func (m *CheckSchemaRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *CheckSchemaRequest) GetSchema() []byte {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+type Violation struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Violation) Reset()         { *m = Violation{} }
+func (m *Violation) String() string { return proto.CompactTextString(m) }
+func (*Violation) ProtoMessage()    {}
+func (*Violation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{10}
+}
+func (m *Violation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Violation.Unmarshal(m, b)
+}
+func (m *Violation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Violation.Marshal(b, m, deterministic)
+}
+func (m *Violation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Violation.Merge(m, src)
+}
+func (m *Violation) XXX_Size() int {
+	return xxx_messageInfo_Violation.Size(m)
+}
+func (m *Violation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Violation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Violation proto.InternalMessageInfo
+
+func (m *Violation) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Violation) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type InvalidConfig struct {
+	Id                   string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Violations           []*Violation `protobuf:"bytes,2,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *InvalidConfig) Reset()         { *m = InvalidConfig{} }
+func (m *InvalidConfig) String() string { return proto.CompactTextString(m) }
+func (*InvalidConfig) ProtoMessage()    {}
+func (*InvalidConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{11}
+}
+func (m *InvalidConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvalidConfig.Unmarshal(m, b)
+}
+func (m *InvalidConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvalidConfig.Marshal(b, m, deterministic)
+}
+func (m *InvalidConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvalidConfig.Merge(m, src)
+}
+func (m *InvalidConfig) XXX_Size() int {
+	return xxx_messageInfo_InvalidConfig.Size(m)
+}
+func (m *InvalidConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvalidConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvalidConfig proto.InternalMessageInfo
+
+func (m *InvalidConfig) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvalidConfig) GetViolations() []*Violation {
+	if m != nil {
+		return m.Violations
+	}
+	return nil
+}
+
+type SchemaCheck struct {
+	Group                string           `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Valid                bool             `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	Checked              int32            `protobuf:"varint,3,opt,name=checked,proto3" json:"checked,omitempty"`
+	Invalid              []*InvalidConfig `protobuf:"bytes,4,rep,name=invalid,proto3" json:"invalid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SchemaCheck) Reset()         { *m = SchemaCheck{} }
+func (m *SchemaCheck) String() string { return proto.CompactTextString(m) }
+func (*SchemaCheck) ProtoMessage()    {}
+func (*SchemaCheck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e10f4c9b19ad8eee, []int{12}
+}
+func (m *SchemaCheck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SchemaCheck.Unmarshal(m, b)
+}
+func (m *SchemaCheck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SchemaCheck.Marshal(b, m, deterministic)
+}
+func (m *SchemaCheck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaCheck.Merge(m, src)
+}
+func (m *SchemaCheck) XXX_Size() int {
+	return xxx_messageInfo_SchemaCheck.Size(m)
+}
+func (m *SchemaCheck) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaCheck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SchemaCheck proto.InternalMessageInfo
+
+func (m *SchemaCheck) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *SchemaCheck) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *SchemaCheck) GetChecked() int32 {
+	if m != nil {
+		return m.Checked
+	}
+	return 0
+}
+
+func (m *SchemaCheck) GetInvalid() []*InvalidConfig {
+	if m != nil {
+		return m.Invalid
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Group)(nil), "grpc.Group")
+	proto.RegisterType((*Defaults)(nil), "grpc.Defaults")
+	proto.RegisterType((*GroupSummary)(nil), "grpc.GroupSummary")
 	proto.RegisterType((*StoreGroupRequest)(nil), "grpc.StoreGroupRequest")
 	proto.RegisterType((*RetrieveGroupRequest)(nil), "grpc.RetrieveGroupRequest")
+	proto.RegisterType((*ListGroupsRequest)(nil), "grpc.ListGroupsRequest")
+	proto.RegisterType((*ListGroupsResponse)(nil), "grpc.ListGroupsResponse")
+	proto.RegisterType((*StoreSchemaRequest)(nil), "grpc.StoreSchemaRequest")
+	proto.RegisterType((*StoreDefaultsRequest)(nil), "grpc.StoreDefaultsRequest")
+	proto.RegisterType((*CheckSchemaRequest)(nil), "grpc.CheckSchemaRequest")
+	proto.RegisterType((*Violation)(nil), "grpc.Violation")
+	proto.RegisterType((*InvalidConfig)(nil), "grpc.InvalidConfig")
+	proto.RegisterType((*SchemaCheck)(nil), "grpc.SchemaCheck")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -167,6 +755,10 @@ const _ = grpc.SupportPackageIsVersion4
 type GroupServiceClient interface {
 	StoreGroup(ctx context.Context, in *StoreGroupRequest, opts ...grpc.CallOption) (*Group, error)
 	RetrieveGroup(ctx context.Context, in *RetrieveGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	StoreSchema(ctx context.Context, in *StoreSchemaRequest, opts ...grpc.CallOption) (*Group, error)
+	StoreDefaults(ctx context.Context, in *StoreDefaultsRequest, opts ...grpc.CallOption) (*Group, error)
+	CheckSchema(ctx context.Context, in *CheckSchemaRequest, opts ...grpc.CallOption) (*SchemaCheck, error)
 }
 
 type groupServiceClient struct {
@@ -195,10 +787,50 @@ func (c *groupServiceClient) RetrieveGroup(ctx context.Context, in *RetrieveGrou
 	return out, nil
 }
 
+func (c *groupServiceClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, "/grpc.GroupService/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) StoreSchema(ctx context.Context, in *StoreSchemaRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/grpc.GroupService/StoreSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) StoreDefaults(ctx context.Context, in *StoreDefaultsRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/grpc.GroupService/StoreDefaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) CheckSchema(ctx context.Context, in *CheckSchemaRequest, opts ...grpc.CallOption) (*SchemaCheck, error) {
+	out := new(SchemaCheck)
+	err := c.cc.Invoke(ctx, "/grpc.GroupService/CheckSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GroupServiceServer is the Handler API for GroupService service.
 type GroupServiceServer interface {
 	StoreGroup(context.Context, *StoreGroupRequest) (*Group, error)
 	RetrieveGroup(context.Context, *RetrieveGroupRequest) (*Group, error)
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	StoreSchema(context.Context, *StoreSchemaRequest) (*Group, error)
+	StoreDefaults(context.Context, *StoreDefaultsRequest) (*Group, error)
+	CheckSchema(context.Context, *CheckSchemaRequest) (*SchemaCheck, error)
 }
 
 func RegisterGroupServiceServer(s *grpc.Server, srv GroupServiceServer) {
@@ -241,6 +873,78 @@ func _GroupService_RetrieveGroup_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GroupService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.GroupService/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_StoreSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).StoreSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.GroupService/StoreSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).StoreSchema(ctx, req.(*StoreSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_StoreDefaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreDefaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).StoreDefaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.GroupService/StoreDefaults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).StoreDefaults(ctx, req.(*StoreDefaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_CheckSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).CheckSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.GroupService/CheckSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).CheckSchema(ctx, req.(*CheckSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _GroupService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "grpc.GroupService",
 	HandlerType: (*GroupServiceServer)(nil),
@@ -253,6 +957,22 @@ var _GroupService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RetrieveGroup",
 			Handler:    _GroupService_RetrieveGroup_Handler,
 		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _GroupService_ListGroups_Handler,
+		},
+		{
+			MethodName: "StoreSchema",
+			Handler:    _GroupService_StoreSchema_Handler,
+		},
+		{
+			MethodName: "StoreDefaults",
+			Handler:    _GroupService_StoreDefaults_Handler,
+		},
+		{
+			MethodName: "CheckSchema",
+			Handler:    _GroupService_CheckSchema_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "group.proto",
@@ -261,17 +981,46 @@ var _GroupService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("group.proto", fileDescriptor_e10f4c9b19ad8eee) }
 
 var fileDescriptor_e10f4c9b19ad8eee = []byte{
-	// 179 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4e, 0x2f, 0xca, 0x2f,
-	0x2d, 0xd0, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x49, 0x2f, 0x2a, 0x48, 0x56, 0x32, 0xe3,
-	0x62, 0x75, 0x07, 0x09, 0x0a, 0xf1, 0x71, 0x31, 0x65, 0xa6, 0x48, 0x30, 0x2a, 0x30, 0x6a, 0x70,
-	0x06, 0x31, 0x65, 0xa6, 0x08, 0xc9, 0x72, 0x71, 0x25, 0xe7, 0xe7, 0xa5, 0x65, 0xa6, 0xc7, 0x67,
-	0xa6, 0x14, 0x4b, 0x30, 0x29, 0x30, 0x6b, 0x70, 0x06, 0x71, 0x42, 0x44, 0x3c, 0x53, 0x8a, 0x95,
-	0x94, 0xb9, 0x04, 0x83, 0x4b, 0xf2, 0x8b, 0x52, 0xc1, 0x9a, 0x83, 0x52, 0x0b, 0x4b, 0x53, 0x8b,
-	0x4b, 0xd0, 0xcd, 0x50, 0x52, 0xe3, 0x12, 0x09, 0x4a, 0x2d, 0x29, 0xca, 0x4c, 0x2d, 0xc3, 0xab,
-	0xce, 0xa8, 0x86, 0x8b, 0x07, 0x2c, 0x1f, 0x9c, 0x5a, 0x54, 0x96, 0x99, 0x9c, 0x2a, 0x64, 0xc4,
-	0xc5, 0x85, 0x30, 0x5c, 0x48, 0x5c, 0x0f, 0xe4, 0x52, 0x3d, 0x0c, 0xeb, 0xa4, 0xb8, 0x21, 0x12,
-	0x10, 0x55, 0x16, 0x5c, 0xbc, 0x28, 0x76, 0x09, 0x49, 0x41, 0x64, 0xb1, 0x39, 0x00, 0x45, 0x67,
-	0x12, 0x1b, 0x38, 0x3c, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x52, 0xf9, 0x07, 0x93, 0x1e,
-	0x01, 0x00, 0x00,
+	// 650 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x95, 0x9d, 0x8f, 0x26, 0xe3, 0xb4, 0xa8, 0x43, 0x55, 0x4c, 0x24, 0x20, 0xf8, 0x14, 0x55,
+	0xa2, 0x48, 0x81, 0x03, 0x70, 0xe1, 0x10, 0xa4, 0xaa, 0x12, 0x07, 0xb4, 0x45, 0x88, 0x4b, 0x55,
+	0x19, 0x7b, 0x9b, 0xae, 0x48, 0x6c, 0xb3, 0xbb, 0x8e, 0xda, 0x2b, 0x7f, 0x8a, 0xdf, 0xc4, 0xbf,
+	0x40, 0xfb, 0x95, 0x6c, 0xea, 0x54, 0x39, 0x70, 0xf3, 0xcc, 0xee, 0xce, 0x7b, 0xf3, 0xe6, 0x4d,
+	0x02, 0xd1, 0x8c, 0x97, 0x75, 0x75, 0x5a, 0xf1, 0x52, 0x96, 0xd8, 0x9e, 0xf1, 0x2a, 0x4b, 0xfe,
+	0x04, 0xd0, 0x39, 0x53, 0x59, 0x3c, 0x80, 0x90, 0xe5, 0x71, 0x30, 0x0a, 0xc6, 0x7d, 0x12, 0xb2,
+	0x1c, 0x9f, 0x01, 0x64, 0x65, 0x71, 0xcd, 0x66, 0x57, 0x2c, 0x17, 0x71, 0x38, 0x6a, 0x8d, 0xfb,
+	0xa4, 0x6f, 0x32, 0xe7, 0xb9, 0xc0, 0x21, 0xf4, 0x38, 0x5d, 0x32, 0xc1, 0xca, 0x22, 0x6e, 0x8d,
+	0x82, 0x71, 0x8b, 0xac, 0x62, 0x7c, 0x01, 0x51, 0x41, 0x6f, 0xe5, 0x55, 0x56, 0x73, 0x51, 0xf2,
+	0xb8, 0xad, 0x6b, 0x82, 0x4a, 0x4d, 0x75, 0x06, 0x8f, 0xa1, 0x2b, 0xb2, 0x1b, 0xba, 0x48, 0xe3,
+	0xce, 0x28, 0x18, 0x0f, 0x88, 0x8d, 0xf0, 0x04, 0x7a, 0x39, 0xbd, 0x4e, 0xeb, 0xb9, 0x14, 0x71,
+	0x77, 0x14, 0x8c, 0xa3, 0xc9, 0xc1, 0xa9, 0xa2, 0x79, 0xfa, 0xc9, 0x66, 0xc9, 0xea, 0x3c, 0xf9,
+	0x0e, 0x3d, 0x97, 0xc5, 0xe7, 0x00, 0x15, 0x2f, 0x2b, 0xca, 0x25, 0xa3, 0x42, 0xf7, 0x30, 0x20,
+	0x5e, 0x46, 0xe1, 0xa5, 0x9c, 0xa7, 0x77, 0xaa, 0x0f, 0xc5, 0xc5, 0x46, 0x78, 0x04, 0x9d, 0xa2,
+	0x9e, 0xcf, 0x85, 0xee, 0xa0, 0x4f, 0x4c, 0x90, 0x5c, 0xc2, 0x40, 0x4b, 0x72, 0x51, 0x2f, 0x16,
+	0x29, 0xbf, 0x6b, 0x28, 0xe3, 0xb7, 0x1e, 0xde, 0x6b, 0xfd, 0x25, 0x0c, 0xac, 0x6a, 0x59, 0x59,
+	0x17, 0x52, 0x17, 0xee, 0x90, 0xc8, 0xe4, 0xa6, 0x2a, 0x95, 0xcc, 0xe0, 0xf0, 0x42, 0x96, 0x9c,
+	0x6a, 0x0c, 0x42, 0x7f, 0xd5, 0x54, 0xc8, 0x06, 0xc6, 0x5a, 0xa1, 0xf0, 0x41, 0x85, 0x5a, 0x3b,
+	0x14, 0xfa, 0x0a, 0x47, 0x84, 0x4a, 0xce, 0xe8, 0x72, 0x27, 0x96, 0x9d, 0x94, 0x55, 0xc7, 0x44,
+	0x4a, 0x9d, 0x39, 0x5b, 0x30, 0xd7, 0x84, 0x09, 0x92, 0x05, 0x1c, 0x7e, 0x66, 0x42, 0xea, 0x8a,
+	0xc2, 0x95, 0x3c, 0x86, 0x6e, 0xc5, 0xe9, 0x35, 0xbb, 0xb5, 0x65, 0x6d, 0x84, 0x08, 0x6d, 0x51,
+	0x72, 0x69, 0x0b, 0xeb, 0x6f, 0x0f, 0xae, 0xb5, 0x1d, 0xae, 0xed, 0xc3, 0xa5, 0x80, 0x3e, 0x9c,
+	0xa8, 0xca, 0x42, 0x50, 0x3c, 0x81, 0xae, 0xf6, 0xb2, 0x1a, 0x76, 0x6b, 0x1c, 0x4d, 0xd0, 0x88,
+	0xe0, 0x8f, 0x8d, 0xd8, 0x1b, 0xf7, 0xdd, 0x18, 0xde, 0x77, 0x63, 0x72, 0x06, 0xa8, 0x07, 0x72,
+	0xa1, 0x25, 0x76, 0x2d, 0x3d, 0x85, 0x9e, 0x2e, 0x70, 0xb5, 0xd2, 0x6a, 0x4f, 0xc7, 0xe7, 0x0f,
+	0x0e, 0x27, 0xb9, 0x84, 0x23, 0x5d, 0x68, 0x35, 0x8b, 0xdd, 0xa5, 0xfc, 0x79, 0x86, 0x3b, 0xe6,
+	0x79, 0x06, 0x38, 0xbd, 0xa1, 0xd9, 0xcf, 0xff, 0xe6, 0xf9, 0x1e, 0xfa, 0xdf, 0x58, 0x39, 0x4f,
+	0xa5, 0x72, 0x2c, 0x42, 0xbb, 0x4a, 0xe5, 0x8d, 0x7d, 0xab, 0xbf, 0x31, 0x86, 0xbd, 0x05, 0x15,
+	0x22, 0x9d, 0x51, 0x2b, 0x97, 0x0b, 0x93, 0x2f, 0xb0, 0x7f, 0x5e, 0x2c, 0xd3, 0x39, 0xcb, 0xa7,
+	0xda, 0xd2, 0x0d, 0x33, 0xbd, 0x06, 0x58, 0xba, 0xda, 0xe6, 0x67, 0x23, 0x9a, 0x3c, 0x32, 0x2d,
+	0xad, 0x30, 0x89, 0x77, 0x25, 0xf9, 0x1d, 0x40, 0x64, 0x3a, 0xd2, 0xcd, 0x29, 0x1b, 0x68, 0xfe,
+	0xb6, 0xa6, 0x09, 0x54, 0x56, 0xa3, 0x6a, 0x3e, 0x3d, 0x62, 0x02, 0xc5, 0x33, 0x53, 0x8f, 0x68,
+	0x6e, 0x3d, 0xea, 0x42, 0x7c, 0x05, 0x7b, 0xcc, 0xf0, 0x8c, 0xdb, 0x9a, 0xc3, 0x63, 0xc3, 0x61,
+	0x83, 0x3c, 0x71, 0x77, 0x26, 0x7f, 0x43, 0xb7, 0xf3, 0x94, 0x2f, 0x59, 0x46, 0x71, 0x02, 0xb0,
+	0x5e, 0x52, 0x7c, 0x62, 0x1e, 0x37, 0xd6, 0x76, 0x18, 0x79, 0xbe, 0xc3, 0x77, 0xb0, 0xbf, 0xb1,
+	0x6f, 0x38, 0x34, 0xa7, 0xdb, 0x96, 0x70, 0xf3, 0xe5, 0x47, 0x80, 0xb5, 0xc9, 0x1d, 0x5a, 0x63,
+	0xcb, 0x86, 0x71, 0xf3, 0xc0, 0xee, 0xc3, 0x5b, 0x88, 0x3c, 0x0b, 0x63, 0xec, 0xf1, 0xdd, 0x70,
+	0x4b, 0x83, 0xf0, 0x86, 0x5f, 0x1d, 0xe1, 0x6d, 0x26, 0xde, 0x7c, 0xf9, 0x01, 0x22, 0xcf, 0x8a,
+	0x0e, 0xaf, 0xe9, 0xce, 0xe1, 0xa1, 0xad, 0xb8, 0x1e, 0xf0, 0x8f, 0xae, 0xfe, 0xff, 0x79, 0xf3,
+	0x6f, 0x00, 0x53, 0x30, 0xd2, 0x3f, 0x8e, 0x06, 0x00, 0x00,
 }
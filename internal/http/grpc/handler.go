@@ -2,8 +2,16 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/http/grpc/rpcstatus"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
+	goGrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"time"
 )
 
@@ -11,45 +19,140 @@ import (
 type Handler struct {
 	adding  adding.Service
 	listing listing.Service
+	signer  *signing.Signer
 }
 
-// NewHandler returns a new Handler
-func NewHandler(adding adding.Service, listing listing.Service) *Handler {
+// NewHandler returns a new Handler. Configs are signed with signer, unless it is nil.
+func NewHandler(adding adding.Service, listing listing.Service, signer *signing.Signer) *Handler {
 	return &Handler{
 		adding:  adding,
 		listing: listing,
+		signer:  signer,
 	}
 }
 
 // StoreGroup maps a request to a group object and stores it in the repository. Subsequently fetches the object and returns it
 // to the caller.
 func (s *Handler) StoreGroup(ctx context.Context, req *StoreGroupRequest) (*Group, error) {
-	addGrp := adding.Group{ID: req.Id}
+	addGrp := adding.Group{ID: req.Id, Schema: req.Schema, Defaults: addingDefaults(req.Defaults)}
 
 	if err := s.adding.AddGroup(addGrp); err != nil {
-		return &Group{}, err
+		return &Group{}, rpcstatus.Error(err)
 	}
 
-	return s.retrieveGroup(req.Id)
+	return s.retrieveGroup(req.Id, listing.Page{})
 }
 
-// RetrieveGroup fetches a group object from repository and maps it to a gRPC response
+// RetrieveGroup fetches a group object from repository and maps it to a gRPC response. Config ids are paginated.
 func (s *Handler) RetrieveGroup(ctx context.Context, req *RetrieveGroupRequest) (*Group, error) {
-	return s.retrieveGroup(req.Id)
+	return s.retrieveGroup(req.Id, listing.Page{Cursor: req.Cursor, Limit: int(req.Limit)})
 }
 
-func (s *Handler) retrieveGroup(groupID string) (*Group, error) {
-	grp, err := s.listing.GetGroup(groupID)
+func (s *Handler) retrieveGroup(groupID string, page listing.Page) (*Group, error) {
+	grp, err := s.listing.GetPagedGroup(groupID, page)
 	if err != nil {
-		return &Group{}, err
+		return &Group{}, rpcstatus.Error(err)
 	}
 
 	return &Group{
-		Id:        grp.ID,
-		ConfigIds: grp.Configs,
+		Id:         grp.ID,
+		ConfigIds:  grp.Configs,
+		Revision:   grp.Revision,
+		NextCursor: grp.NextCursor,
+		Schema:     grp.Schema,
+		Defaults:   mapDefaults(grp.Defaults),
 	}, nil
 }
 
+// StoreDefaults replaces the defaults of a group and returns the updated group
+func (s *Handler) StoreDefaults(ctx context.Context, req *StoreDefaultsRequest) (*Group, error) {
+	if err := s.adding.SetDefaults(req.GroupId, addingDefaults(req.Defaults)); err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	return s.retrieveGroup(req.GroupId, listing.Page{})
+}
+
+func mapDefaults(d *listing.Defaults) *Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &Defaults{
+		Properties: secret.Masked(d.Properties),
+		Arrays:     string(d.Merge.Arrays),
+		Nulls:      string(d.Merge.Nulls),
+	}
+}
+
+func addingDefaults(d *Defaults) *adding.Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &adding.Defaults{
+		Properties: d.Properties,
+		Merge:      properties.MergeRules{Arrays: properties.ArrayMerge(d.Arrays), Nulls: properties.NullMerge(d.Nulls)},
+	}
+}
+
+// StoreSchema replaces the schema of a group and returns the updated group
+func (s *Handler) StoreSchema(ctx context.Context, req *StoreSchemaRequest) (*Group, error) {
+	if err := s.adding.SetSchema(req.GroupId, req.Schema); err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	return s.retrieveGroup(req.GroupId, listing.Page{})
+}
+
+// CheckSchema checks the configs of a group against a schema and maps the result to a gRPC response
+func (s *Handler) CheckSchema(ctx context.Context, req *CheckSchemaRequest) (*SchemaCheck, error) {
+	check, err := s.listing.CheckSchema(req.GroupId, req.Schema)
+	if err != nil {
+		return &SchemaCheck{}, rpcstatus.Error(err)
+	}
+
+	res := &SchemaCheck{
+		Group:   check.Group,
+		Valid:   check.Valid,
+		Checked: int32(check.Checked),
+	}
+	for _, c := range check.Invalid {
+		invalid := &InvalidConfig{Id: c.ID}
+		for _, v := range c.Violations {
+			invalid.Violations = append(invalid.Violations, &Violation{Path: v.Path, Message: v.Message})
+		}
+		res.Invalid = append(res.Invalid, invalid)
+	}
+
+	return res, nil
+}
+
+// ListGroups fetches a page of groups from repository and maps it to a gRPC response
+func (s *Handler) ListGroups(ctx context.Context, req *ListGroupsRequest) (*ListGroupsResponse, error) {
+	query := listing.GroupQuery{
+		Prefix: req.Prefix,
+		Sort:   req.Sort,
+		Page:   listing.Page{Cursor: req.Cursor, Limit: int(req.Limit)},
+	}
+
+	grps, err := s.listing.ListGroups(query)
+	if err != nil {
+		return &ListGroupsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListGroupsResponse{NextCursor: grps.NextCursor}
+	for _, g := range grps.Groups {
+		res.Groups = append(res.Groups, &GroupSummary{
+			Id:          g.ID,
+			Revision:    g.Revision,
+			ConfigCount: int32(g.ConfigCount),
+		})
+	}
+
+	return res, nil
+}
+
 // StoreConfig maps a request to a config object and stores it in the repository. Subsequently fetches the object and returns it
 // to the caller.
 func (s *Handler) StoreConfig(ctx context.Context, req *StoreConfigRequest) (*Config, error) {
@@ -58,33 +161,177 @@ func (s *Handler) StoreConfig(ctx context.Context, req *StoreConfigRequest) (*Co
 		Name:         req.Name,
 		LastModified: time.Now(),
 		Group:        req.Group,
+		Parent:       req.Parent,
 		Properties:   req.Properties,
 	}
 
 	if err := s.adding.AddConfig(addConf); err != nil {
-		return &Config{}, err
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	conf, err := s.retrieveConfig(req.Group, req.Id, false, "", false)
+	if err != nil {
+		return conf, err
 	}
 
-	return s.retrieveConfig(req.Group, req.Id)
+	return s.sign(ctx, conf)
 }
 
-// RetrieveConfig fetches a config object from repository and maps it to a gRPC response
+// RetrieveConfig fetches a config object from repository and maps it to a gRPC response. Properties are the effective
+// properties of the config unless the raw config is requested, with the overlay of the requested environment merged onto
+// them and references resolved.
 func (s *Handler) RetrieveConfig(ctx context.Context, req *RetrieveConfigRequest) (*Config, error) {
-	return s.retrieveConfig(req.GroupId, req.Id)
+	conf, err := s.retrieveConfig(req.GroupId, req.Id, req.Raw, req.Environment, !req.Raw && !req.Unresolved)
+	if err != nil {
+		return conf, err
+	}
+
+	return s.sign(ctx, conf)
 }
 
-func (s *Handler) retrieveConfig(groupID string, configID string) (*Config, error) {
-	conf, err := s.listing.GetConfig(groupID, configID)
+// sign sets the signature of a config as it is returned in the trailer of the call, if configs are signed
+func (s *Handler) sign(ctx context.Context, conf *Config) (*Config, error) {
+	if s.signer == nil {
+		return conf, nil
+	}
+
+	sig, err := s.signer.Sign(signing.Config{ID: conf.Id, Group: conf.Group, Version: int(conf.Version), Properties: conf.Properties})
 	if err != nil {
-		return &Config{}, err
+		return &Config{}, rpcstatus.Error(err)
 	}
 
-	return &Config{
+	if err := goGrpc.SetTrailer(ctx, metadata.Pairs(signing.SignatureTrailer, sig, signing.KeyIDTrailer, s.signer.KeyID())); err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	return conf, nil
+}
+
+// retrieveConfig fetches a config and maps it to a gRPC response, resolving its references first if resolve is set. Secret
+// values are masked after references are resolved, so the secret values of referenced configs are masked too.
+func (s *Handler) retrieveConfig(groupID string, configID string, raw bool, env string, resolve bool) (*Config, error) {
+	get := s.listing.GetConfig
+	switch {
+	case raw && env != "":
+		return &Config{}, rpcstatus.Error(domain.New(domain.InvalidArgument, listing.ConfigResource, "raw configs cannot be retrieved in an environment"))
+	case raw:
+		get = s.listing.GetRawConfig
+	case env != "":
+		get = func(grp string, id string) (*listing.Config, error) {
+			return s.listing.GetEnvironmentConfig(grp, id, env)
+		}
+	}
+
+	conf, err := get(groupID, configID)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	if resolve {
+		resolved := *conf
+		ref := listing.ConfigRef{Group: groupID, ID: configID}
+		if resolved.Properties, err = s.listing.ResolveReferences(ref, nil, conf.Properties); err != nil {
+			return &Config{}, rpcstatus.Error(err)
+		}
+		conf = &resolved
+	}
+
+	return mapConfig(conf), nil
+}
+
+// SearchConfigs finds configs matching all conditions in the request and maps them to a gRPC response
+func (s *Handler) SearchConfigs(ctx context.Context, req *SearchConfigsRequest) (*SearchConfigsResponse, error) {
+	query := listing.Query{
+		Group: req.Group,
+		Page:  listing.Page{Cursor: req.Cursor, Limit: int(req.Limit)},
+	}
+
+	for _, c := range req.Conditions {
+		cond := listing.Condition{Path: c.Path, Operator: listing.Operator(c.Operator)}
+		if len(c.Value) > 0 {
+			if err := json.Unmarshal(c.Value, &cond.Value); err != nil {
+				return &SearchConfigsResponse{}, rpcstatus.Error(listing.InvalidQueryError("value is not valid JSON"))
+			}
+		}
+		query.Conditions = append(query.Conditions, cond)
+	}
+
+	confs, err := s.listing.SearchConfigs(query)
+	if err != nil {
+		return &SearchConfigsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &SearchConfigsResponse{NextCursor: confs.NextCursor}
+	for i := range confs.Configs {
+		res.Configs = append(res.Configs, mapConfig(&confs.Configs[i]))
+	}
+
+	return res, nil
+}
+
+// SearchText does a full-text search for configs and maps the ranked matches to a gRPC response
+func (s *Handler) SearchText(ctx context.Context, req *SearchTextRequest) (*SearchTextResponse, error) {
+	matches, err := s.listing.SearchText(listing.TextQuery{
+		Text:  req.Text,
+		Group: req.Group,
+		Limit: int(req.Limit),
+	})
+	if err != nil {
+		return &SearchTextResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &SearchTextResponse{}
+	for _, m := range matches.Matches {
+		match := &TextMatch{
+			Group: m.Group,
+			Id:    m.ID,
+			Score: m.Score,
+		}
+		for _, snippet := range m.Snippets {
+			match.Snippets = append(match.Snippets, &Snippet{Field: snippet.Field, Text: snippet.Text})
+		}
+		res.Matches = append(res.Matches, match)
+	}
+
+	return res, nil
+}
+
+func mapConfig(conf *listing.Config) *Config {
+	res := &Config{
 		Id:           conf.ID,
 		Name:         conf.Name,
 		LastModified: conf.LastModified.Unix(),
 		Version:      int32(conf.Version),
 		Group:        conf.Group,
-		Properties:   conf.Properties,
-	}, nil
+		Properties:   secret.Masked(conf.Properties),
+		Revision:     conf.Revision,
+		Parent:       conf.Parent,
+	}
+	if conf.Environment != nil {
+		res.Environment = conf.Environment.Name
+	}
+
+	return res
+}
+
+// ListChanges fetches all changes after the requested revision and maps them to a gRPC response
+func (s *Handler) ListChanges(ctx context.Context, req *ListChangesRequest) (*ListChangesResponse, error) {
+	changes, err := s.listing.ListChanges(req.Since)
+	if err != nil {
+		return &ListChangesResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListChangesResponse{Revision: changes.Revision}
+	for _, c := range changes.Changes {
+		res.Changes = append(res.Changes, &Change{
+			Revision:    c.Revision,
+			Resource:    c.Resource,
+			Group:       c.Group,
+			Id:          c.ID,
+			Deleted:     c.Deleted,
+			Environment: c.Environment,
+		})
+	}
+
+	return res, nil
 }
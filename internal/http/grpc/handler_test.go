@@ -1,21 +1,41 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/http/auth"
 	"github.com/larwef/ki/internal/listing"
 	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/secret"
 	"github.com/larwef/ki/test"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"io/ioutil"
+	"log"
+	"os"
+	"strings"
 	"testing"
 )
 
 var testDataFolder = "../../../test/testdata/"
 
+// assertStatus asserts that err is a status error with the code and message
+func assertStatus(t *testing.T, err error, code codes.Code, msg string) {
+	st, ok := status.FromError(err)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, st.Code(), code)
+	test.AssertEqual(t, st.Message(), msg)
+}
+
 func TestHandler_StoreGroup(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	req := &StoreGroupRequest{Id: "someGroup"}
 	ctx := context.Background()
@@ -28,19 +48,19 @@ func TestHandler_StoreGroup(t *testing.T) {
 
 func TestHandler_StoreGroup_Duplicate(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	req := &StoreGroupRequest{Id: "someGroup"}
 	ctx := context.Background()
 	handler.StoreGroup(ctx, req)
 	_, err := handler.StoreGroup(ctx, req)
 
-	test.AssertEqual(t, err, adding.ErrGroupConflict)
+	assertStatus(t, err, codes.AlreadyExists, adding.ErrGroupConflict.Error())
 }
 
 func TestHandler_RetrieveGroup(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID:      "someGroup",
@@ -58,7 +78,7 @@ func TestHandler_RetrieveGroup(t *testing.T) {
 
 func TestHandler_RetrieveGroup_GroupNotFound(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID:      "someGroup",
@@ -69,12 +89,64 @@ func TestHandler_RetrieveGroup_GroupNotFound(t *testing.T) {
 	req := &RetrieveGroupRequest{Id: "someOtherGroup"}
 	_, err := handler.RetrieveGroup(ctx, req)
 
-	test.AssertEqual(t, err, listing.ErrGroupNotFound)
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
+
+	st, _ := status.FromError(err)
+	resourceInfo, ok := st.Details()[0].(*errdetails.ResourceInfo)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, resourceInfo.ResourceType, listing.GroupResource)
+}
+
+func TestHandler_RetrieveGroup_Paginated(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID:      "someGroup",
+		Configs: []string{"config3", "config1", "config2"},
+	})
+
+	ctx := context.Background()
+	req := &RetrieveGroupRequest{Id: "someGroup", Limit: 2}
+	res, err := handler.RetrieveGroup(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.ConfigIds), 2)
+	test.AssertEqual(t, res.ConfigIds[0], "config1")
+
+	req.Cursor = res.NextCursor
+	res, err = handler.RetrieveGroup(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.ConfigIds), 1)
+	test.AssertEqual(t, res.ConfigIds[0], "config3")
+	test.AssertEqual(t, res.NextCursor, "")
+}
+
+func TestHandler_ListGroups(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	for _, id := range []string{"someGroup", "anotherGroup", "someOtherGroup"} {
+		repository.StoreGroup(adding.Group{ID: id})
+	}
+	repository.StoreConfig(adding.Config{ID: "someId", Group: "someOtherGroup"})
+
+	ctx := context.Background()
+	req := &ListGroupsRequest{Prefix: "some", Sort: "-revision"}
+	res, err := handler.ListGroups(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Groups), 2)
+	test.AssertEqual(t, res.Groups[0].Id, "someOtherGroup")
+	test.AssertEqual(t, res.Groups[0].ConfigCount, int32(1))
+	test.AssertEqual(t, res.Groups[1].Id, "someGroup")
+	test.AssertEqual(t, res.NextCursor, "")
 }
 
 func TestHandler_StoreConfig(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID: "someGroup",
@@ -108,7 +180,7 @@ func TestHandler_StoreConfig(t *testing.T) {
 
 func TestHandler_StoreConfig_GroupNotFound(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID: "someGroup",
@@ -125,12 +197,140 @@ func TestHandler_StoreConfig_GroupNotFound(t *testing.T) {
 	}
 	_, err = handler.StoreConfig(ctx, req)
 
-	test.AssertEqual(t, err, listing.ErrGroupNotFound)
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
+}
+
+func TestHandler_StoreConfig_ApprovalRequired(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+	repository.StoreApprovers("someGroup", []string{"bob"})
+
+	ctx := context.Background()
+	_, err := handler.StoreConfig(ctx, &StoreConfigRequest{
+		Id:         "someId",
+		Group:      "someGroup",
+		Properties: []byte(`{"host":"db1"}`),
+	})
+
+	assertStatus(t, err, codes.FailedPrecondition, adding.ErrApprovalRequired.Error())
+}
+
+func TestHandler_StoreConfig_SchemaViolation(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	ctx := context.Background()
+	_, err := handler.StoreGroup(ctx, &StoreGroupRequest{
+		Id:     "someGroup",
+		Schema: []byte(test.GetTestFileAsString(t, testDataFolder+"schemaExample.json")),
+	})
+	test.AssertNotError(t, err)
+
+	properties, err := ioutil.ReadFile(testDataFolder + "properties.json")
+	test.AssertNotError(t, err)
+	req := &StoreConfigRequest{
+		Id:         "someId",
+		Name:       "someName",
+		Group:      "someGroup",
+		Properties: properties,
+	}
+	_, err = handler.StoreConfig(ctx, req)
+
+	st, ok := status.FromError(err)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, st.Code(), codes.InvalidArgument)
+	test.AssertEqual(t, len(st.Details()), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, len(badRequest.FieldViolations), 1)
+	test.AssertEqual(t, badRequest.FieldViolations[0].Field, "/properties/property1")
+	test.AssertEqual(t, badRequest.FieldViolations[0].Description, "expected a value <= 10 but got 12")
+}
+
+func TestHandler_CheckSchema(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	var c adding.Config
+	test.UnmarshalJSONFromFile(t, testDataFolder+"configExample.json", &c)
+	repository.StoreConfig(c)
+
+	ctx := context.Background()
+	res, err := handler.CheckSchema(ctx, &CheckSchemaRequest{
+		GroupId: "someGroup",
+		Schema:  []byte(`{"properties": {"property1": {"maximum": 20}}}`),
+	})
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Valid, true)
+	test.AssertEqual(t, res.Checked, int32(1))
+
+	_, err = handler.StoreSchema(ctx, &StoreSchemaRequest{
+		GroupId: "someGroup",
+		Schema:  []byte(test.GetTestFileAsString(t, testDataFolder+"schemaExample.json")),
+	})
+	test.AssertNotError(t, err)
+
+	res, err = handler.CheckSchema(ctx, &CheckSchemaRequest{GroupId: "someGroup"})
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Valid, false)
+	test.AssertEqual(t, len(res.Invalid), 1)
+	test.AssertEqual(t, res.Invalid[0].Id, "someId")
+	test.AssertEqual(t, res.Invalid[0].Violations[0].Path, "/property1")
+}
+
+func TestHandler_StoreConfig_InvalidID(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	ctx := context.Background()
+	_, err := handler.StoreConfig(ctx, &StoreConfigRequest{Id: "../someId", Group: "someGroup"})
+
+	st, ok := status.FromError(err)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, st.Code(), codes.InvalidArgument)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, badRequest.FieldViolations[0].Field, "id")
+}
+
+func TestHandler_CheckSchema_NoSchema(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	ctx := context.Background()
+	_, err := handler.CheckSchema(ctx, &CheckSchemaRequest{GroupId: "someGroup"})
+
+	assertStatus(t, err, codes.FailedPrecondition, listing.ErrNoSchema.Error())
+
+	st, _ := status.FromError(err)
+	preconditionFailure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, preconditionFailure.Violations[0].Type, listing.SchemaResource)
 }
 
 func TestHandler_RetrieveConfig(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID: "someGroup",
@@ -162,9 +362,103 @@ func TestHandler_RetrieveConfig(t *testing.T) {
 	test.AssertEqual(t, propMap["property5"], 12.1)
 }
 
+func TestHandler_RetrieveConfig_References(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+	repository.StoreGroup(adding.Group{ID: "shared"})
+	repository.StoreGroup(adding.Group{ID: "someGroup"})
+	repository.StoreConfig(adding.Config{ID: "database", Group: "shared", Properties: []byte(`{"host":"db1"}`)})
+	repository.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"${ref:shared/database#host}"}`)})
+
+	// References to other groups are resolved for calls without credentials too
+	res, err := handler.RetrieveConfig(context.Background(), &RetrieveConfigRequest{GroupId: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res.Properties), `{"host":"db1"}`)
+
+	res, err = handler.RetrieveConfig(context.Background(), &RetrieveConfigRequest{GroupId: "someGroup", Id: "someId", Unresolved: true})
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res.Properties), `{"host":"${ref:shared/database#host}"}`)
+}
+
+func TestAuthenticatingUnaryInterceptor(t *testing.T) {
+	hash, err := auth.HashPassword("clientPassword321")
+	test.AssertNotError(t, err)
+	basic := auth.NewBasic()
+	test.AssertNotError(t, basic.RegisterUser(auth.User{Username: "client", PasswordHash: hash, Role: auth.CLIENT}))
+
+	username := func(ctx context.Context) string {
+		res, err := AuthenticatingUnaryInterceptor(basic, noopInterceptor)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			user, _ := auth.FromContext(ctx)
+			return user.Username, nil
+		})
+		test.AssertNotError(t, err)
+		return res.(string)
+	}
+
+	// Calls without valid credentials are handled without a user
+	test.AssertEqual(t, username(context.Background()), "")
+	wrong := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("client:wrongPassword"))))
+	test.AssertEqual(t, username(wrong), "")
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("client:clientPassword321"))))
+	test.AssertEqual(t, username(ctx), "client")
+}
+
+func TestHandler_RetrieveConfig_ReferencedSecret(t *testing.T) {
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+	repository := memory.NewRepository()
+	add := adding.NewService(repository, adding.Secrets(keeper))
+	handler := NewHandler(add, listing.NewService(repository, listing.Secrets(keeper)), nil)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "database", Group: "someGroup", Properties: []byte(`{"password":{"$secret":"hunter2"}}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"password":"${ref:someGroup/database#password}"}`)}))
+
+	// Secret values pulled in through references are masked like the config's own
+	res, err := handler.RetrieveConfig(context.Background(), &RetrieveConfigRequest{GroupId: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res.Properties), `{"password":"********"}`)
+}
+
+func TestInOutLoggingUnaryInterceptor(t *testing.T) {
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository, adding.Secrets(keeper)), listing.NewService(repository, listing.Secrets(keeper)), nil)
+	repository.StoreGroup(adding.Group{ID: "someGroup", Schema: []byte(`{"properties":{"password":{"type":"string","secret":true}}}`)})
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	// Properties the schema marks secret are written as plain values, so the payloads of writes are not logged
+	_, err = InOutLoggingUnaryInterceptor(context.Background(), &StoreConfigRequest{Group: "someGroup", Id: "someId", Properties: []byte(`{"password":"hunter2"}`)}, &grpc.UnaryServerInfo{FullMethod: "/grpc.ConfigService/StoreConfig"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return handler.StoreConfig(ctx, req.(*StoreConfigRequest))
+	})
+	test.AssertNotError(t, err)
+
+	if strings.Contains(logs.String(), "hunter2") || strings.Count(logs.String(), secret.Omitted) != 2 {
+		t.Fatalf("Payload of write logged: %s", logs.String())
+	}
+
+	logs.Reset()
+	_, err = InOutLoggingUnaryInterceptor(context.Background(), &RetrieveConfigRequest{GroupId: "someGroup", Id: "someId"}, &grpc.UnaryServerInfo{FullMethod: "/grpc.ConfigService/RetrieveConfig"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return handler.RetrieveConfig(ctx, req.(*RetrieveConfigRequest))
+	})
+	test.AssertNotError(t, err)
+
+	if strings.Contains(logs.String(), secret.Omitted) || !strings.Contains(logs.String(), "someId") {
+		t.Fatalf("Payload of read not logged: %s", logs.String())
+	}
+}
+
+func noopInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(ctx, req)
+}
+
 func TestHandler_RetrieveConfig_GroupNotFound(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID: "someGroup",
@@ -177,12 +471,12 @@ func TestHandler_RetrieveConfig_GroupNotFound(t *testing.T) {
 	}
 	_, err := handler.RetrieveConfig(ctx, req)
 
-	test.AssertEqual(t, err, listing.ErrGroupNotFound)
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
 }
 
 func TestHandler_RetrieveConfig_ConfigNotFound(t *testing.T) {
 	repository := memory.NewRepository()
-	handler := NewHandler(adding.NewService(repository), listing.NewService(repository))
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
 
 	repository.StoreGroup(adding.Group{
 		ID: "someGroup",
@@ -195,5 +489,90 @@ func TestHandler_RetrieveConfig_ConfigNotFound(t *testing.T) {
 	}
 	_, err := handler.RetrieveConfig(ctx, req)
 
-	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+	assertStatus(t, err, codes.NotFound, listing.ErrConfigNotFound.Error())
+}
+
+func TestHandler_SearchConfigs(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	var c adding.Config
+	test.UnmarshalJSONFromFile(t, testDataFolder+"configExample.json", &c)
+	repository.StoreConfig(c)
+
+	ctx := context.Background()
+	req := &SearchConfigsRequest{
+		Conditions: []*Condition{
+			{Path: "property5", Operator: "gt", Value: []byte("12")},
+			{Path: "$.property2", Operator: "eq", Value: []byte(`"12"`)},
+		},
+	}
+	res, err := handler.SearchConfigs(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Configs), 1)
+	test.AssertEqual(t, res.Configs[0].Id, "someId")
+	test.AssertEqual(t, res.Configs[0].Group, "someGroup")
+
+	req.Conditions[1].Value = []byte("12")
+	res, err = handler.SearchConfigs(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Configs), 0)
+}
+
+func TestHandler_SearchText(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	var c adding.Config
+	test.UnmarshalJSONFromFile(t, testDataFolder+"configExample.json", &c)
+	repository.StoreConfig(c)
+
+	ctx := context.Background()
+	res, err := handler.SearchText(ctx, &SearchTextRequest{Text: "somename"})
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Matches), 1)
+	test.AssertEqual(t, res.Matches[0].Id, "someId")
+	test.AssertEqual(t, res.Matches[0].Group, "someGroup")
+	test.AssertEqual(t, res.Matches[0].Snippets[0].Field, "name")
+	test.AssertEqual(t, res.Matches[0].Snippets[0].Text, "<em>someName</em>")
+
+	_, err = handler.SearchText(ctx, &SearchTextRequest{})
+	test.AssertIsError(t, err)
+}
+
+func TestHandler_ListChanges(t *testing.T) {
+	repository := memory.NewRepository()
+	handler := NewHandler(adding.NewService(repository), listing.NewService(repository), nil)
+
+	repository.StoreGroup(adding.Group{
+		ID: "someGroup",
+	})
+
+	repository.StoreConfig(adding.Config{
+		ID:    "someId",
+		Group: "someGroup",
+	})
+
+	ctx := context.Background()
+	req := &ListChangesRequest{Since: 1}
+	res, err := handler.ListChanges(ctx, req)
+
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Revision, int64(2))
+	test.AssertEqual(t, len(res.Changes), 1)
+	test.AssertEqual(t, res.Changes[0].Revision, int64(2))
+	test.AssertEqual(t, res.Changes[0].Resource, listing.ConfigResource)
+	test.AssertEqual(t, res.Changes[0].Group, "someGroup")
+	test.AssertEqual(t, res.Changes[0].Id, "someId")
 }
@@ -0,0 +1,1149 @@
+package kiv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/http/auth"
+	"github.com/larwef/ki/internal/http/grpc/rpcstatus"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/scheduling"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+	"time"
+)
+
+// propertiesPath is the prefix of update mask paths into the properties of a config
+const propertiesPath = "properties."
+
+// ErrNotStruct is used when properties or a schema stored through another API are not a JSON object and cannot be mapped to
+// a google.protobuf.Struct.
+var ErrNotStruct = domain.New(domain.FailedPrecondition, "", "value is not a JSON object and cannot be represented as a struct")
+
+// ErrUnauthenticated is used when a change request is proposed or reviewed without the credentials of a user
+var ErrUnauthenticated = domain.New(domain.Unauthenticated, "", "change requests are proposed and reviewed by an authenticated user")
+
+// Handler handles processing of ki.v2 gRPC calls
+type Handler struct {
+	adding     adding.Service
+	listing    listing.Service
+	deleting   deleting.Service
+	reviewing  reviewing.Service
+	scheduling scheduling.Service
+	diffing    diffing.Service
+	signer     *signing.Signer
+}
+
+// NewHandler returns a new Handler. Configs are signed with signer, unless it is nil.
+func NewHandler(adding adding.Service, listing listing.Service, deleting deleting.Service, reviewing reviewing.Service, scheduling scheduling.Service, diffing diffing.Service, signer *signing.Signer) *Handler {
+	return &Handler{
+		adding:     adding,
+		listing:    listing,
+		deleting:   deleting,
+		reviewing:  reviewing,
+		scheduling: scheduling,
+		diffing:    diffing,
+		signer:     signer,
+	}
+}
+
+// CreateGroup creates a group and returns it
+func (s *Handler) CreateGroup(ctx context.Context, req *CreateGroupRequest) (*Group, error) {
+	if req.Group == nil {
+		return &Group{}, invalidArgument("group", "required")
+	}
+
+	sch, err := toJSON(req.Group.Schema)
+	if err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	d, err := toDefaults(req.Group.Defaults)
+	if err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	if err := s.adding.AddGroup(adding.Group{ID: req.Group.Id, Schema: sch, Defaults: d}); err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	return s.getGroup(req.Group.Id)
+}
+
+// GetGroup fetches a group and maps it to a gRPC response
+func (s *Handler) GetGroup(ctx context.Context, req *GetGroupRequest) (*Group, error) {
+	return s.getGroup(req.Id)
+}
+
+func (s *Handler) getGroup(id string) (*Group, error) {
+	grp, err := s.listing.GetGroup(id)
+	if err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	sch, err := toStruct(grp.Schema)
+	if err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	d, err := mapDefaults(grp.Defaults)
+	if err != nil {
+		return &Group{}, rpcstatus.Error(err)
+	}
+
+	return &Group{
+		Id:          grp.ID,
+		Revision:    grp.Revision,
+		ConfigCount: int32(len(grp.Configs)),
+		Schema:      sch,
+		Defaults:    d,
+	}, nil
+}
+
+// ListGroups fetches a page of groups and maps it to a gRPC response. Schemas and defaults are left out.
+func (s *Handler) ListGroups(ctx context.Context, req *ListGroupsRequest) (*ListGroupsResponse, error) {
+	grps, err := s.listing.ListGroups(listing.GroupQuery{
+		Prefix: req.Prefix,
+		Sort:   req.OrderBy,
+		Page:   listing.Page{Cursor: req.PageToken, Limit: int(req.PageSize)},
+	})
+	if err != nil {
+		return &ListGroupsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListGroupsResponse{NextPageToken: grps.NextCursor}
+	for _, g := range grps.Groups {
+		res.Groups = append(res.Groups, &Group{
+			Id:          g.ID,
+			Revision:    g.Revision,
+			ConfigCount: int32(g.ConfigCount),
+		})
+	}
+
+	return res, nil
+}
+
+// UpdateGroup updates the fields of a group listed in the update mask and returns the updated group
+func (s *Handler) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*Group, error) {
+	if req.Group == nil {
+		return &Group{}, invalidArgument("group", "required")
+	}
+
+	paths := maskPaths(req.UpdateMask)
+	if len(paths) == 0 {
+		paths = []string{"schema", "defaults"}
+	}
+
+	for _, path := range paths {
+		if path != "schema" && path != "defaults" {
+			return &Group{}, invalidArgument("update_mask", "unknown path "+path)
+		}
+	}
+
+	for _, path := range paths {
+		switch path {
+		case "schema":
+			sch, err := toJSON(req.Group.Schema)
+			if err != nil {
+				return &Group{}, rpcstatus.Error(err)
+			}
+
+			if err := s.adding.SetSchema(req.Group.Id, sch); err != nil {
+				return &Group{}, rpcstatus.Error(err)
+			}
+		case "defaults":
+			d, err := toDefaults(req.Group.Defaults)
+			if err != nil {
+				return &Group{}, rpcstatus.Error(err)
+			}
+
+			if err := s.adding.SetDefaults(req.Group.Id, d); err != nil {
+				return &Group{}, rpcstatus.Error(err)
+			}
+		}
+	}
+
+	return s.getGroup(req.Group.Id)
+}
+
+// DeleteGroup deletes a group. Only groups without configs can be deleted.
+func (s *Handler) DeleteGroup(ctx context.Context, req *DeleteGroupRequest) (*empty.Empty, error) {
+	if err := s.deleting.DeleteGroup(req.Id); err != nil {
+		return &empty.Empty{}, rpcstatus.Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// CreateConfig creates a config and returns it. Fails if the config already exists.
+func (s *Handler) CreateConfig(ctx context.Context, req *CreateConfigRequest) (*Config, error) {
+	if req.Config == nil {
+		return &Config{}, invalidArgument("config", "required")
+	}
+
+	_, err := s.listing.GetRawConfig(req.Config.Group, req.Config.Id)
+	if err == nil {
+		return &Config{}, rpcstatus.Error(adding.ErrConfigConflict)
+	}
+	if err != listing.ErrConfigNotFound {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	props, err := toJSON(req.Config.Properties)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	return s.addConfig(ctx, adding.Config{
+		ID:         req.Config.Id,
+		Name:       req.Config.Name,
+		Version:    int(req.Config.Version),
+		Group:      req.Config.Group,
+		Parent:     req.Config.Parent,
+		Properties: props,
+	})
+}
+
+// GetConfig fetches a config and maps it to a gRPC response. Properties are the effective properties of the config unless
+// the raw config is requested, with the overlay of the requested environment merged onto them and references resolved.
+func (s *Handler) GetConfig(ctx context.Context, req *GetConfigRequest) (*Config, error) {
+	get := s.listing.GetConfig
+	switch {
+	case req.Raw && req.Environment != "":
+		return &Config{}, invalidArgument("environment", "cannot be combined with raw")
+	case req.Raw:
+		get = s.listing.GetRawConfig
+	case req.Environment != "":
+		get = func(grp string, id string) (*listing.Config, error) {
+			return s.listing.GetEnvironmentConfig(grp, id, req.Environment)
+		}
+	}
+
+	conf, err := get(req.Group, req.Id)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	if !req.Raw && !req.Unresolved {
+		resolved := *conf
+		ref := listing.ConfigRef{Group: req.Group, ID: req.Id}
+		if resolved.Properties, err = s.listing.ResolveReferences(ref, nil, conf.Properties); err != nil {
+			return &Config{}, rpcstatus.Error(err)
+		}
+		conf = &resolved
+	}
+
+	res, err := mapConfig(conf)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	return s.sign(ctx, res)
+}
+
+func (s *Handler) getConfig(groupID string, id string, raw bool) (*Config, error) {
+	get := s.listing.GetConfig
+	if raw {
+		get = s.listing.GetRawConfig
+	}
+
+	conf, err := get(groupID, id)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	res, err := mapConfig(conf)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+// ListConfigs fetches a page of the configs in a group, ordered by id, and maps it to a gRPC response
+func (s *Handler) ListConfigs(ctx context.Context, req *ListConfigsRequest) (*ListConfigsResponse, error) {
+	grp, err := s.listing.GetPagedGroup(req.Group, listing.Page{Cursor: req.PageToken, Limit: int(req.PageSize)})
+	if err != nil {
+		return &ListConfigsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListConfigsResponse{NextPageToken: grp.NextCursor}
+	for _, id := range grp.Configs {
+		conf, err := s.getConfig(grp.ID, id, false)
+		if err != nil {
+			return &ListConfigsResponse{}, err
+		}
+		res.Configs = append(res.Configs, conf)
+	}
+
+	return res, nil
+}
+
+// UpdateConfig updates the fields of a config listed in the update mask and returns the updated config. Fails if the config
+// does not exist. Properties are updated in the properties of the config itself, not the effective ones.
+func (s *Handler) UpdateConfig(ctx context.Context, req *UpdateConfigRequest) (*Config, error) {
+	if req.Config == nil {
+		return &Config{}, invalidArgument("config", "required")
+	}
+
+	conf, err := s.listing.GetRawConfig(req.Config.Group, req.Config.Id)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	update := adding.Config{
+		ID:         conf.ID,
+		Name:       conf.Name,
+		Version:    conf.Version,
+		Group:      conf.Group,
+		Parent:     conf.Parent,
+		Properties: conf.Properties,
+	}
+
+	paths := maskPaths(req.UpdateMask)
+	if len(paths) == 0 {
+		paths = []string{"name", "version", "properties"}
+	}
+
+	for _, path := range paths {
+		switch {
+		case path == "name":
+			update.Name = req.Config.Name
+		case path == "version":
+			update.Version = int(req.Config.Version)
+		case path == "properties":
+			if update.Properties, err = toJSON(req.Config.Properties); err != nil {
+				return &Config{}, rpcstatus.Error(err)
+			}
+		case strings.HasPrefix(path, propertiesPath):
+			if update.Properties, err = updateProperty(update.Properties, req.Config.Properties, path); err != nil {
+				return &Config{}, err
+			}
+		default:
+			return &Config{}, invalidArgument("update_mask", "unknown path "+path)
+		}
+	}
+
+	return s.addConfig(ctx, update)
+}
+
+// PatchConfig applies a JSON Merge Patch or a JSON Patch to a config and returns the patched config
+func (s *Handler) PatchConfig(ctx context.Context, req *PatchConfigRequest) (*Config, error) {
+	var patch adding.Patch
+	switch p := req.Patch.(type) {
+	case *PatchConfigRequest_MergePatch:
+		doc, err := toJSON(p.MergePatch)
+		if err != nil {
+			return &Config{}, rpcstatus.Error(err)
+		}
+		if doc == nil {
+			doc = json.RawMessage("{}")
+		}
+		patch = adding.Patch{Type: adding.MergePatch, Document: doc}
+	case *PatchConfigRequest_JsonPatch:
+		doc, err := jsonPatch(p.JsonPatch)
+		if err != nil {
+			return &Config{}, err
+		}
+		patch = adding.Patch{Type: adding.JSONPatch, Document: doc}
+	default:
+		return &Config{}, invalidArgument("patch", "required")
+	}
+
+	if err := s.adding.PatchConfig(req.Group, req.Id, patch); err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	conf, err := s.getConfig(req.Group, req.Id, false)
+	if err != nil {
+		return conf, err
+	}
+
+	return s.sign(ctx, conf)
+}
+
+// DeleteConfig deletes a config and removes it from its group
+func (s *Handler) DeleteConfig(ctx context.Context, req *DeleteConfigRequest) (*empty.Empty, error) {
+	if err := s.deleting.DeleteConfig(req.Group, req.Id); err != nil {
+		return &empty.Empty{}, rpcstatus.Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ListDependents fetches the configs referencing a config and maps them to a gRPC response
+func (s *Handler) ListDependents(ctx context.Context, req *ListDependentsRequest) (*ListDependentsResponse, error) {
+	dependents, err := s.listing.GetDependents(req.Group, req.Id)
+	if err != nil {
+		return &ListDependentsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListDependentsResponse{}
+	for _, ref := range dependents {
+		res.Dependents = append(res.Dependents, &ConfigRef{Group: ref.Group, Id: ref.ID})
+	}
+
+	return res, nil
+}
+
+// SetOverlay creates or replaces the overlay of a config in an environment and returns it
+func (s *Handler) SetOverlay(ctx context.Context, req *SetOverlayRequest) (*Overlay, error) {
+	if req.Overlay == nil {
+		return &Overlay{}, invalidArgument("overlay", "required")
+	}
+
+	props, err := toJSON(req.Overlay.Properties)
+	if err != nil {
+		return &Overlay{}, rpcstatus.Error(err)
+	}
+
+	o := adding.Overlay{
+		Group:       req.Overlay.Group,
+		Config:      req.Overlay.Config,
+		Environment: req.Overlay.Environment,
+		Version:     int(req.Overlay.Version),
+		Properties:  props,
+	}
+	if err := s.adding.SetOverlay(o); err != nil {
+		return &Overlay{}, rpcstatus.Error(err)
+	}
+
+	return s.getOverlay(o.Group, o.Config, o.Environment)
+}
+
+// GetOverlay fetches the overlay of a config in an environment and maps it to a gRPC response
+func (s *Handler) GetOverlay(ctx context.Context, req *GetOverlayRequest) (*Overlay, error) {
+	return s.getOverlay(req.Group, req.Config, req.Environment)
+}
+
+func (s *Handler) getOverlay(groupID string, id string, env string) (*Overlay, error) {
+	o, err := s.listing.GetOverlay(groupID, id, env)
+	if err != nil {
+		return &Overlay{}, rpcstatus.Error(err)
+	}
+
+	res, err := mapOverlay(o)
+	if err != nil {
+		return &Overlay{}, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+// ListOverlays fetches the overlays of a config, ordered by environment, and maps them to a gRPC response
+func (s *Handler) ListOverlays(ctx context.Context, req *ListOverlaysRequest) (*ListOverlaysResponse, error) {
+	overlays, err := s.listing.ListOverlays(req.Group, req.Config)
+	if err != nil {
+		return &ListOverlaysResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListOverlaysResponse{}
+	for i := range overlays {
+		o, err := mapOverlay(&overlays[i])
+		if err != nil {
+			return &ListOverlaysResponse{}, rpcstatus.Error(err)
+		}
+		res.Overlays = append(res.Overlays, o)
+	}
+
+	return res, nil
+}
+
+// PromoteOverlay promotes the overlay of a config in one environment to another and returns the promoted overlay
+func (s *Handler) PromoteOverlay(ctx context.Context, req *PromoteOverlayRequest) (*Overlay, error) {
+	if err := s.adding.PromoteOverlay(req.Group, req.Config, req.From, req.To); err != nil {
+		return &Overlay{}, rpcstatus.Error(err)
+	}
+
+	return s.getOverlay(req.Group, req.Config, req.To)
+}
+
+// DeleteOverlay deletes the overlay of a config in an environment
+func (s *Handler) DeleteOverlay(ctx context.Context, req *DeleteOverlayRequest) (*empty.Empty, error) {
+	if err := s.deleting.DeleteOverlay(req.Group, req.Config, req.Environment); err != nil {
+		return &empty.Empty{}, rpcstatus.Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Commit applies the operations of a batch, all or none of them
+func (s *Handler) Commit(ctx context.Context, req *CommitRequest) (*empty.Empty, error) {
+	ops := make([]adding.Operation, len(req.Operations))
+	for i, op := range req.Operations {
+		var err error
+		if ops[i], err = toOperation(op); err != nil {
+			return &empty.Empty{}, rpcstatus.Error(&adding.OperationError{Index: i, Err: err})
+		}
+	}
+
+	if _, err := s.adding.Commit(ops); err != nil {
+		return &empty.Empty{}, rpcstatus.Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ProposeChange stores a change to a config as an open change request by the authenticated user
+func (s *Handler) ProposeChange(ctx context.Context, req *ProposeChangeRequest) (*ChangeRequest, error) {
+	user, ok := auth.FromContext(ctx)
+	if !ok {
+		return &ChangeRequest{}, rpcstatus.Error(ErrUnauthenticated)
+	}
+
+	if req.Config == nil {
+		return &ChangeRequest{}, invalidArgument("config", "required")
+	}
+
+	props, err := toJSON(req.Config.Properties)
+	if err != nil {
+		return &ChangeRequest{}, rpcstatus.Error(err)
+	}
+
+	cr, err := s.reviewing.Propose(user.Username, reviewing.Proposal{
+		Description: req.Description,
+		Config: adding.Config{
+			ID:         req.Config.Id,
+			Name:       req.Config.Name,
+			Version:    int(req.Config.Version),
+			Group:      req.Config.Group,
+			Parent:     req.Config.Parent,
+			Properties: props,
+		},
+	})
+
+	return mapChangeRequestResult(cr, err)
+}
+
+// GetChangeRequest fetches a change request
+func (s *Handler) GetChangeRequest(ctx context.Context, req *GetChangeRequestRequest) (*ChangeRequest, error) {
+	return mapChangeRequestResult(s.reviewing.GetChangeRequest(req.Id))
+}
+
+// ListChangeRequests fetches the change requests in a group and state, ordered by id
+func (s *Handler) ListChangeRequests(ctx context.Context, req *ListChangeRequestsRequest) (*ListChangeRequestsResponse, error) {
+	crs, err := s.reviewing.ListChangeRequests(reviewing.Query{Group: req.Group, State: reviewing.State(req.State)})
+	if err != nil {
+		return &ListChangeRequestsResponse{}, rpcstatus.Error(err)
+	}
+
+	res := &ListChangeRequestsResponse{ChangeRequests: make([]*ChangeRequest, len(crs))}
+	for i := range crs {
+		if res.ChangeRequests[i], err = mapChangeRequest(&crs[i]); err != nil {
+			return &ListChangeRequestsResponse{}, rpcstatus.Error(err)
+		}
+	}
+
+	return res, nil
+}
+
+// ApproveChangeRequest approves an open change request as the authenticated user
+func (s *Handler) ApproveChangeRequest(ctx context.Context, req *ReviewChangeRequestRequest) (*ChangeRequest, error) {
+	user, ok := auth.FromContext(ctx)
+	if !ok {
+		return &ChangeRequest{}, rpcstatus.Error(ErrUnauthenticated)
+	}
+
+	return mapChangeRequestResult(s.reviewing.Approve(user.Username, req.Id, req.Comment))
+}
+
+// RejectChangeRequest rejects an open change request as the authenticated user
+func (s *Handler) RejectChangeRequest(ctx context.Context, req *ReviewChangeRequestRequest) (*ChangeRequest, error) {
+	user, ok := auth.FromContext(ctx)
+	if !ok {
+		return &ChangeRequest{}, rpcstatus.Error(ErrUnauthenticated)
+	}
+
+	return mapChangeRequestResult(s.reviewing.Reject(user.Username, req.Id, req.Comment))
+}
+
+// ApplyChangeRequest stores the config of an approved change request as the authenticated user
+func (s *Handler) ApplyChangeRequest(ctx context.Context, req *ReviewChangeRequestRequest) (*ChangeRequest, error) {
+	user, ok := auth.FromContext(ctx)
+	if !ok {
+		return &ChangeRequest{}, rpcstatus.Error(ErrUnauthenticated)
+	}
+
+	return mapChangeRequestResult(s.reviewing.Apply(user.Username, req.Id))
+}
+
+// GetApprovers fetches the users who can approve change requests in a group
+func (s *Handler) GetApprovers(ctx context.Context, req *GetApproversRequest) (*Approvers, error) {
+	approvers, err := s.reviewing.GetApprovers(req.Group)
+	if err != nil {
+		return &Approvers{}, rpcstatus.Error(err)
+	}
+
+	return &Approvers{Group: req.Group, Approvers: approvers}, nil
+}
+
+// ScheduleConfig schedules a config to be stored at a time in the future
+func (s *Handler) ScheduleConfig(ctx context.Context, req *ScheduleConfigRequest) (*Activation, error) {
+	if req.Config == nil {
+		return &Activation{}, invalidArgument("config", "required")
+	}
+
+	if req.ActivatesAt == nil {
+		return &Activation{}, invalidArgument("activates_at", "required")
+	}
+
+	at, err := ptypes.Timestamp(req.ActivatesAt)
+	if err != nil {
+		return &Activation{}, invalidArgument("activates_at", err.Error())
+	}
+
+	props, err := toJSON(req.Config.Properties)
+	if err != nil {
+		return &Activation{}, rpcstatus.Error(err)
+	}
+
+	a, err := s.scheduling.Schedule(scheduling.Request{
+		ActivatesAt: at,
+		Config: adding.Config{
+			ID:         req.Config.Id,
+			Name:       req.Config.Name,
+			Version:    int(req.Config.Version),
+			Group:      req.Config.Group,
+			Parent:     req.Config.Parent,
+			Properties: props,
+		},
+	})
+
+	return mapActivationResult(a, err)
+}
+
+// GetSchedule fetches the active version of a config and its pending activations
+func (s *Handler) GetSchedule(ctx context.Context, req *GetScheduleRequest) (*Schedule, error) {
+	schedule, err := s.scheduling.GetSchedule(req.Group, req.Id)
+	if err != nil {
+		return &Schedule{}, rpcstatus.Error(err)
+	}
+
+	res := &Schedule{Pending: make([]*Activation, len(schedule.Pending))}
+	if v := schedule.Active; v != nil {
+		lastModified, err := ptypes.TimestampProto(v.LastModified)
+		if err != nil {
+			return &Schedule{}, rpcstatus.Error(err)
+		}
+		res.Active = &ActiveVersion{Name: v.Name, Version: int32(v.Version), Revision: v.Revision, LastModified: lastModified}
+	}
+
+	for i := range schedule.Pending {
+		if res.Pending[i], err = mapActivation(&schedule.Pending[i]); err != nil {
+			return &Schedule{}, rpcstatus.Error(err)
+		}
+	}
+	if len(res.Pending) > 0 {
+		res.Next = res.Pending[0]
+	}
+
+	return res, nil
+}
+
+// GetActivation fetches an activation of a config
+func (s *Handler) GetActivation(ctx context.Context, req *ActivationRequest) (*Activation, error) {
+	return mapActivationResult(s.scheduling.GetActivation(req.Group, req.Id, req.ActivationId))
+}
+
+// CancelActivation cancels a pending activation of a config
+func (s *Handler) CancelActivation(ctx context.Context, req *ActivationRequest) (*Activation, error) {
+	return mapActivationResult(s.scheduling.Cancel(req.Group, req.Id, req.ActivationId))
+}
+
+// DiffVersions compares a config as it was stored with two versions
+func (s *Handler) DiffVersions(ctx context.Context, req *DiffVersionsRequest) (*Diff, error) {
+	var to *int
+	if req.To != nil {
+		v := int(req.To.Value)
+		to = &v
+	}
+
+	d, err := s.diffing.DiffVersions(req.Group, req.Id, int(req.From), to)
+	return mapDiffResult(d, err, req.Unified)
+}
+
+// DiffRevisions compares a config as it was stored at two revisions
+func (s *Handler) DiffRevisions(ctx context.Context, req *DiffRevisionsRequest) (*Diff, error) {
+	d, err := s.diffing.DiffRevisions(req.Group, req.Id, req.From, req.To)
+	return mapDiffResult(d, err, req.Unified)
+}
+
+// DiffConfigs compares two configs
+func (s *Handler) DiffConfigs(ctx context.Context, req *DiffConfigsRequest) (*Diff, error) {
+	if req.A == nil {
+		return &Diff{}, invalidArgument("a", "required")
+	}
+
+	if req.B == nil {
+		return &Diff{}, invalidArgument("b", "required")
+	}
+
+	a := listing.ConfigRef{Group: req.A.Group, ID: req.A.Id}
+	b := listing.ConfigRef{Group: req.B.Group, ID: req.B.Id}
+	d, err := s.diffing.DiffConfigs(a, b, req.Raw)
+	return mapDiffResult(d, err, req.Unified)
+}
+
+func (s *Handler) addConfig(ctx context.Context, c adding.Config) (*Config, error) {
+	c.LastModified = time.Now()
+	if err := s.adding.AddConfig(c); err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	conf, err := s.getConfig(c.Group, c.ID, false)
+	if err != nil {
+		return conf, err
+	}
+
+	return s.sign(ctx, conf)
+}
+
+// sign sets the signature of a config as it is returned in the trailer of the call, if configs are signed. Configs in lists
+// are not signed.
+func (s *Handler) sign(ctx context.Context, conf *Config) (*Config, error) {
+	if s.signer == nil {
+		return conf, nil
+	}
+
+	props, err := toJSON(conf.Properties)
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	sig, err := s.signer.Sign(signing.Config{ID: conf.Id, Group: conf.Group, Version: int(conf.Version), Properties: props})
+	if err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	if err := grpc.SetTrailer(ctx, metadata.Pairs(signing.SignatureTrailer, sig, signing.KeyIDTrailer, s.signer.KeyID())); err != nil {
+		return &Config{}, rpcstatus.Error(err)
+	}
+
+	return conf, nil
+}
+
+// updateProperty copies the property at a "properties." mask path from the requested properties into the stored ones. The
+// property is removed if it is not set in the request.
+func updateProperty(stored json.RawMessage, requested *structpb.Struct, path string) (json.RawMessage, error) {
+	p, err := properties.ParsePath(strings.TrimPrefix(path, propertiesPath))
+	if err != nil {
+		return nil, invalidArgument("update_mask", "invalid path "+path)
+	}
+
+	req, err := toJSON(requested)
+	if err != nil {
+		return nil, rpcstatus.Error(err)
+	}
+
+	var reqDoc, doc interface{}
+	if len(req) > 0 {
+		json.Unmarshal(req, &reqDoc)
+	}
+	if len(stored) > 0 {
+		if err := json.Unmarshal(stored, &doc); err != nil {
+			return nil, rpcstatus.Error(ErrNotStruct)
+		}
+	}
+
+	if v, ok := properties.Get(reqDoc, p); ok {
+		doc, err = properties.Set(doc, p, v)
+	} else if doc, err = properties.Remove(doc, p); err == properties.ErrPathNotFound {
+		return stored, nil
+	}
+	if err != nil {
+		return nil, invalidArgument("update_mask", "path "+path+" does not refer to a property that can be set")
+	}
+
+	res, err := json.Marshal(doc)
+	if err != nil {
+		return nil, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+// jsonPatch encodes the operations of a JSON Patch as JSON
+func jsonPatch(p *JSONPatch) (json.RawMessage, error) {
+	ops := []properties.Operation{}
+	for i, op := range p.GetOperations() {
+		operation := properties.Operation{Op: op.Op, Path: op.Path, From: op.From}
+		if op.Value != nil {
+			value, err := (&jsonpb.Marshaler{}).MarshalToString(op.Value)
+			if err != nil {
+				return nil, invalidArgument("json_patch", fmt.Sprintf("value of operation %d is not set", i))
+			}
+			operation.Value = json.RawMessage(value)
+		}
+		ops = append(ops, operation)
+	}
+
+	res, err := json.Marshal(ops)
+	if err != nil {
+		return nil, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+func mapConfig(conf *listing.Config) (*Config, error) {
+	props, err := toStruct(secret.Masked(conf.Properties))
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified, err := ptypes.TimestampProto(conf.LastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Config{
+		Group:        conf.Group,
+		Id:           conf.ID,
+		Name:         conf.Name,
+		Version:      int32(conf.Version),
+		Revision:     conf.Revision,
+		LastModified: lastModified,
+		Properties:   props,
+		Parent:       conf.Parent,
+	}
+	if env := conf.Environment; env != nil {
+		res.Environment = &Environment{Name: env.Name, Version: int32(env.Version), Revision: env.Revision}
+	}
+
+	return res, nil
+}
+
+// mapChangeRequestResult maps the change request returned by a reviewing call to a gRPC response, or err to a status error
+func mapChangeRequestResult(cr *reviewing.ChangeRequest, err error) (*ChangeRequest, error) {
+	if err != nil {
+		return &ChangeRequest{}, rpcstatus.Error(err)
+	}
+
+	res, err := mapChangeRequest(cr)
+	if err != nil {
+		return &ChangeRequest{}, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+func mapChangeRequest(cr *reviewing.ChangeRequest) (*ChangeRequest, error) {
+	conf, err := mapConfig(&listing.Config{
+		ID:         cr.Config.ID,
+		Name:       cr.Config.Name,
+		Version:    cr.Config.Version,
+		Group:      cr.Config.Group,
+		Parent:     cr.Config.Parent,
+		Properties: cr.Config.Properties,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The config is not stored yet, so it has no revision or modification time
+	conf.LastModified = nil
+
+	res := &ChangeRequest{
+		Id:           cr.ID,
+		Author:       cr.Author,
+		Description:  cr.Description,
+		State:        string(cr.State),
+		BaseRevision: cr.BaseRevision,
+		Config:       conf,
+		History:      make([]*Transition, len(cr.History)),
+	}
+
+	if res.Diff, err = mapDifferences(cr.Diff); err != nil {
+		return nil, err
+	}
+
+	for i, t := range cr.History {
+		ts, err := ptypes.TimestampProto(t.Time)
+		if err != nil {
+			return nil, err
+		}
+		res.History[i] = &Transition{State: string(t.State), User: t.User, Time: ts, Comment: t.Comment}
+	}
+
+	return res, nil
+}
+
+func mapDifferences(ds []properties.Difference) ([]*Difference, error) {
+	res := make([]*Difference, len(ds))
+	for i, d := range ds {
+		var err error
+		res[i] = &Difference{Type: string(d.Type), Path: d.Path}
+		if res[i].From, err = toValue(d.From); err != nil {
+			return nil, err
+		}
+		if res[i].To, err = toValue(d.To); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// mapDiffResult maps the diff returned by a diffing call to a gRPC response with its unified diff if asked for, or err to a
+// status error
+func mapDiffResult(d *diffing.Diff, err error, unified bool) (*Diff, error) {
+	if err != nil {
+		return &Diff{}, rpcstatus.Error(err)
+	}
+
+	res := &Diff{
+		From: &DiffSide{Group: d.From.Group, Id: d.From.ID, Revision: d.From.Revision, Version: int32(d.From.Version)},
+		To:   &DiffSide{Group: d.To.Group, Id: d.To.ID, Revision: d.To.Revision, Version: int32(d.To.Version)},
+	}
+
+	if res.Differences, err = mapDifferences(d.Differences); err != nil {
+		return &Diff{}, rpcstatus.Error(err)
+	}
+
+	if unified {
+		if res.Unified, err = d.Unified(); err != nil {
+			return &Diff{}, rpcstatus.Error(err)
+		}
+	}
+
+	return res, nil
+}
+
+// mapActivationResult maps the activation returned by a scheduling call to a gRPC response, or err to a status error
+func mapActivationResult(a *scheduling.Activation, err error) (*Activation, error) {
+	if err != nil {
+		return &Activation{}, rpcstatus.Error(err)
+	}
+
+	res, err := mapActivation(a)
+	if err != nil {
+		return &Activation{}, rpcstatus.Error(err)
+	}
+
+	return res, nil
+}
+
+func mapActivation(a *scheduling.Activation) (*Activation, error) {
+	conf, err := mapConfig(&listing.Config{
+		ID:         a.Config.ID,
+		Name:       a.Config.Name,
+		Version:    a.Config.Version,
+		Group:      a.Config.Group,
+		Parent:     a.Config.Parent,
+		Properties: a.Config.Properties,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The config is not stored by the activation yet, so it has no revision or modification time
+	conf.LastModified = nil
+
+	activatesAt, err := ptypes.TimestampProto(a.ActivatesAt)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := ptypes.TimestampProto(a.Created)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Activation{
+		Id:           a.ID,
+		State:        string(a.State),
+		ActivatesAt:  activatesAt,
+		Created:      created,
+		BaseRevision: a.BaseRevision,
+		Revision:     a.Revision,
+		Error:        a.Error,
+		Config:       conf,
+	}
+
+	if !a.Activated.IsZero() {
+		if res.Activated, err = ptypes.TimestampProto(a.Activated); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func mapOverlay(o *listing.Overlay) (*Overlay, error) {
+	props, err := toStruct(secret.Masked(o.Properties))
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified, err := ptypes.TimestampProto(o.LastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Overlay{
+		Group:        o.Group,
+		Config:       o.Config,
+		Environment:  o.Environment,
+		Version:      int32(o.Version),
+		Revision:     o.Revision,
+		LastModified: lastModified,
+		Properties:   props,
+	}
+	if p := o.PromotedFrom; p != nil {
+		res.PromotedFrom = &Promotion{Environment: p.Environment, Revision: p.Revision}
+	}
+
+	return res, nil
+}
+
+// mapDefaults maps the defaults of a group, which can be nil
+func mapDefaults(d *listing.Defaults) (*Defaults, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	props, err := toStruct(secret.Masked(d.Properties))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Defaults{Properties: props, Arrays: string(d.Merge.Arrays), Nulls: string(d.Merge.Nulls)}, nil
+}
+
+// toDefaults maps the defaults of a group in a request, which can be nil
+func toDefaults(d *Defaults) (*adding.Defaults, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	props, err := toJSON(d.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adding.Defaults{
+		Properties: props,
+		Merge:      properties.MergeRules{Arrays: properties.ArrayMerge(d.Arrays), Nulls: properties.NullMerge(d.Nulls)},
+	}, nil
+}
+
+// toOperation maps an operation of a batch in a request
+func toOperation(op *Operation) (adding.Operation, error) {
+	var o adding.Operation
+	if op.GetRevision() != nil {
+		revision := op.Revision.Value
+		o.Revision = &revision
+	}
+
+	switch v := op.GetOperation().(type) {
+	case *Operation_CreateGroup:
+		sch, err := toJSON(v.CreateGroup.GetSchema())
+		if err != nil {
+			return o, err
+		}
+
+		d, err := toDefaults(v.CreateGroup.GetDefaults())
+		if err != nil {
+			return o, err
+		}
+
+		o.Type = adding.CreateGroup
+		o.Group = adding.Group{ID: v.CreateGroup.GetId(), Schema: sch, Defaults: d}
+	case *Operation_PutConfig:
+		props, err := toJSON(v.PutConfig.GetProperties())
+		if err != nil {
+			return o, err
+		}
+
+		o.Type = adding.PutConfig
+		o.Config = adding.Config{
+			ID:         v.PutConfig.GetId(),
+			Name:       v.PutConfig.GetName(),
+			Version:    int(v.PutConfig.GetVersion()),
+			Group:      v.PutConfig.GetGroup(),
+			Parent:     v.PutConfig.GetParent(),
+			Properties: props,
+		}
+	case *Operation_DeleteConfig:
+		o.Type = adding.DeleteConfig
+		o.Config = adding.Config{ID: v.DeleteConfig.GetId(), Group: v.DeleteConfig.GetGroup()}
+	default:
+		return o, domain.New(domain.InvalidArgument, "", "invalid operation: required", domain.Violation{Field: "operation", Description: "required"})
+	}
+
+	return o, nil
+}
+
+// maskPaths returns the paths of a field mask, which can be nil
+func maskPaths(mask *field_mask.FieldMask) []string {
+	if mask == nil {
+		return nil
+	}
+
+	return mask.Paths
+}
+
+// toJSON encodes a struct as a JSON object. A nil struct is encoded as nil.
+func toJSON(s *structpb.Struct) (json.RawMessage, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	str, err := (&jsonpb.Marshaler{}).MarshalToString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(str), nil
+}
+
+// toStruct decodes a JSON object to a struct. Empty or null JSON is decoded as nil, and other values than objects give
+// ErrNotStruct.
+func toStruct(raw json.RawMessage) (*structpb.Struct, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	s := &structpb.Struct{}
+	if err := jsonpb.UnmarshalString(string(raw), s); err != nil {
+		return nil, ErrNotStruct
+	}
+
+	return s, nil
+}
+
+// toValue encodes a decoded JSON value as a value. Nil, like a value missing from a difference, is encoded as nil.
+func toValue(v interface{}) (*structpb.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	val := &structpb.Value{}
+	if err := jsonpb.UnmarshalString(string(b), val); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// invalidArgument returns a status error for an invalid field of a request
+func invalidArgument(field, reason string) error {
+	return rpcstatus.Error(domain.New(domain.InvalidArgument, "", "invalid "+field+": "+reason, domain.Violation{Field: field, Description: reason}))
+}
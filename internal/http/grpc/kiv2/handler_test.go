@@ -0,0 +1,723 @@
+package kiv2
+
+import (
+	"bytes"
+	"context"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
+	"github.com/larwef/ki/internal/http/auth"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/scheduling"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/signing"
+	"github.com/larwef/ki/test"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func newHandler() (*Handler, *memory.Repository) {
+	repository := memory.NewRepository()
+	add := adding.NewService(repository)
+	list := listing.NewService(repository)
+	return NewHandler(add, list, deleting.NewService(repository), reviewing.NewService(repository, adding.NewService(repository, adding.Reviewed())), scheduling.NewScheduler(repository, add), diffing.NewService(repository, list), nil), repository
+}
+
+// assertStatus asserts that err is a status error with the code and message
+func assertStatus(t *testing.T, err error, code codes.Code, msg string) {
+	st, ok := status.FromError(err)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, st.Code(), code)
+	test.AssertEqual(t, st.Message(), msg)
+}
+
+func newStruct(t *testing.T, json string) *structpb.Struct {
+	s := &structpb.Struct{}
+	if err := jsonpb.UnmarshalString(json, s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func assertStructJSON(t *testing.T, s *structpb.Struct, expected string) {
+	actual, err := (&jsonpb.Marshaler{}).MarshalToString(s)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, actual, expected)
+}
+
+func TestHandler_CreateAndGetGroup(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+
+	res, err := handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup", Schema: newStruct(t, `{"type":"object"}`)}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Id, "someGroup")
+	test.AssertEqual(t, res.Revision, int64(1))
+	assertStructJSON(t, res.Schema, `{"type":"object"}`)
+
+	_, err = handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	assertStatus(t, err, codes.AlreadyExists, adding.ErrGroupConflict.Error())
+
+	_, err = handler.CreateGroup(ctx, &CreateGroupRequest{})
+	assertStatus(t, err, codes.InvalidArgument, "invalid group: required")
+
+	_, err = handler.GetGroup(ctx, &GetGroupRequest{Id: "someOtherGroup"})
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
+}
+
+func TestHandler_ListGroups(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+
+	for _, id := range []string{"c", "a", "b"} {
+		handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: id, Schema: newStruct(t, `{}`)}})
+	}
+
+	res, err := handler.ListGroups(ctx, &ListGroupsRequest{PageSize: 2})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Groups), 2)
+	test.AssertEqual(t, res.Groups[0].Id, "a")
+	test.AssertEqual(t, res.Groups[1].Id, "b")
+	test.AssertEqual(t, res.Groups[0].Schema == nil, true)
+
+	res, err = handler.ListGroups(ctx, &ListGroupsRequest{PageSize: 2, PageToken: res.NextPageToken})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Groups), 1)
+	test.AssertEqual(t, res.Groups[0].Id, "c")
+	test.AssertEqual(t, res.NextPageToken, "")
+
+	_, err = handler.ListGroups(ctx, &ListGroupsRequest{OrderBy: "name"})
+	assertStatus(t, err, codes.InvalidArgument, listing.ErrInvalidSort.Error())
+}
+
+func TestHandler_UpdateGroup(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+
+	res, err := handler.UpdateGroup(ctx, &UpdateGroupRequest{
+		Group:      &Group{Id: "someGroup", Schema: newStruct(t, `{"required":["host"]}`)},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"schema"}},
+	})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Schema, `{"required":["host"]}`)
+
+	res, err = handler.UpdateGroup(ctx, &UpdateGroupRequest{Group: &Group{Id: "someGroup"}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Schema == nil, true)
+
+	_, err = handler.UpdateGroup(ctx, &UpdateGroupRequest{
+		Group:      &Group{Id: "someGroup"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"revision"}},
+	})
+	assertStatus(t, err, codes.InvalidArgument, "invalid update_mask: unknown path revision")
+
+	_, err = handler.UpdateGroup(ctx, &UpdateGroupRequest{Group: &Group{Id: "someOtherGroup"}})
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
+}
+
+func TestHandler_DeleteGroup(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId"}})
+
+	_, err := handler.DeleteGroup(ctx, &DeleteGroupRequest{Id: "someGroup"})
+	assertStatus(t, err, codes.FailedPrecondition, deleting.ErrGroupNotEmpty.Error())
+
+	_, err = handler.DeleteConfig(ctx, &DeleteConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+
+	_, err = handler.DeleteGroup(ctx, &DeleteGroupRequest{Id: "someGroup"})
+	test.AssertNotError(t, err)
+
+	_, err = handler.GetGroup(ctx, &GetGroupRequest{Id: "someGroup"})
+	assertStatus(t, err, codes.NotFound, listing.ErrGroupNotFound.Error())
+}
+
+func TestHandler_CreateAndGetConfig(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+
+	before := time.Now()
+	res, err := handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{
+		Group:      "someGroup",
+		Id:         "someId",
+		Name:       "someName",
+		Version:    2,
+		Properties: newStruct(t, `{"database":{"hosts":["db1","db2"],"port":5432}}`),
+	}})
+	test.AssertNotError(t, err)
+
+	res, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Name, "someName")
+	test.AssertEqual(t, res.Version, int32(2))
+	test.AssertEqual(t, res.Revision, int64(2))
+	assertStructJSON(t, res.Properties, `{"database":{"hosts":["db1","db2"],"port":5432}}`)
+
+	lastModified, err := ptypes.Timestamp(res.LastModified)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, lastModified.Before(before), false)
+
+	_, err = handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId"}})
+	assertStatus(t, err, codes.AlreadyExists, adding.ErrConfigConflict.Error())
+
+	_, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someOtherId"})
+	assertStatus(t, err, codes.NotFound, listing.ErrConfigNotFound.Error())
+}
+
+func TestHandler_GetConfig_PropertiesNotObject(t *testing.T) {
+	handler, repository := newHandler()
+	ctx := context.Background()
+	repository.StoreGroup(adding.Group{ID: "someGroup"})
+	repository.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`[1,2]`)})
+
+	_, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	assertStatus(t, err, codes.FailedPrecondition, ErrNotStruct.Error())
+}
+
+func TestHandler_ListConfigs(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	for _, id := range []string{"c", "a", "b"} {
+		handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: id}})
+	}
+
+	res, err := handler.ListConfigs(ctx, &ListConfigsRequest{Group: "someGroup", PageSize: 2})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Configs), 2)
+	test.AssertEqual(t, res.Configs[0].Id, "a")
+	test.AssertEqual(t, res.Configs[1].Id, "b")
+
+	res, err = handler.ListConfigs(ctx, &ListConfigsRequest{Group: "someGroup", PageToken: res.NextPageToken})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(res.Configs), 1)
+	test.AssertEqual(t, res.Configs[0].Id, "c")
+	test.AssertEqual(t, res.NextPageToken, "")
+}
+
+func TestHandler_UpdateConfig(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{
+		Group:      "someGroup",
+		Id:         "someId",
+		Name:       "someName",
+		Version:    1,
+		Properties: newStruct(t, `{"database":{"hosts":["db1","db2"],"port":5432},"debug":true}`),
+	}})
+
+	res, err := handler.UpdateConfig(ctx, &UpdateConfigRequest{
+		Config: &Config{
+			Group:      "someGroup",
+			Id:         "someId",
+			Name:       "ignored",
+			Version:    2,
+			Properties: newStruct(t, `{"database":{"hosts":["db3"]},"timeout":30}`),
+		},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"version", "properties.database.hosts[0]", "properties.timeout", "properties.debug"}},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Name, "someName")
+	test.AssertEqual(t, res.Version, int32(2))
+	assertStructJSON(t, res.Properties, `{"database":{"hosts":["db3","db2"],"port":5432},"timeout":30}`)
+
+	res, err = handler.UpdateConfig(ctx, &UpdateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Name: "newName"}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Name, "newName")
+	test.AssertEqual(t, res.Version, int32(0))
+	test.AssertEqual(t, res.Properties == nil, true)
+}
+
+func TestHandler_UpdateConfig_Inherited(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{
+		Id:       "someGroup",
+		Defaults: &Defaults{Properties: newStruct(t, `{"port":5432}`)},
+	}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "base", Properties: newStruct(t, `{"host":"db1"}`)}})
+
+	res, err := handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "child", Parent: "base"}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Parent, "base")
+	assertStructJSON(t, res.Properties, `{"host":"db1","port":5432}`)
+
+	// Only the properties of the config itself are updated
+	res, err = handler.UpdateConfig(ctx, &UpdateConfigRequest{
+		Config:     &Config{Group: "someGroup", Id: "child", Properties: newStruct(t, `{"debug":true}`)},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"properties.debug"}},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Parent, "base")
+	assertStructJSON(t, res.Properties, `{"debug":true,"host":"db1","port":5432}`)
+
+	res, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "child", Raw: true})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Properties, `{"debug":true}`)
+
+	grp, err := handler.UpdateGroup(ctx, &UpdateGroupRequest{
+		Group:      &Group{Id: "someGroup"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"defaults"}},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, grp.Defaults == nil, true)
+
+	_, err = handler.DeleteConfig(ctx, &DeleteConfigRequest{Group: "someGroup", Id: "base"})
+	assertStatus(t, err, codes.FailedPrecondition, "config has children and cannot be deleted")
+}
+
+func TestHandler_Overlays(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"db1","port":5432}`)}})
+
+	staging, err := handler.SetOverlay(ctx, &SetOverlayRequest{Overlay: &Overlay{
+		Group:       "someGroup",
+		Config:      "someId",
+		Environment: "staging",
+		Version:     1,
+		Properties:  newStruct(t, `{"host":"db2"}`),
+	}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, staging.Environment, "staging")
+
+	prod, err := handler.PromoteOverlay(ctx, &PromoteOverlayRequest{Group: "someGroup", Config: "someId", From: "staging", To: "prod"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, prod.Version, int32(1))
+	test.AssertEqual(t, prod.PromotedFrom.Environment, "staging")
+	test.AssertEqual(t, prod.PromotedFrom.Revision, staging.Revision)
+
+	res, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId", Environment: "prod"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Environment.Name, "prod")
+	test.AssertEqual(t, res.Environment.Revision, prod.Revision)
+	assertStructJSON(t, res.Properties, `{"host":"db2","port":5432}`)
+
+	_, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId", Environment: "prod", Raw: true})
+	assertStatus(t, err, codes.InvalidArgument, "invalid environment: cannot be combined with raw")
+
+	_, err = handler.DeleteOverlay(ctx, &DeleteOverlayRequest{Group: "someGroup", Config: "someId", Environment: "staging"})
+	test.AssertNotError(t, err)
+
+	list, err := handler.ListOverlays(ctx, &ListOverlaysRequest{Group: "someGroup", Config: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(list.Overlays), 1)
+	test.AssertEqual(t, list.Overlays[0].Environment, "prod")
+
+	_, err = handler.GetOverlay(ctx, &GetOverlayRequest{Group: "someGroup", Config: "someId", Environment: "staging"})
+	assertStatus(t, err, codes.NotFound, "overlay not found")
+}
+
+func TestHandler_GetConfig_References(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "shared"}})
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "shared", Id: "database", Properties: newStruct(t, `{"host":"db1"}`)}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"${ref:shared/database#host}"}`)}})
+
+	// References to other groups are resolved for calls without a user too
+	res, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Properties, `{"host":"db1"}`)
+
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "shared", Id: "api", Properties: newStruct(t, `{"db":"${ref:shared/database#host}"}`)}})
+	res, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "shared", Id: "api"})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Properties, `{"db":"db1"}`)
+
+	res, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId", Unresolved: true})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Properties, `{"host":"${ref:shared/database#host}"}`)
+
+	dependents, err := handler.ListDependents(ctx, &ListDependentsRequest{Group: "shared", Id: "database"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(dependents.Dependents), 2)
+	test.AssertEqual(t, dependents.Dependents[0].Id, "api")
+	test.AssertEqual(t, dependents.Dependents[1].Id, "someId")
+
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "broken", Properties: newStruct(t, `{"host":"${ref:shared/other#host}"}`)}})
+	_, err = handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "broken"})
+	assertStatus(t, err, codes.FailedPrecondition, "unresolved reference")
+}
+
+func TestHandler_GetConfig_Secrets(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+
+	// Secret values cannot be written without a key
+	_, err := handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"password":{"$secret":"hunter2"}}`)}})
+	assertStatus(t, err, codes.FailedPrecondition, secret.ErrNoKey.Error())
+
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+	repository := memory.NewRepository()
+	handler = NewHandler(adding.NewService(repository, adding.Secrets(keeper)), listing.NewService(repository, listing.Secrets(keeper)), deleting.NewService(repository), nil, nil, nil, nil)
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+
+	_, err = handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"user":"admin","password":{"$secret":"hunter2"}}`)}})
+	test.AssertNotError(t, err)
+
+	// Secret values are always masked over gRPC
+	res, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, res.Properties, `{"user":"admin","password":"********"}`)
+}
+
+// trailerStream is a grpc.ServerTransportStream keeping the trailers set by a handler
+type trailerStream struct {
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string                  { return "" }
+func (s *trailerStream) SetHeader(md metadata.MD) error  { return nil }
+func (s *trailerStream) SendHeader(md metadata.MD) error { return nil }
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestHandler_GetConfig_Signed(t *testing.T) {
+	handler, _ := newHandler()
+	signer, err := signing.NewSigner(bytes.Repeat([]byte{1}, 32))
+	test.AssertNotError(t, err)
+	handler.signer = signer
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	_, err = handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Version: 2, Properties: newStruct(t, `{"host":"db1","port":5432}`)}})
+	test.AssertNotError(t, err)
+
+	stream.trailer = nil
+	res, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(stream.trailer.Get(signing.SignatureTrailer)), 1)
+	test.AssertEqual(t, stream.trailer.Get(signing.KeyIDTrailer)[0], signer.KeyID())
+
+	// The signature matches the JSON of the config as the client reads it
+	props, err := (&jsonpb.Marshaler{}).MarshalToString(res.Properties)
+	test.AssertNotError(t, err)
+	conf := signing.Config{ID: res.Id, Group: res.Group, Version: int(res.Version), Properties: []byte(props)}
+	test.AssertNotError(t, signing.Verify(signer.PublicKey(), stream.trailer.Get(signing.KeyIDTrailer)[0], conf, stream.trailer.Get(signing.SignatureTrailer)[0]))
+
+	conf.Version = 3
+	test.AssertEqual(t, signing.Verify(signer.PublicKey(), signer.KeyID(), conf, stream.trailer.Get(signing.SignatureTrailer)[0]), signing.ErrInvalidSignature)
+}
+
+func TestHandler_UpdateConfig_Invalid(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"port":5432}`)}})
+
+	tests := map[string]string{
+		"id":                  "invalid update_mask: unknown path id",
+		"properties.a..b":     "invalid update_mask: invalid path properties.a..b",
+		"properties.port.max": "invalid update_mask: path properties.port.max does not refer to a property that can be set",
+	}
+
+	for path, msg := range tests {
+		_, err := handler.UpdateConfig(ctx, &UpdateConfigRequest{
+			Config:     &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"port":{"max":1}}`)},
+			UpdateMask: &field_mask.FieldMask{Paths: []string{path}},
+		})
+		assertStatus(t, err, codes.InvalidArgument, msg)
+	}
+
+	_, err := handler.UpdateConfig(ctx, &UpdateConfigRequest{Config: &Config{Group: "someGroup", Id: "someOtherId"}})
+	assertStatus(t, err, codes.NotFound, listing.ErrConfigNotFound.Error())
+}
+
+func TestHandler_PatchConfig(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{
+		Group:      "someGroup",
+		Id:         "someId",
+		Name:       "someName",
+		Properties: newStruct(t, `{"database":{"host":"db1","port":5432},"debug":true}`),
+	}})
+
+	res, err := handler.PatchConfig(ctx, &PatchConfigRequest{
+		Group: "someGroup",
+		Id:    "someId",
+		Patch: &PatchConfigRequest_MergePatch{MergePatch: newStruct(t, `{"name":"someOtherName","properties":{"debug":null}}`)},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Name, "someOtherName")
+	assertStructJSON(t, res.Properties, `{"database":{"host":"db1","port":5432}}`)
+
+	res, err = handler.PatchConfig(ctx, &PatchConfigRequest{
+		Group: "someGroup",
+		Id:    "someId",
+		Patch: &PatchConfigRequest_JsonPatch{JsonPatch: &JSONPatch{Operations: []*PatchOperation{
+			{Op: "test", Path: "/revision", Value: &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: 3}}},
+			{Op: "replace", Path: "/properties/database/port", Value: &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: 5433}}},
+			{Op: "move", From: "/properties/database/host", Path: "/properties/host"},
+		}}},
+	})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, res.Revision, int64(4))
+	assertStructJSON(t, res.Properties, `{"database":{"port":5433},"host":"db1"}`)
+
+	_, err = handler.PatchConfig(ctx, &PatchConfigRequest{
+		Group: "someGroup",
+		Id:    "someId",
+		Patch: &PatchConfigRequest_JsonPatch{JsonPatch: &JSONPatch{Operations: []*PatchOperation{
+			{Op: "test", Path: "/revision", Value: &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: 3}}},
+		}}},
+	})
+	assertStatus(t, err, codes.FailedPrecondition, `test operation 0 failed: value at "/revision" does not match`)
+
+	_, err = handler.PatchConfig(ctx, &PatchConfigRequest{Group: "someGroup", Id: "someId"})
+	assertStatus(t, err, codes.InvalidArgument, "invalid patch: required")
+}
+
+func TestHandler_Commit(t *testing.T) {
+	handler, repository := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId"}})
+
+	_, err := handler.Commit(ctx, &CommitRequest{Operations: []*Operation{
+		{Operation: &Operation_CreateGroup{CreateGroup: &Group{Id: "someOtherGroup", Schema: newStruct(t, `{"required":["host"]}`)}}},
+		{Operation: &Operation_PutConfig{PutConfig: &Config{Group: "someOtherGroup", Id: "someId", Properties: newStruct(t, `{"host":"db1"}`)}}, Revision: &wrappers.Int64Value{Value: 0}},
+		{Operation: &Operation_DeleteConfig{DeleteConfig: &ConfigRef{Group: "someGroup", Id: "someId"}}, Revision: &wrappers.Int64Value{Value: 2}},
+	}})
+	test.AssertNotError(t, err)
+
+	conf, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someOtherGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, conf.Properties, `{"host":"db1"}`)
+
+	_, err = repository.RetrieveConfig("someGroup", "someId")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	// Nothing is applied when an operation fails
+	_, err = handler.Commit(ctx, &CommitRequest{Operations: []*Operation{
+		{Operation: &Operation_PutConfig{PutConfig: &Config{Group: "someGroup", Id: "someId"}}},
+		{Operation: &Operation_PutConfig{PutConfig: &Config{Group: "someOtherGroup", Id: "someId"}}, Revision: &wrappers.Int64Value{Value: 0}},
+	}})
+	assertStatus(t, err, codes.FailedPrecondition, "operation 1: config is not at the required revision")
+
+	_, err = repository.RetrieveConfig("someGroup", "someId")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	_, err = handler.Commit(ctx, &CommitRequest{Operations: []*Operation{
+		{Operation: &Operation_PutConfig{PutConfig: &Config{Group: "someGroup", Id: "someId"}}},
+		{},
+	}})
+	assertStatus(t, err, codes.InvalidArgument, "operation 1: invalid operation: required")
+
+	_, err = handler.Commit(ctx, &CommitRequest{Operations: []*Operation{
+		{Operation: &Operation_CreateGroup{CreateGroup: &Group{Id: "some/Group"}}},
+	}})
+	assertStatus(t, err, codes.InvalidArgument, "operation 0: invalid id: has to start with a letter or digit and contain only letters, digits, '.', '_' or '-'")
+}
+
+func TestHandler_ChangeRequests(t *testing.T) {
+	handler, repository := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"db1","port":5432}`)}})
+
+	alice := auth.NewContext(ctx, auth.User{Username: "alice"})
+	bob := auth.NewContext(ctx, auth.User{Username: "bob"})
+
+	// Change requests are proposed and reviewed by the authenticated user
+	proposal := &ProposeChangeRequest{Description: "Move to db2", Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"db2"}`)}}
+	_, err := handler.ProposeChange(ctx, proposal)
+	assertStatus(t, err, codes.Unauthenticated, "change requests are proposed and reviewed by an authenticated user")
+
+	_, err = handler.ProposeChange(alice, proposal)
+	assertStatus(t, err, codes.FailedPrecondition, "group has no approvers")
+
+	test.AssertNotError(t, repository.StoreApprovers("someGroup", []string{"bob"}))
+	approvers, err := handler.GetApprovers(ctx, &GetApproversRequest{Group: "someGroup"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(approvers.Approvers), 1)
+
+	// Configs in a group with approvers are only changed by applying approved change requests
+	_, err = handler.UpdateConfig(ctx, &UpdateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"db3"}`)}})
+	assertStatus(t, err, codes.FailedPrecondition, "configs in the group are changed by approved change requests")
+	_, err = handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "newId", Properties: newStruct(t, `{}`)}})
+	assertStatus(t, err, codes.FailedPrecondition, "configs in the group are changed by approved change requests")
+	_, err = handler.Commit(ctx, &CommitRequest{Operations: []*Operation{
+		{Operation: &Operation_PutConfig{PutConfig: &Config{Group: "someGroup", Id: "someId", Properties: newStruct(t, `{"host":"db3"}`)}}},
+	}})
+	assertStatus(t, err, codes.FailedPrecondition, "operation 0: configs in the group are changed by approved change requests")
+
+	cr, err := handler.ProposeChange(alice, proposal)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, "open")
+	test.AssertEqual(t, cr.History[0].User, "alice")
+	test.AssertEqual(t, cr.BaseRevision, int64(2))
+	assertStructJSON(t, cr.Config.Properties, `{"host":"db2"}`)
+	test.AssertEqual(t, len(cr.Diff), 2)
+	test.AssertEqual(t, cr.Diff[0].Path, "/host")
+	test.AssertEqual(t, cr.Diff[0].From.GetStringValue(), "db1")
+	test.AssertEqual(t, cr.Diff[0].To.GetStringValue(), "db2")
+	test.AssertEqual(t, cr.Diff[1].Type, "removed")
+	test.AssertEqual(t, cr.Diff[1].From.GetNumberValue(), float64(5432))
+	test.AssertEqual(t, cr.Diff[1].To == nil, true)
+
+	_, err = handler.ApproveChangeRequest(alice, &ReviewChangeRequestRequest{Id: cr.Id})
+	assertStatus(t, err, codes.PermissionDenied, "user is not an approver of the group")
+
+	_, err = handler.ApproveChangeRequest(ctx, &ReviewChangeRequestRequest{Id: cr.Id})
+	assertStatus(t, err, codes.Unauthenticated, "change requests are proposed and reviewed by an authenticated user")
+	_, err = handler.RejectChangeRequest(ctx, &ReviewChangeRequestRequest{Id: cr.Id})
+	assertStatus(t, err, codes.Unauthenticated, "change requests are proposed and reviewed by an authenticated user")
+
+	_, err = handler.ApplyChangeRequest(alice, &ReviewChangeRequestRequest{Id: cr.Id})
+	assertStatus(t, err, codes.FailedPrecondition, "change request is not approved")
+
+	_, err = handler.ApproveChangeRequest(bob, &ReviewChangeRequestRequest{Id: cr.Id, Comment: "Looks good"})
+	test.AssertNotError(t, err)
+
+	_, err = handler.ApplyChangeRequest(ctx, &ReviewChangeRequestRequest{Id: cr.Id})
+	assertStatus(t, err, codes.Unauthenticated, "change requests are proposed and reviewed by an authenticated user")
+
+	cr, err = handler.ApplyChangeRequest(alice, &ReviewChangeRequestRequest{Id: cr.Id})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, "applied")
+	test.AssertEqual(t, len(cr.History), 3)
+	test.AssertEqual(t, cr.History[1].User, "bob")
+	test.AssertEqual(t, cr.History[1].Comment, "Looks good")
+
+	conf, err := handler.GetConfig(ctx, &GetConfigRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	assertStructJSON(t, conf.Properties, `{"host":"db2"}`)
+
+	list, err := handler.ListChangeRequests(ctx, &ListChangeRequestsRequest{Group: "someGroup", State: "applied"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(list.ChangeRequests), 1)
+
+	_, err = handler.GetChangeRequest(ctx, &GetChangeRequestRequest{Id: 100})
+	assertStatus(t, err, codes.NotFound, "change request not found")
+
+	_, err = handler.ProposeChange(alice, &ProposeChangeRequest{})
+	assertStatus(t, err, codes.InvalidArgument, "invalid config: required")
+}
+
+func TestHandler_Schedule(t *testing.T) {
+	handler, repository := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "someGroup"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "someGroup", Id: "someId", Version: 1, Properties: newStruct(t, `{"host":"db1"}`)}})
+
+	at, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	test.AssertNotError(t, err)
+
+	_, err = handler.ScheduleConfig(ctx, &ScheduleConfigRequest{Config: &Config{Group: "someGroup", Id: "someId"}})
+	assertStatus(t, err, codes.InvalidArgument, "invalid activates_at: required")
+
+	past, err := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+	test.AssertNotError(t, err)
+	_, err = handler.ScheduleConfig(ctx, &ScheduleConfigRequest{ActivatesAt: past, Config: &Config{Group: "someGroup", Id: "someId"}})
+	assertStatus(t, err, codes.InvalidArgument, "invalid activatesAt: has to be in the future")
+
+	a, err := handler.ScheduleConfig(ctx, &ScheduleConfigRequest{ActivatesAt: at, Config: &Config{Group: "someGroup", Id: "someId", Version: 2, Properties: newStruct(t, `{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, "pending")
+	test.AssertEqual(t, a.Activated == nil, true)
+	assertStructJSON(t, a.Config.Properties, `{"host":"db2"}`)
+
+	schedule, err := handler.GetSchedule(ctx, &GetScheduleRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Active.Version, int32(1))
+	test.AssertEqual(t, schedule.Next.Id, a.Id)
+	test.AssertEqual(t, schedule.Next.Config.Version, int32(2))
+	test.AssertEqual(t, len(schedule.Pending), 1)
+
+	_, err = handler.GetActivation(ctx, &ActivationRequest{Group: "someGroup", Id: "someOtherId", ActivationId: a.Id})
+	assertStatus(t, err, codes.NotFound, "activation not found")
+
+	a, err = handler.CancelActivation(ctx, &ActivationRequest{Group: "someGroup", Id: "someId", ActivationId: a.Id})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, "cancelled")
+	test.AssertEqual(t, a.Activated != nil, true)
+
+	_, err = handler.CancelActivation(ctx, &ActivationRequest{Group: "someGroup", Id: "someId", ActivationId: a.Id})
+	assertStatus(t, err, codes.FailedPrecondition, "activation is not pending")
+
+	schedule, err = handler.GetSchedule(ctx, &GetScheduleRequest{Group: "someGroup", Id: "someId"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Next == nil, true)
+	test.AssertEqual(t, len(schedule.Pending), 0)
+
+	conf, err := repository.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Version, 1)
+}
+
+func TestHandler_Diff(t *testing.T) {
+	handler, _ := newHandler()
+	ctx := context.Background()
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "staging"}})
+	handler.CreateGroup(ctx, &CreateGroupRequest{Group: &Group{Id: "prod"}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "staging", Id: "api", Version: 1, Properties: newStruct(t, `{"host":"db1","port":5432}`)}})
+	handler.UpdateConfig(ctx, &UpdateConfigRequest{Config: &Config{Group: "staging", Id: "api", Version: 2, Properties: newStruct(t, `{"host":"db2","port":5432}`)}})
+	handler.CreateConfig(ctx, &CreateConfigRequest{Config: &Config{Group: "prod", Id: "api", Version: 1, Properties: newStruct(t, `{"host":"db3"}`)}})
+
+	d, err := handler.DiffVersions(ctx, &DiffVersionsRequest{Group: "staging", Id: "api", From: 1, To: &wrappers.Int32Value{Value: 2}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.From.Revision, int64(3))
+	test.AssertEqual(t, d.To.Version, int32(2))
+	test.AssertEqual(t, len(d.Differences), 1)
+
+	d, err = handler.DiffVersions(ctx, &DiffVersionsRequest{Group: "staging", Id: "api", From: 2})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(d.Differences), 0)
+
+	_, err = handler.DiffVersions(ctx, &DiffVersionsRequest{Group: "staging", Id: "api", From: 3})
+	assertStatus(t, err, codes.NotFound, "config version not found")
+
+	d, err = handler.DiffRevisions(ctx, &DiffRevisionsRequest{Group: "staging", Id: "api", From: 3, Unified: true})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.From.Version, int32(1))
+	test.AssertEqual(t, d.To.Revision, int64(4))
+	test.AssertEqual(t, len(d.Differences), 1)
+	test.AssertEqual(t, d.Differences[0].Type, "changed")
+	test.AssertEqual(t, d.Differences[0].Path, "/host")
+	test.AssertEqual(t, d.Differences[0].To.GetStringValue(), "db2")
+	test.AssertEqual(t, d.Unified, "--- staging/api@3\n+++ staging/api@4\n@@ -1,4 +1,4 @@\n {\n-  \"host\": \"db1\",\n+  \"host\": \"db2\",\n   \"port\": 5432\n }\n")
+
+	_, err = handler.DiffRevisions(ctx, &DiffRevisionsRequest{Group: "staging", Id: "api", From: 5})
+	assertStatus(t, err, codes.NotFound, "config revision not found")
+
+	_, err = handler.DiffRevisions(ctx, &DiffRevisionsRequest{Group: "staging", Id: "api"})
+	assertStatus(t, err, codes.InvalidArgument, "invalid from: has to be a revision")
+
+	d, err = handler.DiffConfigs(ctx, &DiffConfigsRequest{A: &ConfigRef{Group: "staging", Id: "api"}, B: &ConfigRef{Group: "prod", Id: "api"}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, d.To.Group, "prod")
+	test.AssertEqual(t, len(d.Differences), 2)
+	test.AssertEqual(t, d.Differences[1].Type, "removed")
+	test.AssertEqual(t, d.Differences[1].From.GetNumberValue(), float64(5432))
+	test.AssertEqual(t, d.Differences[1].To == nil, true)
+	test.AssertEqual(t, d.Unified, "")
+
+	_, err = handler.DiffConfigs(ctx, &DiffConfigsRequest{A: &ConfigRef{Group: "staging", Id: "api"}})
+	assertStatus(t, err, codes.InvalidArgument, "invalid b: required")
+}
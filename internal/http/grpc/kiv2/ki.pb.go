@@ -0,0 +1,4568 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ki.proto
+
+// ki.v2 is the second version of the gRPC API. Properties and schemas are structured values instead of JSON encoded bytes,
+// and both groups and configs can be created, updated, listed and deleted.
+
+package kiv2
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
+	math "math"
+)
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Group struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// revision is output only
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// config_count is output only
+	ConfigCount int32 `protobuf:"varint,3,opt,name=config_count,json=configCount,proto3" json:"config_count,omitempty"`
+	// schema is the JSON Schema the properties of configs in the group have to satisfy, if set. Left out when listing groups.
+	Schema *_struct.Struct `protobuf:"bytes,4,opt,name=schema,proto3" json:"schema,omitempty"`
+	// defaults are the properties configs in the group inherit, if set. Left out when listing groups.
+	Defaults             *Defaults `protobuf:"bytes,5,opt,name=defaults,proto3" json:"defaults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Group) Reset()         { *m = Group{} }
+func (m *Group) String() string { return proto.CompactTextString(m) }
+func (*Group) ProtoMessage()    {}
+func (*Group) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{0}
+}
+func (m *Group) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Group.Unmarshal(m, b)
+}
+func (m *Group) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Group.Marshal(b, m, deterministic)
+}
+func (m *Group) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Group.Merge(m, src)
+}
+func (m *Group) XXX_Size() int {
+	return xxx_messageInfo_Group.Size(m)
+}
+func (m *Group) XXX_DiscardUnknown() {
+	xxx_messageInfo_Group.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Group proto.InternalMessageInfo
+
+func (m *Group) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Group) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Group) GetConfigCount() int32 {
+	if m != nil {
+		return m.ConfigCount
+	}
+	return 0
+}
+
+func (m *Group) GetSchema() *_struct.Struct {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+func (m *Group) GetDefaults() *Defaults {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
+// Defaults are the properties configs in a group inherit and the rules for merging config properties onto them. Arrays is
+// one of "replace" and "append", and nulls one of "keep" and "delete". Both default to the first.
+type Defaults struct {
+	Properties           *_struct.Struct `protobuf:"bytes,1,opt,name=properties,proto3" json:"properties,omitempty"`
+	Arrays               string          `protobuf:"bytes,2,opt,name=arrays,proto3" json:"arrays,omitempty"`
+	Nulls                string          `protobuf:"bytes,3,opt,name=nulls,proto3" json:"nulls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Defaults) Reset()         { *m = Defaults{} }
+func (m *Defaults) String() string { return proto.CompactTextString(m) }
+func (*Defaults) ProtoMessage()    {}
+func (*Defaults) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{1}
+}
+func (m *Defaults) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Defaults.Unmarshal(m, b)
+}
+func (m *Defaults) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Defaults.Marshal(b, m, deterministic)
+}
+func (m *Defaults) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Defaults.Merge(m, src)
+}
+func (m *Defaults) XXX_Size() int {
+	return xxx_messageInfo_Defaults.Size(m)
+}
+func (m *Defaults) XXX_DiscardUnknown() {
+	xxx_messageInfo_Defaults.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Defaults proto.InternalMessageInfo
+
+func (m *Defaults) GetProperties() *_struct.Struct {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func (m *Defaults) GetArrays() string {
+	if m != nil {
+		return m.Arrays
+	}
+	return ""
+}
+
+func (m *Defaults) GetNulls() string {
+	if m != nil {
+		return m.Nulls
+	}
+	return ""
+}
+
+type CreateGroupRequest struct {
+	Group                *Group   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateGroupRequest) Reset()         { *m = CreateGroupRequest{} }
+func (m *CreateGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateGroupRequest) ProtoMessage()    {}
+func (*CreateGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{2}
+}
+func (m *CreateGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGroupRequest.Unmarshal(m, b)
+}
+func (m *CreateGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGroupRequest.Merge(m, src)
+}
+func (m *CreateGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateGroupRequest.Size(m)
+}
+func (m *CreateGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateGroupRequest proto.InternalMessageInfo
+
+func (m *CreateGroupRequest) GetGroup() *Group {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+type GetGroupRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetGroupRequest) Reset()         { *m = GetGroupRequest{} }
+func (m *GetGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*GetGroupRequest) ProtoMessage()    {}
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{3}
+}
+func (m *GetGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGroupRequest.Unmarshal(m, b)
+}
+func (m *GetGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *GetGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGroupRequest.Merge(m, src)
+}
+func (m *GetGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_GetGroupRequest.Size(m)
+}
+func (m *GetGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGroupRequest proto.InternalMessageInfo
+
+func (m *GetGroupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListGroupsRequest struct {
+	PageSize  int32  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// prefix lists only groups with ids starting with it
+	Prefix string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// order_by is one of "id" or "revision", optionally prefixed with "-" for descending order. Defaults to "id".
+	OrderBy              string   `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupsRequest) Reset()         { *m = ListGroupsRequest{} }
+func (m *ListGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsRequest) ProtoMessage()    {}
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{4}
+}
+func (m *ListGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsRequest.Unmarshal(m, b)
+}
+func (m *ListGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsRequest.Merge(m, src)
+}
+func (m *ListGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsRequest.Size(m)
+}
+func (m *ListGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsRequest proto.InternalMessageInfo
+
+func (m *ListGroupsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListGroupsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListGroupsRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListGroupsRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+type ListGroupsResponse struct {
+	Groups               []*Group `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupsResponse) Reset()         { *m = ListGroupsResponse{} }
+func (m *ListGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsResponse) ProtoMessage()    {}
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{5}
+}
+func (m *ListGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsResponse.Merge(m, src)
+}
+func (m *ListGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsResponse.Size(m)
+}
+func (m *ListGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsResponse proto.InternalMessageInfo
+
+func (m *ListGroupsResponse) GetGroups() []*Group {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *ListGroupsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// UpdateGroupRequest updates the fields of group.id listed in update_mask. The mutable fields are "schema" and "defaults".
+// An empty mask updates every mutable field.
+type UpdateGroupRequest struct {
+	Group                *Group                `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateGroupRequest) Reset()         { *m = UpdateGroupRequest{} }
+func (m *UpdateGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateGroupRequest) ProtoMessage()    {}
+func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{6}
+}
+func (m *UpdateGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateGroupRequest.Unmarshal(m, b)
+}
+func (m *UpdateGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateGroupRequest.Merge(m, src)
+}
+func (m *UpdateGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateGroupRequest.Size(m)
+}
+func (m *UpdateGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateGroupRequest proto.InternalMessageInfo
+
+func (m *UpdateGroupRequest) GetGroup() *Group {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+func (m *UpdateGroupRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+type DeleteGroupRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteGroupRequest) Reset()         { *m = DeleteGroupRequest{} }
+func (m *DeleteGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteGroupRequest) ProtoMessage()    {}
+func (*DeleteGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{7}
+}
+func (m *DeleteGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteGroupRequest.Unmarshal(m, b)
+}
+func (m *DeleteGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteGroupRequest.Merge(m, src)
+}
+func (m *DeleteGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteGroupRequest.Size(m)
+}
+func (m *DeleteGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteGroupRequest proto.InternalMessageInfo
+
+func (m *DeleteGroupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type Config struct {
+	Group   string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name    string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Version int32  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// revision is output only
+	Revision int64 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// last_modified is output only
+	LastModified *timestamp.Timestamp `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	// properties are the effective properties of the config, merged onto the defaults of its group and the properties of
+	// its ancestors, unless the config is gotten raw.
+	Properties *_struct.Struct `protobuf:"bytes,7,opt,name=properties,proto3" json:"properties,omitempty"`
+	// parent is the id of the config in the same group the config inherits properties from, if set. It cannot be updated.
+	Parent string `protobuf:"bytes,8,opt,name=parent,proto3" json:"parent,omitempty"`
+	// environment is output only, and set when the config is gotten in an environment
+	Environment          *Environment `protobuf:"bytes,9,opt,name=environment,proto3" json:"environment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{8}
+}
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func (m *Config) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *Config) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Config) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Config) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Config) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Config) GetLastModified() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastModified
+	}
+	return nil
+}
+
+func (m *Config) GetProperties() *_struct.Struct {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func (m *Config) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *Config) GetEnvironment() *Environment {
+	if m != nil {
+		return m.Environment
+	}
+	return nil
+}
+
+// Environment identifies the overlay merged onto the properties of a config gotten in an environment
+type Environment struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Environment) Reset()         { *m = Environment{} }
+func (m *Environment) String() string { return proto.CompactTextString(m) }
+func (*Environment) ProtoMessage()    {}
+func (*Environment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{9}
+}
+func (m *Environment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Environment.Unmarshal(m, b)
+}
+func (m *Environment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Environment.Marshal(b, m, deterministic)
+}
+func (m *Environment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Environment.Merge(m, src)
+}
+func (m *Environment) XXX_Size() int {
+	return xxx_messageInfo_Environment.Size(m)
+}
+func (m *Environment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Environment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Environment proto.InternalMessageInfo
+
+func (m *Environment) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Environment) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Environment) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+type CreateConfigRequest struct {
+	Config               *Config  `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateConfigRequest) Reset()         { *m = CreateConfigRequest{} }
+func (m *CreateConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateConfigRequest) ProtoMessage()    {}
+func (*CreateConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{10}
+}
+func (m *CreateConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateConfigRequest.Unmarshal(m, b)
+}
+func (m *CreateConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateConfigRequest.Merge(m, src)
+}
+func (m *CreateConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateConfigRequest.Size(m)
+}
+func (m *CreateConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateConfigRequest proto.InternalMessageInfo
+
+func (m *CreateConfigRequest) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type GetConfigRequest struct {
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id    string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// raw gets the config with only its own properties
+	Raw bool `protobuf:"varint,3,opt,name=raw,proto3" json:"raw,omitempty"`
+	// environment gets the config with the overlay of the environment merged onto its effective properties. It cannot be
+	// combined with raw.
+	Environment string `protobuf:"bytes,4,opt,name=environment,proto3" json:"environment,omitempty"`
+	// unresolved gets the config with references like "${ref:shared/database#host}" left as they are. Raw configs are
+	// never resolved.
+	Unresolved           bool     `protobuf:"varint,5,opt,name=unresolved,proto3" json:"unresolved,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
+func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*GetConfigRequest) ProtoMessage()    {}
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{11}
+}
+func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConfigRequest.Unmarshal(m, b)
+}
+func (m *GetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *GetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConfigRequest.Merge(m, src)
+}
+func (m *GetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_GetConfigRequest.Size(m)
+}
+func (m *GetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetConfigRequest proto.InternalMessageInfo
+
+func (m *GetConfigRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GetConfigRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GetConfigRequest) GetRaw() bool {
+	if m != nil {
+		return m.Raw
+	}
+	return false
+}
+
+func (m *GetConfigRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *GetConfigRequest) GetUnresolved() bool {
+	if m != nil {
+		return m.Unresolved
+	}
+	return false
+}
+
+// ListConfigsRequest lists the configs of a group ordered by id
+type ListConfigsRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	PageSize             int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListConfigsRequest) Reset()         { *m = ListConfigsRequest{} }
+func (m *ListConfigsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListConfigsRequest) ProtoMessage()    {}
+func (*ListConfigsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{12}
+}
+func (m *ListConfigsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListConfigsRequest.Unmarshal(m, b)
+}
+func (m *ListConfigsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListConfigsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListConfigsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListConfigsRequest.Merge(m, src)
+}
+func (m *ListConfigsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListConfigsRequest.Size(m)
+}
+func (m *ListConfigsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListConfigsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListConfigsRequest proto.InternalMessageInfo
+
+func (m *ListConfigsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListConfigsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListConfigsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type ListConfigsResponse struct {
+	Configs              []*Config `protobuf:"bytes,1,rep,name=configs,proto3" json:"configs,omitempty"`
+	NextPageToken        string    `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListConfigsResponse) Reset()         { *m = ListConfigsResponse{} }
+func (m *ListConfigsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListConfigsResponse) ProtoMessage()    {}
+func (*ListConfigsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{13}
+}
+func (m *ListConfigsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListConfigsResponse.Unmarshal(m, b)
+}
+func (m *ListConfigsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListConfigsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListConfigsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListConfigsResponse.Merge(m, src)
+}
+func (m *ListConfigsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListConfigsResponse.Size(m)
+}
+func (m *ListConfigsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListConfigsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListConfigsResponse proto.InternalMessageInfo
+
+func (m *ListConfigsResponse) GetConfigs() []*Config {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *ListConfigsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// UpdateConfigRequest updates the fields of the config identified by config.group and config.id listed in update_mask. The
+// mutable fields are "name", "version" and "properties". A single property is updated by a path into the properties, like
+// "properties.database.hosts[0]", and removed if it is not set in config. An empty mask updates every mutable field.
+type UpdateConfigRequest struct {
+	Config               *Config               `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateConfigRequest) Reset()         { *m = UpdateConfigRequest{} }
+func (m *UpdateConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateConfigRequest) ProtoMessage()    {}
+func (*UpdateConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{14}
+}
+func (m *UpdateConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateConfigRequest.Unmarshal(m, b)
+}
+func (m *UpdateConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateConfigRequest.Merge(m, src)
+}
+func (m *UpdateConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateConfigRequest.Size(m)
+}
+func (m *UpdateConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateConfigRequest proto.InternalMessageInfo
+
+func (m *UpdateConfigRequest) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *UpdateConfigRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+type PatchConfigRequest struct {
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id    string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Patch:
+	//	*PatchConfigRequest_MergePatch
+	//	*PatchConfigRequest_JsonPatch
+	Patch                isPatchConfigRequest_Patch `protobuf_oneof:"patch"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *PatchConfigRequest) Reset()         { *m = PatchConfigRequest{} }
+func (m *PatchConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*PatchConfigRequest) ProtoMessage()    {}
+func (*PatchConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{15}
+}
+func (m *PatchConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PatchConfigRequest.Unmarshal(m, b)
+}
+func (m *PatchConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PatchConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *PatchConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PatchConfigRequest.Merge(m, src)
+}
+func (m *PatchConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_PatchConfigRequest.Size(m)
+}
+func (m *PatchConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PatchConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PatchConfigRequest proto.InternalMessageInfo
+
+func (m *PatchConfigRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *PatchConfigRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type isPatchConfigRequest_Patch interface {
+	isPatchConfigRequest_Patch()
+}
+
+type PatchConfigRequest_MergePatch struct {
+	MergePatch *_struct.Struct `protobuf:"bytes,3,opt,name=merge_patch,json=mergePatch,proto3,oneof"`
+}
+
+type PatchConfigRequest_JsonPatch struct {
+	JsonPatch *JSONPatch `protobuf:"bytes,4,opt,name=json_patch,json=jsonPatch,proto3,oneof"`
+}
+
+func (*PatchConfigRequest_MergePatch) isPatchConfigRequest_Patch() {}
+
+func (*PatchConfigRequest_JsonPatch) isPatchConfigRequest_Patch() {}
+
+func (m *PatchConfigRequest) GetPatch() isPatchConfigRequest_Patch {
+	if m != nil {
+		return m.Patch
+	}
+	return nil
+}
+
+func (m *PatchConfigRequest) GetMergePatch() *_struct.Struct {
+	if x, ok := m.GetPatch().(*PatchConfigRequest_MergePatch); ok {
+		return x.MergePatch
+	}
+	return nil
+}
+
+func (m *PatchConfigRequest) GetJsonPatch() *JSONPatch {
+	if x, ok := m.GetPatch().(*PatchConfigRequest_JsonPatch); ok {
+		return x.JsonPatch
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*PatchConfigRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*PatchConfigRequest_MergePatch)(nil),
+		(*PatchConfigRequest_JsonPatch)(nil),
+	}
+}
+
+type JSONPatch struct {
+	Operations           []*PatchOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *JSONPatch) Reset()         { *m = JSONPatch{} }
+func (m *JSONPatch) String() string { return proto.CompactTextString(m) }
+func (*JSONPatch) ProtoMessage()    {}
+func (*JSONPatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{16}
+}
+func (m *JSONPatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JSONPatch.Unmarshal(m, b)
+}
+func (m *JSONPatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JSONPatch.Marshal(b, m, deterministic)
+}
+func (m *JSONPatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JSONPatch.Merge(m, src)
+}
+func (m *JSONPatch) XXX_Size() int {
+	return xxx_messageInfo_JSONPatch.Size(m)
+}
+func (m *JSONPatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_JSONPatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JSONPatch proto.InternalMessageInfo
+
+func (m *JSONPatch) GetOperations() []*PatchOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+// PatchOperation is an operation of a JSON Patch. Paths are JSON Pointers into the config, like "/properties/port".
+type PatchOperation struct {
+	Op                   string         `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Path                 string         `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	From                 string         `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	Value                *_struct.Value `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PatchOperation) Reset()         { *m = PatchOperation{} }
+func (m *PatchOperation) String() string { return proto.CompactTextString(m) }
+func (*PatchOperation) ProtoMessage()    {}
+func (*PatchOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{17}
+}
+func (m *PatchOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PatchOperation.Unmarshal(m, b)
+}
+func (m *PatchOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PatchOperation.Marshal(b, m, deterministic)
+}
+func (m *PatchOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PatchOperation.Merge(m, src)
+}
+func (m *PatchOperation) XXX_Size() int {
+	return xxx_messageInfo_PatchOperation.Size(m)
+}
+func (m *PatchOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PatchOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PatchOperation proto.InternalMessageInfo
+
+func (m *PatchOperation) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *PatchOperation) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PatchOperation) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *PatchOperation) GetValue() *_struct.Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type DeleteConfigRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteConfigRequest) Reset()         { *m = DeleteConfigRequest{} }
+func (m *DeleteConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteConfigRequest) ProtoMessage()    {}
+func (*DeleteConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{18}
+}
+func (m *DeleteConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteConfigRequest.Unmarshal(m, b)
+}
+func (m *DeleteConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteConfigRequest.Merge(m, src)
+}
+func (m *DeleteConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteConfigRequest.Size(m)
+}
+func (m *DeleteConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteConfigRequest proto.InternalMessageInfo
+
+func (m *DeleteConfigRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *DeleteConfigRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListDependentsRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDependentsRequest) Reset()         { *m = ListDependentsRequest{} }
+func (m *ListDependentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDependentsRequest) ProtoMessage()    {}
+func (*ListDependentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{19}
+}
+func (m *ListDependentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDependentsRequest.Unmarshal(m, b)
+}
+func (m *ListDependentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDependentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDependentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDependentsRequest.Merge(m, src)
+}
+func (m *ListDependentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDependentsRequest.Size(m)
+}
+func (m *ListDependentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDependentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDependentsRequest proto.InternalMessageInfo
+
+func (m *ListDependentsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListDependentsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ConfigRef identifies a config
+type ConfigRef struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigRef) Reset()         { *m = ConfigRef{} }
+func (m *ConfigRef) String() string { return proto.CompactTextString(m) }
+func (*ConfigRef) ProtoMessage()    {}
+func (*ConfigRef) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{20}
+}
+func (m *ConfigRef) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigRef.Unmarshal(m, b)
+}
+func (m *ConfigRef) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigRef.Marshal(b, m, deterministic)
+}
+func (m *ConfigRef) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigRef.Merge(m, src)
+}
+func (m *ConfigRef) XXX_Size() int {
+	return xxx_messageInfo_ConfigRef.Size(m)
+}
+func (m *ConfigRef) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigRef.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigRef proto.InternalMessageInfo
+
+func (m *ConfigRef) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ConfigRef) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListDependentsResponse struct {
+	Dependents           []*ConfigRef `protobuf:"bytes,1,rep,name=dependents,proto3" json:"dependents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListDependentsResponse) Reset()         { *m = ListDependentsResponse{} }
+func (m *ListDependentsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDependentsResponse) ProtoMessage()    {}
+func (*ListDependentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{21}
+}
+func (m *ListDependentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDependentsResponse.Unmarshal(m, b)
+}
+func (m *ListDependentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDependentsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDependentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDependentsResponse.Merge(m, src)
+}
+func (m *ListDependentsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDependentsResponse.Size(m)
+}
+func (m *ListDependentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDependentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDependentsResponse proto.InternalMessageInfo
+
+func (m *ListDependentsResponse) GetDependents() []*ConfigRef {
+	if m != nil {
+		return m.Dependents
+	}
+	return nil
+}
+
+// Overlay holds the properties of a config in a named environment, like "staging" or "prod"
+type Overlay struct {
+	Group       string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Config      string `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Environment string `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	Version     int32  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// revision is output only
+	Revision int64 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// last_modified is output only
+	LastModified *timestamp.Timestamp `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Properties   *_struct.Struct      `protobuf:"bytes,7,opt,name=properties,proto3" json:"properties,omitempty"`
+	// promoted_from is output only, and set if the overlay was promoted from another environment
+	PromotedFrom         *Promotion `protobuf:"bytes,8,opt,name=promoted_from,json=promotedFrom,proto3" json:"promoted_from,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Overlay) Reset()         { *m = Overlay{} }
+func (m *Overlay) String() string { return proto.CompactTextString(m) }
+func (*Overlay) ProtoMessage()    {}
+func (*Overlay) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{22}
+}
+func (m *Overlay) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Overlay.Unmarshal(m, b)
+}
+func (m *Overlay) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Overlay.Marshal(b, m, deterministic)
+}
+func (m *Overlay) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Overlay.Merge(m, src)
+}
+func (m *Overlay) XXX_Size() int {
+	return xxx_messageInfo_Overlay.Size(m)
+}
+func (m *Overlay) XXX_DiscardUnknown() {
+	xxx_messageInfo_Overlay.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Overlay proto.InternalMessageInfo
+
+func (m *Overlay) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *Overlay) GetConfig() string {
+	if m != nil {
+		return m.Config
+	}
+	return ""
+}
+
+func (m *Overlay) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *Overlay) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Overlay) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Overlay) GetLastModified() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastModified
+	}
+	return nil
+}
+
+func (m *Overlay) GetProperties() *_struct.Struct {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func (m *Overlay) GetPromotedFrom() *Promotion {
+	if m != nil {
+		return m.PromotedFrom
+	}
+	return nil
+}
+
+// Promotion identifies the environment and revision an overlay was promoted from
+type Promotion struct {
+	Environment          string   `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Revision             int64    `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Promotion) Reset()         { *m = Promotion{} }
+func (m *Promotion) String() string { return proto.CompactTextString(m) }
+func (*Promotion) ProtoMessage()    {}
+func (*Promotion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{23}
+}
+func (m *Promotion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Promotion.Unmarshal(m, b)
+}
+func (m *Promotion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Promotion.Marshal(b, m, deterministic)
+}
+func (m *Promotion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Promotion.Merge(m, src)
+}
+func (m *Promotion) XXX_Size() int {
+	return xxx_messageInfo_Promotion.Size(m)
+}
+func (m *Promotion) XXX_DiscardUnknown() {
+	xxx_messageInfo_Promotion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Promotion proto.InternalMessageInfo
+
+func (m *Promotion) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *Promotion) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+type SetOverlayRequest struct {
+	Overlay              *Overlay `protobuf:"bytes,1,opt,name=overlay,proto3" json:"overlay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetOverlayRequest) Reset()         { *m = SetOverlayRequest{} }
+func (m *SetOverlayRequest) String() string { return proto.CompactTextString(m) }
+func (*SetOverlayRequest) ProtoMessage()    {}
+func (*SetOverlayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{24}
+}
+func (m *SetOverlayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetOverlayRequest.Unmarshal(m, b)
+}
+func (m *SetOverlayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetOverlayRequest.Marshal(b, m, deterministic)
+}
+func (m *SetOverlayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetOverlayRequest.Merge(m, src)
+}
+func (m *SetOverlayRequest) XXX_Size() int {
+	return xxx_messageInfo_SetOverlayRequest.Size(m)
+}
+func (m *SetOverlayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetOverlayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetOverlayRequest proto.InternalMessageInfo
+
+func (m *SetOverlayRequest) GetOverlay() *Overlay {
+	if m != nil {
+		return m.Overlay
+	}
+	return nil
+}
+
+type GetOverlayRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Config               string   `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Environment          string   `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOverlayRequest) Reset()         { *m = GetOverlayRequest{} }
+func (m *GetOverlayRequest) String() string { return proto.CompactTextString(m) }
+func (*GetOverlayRequest) ProtoMessage()    {}
+func (*GetOverlayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{25}
+}
+func (m *GetOverlayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOverlayRequest.Unmarshal(m, b)
+}
+func (m *GetOverlayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOverlayRequest.Marshal(b, m, deterministic)
+}
+func (m *GetOverlayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOverlayRequest.Merge(m, src)
+}
+func (m *GetOverlayRequest) XXX_Size() int {
+	return xxx_messageInfo_GetOverlayRequest.Size(m)
+}
+func (m *GetOverlayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOverlayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOverlayRequest proto.InternalMessageInfo
+
+func (m *GetOverlayRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GetOverlayRequest) GetConfig() string {
+	if m != nil {
+		return m.Config
+	}
+	return ""
+}
+
+func (m *GetOverlayRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+type ListOverlaysRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Config               string   `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOverlaysRequest) Reset()         { *m = ListOverlaysRequest{} }
+func (m *ListOverlaysRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOverlaysRequest) ProtoMessage()    {}
+func (*ListOverlaysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{26}
+}
+func (m *ListOverlaysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOverlaysRequest.Unmarshal(m, b)
+}
+func (m *ListOverlaysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOverlaysRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOverlaysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOverlaysRequest.Merge(m, src)
+}
+func (m *ListOverlaysRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOverlaysRequest.Size(m)
+}
+func (m *ListOverlaysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOverlaysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOverlaysRequest proto.InternalMessageInfo
+
+func (m *ListOverlaysRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListOverlaysRequest) GetConfig() string {
+	if m != nil {
+		return m.Config
+	}
+	return ""
+}
+
+type ListOverlaysResponse struct {
+	Overlays             []*Overlay `protobuf:"bytes,1,rep,name=overlays,proto3" json:"overlays,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListOverlaysResponse) Reset()         { *m = ListOverlaysResponse{} }
+func (m *ListOverlaysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListOverlaysResponse) ProtoMessage()    {}
+func (*ListOverlaysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{27}
+}
+func (m *ListOverlaysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOverlaysResponse.Unmarshal(m, b)
+}
+func (m *ListOverlaysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOverlaysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListOverlaysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOverlaysResponse.Merge(m, src)
+}
+func (m *ListOverlaysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListOverlaysResponse.Size(m)
+}
+func (m *ListOverlaysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOverlaysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOverlaysResponse proto.InternalMessageInfo
+
+func (m *ListOverlaysResponse) GetOverlays() []*Overlay {
+	if m != nil {
+		return m.Overlays
+	}
+	return nil
+}
+
+type PromoteOverlayRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Config               string   `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	From                 string   `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromoteOverlayRequest) Reset()         { *m = PromoteOverlayRequest{} }
+func (m *PromoteOverlayRequest) String() string { return proto.CompactTextString(m) }
+func (*PromoteOverlayRequest) ProtoMessage()    {}
+func (*PromoteOverlayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{28}
+}
+func (m *PromoteOverlayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteOverlayRequest.Unmarshal(m, b)
+}
+func (m *PromoteOverlayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteOverlayRequest.Marshal(b, m, deterministic)
+}
+func (m *PromoteOverlayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteOverlayRequest.Merge(m, src)
+}
+func (m *PromoteOverlayRequest) XXX_Size() int {
+	return xxx_messageInfo_PromoteOverlayRequest.Size(m)
+}
+func (m *PromoteOverlayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteOverlayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteOverlayRequest proto.InternalMessageInfo
+
+func (m *PromoteOverlayRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *PromoteOverlayRequest) GetConfig() string {
+	if m != nil {
+		return m.Config
+	}
+	return ""
+}
+
+func (m *PromoteOverlayRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *PromoteOverlayRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type DeleteOverlayRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Config               string   `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Environment          string   `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteOverlayRequest) Reset()         { *m = DeleteOverlayRequest{} }
+func (m *DeleteOverlayRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteOverlayRequest) ProtoMessage()    {}
+func (*DeleteOverlayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{29}
+}
+func (m *DeleteOverlayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteOverlayRequest.Unmarshal(m, b)
+}
+func (m *DeleteOverlayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteOverlayRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteOverlayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteOverlayRequest.Merge(m, src)
+}
+func (m *DeleteOverlayRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteOverlayRequest.Size(m)
+}
+func (m *DeleteOverlayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteOverlayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteOverlayRequest proto.InternalMessageInfo
+
+func (m *DeleteOverlayRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *DeleteOverlayRequest) GetConfig() string {
+	if m != nil {
+		return m.Config
+	}
+	return ""
+}
+
+func (m *DeleteOverlayRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+type CommitRequest struct {
+	Operations           []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CommitRequest) Reset()         { *m = CommitRequest{} }
+func (m *CommitRequest) String() string { return proto.CompactTextString(m) }
+func (*CommitRequest) ProtoMessage()    {}
+func (*CommitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{30}
+}
+func (m *CommitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitRequest.Unmarshal(m, b)
+}
+func (m *CommitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitRequest.Marshal(b, m, deterministic)
+}
+func (m *CommitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitRequest.Merge(m, src)
+}
+func (m *CommitRequest) XXX_Size() int {
+	return xxx_messageInfo_CommitRequest.Size(m)
+}
+func (m *CommitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitRequest proto.InternalMessageInfo
+
+func (m *CommitRequest) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+// Operation is a change to a group or config made by a batch
+type Operation struct {
+	// Types that are valid to be assigned to Operation:
+	//	*Operation_CreateGroup
+	//	*Operation_PutConfig
+	//	*Operation_DeleteConfig
+	Operation isOperation_Operation `protobuf_oneof:"operation"`
+	// revision, if set, is the revision the config has to be at for the operation to be applied. Zero requires the config to
+	// not exist. Only config operations can have a revision.
+	Revision             *wrappers.Int64Value `protobuf:"bytes,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Operation) Reset()         { *m = Operation{} }
+func (m *Operation) String() string { return proto.CompactTextString(m) }
+func (*Operation) ProtoMessage()    {}
+func (*Operation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{31}
+}
+func (m *Operation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operation.Unmarshal(m, b)
+}
+func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+}
+func (m *Operation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operation.Merge(m, src)
+}
+func (m *Operation) XXX_Size() int {
+	return xxx_messageInfo_Operation.Size(m)
+}
+func (m *Operation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operation proto.InternalMessageInfo
+
+type isOperation_Operation interface {
+	isOperation_Operation()
+}
+
+type Operation_CreateGroup struct {
+	CreateGroup *Group `protobuf:"bytes,1,opt,name=create_group,json=createGroup,proto3,oneof"`
+}
+
+type Operation_PutConfig struct {
+	PutConfig *Config `protobuf:"bytes,2,opt,name=put_config,json=putConfig,proto3,oneof"`
+}
+
+type Operation_DeleteConfig struct {
+	DeleteConfig *ConfigRef `protobuf:"bytes,3,opt,name=delete_config,json=deleteConfig,proto3,oneof"`
+}
+
+func (*Operation_CreateGroup) isOperation_Operation() {}
+
+func (*Operation_PutConfig) isOperation_Operation() {}
+
+func (*Operation_DeleteConfig) isOperation_Operation() {}
+
+func (m *Operation) GetOperation() isOperation_Operation {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *Operation) GetCreateGroup() *Group {
+	if x, ok := m.GetOperation().(*Operation_CreateGroup); ok {
+		return x.CreateGroup
+	}
+	return nil
+}
+
+func (m *Operation) GetPutConfig() *Config {
+	if x, ok := m.GetOperation().(*Operation_PutConfig); ok {
+		return x.PutConfig
+	}
+	return nil
+}
+
+func (m *Operation) GetDeleteConfig() *ConfigRef {
+	if x, ok := m.GetOperation().(*Operation_DeleteConfig); ok {
+		return x.DeleteConfig
+	}
+	return nil
+}
+
+func (m *Operation) GetRevision() *wrappers.Int64Value {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Operation) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Operation_CreateGroup)(nil),
+		(*Operation_PutConfig)(nil),
+		(*Operation_DeleteConfig)(nil),
+	}
+}
+
+// ChangeRequest is a change to a config waiting to be approved and applied. All fields are output only.
+type ChangeRequest struct {
+	Id          int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author      string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// state is one of "open", "approved", "rejected" and "applied"
+	State string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	// base_revision is the revision of the config the change was proposed against, or zero if the config did not exist
+	BaseRevision int64 `protobuf:"varint,5,opt,name=base_revision,json=baseRevision,proto3" json:"base_revision,omitempty"`
+	// config is the config as it will be stored, with secret values masked
+	Config *Config `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`
+	// diff holds the differences from the properties at base_revision
+	Diff []*Difference `protobuf:"bytes,7,rep,name=diff,proto3" json:"diff,omitempty"`
+	// history records every state the change request has been in, starting with "open"
+	History              []*Transition `protobuf:"bytes,8,rep,name=history,proto3" json:"history,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ChangeRequest) Reset()         { *m = ChangeRequest{} }
+func (m *ChangeRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequest) ProtoMessage()    {}
+func (*ChangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{32}
+}
+func (m *ChangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequest.Unmarshal(m, b)
+}
+func (m *ChangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequest.Merge(m, src)
+}
+func (m *ChangeRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequest.Size(m)
+}
+func (m *ChangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequest proto.InternalMessageInfo
+
+func (m *ChangeRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ChangeRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetBaseRevision() int64 {
+	if m != nil {
+		return m.BaseRevision
+	}
+	return 0
+}
+
+func (m *ChangeRequest) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetDiff() []*Difference {
+	if m != nil {
+		return m.Diff
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetHistory() []*Transition {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+// Difference is a value added, removed or changed at path, a JSON Pointer. Type is one of "added", "removed" and "changed".
+type Difference struct {
+	Type                 string         `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Path                 string         `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	From                 *_struct.Value `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *_struct.Value `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Difference) Reset()         { *m = Difference{} }
+func (m *Difference) String() string { return proto.CompactTextString(m) }
+func (*Difference) ProtoMessage()    {}
+func (*Difference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{33}
+}
+func (m *Difference) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Difference.Unmarshal(m, b)
+}
+func (m *Difference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Difference.Marshal(b, m, deterministic)
+}
+func (m *Difference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Difference.Merge(m, src)
+}
+func (m *Difference) XXX_Size() int {
+	return xxx_messageInfo_Difference.Size(m)
+}
+func (m *Difference) XXX_DiscardUnknown() {
+	xxx_messageInfo_Difference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Difference proto.InternalMessageInfo
+
+func (m *Difference) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Difference) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Difference) GetFrom() *_struct.Value {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *Difference) GetTo() *_struct.Value {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+type Transition struct {
+	State                string               `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	User                 string               `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Comment              string               `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Transition) Reset()         { *m = Transition{} }
+func (m *Transition) String() string { return proto.CompactTextString(m) }
+func (*Transition) ProtoMessage()    {}
+func (*Transition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{34}
+}
+func (m *Transition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transition.Unmarshal(m, b)
+}
+func (m *Transition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transition.Marshal(b, m, deterministic)
+}
+func (m *Transition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transition.Merge(m, src)
+}
+func (m *Transition) XXX_Size() int {
+	return xxx_messageInfo_Transition.Size(m)
+}
+func (m *Transition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transition proto.InternalMessageInfo
+
+func (m *Transition) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Transition) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Transition) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *Transition) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+// ProposeChangeRequest proposes config as the new version of the config with its group and id
+type ProposeChangeRequest struct {
+	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Config               *Config  `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProposeChangeRequest) Reset()         { *m = ProposeChangeRequest{} }
+func (m *ProposeChangeRequest) String() string { return proto.CompactTextString(m) }
+func (*ProposeChangeRequest) ProtoMessage()    {}
+func (*ProposeChangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{35}
+}
+func (m *ProposeChangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProposeChangeRequest.Unmarshal(m, b)
+}
+func (m *ProposeChangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProposeChangeRequest.Marshal(b, m, deterministic)
+}
+func (m *ProposeChangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProposeChangeRequest.Merge(m, src)
+}
+func (m *ProposeChangeRequest) XXX_Size() int {
+	return xxx_messageInfo_ProposeChangeRequest.Size(m)
+}
+func (m *ProposeChangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProposeChangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProposeChangeRequest proto.InternalMessageInfo
+
+func (m *ProposeChangeRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ProposeChangeRequest) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type GetChangeRequestRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetChangeRequestRequest) Reset()         { *m = GetChangeRequestRequest{} }
+func (m *GetChangeRequestRequest) String() string { return proto.CompactTextString(m) }
+func (*GetChangeRequestRequest) ProtoMessage()    {}
+func (*GetChangeRequestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{36}
+}
+func (m *GetChangeRequestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChangeRequestRequest.Unmarshal(m, b)
+}
+func (m *GetChangeRequestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChangeRequestRequest.Marshal(b, m, deterministic)
+}
+func (m *GetChangeRequestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChangeRequestRequest.Merge(m, src)
+}
+func (m *GetChangeRequestRequest) XXX_Size() int {
+	return xxx_messageInfo_GetChangeRequestRequest.Size(m)
+}
+func (m *GetChangeRequestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChangeRequestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChangeRequestRequest proto.InternalMessageInfo
+
+func (m *GetChangeRequestRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// ListChangeRequestsRequest lists the change requests in group and state, ordered by id. Empty fields match every change
+// request.
+type ListChangeRequestsRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChangeRequestsRequest) Reset()         { *m = ListChangeRequestsRequest{} }
+func (m *ListChangeRequestsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChangeRequestsRequest) ProtoMessage()    {}
+func (*ListChangeRequestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{37}
+}
+func (m *ListChangeRequestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChangeRequestsRequest.Unmarshal(m, b)
+}
+func (m *ListChangeRequestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChangeRequestsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChangeRequestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChangeRequestsRequest.Merge(m, src)
+}
+func (m *ListChangeRequestsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChangeRequestsRequest.Size(m)
+}
+func (m *ListChangeRequestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChangeRequestsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChangeRequestsRequest proto.InternalMessageInfo
+
+func (m *ListChangeRequestsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListChangeRequestsRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type ListChangeRequestsResponse struct {
+	ChangeRequests       []*ChangeRequest `protobuf:"bytes,1,rep,name=change_requests,json=changeRequests,proto3" json:"change_requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListChangeRequestsResponse) Reset()         { *m = ListChangeRequestsResponse{} }
+func (m *ListChangeRequestsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChangeRequestsResponse) ProtoMessage()    {}
+func (*ListChangeRequestsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{38}
+}
+func (m *ListChangeRequestsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChangeRequestsResponse.Unmarshal(m, b)
+}
+func (m *ListChangeRequestsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChangeRequestsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChangeRequestsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChangeRequestsResponse.Merge(m, src)
+}
+func (m *ListChangeRequestsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChangeRequestsResponse.Size(m)
+}
+func (m *ListChangeRequestsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChangeRequestsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChangeRequestsResponse proto.InternalMessageInfo
+
+func (m *ListChangeRequestsResponse) GetChangeRequests() []*ChangeRequest {
+	if m != nil {
+		return m.ChangeRequests
+	}
+	return nil
+}
+
+// ReviewChangeRequestRequest approves, rejects or applies the change request with id. The comment is recorded in the
+// history, and not used when applying.
+type ReviewChangeRequestRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Comment              string   `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReviewChangeRequestRequest) Reset()         { *m = ReviewChangeRequestRequest{} }
+func (m *ReviewChangeRequestRequest) String() string { return proto.CompactTextString(m) }
+func (*ReviewChangeRequestRequest) ProtoMessage()    {}
+func (*ReviewChangeRequestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{39}
+}
+func (m *ReviewChangeRequestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReviewChangeRequestRequest.Unmarshal(m, b)
+}
+func (m *ReviewChangeRequestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReviewChangeRequestRequest.Marshal(b, m, deterministic)
+}
+func (m *ReviewChangeRequestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReviewChangeRequestRequest.Merge(m, src)
+}
+func (m *ReviewChangeRequestRequest) XXX_Size() int {
+	return xxx_messageInfo_ReviewChangeRequestRequest.Size(m)
+}
+func (m *ReviewChangeRequestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReviewChangeRequestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReviewChangeRequestRequest proto.InternalMessageInfo
+
+func (m *ReviewChangeRequestRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ReviewChangeRequestRequest) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+type GetApproversRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetApproversRequest) Reset()         { *m = GetApproversRequest{} }
+func (m *GetApproversRequest) String() string { return proto.CompactTextString(m) }
+func (*GetApproversRequest) ProtoMessage()    {}
+func (*GetApproversRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{40}
+}
+func (m *GetApproversRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetApproversRequest.Unmarshal(m, b)
+}
+func (m *GetApproversRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetApproversRequest.Marshal(b, m, deterministic)
+}
+func (m *GetApproversRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetApproversRequest.Merge(m, src)
+}
+func (m *GetApproversRequest) XXX_Size() int {
+	return xxx_messageInfo_GetApproversRequest.Size(m)
+}
+func (m *GetApproversRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetApproversRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetApproversRequest proto.InternalMessageInfo
+
+func (m *GetApproversRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type Approvers struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Approvers            []string `protobuf:"bytes,2,rep,name=approvers,proto3" json:"approvers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Approvers) Reset()         { *m = Approvers{} }
+func (m *Approvers) String() string { return proto.CompactTextString(m) }
+func (*Approvers) ProtoMessage()    {}
+func (*Approvers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{41}
+}
+func (m *Approvers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Approvers.Unmarshal(m, b)
+}
+func (m *Approvers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Approvers.Marshal(b, m, deterministic)
+}
+func (m *Approvers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Approvers.Merge(m, src)
+}
+func (m *Approvers) XXX_Size() int {
+	return xxx_messageInfo_Approvers.Size(m)
+}
+func (m *Approvers) XXX_DiscardUnknown() {
+	xxx_messageInfo_Approvers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Approvers proto.InternalMessageInfo
+
+func (m *Approvers) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *Approvers) GetApprovers() []string {
+	if m != nil {
+		return m.Approvers
+	}
+	return nil
+}
+
+// Activation is a config to be stored at activates_at. All fields are output only.
+type Activation struct {
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// state is one of "pending", "activated", "cancelled" and "failed"
+	State       string               `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	ActivatesAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=activates_at,json=activatesAt,proto3" json:"activates_at,omitempty"`
+	Created     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	// activated is when the activation left the pending state, if it has
+	Activated *timestamp.Timestamp `protobuf:"bytes,5,opt,name=activated,proto3" json:"activated,omitempty"`
+	// revision is the revision of the config stored by the activation
+	Revision int64 `protobuf:"varint,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	// error is why the config could not be stored, if the activation failed
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// config is the config as it will be stored, with secret values masked
+	Config *Config `protobuf:"bytes,8,opt,name=config,proto3" json:"config,omitempty"`
+	// base_revision is the revision of the config when the activation was scheduled, or zero if it did not exist. The
+	// activation fails if the config was changed by other means than activations since.
+	BaseRevision         int64    `protobuf:"varint,9,opt,name=base_revision,json=baseRevision,proto3" json:"base_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Activation) Reset()         { *m = Activation{} }
+func (m *Activation) String() string { return proto.CompactTextString(m) }
+func (*Activation) ProtoMessage()    {}
+func (*Activation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{42}
+}
+func (m *Activation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Activation.Unmarshal(m, b)
+}
+func (m *Activation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Activation.Marshal(b, m, deterministic)
+}
+func (m *Activation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Activation.Merge(m, src)
+}
+func (m *Activation) XXX_Size() int {
+	return xxx_messageInfo_Activation.Size(m)
+}
+func (m *Activation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Activation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Activation proto.InternalMessageInfo
+
+func (m *Activation) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Activation) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Activation) GetActivatesAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ActivatesAt
+	}
+	return nil
+}
+
+func (m *Activation) GetCreated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *Activation) GetActivated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Activated
+	}
+	return nil
+}
+
+func (m *Activation) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Activation) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Activation) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *Activation) GetBaseRevision() int64 {
+	if m != nil {
+		return m.BaseRevision
+	}
+	return 0
+}
+
+// ScheduleConfigRequest schedules config as the version of the config with its group and id from activates_at
+type ScheduleConfigRequest struct {
+	ActivatesAt          *timestamp.Timestamp `protobuf:"bytes,1,opt,name=activates_at,json=activatesAt,proto3" json:"activates_at,omitempty"`
+	Config               *Config              `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ScheduleConfigRequest) Reset()         { *m = ScheduleConfigRequest{} }
+func (m *ScheduleConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleConfigRequest) ProtoMessage()    {}
+func (*ScheduleConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{43}
+}
+func (m *ScheduleConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleConfigRequest.Unmarshal(m, b)
+}
+func (m *ScheduleConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *ScheduleConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleConfigRequest.Merge(m, src)
+}
+func (m *ScheduleConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduleConfigRequest.Size(m)
+}
+func (m *ScheduleConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleConfigRequest proto.InternalMessageInfo
+
+func (m *ScheduleConfigRequest) GetActivatesAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ActivatesAt
+	}
+	return nil
+}
+
+func (m *ScheduleConfigRequest) GetConfig() *Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type GetScheduleRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetScheduleRequest) Reset()         { *m = GetScheduleRequest{} }
+func (m *GetScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*GetScheduleRequest) ProtoMessage()    {}
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{44}
+}
+func (m *GetScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetScheduleRequest.Unmarshal(m, b)
+}
+func (m *GetScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetScheduleRequest.Marshal(b, m, deterministic)
+}
+func (m *GetScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetScheduleRequest.Merge(m, src)
+}
+func (m *GetScheduleRequest) XXX_Size() int {
+	return xxx_messageInfo_GetScheduleRequest.Size(m)
+}
+func (m *GetScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetScheduleRequest proto.InternalMessageInfo
+
+func (m *GetScheduleRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GetScheduleRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// Schedule reports the active version of a config and its pending activations, ordered by when they are due
+type Schedule struct {
+	// active is unset if the config does not exist yet
+	Active *ActiveVersion `protobuf:"bytes,1,opt,name=active,proto3" json:"active,omitempty"`
+	// next is the first pending activation, if there is one
+	Next                 *Activation   `protobuf:"bytes,2,opt,name=next,proto3" json:"next,omitempty"`
+	Pending              []*Activation `protobuf:"bytes,3,rep,name=pending,proto3" json:"pending,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{45}
+}
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetActive() *ActiveVersion {
+	if m != nil {
+		return m.Active
+	}
+	return nil
+}
+
+func (m *Schedule) GetNext() *Activation {
+	if m != nil {
+		return m.Next
+	}
+	return nil
+}
+
+func (m *Schedule) GetPending() []*Activation {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
+type ActiveVersion struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version              int32                `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Revision             int64                `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	LastModified         *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ActiveVersion) Reset()         { *m = ActiveVersion{} }
+func (m *ActiveVersion) String() string { return proto.CompactTextString(m) }
+func (*ActiveVersion) ProtoMessage()    {}
+func (*ActiveVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{46}
+}
+func (m *ActiveVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActiveVersion.Unmarshal(m, b)
+}
+func (m *ActiveVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActiveVersion.Marshal(b, m, deterministic)
+}
+func (m *ActiveVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveVersion.Merge(m, src)
+}
+func (m *ActiveVersion) XXX_Size() int {
+	return xxx_messageInfo_ActiveVersion.Size(m)
+}
+func (m *ActiveVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveVersion proto.InternalMessageInfo
+
+func (m *ActiveVersion) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActiveVersion) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ActiveVersion) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ActiveVersion) GetLastModified() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastModified
+	}
+	return nil
+}
+
+type ActivationRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ActivationId         int64    `protobuf:"varint,3,opt,name=activation_id,json=activationId,proto3" json:"activation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActivationRequest) Reset()         { *m = ActivationRequest{} }
+func (m *ActivationRequest) String() string { return proto.CompactTextString(m) }
+func (*ActivationRequest) ProtoMessage()    {}
+func (*ActivationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{47}
+}
+func (m *ActivationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActivationRequest.Unmarshal(m, b)
+}
+func (m *ActivationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActivationRequest.Marshal(b, m, deterministic)
+}
+func (m *ActivationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActivationRequest.Merge(m, src)
+}
+func (m *ActivationRequest) XXX_Size() int {
+	return xxx_messageInfo_ActivationRequest.Size(m)
+}
+func (m *ActivationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActivationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActivationRequest proto.InternalMessageInfo
+
+func (m *ActivationRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ActivationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ActivationRequest) GetActivationId() int64 {
+	if m != nil {
+		return m.ActivationId
+	}
+	return 0
+}
+
+type DiffVersionsRequest struct {
+	Group string               `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id    string               `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	From  int32                `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To    *wrappers.Int32Value `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// unified sets the unified text diff of the diff
+	Unified              bool     `protobuf:"varint,5,opt,name=unified,proto3" json:"unified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffVersionsRequest) Reset()         { *m = DiffVersionsRequest{} }
+func (m *DiffVersionsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffVersionsRequest) ProtoMessage()    {}
+func (*DiffVersionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{48}
+}
+func (m *DiffVersionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffVersionsRequest.Unmarshal(m, b)
+}
+func (m *DiffVersionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffVersionsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffVersionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffVersionsRequest.Merge(m, src)
+}
+func (m *DiffVersionsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffVersionsRequest.Size(m)
+}
+func (m *DiffVersionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffVersionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffVersionsRequest proto.InternalMessageInfo
+
+func (m *DiffVersionsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *DiffVersionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DiffVersionsRequest) GetFrom() int32 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *DiffVersionsRequest) GetTo() *wrappers.Int32Value {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *DiffVersionsRequest) GetUnified() bool {
+	if m != nil {
+		return m.Unified
+	}
+	return false
+}
+
+type DiffRevisionsRequest struct {
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id    string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	From  int64  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To    int64  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	// unified sets the unified text diff of the diff
+	Unified              bool     `protobuf:"varint,5,opt,name=unified,proto3" json:"unified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffRevisionsRequest) Reset()         { *m = DiffRevisionsRequest{} }
+func (m *DiffRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffRevisionsRequest) ProtoMessage()    {}
+func (*DiffRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{49}
+}
+func (m *DiffRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffRevisionsRequest.Unmarshal(m, b)
+}
+func (m *DiffRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffRevisionsRequest.Merge(m, src)
+}
+func (m *DiffRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffRevisionsRequest.Size(m)
+}
+func (m *DiffRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffRevisionsRequest proto.InternalMessageInfo
+
+func (m *DiffRevisionsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *DiffRevisionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DiffRevisionsRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *DiffRevisionsRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *DiffRevisionsRequest) GetUnified() bool {
+	if m != nil {
+		return m.Unified
+	}
+	return false
+}
+
+type DiffConfigsRequest struct {
+	A   *ConfigRef `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B   *ConfigRef `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	Raw bool       `protobuf:"varint,3,opt,name=raw,proto3" json:"raw,omitempty"`
+	// unified sets the unified text diff of the diff
+	Unified              bool     `protobuf:"varint,4,opt,name=unified,proto3" json:"unified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffConfigsRequest) Reset()         { *m = DiffConfigsRequest{} }
+func (m *DiffConfigsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffConfigsRequest) ProtoMessage()    {}
+func (*DiffConfigsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{50}
+}
+func (m *DiffConfigsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffConfigsRequest.Unmarshal(m, b)
+}
+func (m *DiffConfigsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffConfigsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffConfigsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffConfigsRequest.Merge(m, src)
+}
+func (m *DiffConfigsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffConfigsRequest.Size(m)
+}
+func (m *DiffConfigsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffConfigsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffConfigsRequest proto.InternalMessageInfo
+
+func (m *DiffConfigsRequest) GetA() *ConfigRef {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *DiffConfigsRequest) GetB() *ConfigRef {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+func (m *DiffConfigsRequest) GetRaw() bool {
+	if m != nil {
+		return m.Raw
+	}
+	return false
+}
+
+func (m *DiffConfigsRequest) GetUnified() bool {
+	if m != nil {
+		return m.Unified
+	}
+	return false
+}
+
+// Diff holds the differences between the properties of two configs, ordered by path
+type Diff struct {
+	From        *DiffSide     `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To          *DiffSide     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Differences []*Difference `protobuf:"bytes,3,rep,name=differences,proto3" json:"differences,omitempty"`
+	// unified is the differences as a unified diff of the properties written as indented JSON, if asked for. It is empty
+	// if the properties are equal.
+	Unified              string   `protobuf:"bytes,4,opt,name=unified,proto3" json:"unified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Diff) Reset()         { *m = Diff{} }
+func (m *Diff) String() string { return proto.CompactTextString(m) }
+func (*Diff) ProtoMessage()    {}
+func (*Diff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{51}
+}
+func (m *Diff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Diff.Unmarshal(m, b)
+}
+func (m *Diff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Diff.Marshal(b, m, deterministic)
+}
+func (m *Diff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Diff.Merge(m, src)
+}
+func (m *Diff) XXX_Size() int {
+	return xxx_messageInfo_Diff.Size(m)
+}
+func (m *Diff) XXX_DiscardUnknown() {
+	xxx_messageInfo_Diff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Diff proto.InternalMessageInfo
+
+func (m *Diff) GetFrom() *DiffSide {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *Diff) GetTo() *DiffSide {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *Diff) GetDifferences() []*Difference {
+	if m != nil {
+		return m.Differences
+	}
+	return nil
+}
+
+func (m *Diff) GetUnified() string {
+	if m != nil {
+		return m.Unified
+	}
+	return ""
+}
+
+// DiffSide is a config compared by a diff
+type DiffSide struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	Version              int32    `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffSide) Reset()         { *m = DiffSide{} }
+func (m *DiffSide) String() string { return proto.CompactTextString(m) }
+func (*DiffSide) ProtoMessage()    {}
+func (*DiffSide) Descriptor() ([]byte, []int) {
+	return fileDescriptor_30d0a3ff1c342361, []int{52}
+}
+func (m *DiffSide) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffSide.Unmarshal(m, b)
+}
+func (m *DiffSide) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffSide.Marshal(b, m, deterministic)
+}
+func (m *DiffSide) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffSide.Merge(m, src)
+}
+func (m *DiffSide) XXX_Size() int {
+	return xxx_messageInfo_DiffSide.Size(m)
+}
+func (m *DiffSide) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffSide.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffSide proto.InternalMessageInfo
+
+func (m *DiffSide) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *DiffSide) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DiffSide) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *DiffSide) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Group)(nil), "ki.v2.Group")
+	proto.RegisterType((*Defaults)(nil), "ki.v2.Defaults")
+	proto.RegisterType((*CreateGroupRequest)(nil), "ki.v2.CreateGroupRequest")
+	proto.RegisterType((*GetGroupRequest)(nil), "ki.v2.GetGroupRequest")
+	proto.RegisterType((*ListGroupsRequest)(nil), "ki.v2.ListGroupsRequest")
+	proto.RegisterType((*ListGroupsResponse)(nil), "ki.v2.ListGroupsResponse")
+	proto.RegisterType((*UpdateGroupRequest)(nil), "ki.v2.UpdateGroupRequest")
+	proto.RegisterType((*DeleteGroupRequest)(nil), "ki.v2.DeleteGroupRequest")
+	proto.RegisterType((*Config)(nil), "ki.v2.Config")
+	proto.RegisterType((*Environment)(nil), "ki.v2.Environment")
+	proto.RegisterType((*CreateConfigRequest)(nil), "ki.v2.CreateConfigRequest")
+	proto.RegisterType((*GetConfigRequest)(nil), "ki.v2.GetConfigRequest")
+	proto.RegisterType((*ListConfigsRequest)(nil), "ki.v2.ListConfigsRequest")
+	proto.RegisterType((*ListConfigsResponse)(nil), "ki.v2.ListConfigsResponse")
+	proto.RegisterType((*UpdateConfigRequest)(nil), "ki.v2.UpdateConfigRequest")
+	proto.RegisterType((*PatchConfigRequest)(nil), "ki.v2.PatchConfigRequest")
+	proto.RegisterType((*JSONPatch)(nil), "ki.v2.JSONPatch")
+	proto.RegisterType((*PatchOperation)(nil), "ki.v2.PatchOperation")
+	proto.RegisterType((*DeleteConfigRequest)(nil), "ki.v2.DeleteConfigRequest")
+	proto.RegisterType((*ListDependentsRequest)(nil), "ki.v2.ListDependentsRequest")
+	proto.RegisterType((*ConfigRef)(nil), "ki.v2.ConfigRef")
+	proto.RegisterType((*ListDependentsResponse)(nil), "ki.v2.ListDependentsResponse")
+	proto.RegisterType((*Overlay)(nil), "ki.v2.Overlay")
+	proto.RegisterType((*Promotion)(nil), "ki.v2.Promotion")
+	proto.RegisterType((*SetOverlayRequest)(nil), "ki.v2.SetOverlayRequest")
+	proto.RegisterType((*GetOverlayRequest)(nil), "ki.v2.GetOverlayRequest")
+	proto.RegisterType((*ListOverlaysRequest)(nil), "ki.v2.ListOverlaysRequest")
+	proto.RegisterType((*ListOverlaysResponse)(nil), "ki.v2.ListOverlaysResponse")
+	proto.RegisterType((*PromoteOverlayRequest)(nil), "ki.v2.PromoteOverlayRequest")
+	proto.RegisterType((*DeleteOverlayRequest)(nil), "ki.v2.DeleteOverlayRequest")
+	proto.RegisterType((*CommitRequest)(nil), "ki.v2.CommitRequest")
+	proto.RegisterType((*Operation)(nil), "ki.v2.Operation")
+	proto.RegisterType((*ChangeRequest)(nil), "ki.v2.ChangeRequest")
+	proto.RegisterType((*Difference)(nil), "ki.v2.Difference")
+	proto.RegisterType((*Transition)(nil), "ki.v2.Transition")
+	proto.RegisterType((*ProposeChangeRequest)(nil), "ki.v2.ProposeChangeRequest")
+	proto.RegisterType((*GetChangeRequestRequest)(nil), "ki.v2.GetChangeRequestRequest")
+	proto.RegisterType((*ListChangeRequestsRequest)(nil), "ki.v2.ListChangeRequestsRequest")
+	proto.RegisterType((*ListChangeRequestsResponse)(nil), "ki.v2.ListChangeRequestsResponse")
+	proto.RegisterType((*ReviewChangeRequestRequest)(nil), "ki.v2.ReviewChangeRequestRequest")
+	proto.RegisterType((*GetApproversRequest)(nil), "ki.v2.GetApproversRequest")
+	proto.RegisterType((*Approvers)(nil), "ki.v2.Approvers")
+	proto.RegisterType((*Activation)(nil), "ki.v2.Activation")
+	proto.RegisterType((*ScheduleConfigRequest)(nil), "ki.v2.ScheduleConfigRequest")
+	proto.RegisterType((*GetScheduleRequest)(nil), "ki.v2.GetScheduleRequest")
+	proto.RegisterType((*Schedule)(nil), "ki.v2.Schedule")
+	proto.RegisterType((*ActiveVersion)(nil), "ki.v2.ActiveVersion")
+	proto.RegisterType((*ActivationRequest)(nil), "ki.v2.ActivationRequest")
+	proto.RegisterType((*DiffVersionsRequest)(nil), "ki.v2.DiffVersionsRequest")
+	proto.RegisterType((*DiffRevisionsRequest)(nil), "ki.v2.DiffRevisionsRequest")
+	proto.RegisterType((*DiffConfigsRequest)(nil), "ki.v2.DiffConfigsRequest")
+	proto.RegisterType((*Diff)(nil), "ki.v2.Diff")
+	proto.RegisterType((*DiffSide)(nil), "ki.v2.DiffSide")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GroupServiceClient is the client API for GroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GroupServiceClient interface {
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// DeleteGroup deletes a group. Fails with FAILED_PRECONDITION if the group still has configs.
+	DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+type groupServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGroupServiceClient(cc *grpc.ClientConn) GroupServiceClient {
+	return &groupServiceClient{cc}
+}
+
+func (c *groupServiceClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/ki.v2.GroupService/CreateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/ki.v2.GroupService/GetGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, "/ki.v2.GroupService/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/ki.v2.GroupService/UpdateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ki.v2.GroupService/DeleteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GroupServiceServer is the Handler API for GroupService service.
+type GroupServiceServer interface {
+	CreateGroup(context.Context, *CreateGroupRequest) (*Group, error)
+	GetGroup(context.Context, *GetGroupRequest) (*Group, error)
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	UpdateGroup(context.Context, *UpdateGroupRequest) (*Group, error)
+	// DeleteGroup deletes a group. Fails with FAILED_PRECONDITION if the group still has configs.
+	DeleteGroup(context.Context, *DeleteGroupRequest) (*empty.Empty, error)
+}
+
+func RegisterGroupServiceServer(s *grpc.Server, srv GroupServiceServer) {
+	s.RegisterService(&_GroupService_serviceDesc, srv)
+}
+
+func _GroupService_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.GroupService/CreateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.GroupService/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.GroupService/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.GroupService/UpdateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.GroupService/DeleteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).DeleteGroup(ctx, req.(*DeleteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GroupService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.GroupService",
+	HandlerType: (*GroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGroup",
+			Handler:    _GroupService_CreateGroup_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _GroupService_GetGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _GroupService_ListGroups_Handler,
+		},
+		{
+			MethodName: "UpdateGroup",
+			Handler:    _GroupService_UpdateGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _GroupService_DeleteGroup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+// ConfigServiceClient is the client API for ConfigService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ConfigServiceClient interface {
+	CreateConfig(ctx context.Context, in *CreateConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	ListConfigs(ctx context.Context, in *ListConfigsRequest, opts ...grpc.CallOption) (*ListConfigsResponse, error)
+	UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	// PatchConfig applies a JSON Merge Patch or a JSON Patch to the JSON representation of a config, atomically against the
+	// current config. A failing test operation fails with FAILED_PRECONDITION.
+	PatchConfig(ctx context.Context, in *PatchConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ListDependents lists the configs referencing a config, directly or through other configs, ordered by group and id
+	ListDependents(ctx context.Context, in *ListDependentsRequest, opts ...grpc.CallOption) (*ListDependentsResponse, error)
+	// SetOverlay creates or replaces the overlay of a config in an environment. The config merged with the overlay has to
+	// satisfy the schema of its group.
+	SetOverlay(ctx context.Context, in *SetOverlayRequest, opts ...grpc.CallOption) (*Overlay, error)
+	GetOverlay(ctx context.Context, in *GetOverlayRequest, opts ...grpc.CallOption) (*Overlay, error)
+	// ListOverlays lists the overlays of a config ordered by environment
+	ListOverlays(ctx context.Context, in *ListOverlaysRequest, opts ...grpc.CallOption) (*ListOverlaysResponse, error)
+	// PromoteOverlay copies the overlay of a config in one environment to another and records where it was promoted from
+	PromoteOverlay(ctx context.Context, in *PromoteOverlayRequest, opts ...grpc.CallOption) (*Overlay, error)
+	DeleteOverlay(ctx context.Context, in *DeleteOverlayRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+type configServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConfigServiceClient(cc *grpc.ClientConn) ConfigServiceClient {
+	return &configServiceClient{cc}
+}
+
+func (c *configServiceClient) CreateConfig(ctx context.Context, in *CreateConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	out := new(Config)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/CreateConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	out := new(Config)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ListConfigs(ctx context.Context, in *ListConfigsRequest, opts ...grpc.CallOption) (*ListConfigsResponse, error) {
+	out := new(ListConfigsResponse)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/ListConfigs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	out := new(Config)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/UpdateConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) PatchConfig(ctx context.Context, in *PatchConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	out := new(Config)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/PatchConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/DeleteConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ListDependents(ctx context.Context, in *ListDependentsRequest, opts ...grpc.CallOption) (*ListDependentsResponse, error) {
+	out := new(ListDependentsResponse)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/ListDependents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) SetOverlay(ctx context.Context, in *SetOverlayRequest, opts ...grpc.CallOption) (*Overlay, error) {
+	out := new(Overlay)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/SetOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) GetOverlay(ctx context.Context, in *GetOverlayRequest, opts ...grpc.CallOption) (*Overlay, error) {
+	out := new(Overlay)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/GetOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ListOverlays(ctx context.Context, in *ListOverlaysRequest, opts ...grpc.CallOption) (*ListOverlaysResponse, error) {
+	out := new(ListOverlaysResponse)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/ListOverlays", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) PromoteOverlay(ctx context.Context, in *PromoteOverlayRequest, opts ...grpc.CallOption) (*Overlay, error) {
+	out := new(Overlay)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/PromoteOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) DeleteOverlay(ctx context.Context, in *DeleteOverlayRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ki.v2.ConfigService/DeleteOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigServiceServer is the Handler API for ConfigService service.
+type ConfigServiceServer interface {
+	CreateConfig(context.Context, *CreateConfigRequest) (*Config, error)
+	GetConfig(context.Context, *GetConfigRequest) (*Config, error)
+	ListConfigs(context.Context, *ListConfigsRequest) (*ListConfigsResponse, error)
+	UpdateConfig(context.Context, *UpdateConfigRequest) (*Config, error)
+	// PatchConfig applies a JSON Merge Patch or a JSON Patch to the JSON representation of a config, atomically against the
+	// current config. A failing test operation fails with FAILED_PRECONDITION.
+	PatchConfig(context.Context, *PatchConfigRequest) (*Config, error)
+	DeleteConfig(context.Context, *DeleteConfigRequest) (*empty.Empty, error)
+	// ListDependents lists the configs referencing a config, directly or through other configs, ordered by group and id
+	ListDependents(context.Context, *ListDependentsRequest) (*ListDependentsResponse, error)
+	// SetOverlay creates or replaces the overlay of a config in an environment. The config merged with the overlay has to
+	// satisfy the schema of its group.
+	SetOverlay(context.Context, *SetOverlayRequest) (*Overlay, error)
+	GetOverlay(context.Context, *GetOverlayRequest) (*Overlay, error)
+	// ListOverlays lists the overlays of a config ordered by environment
+	ListOverlays(context.Context, *ListOverlaysRequest) (*ListOverlaysResponse, error)
+	// PromoteOverlay copies the overlay of a config in one environment to another and records where it was promoted from
+	PromoteOverlay(context.Context, *PromoteOverlayRequest) (*Overlay, error)
+	DeleteOverlay(context.Context, *DeleteOverlayRequest) (*empty.Empty, error)
+}
+
+func RegisterConfigServiceServer(s *grpc.Server, srv ConfigServiceServer) {
+	s.RegisterService(&_ConfigService_serviceDesc, srv)
+}
+
+func _ConfigService_CreateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).CreateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/CreateConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).CreateConfig(ctx, req.(*CreateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ListConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/ListConfigs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListConfigs(ctx, req.(*ListConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_UpdateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).UpdateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/UpdateConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).UpdateConfig(ctx, req.(*UpdateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_PatchConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).PatchConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/PatchConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).PatchConfig(ctx, req.(*PatchConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_DeleteConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).DeleteConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/DeleteConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).DeleteConfig(ctx, req.(*DeleteConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ListDependents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDependentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListDependents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/ListDependents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListDependents(ctx, req.(*ListDependentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_SetOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).SetOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/SetOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).SetOverlay(ctx, req.(*SetOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_GetOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/GetOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).GetOverlay(ctx, req.(*GetOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ListOverlays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOverlaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListOverlays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/ListOverlays",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListOverlays(ctx, req.(*ListOverlaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_PromoteOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).PromoteOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/PromoteOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).PromoteOverlay(ctx, req.(*PromoteOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_DeleteOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).DeleteOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ConfigService/DeleteOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).DeleteOverlay(ctx, req.(*DeleteOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfigService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.ConfigService",
+	HandlerType: (*ConfigServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateConfig",
+			Handler:    _ConfigService_CreateConfig_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _ConfigService_GetConfig_Handler,
+		},
+		{
+			MethodName: "ListConfigs",
+			Handler:    _ConfigService_ListConfigs_Handler,
+		},
+		{
+			MethodName: "UpdateConfig",
+			Handler:    _ConfigService_UpdateConfig_Handler,
+		},
+		{
+			MethodName: "PatchConfig",
+			Handler:    _ConfigService_PatchConfig_Handler,
+		},
+		{
+			MethodName: "DeleteConfig",
+			Handler:    _ConfigService_DeleteConfig_Handler,
+		},
+		{
+			MethodName: "ListDependents",
+			Handler:    _ConfigService_ListDependents_Handler,
+		},
+		{
+			MethodName: "SetOverlay",
+			Handler:    _ConfigService_SetOverlay_Handler,
+		},
+		{
+			MethodName: "GetOverlay",
+			Handler:    _ConfigService_GetOverlay_Handler,
+		},
+		{
+			MethodName: "ListOverlays",
+			Handler:    _ConfigService_ListOverlays_Handler,
+		},
+		{
+			MethodName: "PromoteOverlay",
+			Handler:    _ConfigService_PromoteOverlay_Handler,
+		},
+		{
+			MethodName: "DeleteOverlay",
+			Handler:    _ConfigService_DeleteOverlay_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+// BatchServiceClient is the client API for BatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BatchServiceClient interface {
+	// Commit applies the operations of a batch in order, all or none of them. Each operation is validated like the call it
+	// corresponds to, against the changes made by the operations before it. A revision that does not match fails with
+	// FAILED_PRECONDITION, and the fields of the violations are prefixed with the operation, like "operations[1].id".
+	Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+type batchServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBatchServiceClient(cc *grpc.ClientConn) BatchServiceClient {
+	return &batchServiceClient{cc}
+}
+
+func (c *batchServiceClient) Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ki.v2.BatchService/Commit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BatchServiceServer is the Handler API for BatchService service.
+type BatchServiceServer interface {
+	// Commit applies the operations of a batch in order, all or none of them. Each operation is validated like the call it
+	// corresponds to, against the changes made by the operations before it. A revision that does not match fails with
+	// FAILED_PRECONDITION, and the fields of the violations are prefixed with the operation, like "operations[1].id".
+	Commit(context.Context, *CommitRequest) (*empty.Empty, error)
+}
+
+func RegisterBatchServiceServer(s *grpc.Server, srv BatchServiceServer) {
+	s.RegisterService(&_BatchService_serviceDesc, srv)
+}
+
+func _BatchService_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BatchServiceServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.BatchService/Commit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BatchServiceServer).Commit(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BatchService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.BatchService",
+	HandlerType: (*BatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Commit",
+			Handler:    _BatchService_Commit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+// ChangeRequestServiceClient is the client API for ChangeRequestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChangeRequestServiceClient interface {
+	// ProposeChange stores a change to a config as an open change request, if the config is valid like when creating it.
+	// Fails with FAILED_PRECONDITION if the group has no approvers.
+	ProposeChange(ctx context.Context, in *ProposeChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	GetChangeRequest(ctx context.Context, in *GetChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	ListChangeRequests(ctx context.Context, in *ListChangeRequestsRequest, opts ...grpc.CallOption) (*ListChangeRequestsResponse, error)
+	// ApproveChangeRequest approves an open change request. Fails with PERMISSION_DENIED if the user is not an approver of the
+	// group or proposed the change.
+	ApproveChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	// RejectChangeRequest rejects an open change request, as an approver of the group or the author withdrawing it
+	RejectChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	// ApplyChangeRequest stores the config of an approved change request. Fails with FAILED_PRECONDITION if the config
+	// changed after the change was proposed.
+	ApplyChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	GetApprovers(ctx context.Context, in *GetApproversRequest, opts ...grpc.CallOption) (*Approvers, error)
+}
+
+type changeRequestServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChangeRequestServiceClient(cc *grpc.ClientConn) ChangeRequestServiceClient {
+	return &changeRequestServiceClient{cc}
+}
+
+func (c *changeRequestServiceClient) ProposeChange(ctx context.Context, in *ProposeChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/ProposeChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) GetChangeRequest(ctx context.Context, in *GetChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/GetChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) ListChangeRequests(ctx context.Context, in *ListChangeRequestsRequest, opts ...grpc.CallOption) (*ListChangeRequestsResponse, error) {
+	out := new(ListChangeRequestsResponse)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/ListChangeRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) ApproveChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/ApproveChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) RejectChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/RejectChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) ApplyChangeRequest(ctx context.Context, in *ReviewChangeRequestRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/ApplyChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestServiceClient) GetApprovers(ctx context.Context, in *GetApproversRequest, opts ...grpc.CallOption) (*Approvers, error) {
+	out := new(Approvers)
+	err := c.cc.Invoke(ctx, "/ki.v2.ChangeRequestService/GetApprovers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChangeRequestServiceServer is the Handler API for ChangeRequestService service.
+type ChangeRequestServiceServer interface {
+	// ProposeChange stores a change to a config as an open change request, if the config is valid like when creating it.
+	// Fails with FAILED_PRECONDITION if the group has no approvers.
+	ProposeChange(context.Context, *ProposeChangeRequest) (*ChangeRequest, error)
+	GetChangeRequest(context.Context, *GetChangeRequestRequest) (*ChangeRequest, error)
+	ListChangeRequests(context.Context, *ListChangeRequestsRequest) (*ListChangeRequestsResponse, error)
+	// ApproveChangeRequest approves an open change request. Fails with PERMISSION_DENIED if the user is not an approver of the
+	// group or proposed the change.
+	ApproveChangeRequest(context.Context, *ReviewChangeRequestRequest) (*ChangeRequest, error)
+	// RejectChangeRequest rejects an open change request, as an approver of the group or the author withdrawing it
+	RejectChangeRequest(context.Context, *ReviewChangeRequestRequest) (*ChangeRequest, error)
+	// ApplyChangeRequest stores the config of an approved change request. Fails with FAILED_PRECONDITION if the config
+	// changed after the change was proposed.
+	ApplyChangeRequest(context.Context, *ReviewChangeRequestRequest) (*ChangeRequest, error)
+	GetApprovers(context.Context, *GetApproversRequest) (*Approvers, error)
+}
+
+func RegisterChangeRequestServiceServer(s *grpc.Server, srv ChangeRequestServiceServer) {
+	s.RegisterService(&_ChangeRequestService_serviceDesc, srv)
+}
+
+func _ChangeRequestService_ProposeChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).ProposeChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/ProposeChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).ProposeChange(ctx, req.(*ProposeChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_GetChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangeRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).GetChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/GetChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).GetChangeRequest(ctx, req.(*GetChangeRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_ListChangeRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangeRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).ListChangeRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/ListChangeRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).ListChangeRequests(ctx, req.(*ListChangeRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_ApproveChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewChangeRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).ApproveChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/ApproveChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).ApproveChangeRequest(ctx, req.(*ReviewChangeRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_RejectChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewChangeRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).RejectChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/RejectChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).RejectChangeRequest(ctx, req.(*ReviewChangeRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_ApplyChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewChangeRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).ApplyChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/ApplyChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).ApplyChangeRequest(ctx, req.(*ReviewChangeRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestService_GetApprovers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetApproversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestServiceServer).GetApprovers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ChangeRequestService/GetApprovers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestServiceServer).GetApprovers(ctx, req.(*GetApproversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ChangeRequestService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.ChangeRequestService",
+	HandlerType: (*ChangeRequestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProposeChange",
+			Handler:    _ChangeRequestService_ProposeChange_Handler,
+		},
+		{
+			MethodName: "GetChangeRequest",
+			Handler:    _ChangeRequestService_GetChangeRequest_Handler,
+		},
+		{
+			MethodName: "ListChangeRequests",
+			Handler:    _ChangeRequestService_ListChangeRequests_Handler,
+		},
+		{
+			MethodName: "ApproveChangeRequest",
+			Handler:    _ChangeRequestService_ApproveChangeRequest_Handler,
+		},
+		{
+			MethodName: "RejectChangeRequest",
+			Handler:    _ChangeRequestService_RejectChangeRequest_Handler,
+		},
+		{
+			MethodName: "ApplyChangeRequest",
+			Handler:    _ChangeRequestService_ApplyChangeRequest_Handler,
+		},
+		{
+			MethodName: "GetApprovers",
+			Handler:    _ChangeRequestService_GetApprovers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+// ScheduleServiceClient is the client API for ScheduleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ScheduleServiceClient interface {
+	// ScheduleConfig schedules config to be stored at activates_at, if the config is valid like when creating it. It is
+	// validated again when it is due. Fails with INVALID_ARGUMENT if activates_at is not in the future.
+	ScheduleConfig(ctx context.Context, in *ScheduleConfigRequest, opts ...grpc.CallOption) (*Activation, error)
+	GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*Schedule, error)
+	GetActivation(ctx context.Context, in *ActivationRequest, opts ...grpc.CallOption) (*Activation, error)
+	// CancelActivation cancels a pending activation. Fails with FAILED_PRECONDITION if it is not pending.
+	CancelActivation(ctx context.Context, in *ActivationRequest, opts ...grpc.CallOption) (*Activation, error)
+}
+
+type scheduleServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewScheduleServiceClient(cc *grpc.ClientConn) ScheduleServiceClient {
+	return &scheduleServiceClient{cc}
+}
+
+func (c *scheduleServiceClient) ScheduleConfig(ctx context.Context, in *ScheduleConfigRequest, opts ...grpc.CallOption) (*Activation, error) {
+	out := new(Activation)
+	err := c.cc.Invoke(ctx, "/ki.v2.ScheduleService/ScheduleConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*Schedule, error) {
+	out := new(Schedule)
+	err := c.cc.Invoke(ctx, "/ki.v2.ScheduleService/GetSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GetActivation(ctx context.Context, in *ActivationRequest, opts ...grpc.CallOption) (*Activation, error) {
+	out := new(Activation)
+	err := c.cc.Invoke(ctx, "/ki.v2.ScheduleService/GetActivation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) CancelActivation(ctx context.Context, in *ActivationRequest, opts ...grpc.CallOption) (*Activation, error) {
+	out := new(Activation)
+	err := c.cc.Invoke(ctx, "/ki.v2.ScheduleService/CancelActivation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScheduleServiceServer is the Handler API for ScheduleService service.
+type ScheduleServiceServer interface {
+	// ScheduleConfig schedules config to be stored at activates_at, if the config is valid like when creating it. It is
+	// validated again when it is due. Fails with INVALID_ARGUMENT if activates_at is not in the future.
+	ScheduleConfig(context.Context, *ScheduleConfigRequest) (*Activation, error)
+	GetSchedule(context.Context, *GetScheduleRequest) (*Schedule, error)
+	GetActivation(context.Context, *ActivationRequest) (*Activation, error)
+	// CancelActivation cancels a pending activation. Fails with FAILED_PRECONDITION if it is not pending.
+	CancelActivation(context.Context, *ActivationRequest) (*Activation, error)
+}
+
+func RegisterScheduleServiceServer(s *grpc.Server, srv ScheduleServiceServer) {
+	s.RegisterService(&_ScheduleService_serviceDesc, srv)
+}
+
+func _ScheduleService_ScheduleConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).ScheduleConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ScheduleService/ScheduleConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).ScheduleConfig(ctx, req.(*ScheduleConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ScheduleService/GetSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetSchedule(ctx, req.(*GetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GetActivation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetActivation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ScheduleService/GetActivation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetActivation(ctx, req.(*ActivationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_CancelActivation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).CancelActivation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.ScheduleService/CancelActivation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).CancelActivation(ctx, req.(*ActivationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ScheduleService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.ScheduleService",
+	HandlerType: (*ScheduleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ScheduleConfig",
+			Handler:    _ScheduleService_ScheduleConfig_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _ScheduleService_GetSchedule_Handler,
+		},
+		{
+			MethodName: "GetActivation",
+			Handler:    _ScheduleService_GetActivation_Handler,
+		},
+		{
+			MethodName: "CancelActivation",
+			Handler:    _ScheduleService_CancelActivation_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+// DiffServiceClient is the client API for DiffService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DiffServiceClient interface {
+	// DiffVersions compares a config as it was stored with two versions, or with a version and now if to is not set. A version
+	// stored more than once is compared as it was stored last. Fails with NOT_FOUND if the config was never stored with a
+	// version.
+	DiffVersions(ctx context.Context, in *DiffVersionsRequest, opts ...grpc.CallOption) (*Diff, error)
+	// DiffRevisions compares a config as it was stored at two revisions, or at a revision and now if to is zero. Only the
+	// properties of the config itself are compared. Fails with NOT_FOUND if the config was not stored at a revision.
+	DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*Diff, error)
+	// DiffConfigs compares the effective properties of two configs, or their own properties if raw is set
+	DiffConfigs(ctx context.Context, in *DiffConfigsRequest, opts ...grpc.CallOption) (*Diff, error)
+}
+
+type diffServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDiffServiceClient(cc *grpc.ClientConn) DiffServiceClient {
+	return &diffServiceClient{cc}
+}
+
+func (c *diffServiceClient) DiffVersions(ctx context.Context, in *DiffVersionsRequest, opts ...grpc.CallOption) (*Diff, error) {
+	out := new(Diff)
+	err := c.cc.Invoke(ctx, "/ki.v2.DiffService/DiffVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diffServiceClient) DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*Diff, error) {
+	out := new(Diff)
+	err := c.cc.Invoke(ctx, "/ki.v2.DiffService/DiffRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diffServiceClient) DiffConfigs(ctx context.Context, in *DiffConfigsRequest, opts ...grpc.CallOption) (*Diff, error) {
+	out := new(Diff)
+	err := c.cc.Invoke(ctx, "/ki.v2.DiffService/DiffConfigs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DiffServiceServer is the Handler API for DiffService service.
+type DiffServiceServer interface {
+	// DiffVersions compares a config as it was stored with two versions, or with a version and now if to is not set. A version
+	// stored more than once is compared as it was stored last. Fails with NOT_FOUND if the config was never stored with a
+	// version.
+	DiffVersions(context.Context, *DiffVersionsRequest) (*Diff, error)
+	// DiffRevisions compares a config as it was stored at two revisions, or at a revision and now if to is zero. Only the
+	// properties of the config itself are compared. Fails with NOT_FOUND if the config was not stored at a revision.
+	DiffRevisions(context.Context, *DiffRevisionsRequest) (*Diff, error)
+	// DiffConfigs compares the effective properties of two configs, or their own properties if raw is set
+	DiffConfigs(context.Context, *DiffConfigsRequest) (*Diff, error)
+}
+
+func RegisterDiffServiceServer(s *grpc.Server, srv DiffServiceServer) {
+	s.RegisterService(&_DiffService_serviceDesc, srv)
+}
+
+func _DiffService_DiffVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiffServiceServer).DiffVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.DiffService/DiffVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiffServiceServer).DiffVersions(ctx, req.(*DiffVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiffService_DiffRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiffServiceServer).DiffRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.DiffService/DiffRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiffServiceServer).DiffRevisions(ctx, req.(*DiffRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiffService_DiffConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiffServiceServer).DiffConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ki.v2.DiffService/DiffConfigs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiffServiceServer).DiffConfigs(ctx, req.(*DiffConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DiffService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ki.v2.DiffService",
+	HandlerType: (*DiffServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DiffVersions",
+			Handler:    _DiffService_DiffVersions_Handler,
+		},
+		{
+			MethodName: "DiffRevisions",
+			Handler:    _DiffService_DiffRevisions_Handler,
+		},
+		{
+			MethodName: "DiffConfigs",
+			Handler:    _DiffService_DiffConfigs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ki.proto",
+}
+
+func init() { proto.RegisterFile("ki.proto", fileDescriptor_30d0a3ff1c342361) }
+
+var fileDescriptor_30d0a3ff1c342361 = []byte{
+	// 2390 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0xdb, 0x6e, 0xdc, 0xc6,
+	0xd5, 0xe4, 0x5e, 0xb4, 0x3c, 0xbb, 0xab, 0xcb, 0x48, 0xb6, 0xd7, 0xb4, 0xe3, 0xc8, 0x74, 0x9c,
+	0xba, 0x71, 0x20, 0xdb, 0x72, 0x6c, 0xc7, 0x89, 0x5d, 0x57, 0x92, 0x63, 0xd9, 0x46, 0x1d, 0x1b,
+	0x94, 0x9b, 0xa2, 0x2d, 0xd0, 0x05, 0xb5, 0x9c, 0x95, 0x18, 0xed, 0x92, 0x0c, 0x39, 0x2b, 0x47,
+	0x06, 0x0a, 0x04, 0x7d, 0x2a, 0xd0, 0xa0, 0x7d, 0xeb, 0x43, 0xfb, 0x0d, 0x7d, 0x29, 0x5a, 0xa0,
+	0x8f, 0xfd, 0x93, 0x7e, 0x41, 0xff, 0xa1, 0x98, 0x1b, 0x77, 0x86, 0xe4, 0xae, 0x2e, 0x76, 0x1f,
+	0xf2, 0xc6, 0x99, 0x73, 0xce, 0xcc, 0xb9, 0xcf, 0x39, 0x87, 0xd0, 0xd8, 0x0b, 0x56, 0xe2, 0x24,
+	0x22, 0x11, 0xaa, 0xed, 0x05, 0x2b, 0xfb, 0xab, 0xf6, 0xf9, 0x9d, 0x28, 0xda, 0x19, 0xe0, 0xeb,
+	0x6c, 0x73, 0x7b, 0xd4, 0xbf, 0x8e, 0x87, 0x31, 0x39, 0xe0, 0x38, 0xf6, 0x72, 0x1e, 0xd8, 0x0f,
+	0xf0, 0xc0, 0xef, 0x0e, 0xbd, 0x74, 0x4f, 0x60, 0x5c, 0xc8, 0x63, 0xa4, 0x24, 0x19, 0xf5, 0x88,
+	0x80, 0xbe, 0x9f, 0x87, 0x92, 0x60, 0x88, 0x53, 0xe2, 0x0d, 0x63, 0x81, 0x70, 0x31, 0x8f, 0xf0,
+	0x3a, 0xf1, 0xe2, 0x18, 0x27, 0x29, 0x87, 0x3b, 0xff, 0x30, 0xa0, 0xb6, 0x99, 0x44, 0xa3, 0x18,
+	0xcd, 0x82, 0x19, 0xf8, 0x1d, 0x63, 0xd9, 0xb8, 0x6a, 0xb9, 0x66, 0xe0, 0x23, 0x1b, 0x1a, 0x09,
+	0xde, 0x0f, 0xd2, 0x20, 0x0a, 0x3b, 0xe6, 0xb2, 0x71, 0xb5, 0xe2, 0x66, 0x6b, 0x74, 0x09, 0x5a,
+	0xbd, 0x28, 0xec, 0x07, 0x3b, 0xdd, 0x5e, 0x34, 0x0a, 0x49, 0xa7, 0xb2, 0x6c, 0x5c, 0xad, 0xb9,
+	0x4d, 0xbe, 0xb7, 0x41, 0xb7, 0xd0, 0x75, 0xa8, 0xa7, 0xbd, 0x5d, 0x3c, 0xf4, 0x3a, 0xd5, 0x65,
+	0xe3, 0x6a, 0x73, 0xf5, 0xec, 0x0a, 0xe7, 0x64, 0x45, 0x72, 0xb2, 0xb2, 0xc5, 0x04, 0x71, 0x05,
+	0x1a, 0xba, 0x06, 0x0d, 0x1f, 0xf7, 0xbd, 0xd1, 0x80, 0xa4, 0x9d, 0x1a, 0x23, 0x99, 0x5b, 0x61,
+	0x1a, 0x5c, 0x79, 0x24, 0xb6, 0xdd, 0x0c, 0xc1, 0xf9, 0x06, 0x1a, 0x72, 0x17, 0xdd, 0x05, 0x88,
+	0x93, 0x28, 0xc6, 0x09, 0x09, 0x70, 0xda, 0x31, 0xa6, 0xdf, 0xa6, 0xa0, 0xa2, 0x33, 0x50, 0xf7,
+	0x92, 0xc4, 0x3b, 0x48, 0x99, 0x7c, 0x96, 0x2b, 0x56, 0x68, 0x09, 0x6a, 0xe1, 0x68, 0x30, 0x48,
+	0x99, 0x58, 0x96, 0xcb, 0x17, 0xce, 0xa7, 0x80, 0x36, 0x12, 0xec, 0x11, 0xcc, 0xd4, 0xe5, 0xe2,
+	0x6f, 0x46, 0x38, 0x25, 0xc8, 0x81, 0xda, 0x0e, 0x5d, 0x8b, 0x7b, 0x5b, 0x82, 0x65, 0x8e, 0xc3,
+	0x41, 0xce, 0x25, 0x98, 0xdb, 0xc4, 0x44, 0x23, 0xcb, 0x29, 0xdb, 0xf9, 0x9d, 0x01, 0x0b, 0x3f,
+	0x0b, 0x52, 0x8e, 0x94, 0x4a, 0xac, 0xf3, 0x60, 0xc5, 0xde, 0x0e, 0xee, 0xa6, 0xc1, 0x1b, 0xcc,
+	0x90, 0x6b, 0x6e, 0x83, 0x6e, 0x6c, 0x05, 0x6f, 0x30, 0x7a, 0x0f, 0x80, 0x01, 0x49, 0xb4, 0x87,
+	0x43, 0x21, 0x01, 0x43, 0x7f, 0x45, 0x37, 0xa8, 0x70, 0x71, 0x82, 0xfb, 0xc1, 0xb7, 0x42, 0x0a,
+	0xb1, 0x42, 0xe7, 0xa0, 0x11, 0x25, 0x3e, 0x4e, 0xba, 0xdb, 0x07, 0xcc, 0x32, 0x96, 0x3b, 0xc3,
+	0xd6, 0xeb, 0x07, 0xce, 0x36, 0x20, 0x95, 0x87, 0x34, 0x8e, 0xc2, 0x14, 0xa3, 0x0f, 0xa0, 0xce,
+	0xc4, 0xa0, 0xaa, 0xad, 0x14, 0x44, 0x14, 0x30, 0xf4, 0x21, 0xcc, 0x85, 0xf8, 0x5b, 0xd2, 0x2d,
+	0xb0, 0xd4, 0xa6, 0xdb, 0x2f, 0x25, 0x5b, 0xce, 0x08, 0xd0, 0xcf, 0x63, 0xff, 0x04, 0x5a, 0x44,
+	0x9f, 0x43, 0x73, 0xc4, 0x28, 0x59, 0x74, 0xb0, 0xd3, 0x9b, 0xab, 0x76, 0xc1, 0xce, 0x8f, 0x69,
+	0x00, 0x3d, 0xf7, 0xd2, 0x3d, 0x17, 0x38, 0x3a, 0xfd, 0x76, 0x3e, 0x00, 0xf4, 0x08, 0x0f, 0x30,
+	0xc1, 0x53, 0xad, 0xf0, 0x6f, 0x13, 0xea, 0x1b, 0xcc, 0x87, 0xa9, 0x0f, 0x8c, 0x39, 0xb2, 0x24,
+	0x0f, 0x9c, 0xc0, 0xcc, 0x62, 0x04, 0x41, 0x35, 0xf4, 0x86, 0x58, 0xa8, 0x98, 0x7d, 0xa3, 0x0e,
+	0xcc, 0xec, 0xe3, 0x84, 0x85, 0x4d, 0x95, 0x99, 0x4c, 0x2e, 0xb5, 0x88, 0xaa, 0xe5, 0x22, 0xea,
+	0x21, 0xb4, 0x07, 0x5e, 0x4a, 0xba, 0xc3, 0xc8, 0x0f, 0xfa, 0x01, 0xf6, 0x3b, 0xf5, 0x09, 0xf2,
+	0xbd, 0x92, 0x01, 0xee, 0xb6, 0x28, 0xc1, 0x73, 0x81, 0x9f, 0x8b, 0x82, 0x99, 0x63, 0x45, 0x41,
+	0xec, 0x25, 0x38, 0x24, 0x9d, 0x86, 0x70, 0x14, 0xb6, 0x42, 0x9f, 0x40, 0x13, 0x87, 0xfb, 0x41,
+	0x12, 0x85, 0x43, 0x0a, 0xb4, 0xd8, 0x89, 0x48, 0x58, 0xe6, 0x8b, 0x31, 0xc4, 0x55, 0xd1, 0x9c,
+	0x5f, 0x40, 0x53, 0x81, 0x65, 0x0a, 0x32, 0xca, 0x15, 0x64, 0x4e, 0x56, 0x50, 0x45, 0x57, 0x90,
+	0x73, 0x1f, 0x16, 0x79, 0xf8, 0x71, 0x03, 0x49, 0x13, 0x5e, 0x81, 0x3a, 0xcf, 0x3a, 0xc2, 0x75,
+	0xda, 0x82, 0x41, 0x81, 0x25, 0x80, 0xce, 0xf7, 0x06, 0xcc, 0x6f, 0x62, 0xa2, 0xd3, 0x1e, 0xcd,
+	0xc6, 0xf3, 0x50, 0x49, 0xbc, 0xd7, 0x8c, 0x9f, 0x86, 0x4b, 0x3f, 0xd1, 0xb2, 0xae, 0x19, 0x1e,
+	0x45, 0xea, 0x16, 0xba, 0x08, 0x30, 0x0a, 0x13, 0x9c, 0x46, 0x83, 0x7d, 0xec, 0x33, 0x5b, 0x37,
+	0x5c, 0x65, 0xc7, 0xe9, 0xf3, 0x48, 0xe3, 0xec, 0xa4, 0xd3, 0xf9, 0xd1, 0x92, 0x80, 0x39, 0x35,
+	0x09, 0x54, 0x72, 0x49, 0xc0, 0xe9, 0xc3, 0xa2, 0x76, 0x8f, 0x08, 0xe9, 0x1f, 0xc1, 0x0c, 0xd7,
+	0x8b, 0x8c, 0xe9, 0x9c, 0xd6, 0x24, 0xf4, 0xc8, 0x51, 0x7d, 0x00, 0x8b, 0x3c, 0xaa, 0x4f, 0x62,
+	0x9c, 0xb7, 0x8b, 0xec, 0xbf, 0x1b, 0x80, 0x5e, 0x7a, 0xa4, 0xb7, 0x7b, 0x12, 0xdb, 0x7e, 0x06,
+	0xcd, 0x21, 0x4e, 0x76, 0x70, 0x37, 0xa6, 0x27, 0x74, 0x2a, 0x53, 0xa3, 0xe6, 0xc9, 0x29, 0x17,
+	0x18, 0x36, 0xbb, 0x0e, 0xdd, 0x04, 0xf8, 0x3a, 0x8d, 0x42, 0x41, 0xca, 0x1f, 0xb9, 0x79, 0x21,
+	0xe0, 0xb3, 0xad, 0x17, 0x5f, 0x32, 0xac, 0x27, 0xa7, 0x5c, 0x8b, 0x62, 0xb1, 0xc5, 0xfa, 0x0c,
+	0xd4, 0x18, 0xb6, 0xb3, 0x0e, 0x56, 0x86, 0x82, 0x6e, 0x03, 0xd0, 0x60, 0xf4, 0x48, 0x10, 0x85,
+	0xd2, 0x20, 0xa7, 0xc5, 0x41, 0x0c, 0xe3, 0x85, 0x84, 0xba, 0x0a, 0xa2, 0xb3, 0x0f, 0xb3, 0x3a,
+	0x94, 0x4a, 0x17, 0x49, 0x81, 0xcd, 0x28, 0xa6, 0xc1, 0x17, 0x7b, 0x64, 0x57, 0xc8, 0xcb, 0xbe,
+	0xe9, 0x5e, 0x3f, 0x89, 0x86, 0x32, 0x63, 0xd1, 0x6f, 0xf4, 0x31, 0xd4, 0xf6, 0xbd, 0xc1, 0x08,
+	0x0b, 0x21, 0xce, 0x14, 0xe4, 0xff, 0x8a, 0x42, 0x5d, 0x8e, 0xe4, 0x7c, 0x0e, 0x8b, 0x3c, 0x95,
+	0x9e, 0x40, 0xe1, 0xce, 0x03, 0x38, 0x4d, 0x1d, 0xf2, 0x11, 0x8e, 0x71, 0xe8, 0xe3, 0x90, 0xa4,
+	0xc7, 0x23, 0xbf, 0x09, 0x96, 0xbc, 0xb5, 0x7f, 0x44, 0x92, 0x67, 0x70, 0x26, 0x7f, 0xa3, 0x88,
+	0x82, 0x1b, 0x00, 0x7e, 0xb6, 0x2b, 0xf4, 0x3e, 0xaf, 0x7b, 0x28, 0xee, 0xbb, 0x0a, 0x0e, 0x7d,
+	0x1f, 0x66, 0x5e, 0xec, 0xe3, 0x64, 0xe0, 0x1d, 0x4c, 0xb8, 0xfd, 0x4c, 0xe6, 0xf1, 0xa2, 0xa4,
+	0xe0, 0xab, 0x7c, 0xca, 0xa8, 0x14, 0x53, 0xc6, 0x0f, 0xed, 0xd9, 0xb8, 0x0d, 0xed, 0x38, 0x89,
+	0x86, 0x11, 0xc1, 0x7e, 0x97, 0x79, 0x54, 0x43, 0x8b, 0x80, 0x97, 0x0c, 0x46, 0x7d, 0xb6, 0x25,
+	0xd1, 0x1e, 0x27, 0xd1, 0xd0, 0x79, 0x0a, 0x56, 0x06, 0xca, 0x6b, 0xc5, 0x28, 0x6a, 0x65, 0x4a,
+	0x11, 0xea, 0x3c, 0x80, 0x85, 0x2d, 0x4c, 0x84, 0x3d, 0xa4, 0x1f, 0x5d, 0x85, 0x99, 0x88, 0xef,
+	0x88, 0x9c, 0x33, 0x2b, 0x18, 0x92, 0x78, 0x12, 0xec, 0xf4, 0x60, 0x61, 0xb3, 0x40, 0xfe, 0x8e,
+	0xad, 0xea, 0x6c, 0xf0, 0x04, 0x2c, 0x6e, 0x49, 0x4f, 0x74, 0x8d, 0xb3, 0x0e, 0x4b, 0xfa, 0x21,
+	0xc2, 0x81, 0x3f, 0x82, 0x86, 0x10, 0x46, 0xba, 0x6f, 0x5e, 0xd8, 0x0c, 0xee, 0x04, 0x70, 0x9a,
+	0xeb, 0x1d, 0xbf, 0x95, 0xc4, 0x65, 0xe9, 0x63, 0x16, 0x4c, 0x12, 0x89, 0x57, 0xd0, 0x24, 0x91,
+	0xd3, 0x87, 0x25, 0x9e, 0x20, 0xfe, 0xcf, 0xba, 0x5d, 0x83, 0xf6, 0x46, 0x34, 0x1c, 0x06, 0x44,
+	0x5e, 0x70, 0xa3, 0x24, 0x91, 0x4a, 0x7f, 0x2c, 0xcf, 0xa1, 0xff, 0x35, 0xc0, 0xca, 0x20, 0xe8,
+	0x26, 0xb4, 0x7a, 0xac, 0xc4, 0xe8, 0x4e, 0x2c, 0x46, 0x9f, 0x9c, 0x72, 0x9b, 0xbd, 0x71, 0x17,
+	0x80, 0x56, 0x00, 0xe2, 0x11, 0xe9, 0x2a, 0x12, 0xe4, 0x5f, 0x39, 0xfa, 0x02, 0xc4, 0x23, 0xf1,
+	0x04, 0xa3, 0xbb, 0xd0, 0xf6, 0x99, 0x6e, 0x24, 0x49, 0x45, 0x8b, 0x9a, 0x2c, 0xed, 0x3c, 0x39,
+	0xe5, 0xb6, 0x7c, 0x25, 0xcb, 0xa2, 0xbb, 0x4a, 0x20, 0xf0, 0x34, 0x7d, 0xbe, 0x10, 0xa5, 0x4f,
+	0x43, 0x72, 0xe7, 0x13, 0x9e, 0xab, 0x33, 0xe4, 0xf5, 0x26, 0x58, 0x99, 0xc0, 0xce, 0x9f, 0x4c,
+	0x68, 0x6f, 0xec, 0x7a, 0xe1, 0x0e, 0x2e, 0x96, 0xc0, 0x15, 0xf6, 0x22, 0xd2, 0x9e, 0x68, 0x44,
+	0x76, 0xa3, 0x24, 0xeb, 0x89, 0xd8, 0x8a, 0x9a, 0xc3, 0xc7, 0x69, 0x2f, 0x09, 0x62, 0x22, 0xab,
+	0x33, 0xcb, 0x55, 0xb7, 0xa8, 0x79, 0x53, 0xe2, 0x11, 0x2c, 0x3c, 0x81, 0x2f, 0xd0, 0x65, 0x68,
+	0x6f, 0x7b, 0x29, 0xee, 0xe6, 0x32, 0x58, 0x8b, 0x6e, 0xba, 0x62, 0x4f, 0xa9, 0x13, 0xea, 0xd3,
+	0xea, 0x84, 0x2b, 0x50, 0xf5, 0x83, 0x7e, 0xbf, 0x33, 0xc3, 0x2c, 0xbb, 0x20, 0xbb, 0xc3, 0xa0,
+	0xdf, 0xc7, 0x09, 0x0e, 0x7b, 0xd8, 0x65, 0x60, 0x74, 0x0d, 0x66, 0x76, 0x83, 0x94, 0x44, 0xc9,
+	0x41, 0xa7, 0xa1, 0x61, 0xbe, 0x4a, 0xbc, 0x30, 0x0d, 0x98, 0x13, 0x48, 0x0c, 0xe7, 0xf7, 0x06,
+	0xc0, 0xf8, 0x04, 0xea, 0xdf, 0xe4, 0x20, 0xce, 0xea, 0x55, 0xfa, 0x5d, 0xfa, 0x8c, 0x7e, 0xa4,
+	0xc4, 0xc1, 0xe4, 0x17, 0x93, 0xc7, 0xc7, 0x87, 0x59, 0x7c, 0x4c, 0xc6, 0xa4, 0x71, 0xf3, 0x9d,
+	0x01, 0x30, 0x66, 0x71, 0xac, 0x4f, 0x43, 0xd5, 0x27, 0x82, 0xea, 0x28, 0xc5, 0xd2, 0x3a, 0xec,
+	0x1b, 0xad, 0x40, 0x95, 0xb6, 0xfd, 0x9d, 0xca, 0xa1, 0xb9, 0x9f, 0xe1, 0xd1, 0xa7, 0xa6, 0x17,
+	0x0d, 0x95, 0xda, 0x55, 0x2e, 0x9d, 0x3d, 0x58, 0x7a, 0x99, 0x44, 0x71, 0x94, 0x62, 0xdd, 0x4b,
+	0x72, 0xd6, 0x37, 0x8b, 0xd6, 0x1f, 0x9b, 0xb0, 0x32, 0xc5, 0x84, 0xcf, 0xaa, 0x0d, 0x63, 0xde,
+	0xe4, 0x6c, 0x3b, 0x3f, 0x86, 0xb3, 0xb4, 0x24, 0x57, 0x2f, 0x9a, 0xe0, 0x95, 0xce, 0x26, 0x9c,
+	0x63, 0x75, 0xac, 0x8a, 0x7b, 0x48, 0x32, 0xcd, 0xd4, 0x67, 0x2a, 0xea, 0x73, 0x7e, 0x0d, 0x76,
+	0xd9, 0x41, 0x22, 0xa1, 0x3e, 0x80, 0xb9, 0x1e, 0x83, 0x74, 0x13, 0x01, 0x12, 0x59, 0x64, 0x49,
+	0x4a, 0xa3, 0x31, 0x3b, 0xdb, 0xd3, 0x8e, 0x71, 0x5c, 0xb0, 0xa9, 0x4b, 0xe3, 0xd7, 0x47, 0x91,
+	0x49, 0xb5, 0x42, 0x45, 0xb3, 0xc2, 0xb3, 0x6a, 0xc3, 0x9c, 0xaf, 0x08, 0x25, 0x5d, 0x83, 0xc5,
+	0x4d, 0x4c, 0xd6, 0xe2, 0x38, 0xa1, 0xa9, 0x7c, 0xba, 0xcc, 0xce, 0x43, 0xb0, 0x32, 0xcc, 0x09,
+	0x6a, 0xb9, 0x00, 0x96, 0x27, 0x51, 0x3a, 0xe6, 0x72, 0x85, 0xf6, 0x0b, 0xd9, 0x86, 0xf3, 0x1f,
+	0x13, 0x60, 0xad, 0x47, 0x82, 0xfd, 0xac, 0xa0, 0xd4, 0x58, 0x2e, 0xd5, 0x29, 0x7a, 0x00, 0x2d,
+	0x8f, 0xd3, 0xe0, 0xb4, 0xeb, 0x91, 0x23, 0xb8, 0x61, 0x33, 0xc3, 0x5f, 0xa3, 0x7d, 0xe6, 0x0c,
+	0xcf, 0xa8, 0x7e, 0xa7, 0x7a, 0x28, 0xa5, 0x44, 0x45, 0x9f, 0x82, 0x25, 0x0f, 0xf1, 0x3b, 0xb5,
+	0x43, 0xe9, 0xc6, 0xc8, 0x5a, 0x49, 0x51, 0xcf, 0x95, 0x53, 0x4b, 0x50, 0xc3, 0x49, 0x12, 0x25,
+	0xac, 0x10, 0xb2, 0x5c, 0xbe, 0x50, 0x7c, 0xbb, 0x31, 0x2d, 0x3d, 0x15, 0x52, 0x9d, 0x55, 0x4c,
+	0x75, 0xce, 0x6f, 0xe1, 0xf4, 0x56, 0x6f, 0x17, 0xfb, 0xa3, 0x41, 0xae, 0x7e, 0xce, 0x6b, 0xd1,
+	0x38, 0x9e, 0x16, 0xaf, 0x68, 0xcf, 0xe8, 0xc4, 0x3e, 0xf8, 0x33, 0x40, 0x9b, 0x98, 0x48, 0x0e,
+	0x8e, 0x57, 0x7c, 0x7f, 0x6f, 0x40, 0x43, 0x52, 0xa2, 0x8f, 0xa1, 0xce, 0xae, 0xc7, 0x82, 0x51,
+	0x19, 0x21, 0xcc, 0x7b, 0xf0, 0x57, 0xbc, 0x74, 0x75, 0x05, 0x0e, 0xcd, 0xdc, 0xb4, 0x61, 0x14,
+	0xbc, 0x2d, 0xa8, 0xb8, 0xfc, 0x51, 0x66, 0x60, 0x9a, 0xb9, 0x69, 0xad, 0x1d, 0x84, 0x34, 0x8b,
+	0x54, 0xca, 0x31, 0x25, 0x86, 0xf3, 0x17, 0x03, 0xda, 0xda, 0x6d, 0xef, 0x6e, 0xd8, 0x50, 0x2c,
+	0xab, 0xab, 0xc7, 0x2b, 0xab, 0x9d, 0xdf, 0xc0, 0x82, 0xc2, 0xf3, 0xb1, 0x7a, 0xd2, 0xcb, 0xd0,
+	0xf6, 0x32, 0xd2, 0x6e, 0xe0, 0x0b, 0xe6, 0x5a, 0xe3, 0xcd, 0xa7, 0xbe, 0xf3, 0x67, 0x03, 0x16,
+	0xe9, 0xb3, 0x25, 0x44, 0x3f, 0x5e, 0x1b, 0xa5, 0x55, 0x71, 0x35, 0xf1, 0x4a, 0x5d, 0x53, 0x5e,
+	0xa9, 0xd2, 0xd2, 0xe2, 0xd6, 0x6a, 0xf6, 0x54, 0x51, 0xad, 0x8e, 0x42, 0xae, 0x19, 0x3e, 0xdc,
+	0x90, 0x4b, 0xe7, 0x0d, 0x2c, 0x51, 0xbe, 0xa4, 0xbf, 0xbf, 0x05, 0x63, 0x95, 0x42, 0x79, 0x59,
+	0x39, 0xe4, 0xee, 0xef, 0x0c, 0x40, 0xf4, 0xf2, 0xdc, 0x58, 0xe5, 0x22, 0x18, 0x5e, 0xc7, 0x28,
+	0xaf, 0xb3, 0x5c, 0xc3, 0xa3, 0xf0, 0xed, 0x8e, 0x39, 0x09, 0xbe, 0x5d, 0x32, 0x00, 0x52, 0x58,
+	0xa8, 0xea, 0x2c, 0xfc, 0xd5, 0x80, 0x2a, 0x65, 0x01, 0x5d, 0x16, 0x92, 0x18, 0xfa, 0x24, 0x3b,
+	0xe8, 0xf7, 0xb7, 0x02, 0x5f, 0x56, 0x06, 0xef, 0x33, 0xd1, 0xcc, 0x72, 0x14, 0x2a, 0xeb, 0x2d,
+	0x68, 0xfa, 0x59, 0x71, 0x92, 0xe6, 0x82, 0x42, 0x29, 0x7c, 0x54, 0xac, 0x3c, 0x77, 0xd6, 0x98,
+	0xbb, 0x3e, 0x34, 0xe4, 0xf1, 0x47, 0x34, 0xc8, 0xb4, 0x20, 0x99, 0xd8, 0xb1, 0xae, 0xfe, 0xd3,
+	0x84, 0x16, 0xab, 0x8f, 0xb7, 0x70, 0xb2, 0x1f, 0xf4, 0x30, 0xba, 0x03, 0x4d, 0x65, 0x76, 0x8e,
+	0xce, 0x49, 0x35, 0x17, 0xe6, 0xe9, 0xb6, 0x56, 0x6d, 0xa3, 0x1b, 0xd0, 0x90, 0x93, 0x73, 0x74,
+	0x46, 0x42, 0x30, 0x99, 0x42, 0xb1, 0x06, 0x30, 0x9e, 0x61, 0xa3, 0x8e, 0x80, 0x15, 0x46, 0xeb,
+	0xf6, 0xb9, 0x12, 0x88, 0xa8, 0x02, 0xee, 0x40, 0x53, 0x19, 0x51, 0x67, 0xcc, 0x16, 0xc7, 0xd6,
+	0xb9, 0xab, 0x7f, 0x0a, 0x4d, 0x65, 0xc6, 0x9c, 0xd1, 0x15, 0xe7, 0xce, 0x76, 0xb1, 0x0a, 0xfc,
+	0x82, 0xfe, 0x13, 0x5a, 0xfd, 0x43, 0x1d, 0xda, 0xdc, 0xf5, 0xa4, 0xe2, 0xee, 0x41, 0x4b, 0x9d,
+	0x7a, 0x22, 0x5b, 0xd3, 0x9c, 0xf6, 0x82, 0xd8, 0x7a, 0xca, 0x47, 0xb7, 0xc0, 0xca, 0x26, 0x9e,
+	0xe8, 0xec, 0x58, 0x79, 0x53, 0x89, 0x1e, 0x41, 0x53, 0x19, 0x18, 0x22, 0x55, 0x4b, 0x7a, 0x54,
+	0xd9, 0x76, 0x19, 0x48, 0x68, 0xf0, 0x1e, 0xb4, 0xd4, 0x71, 0x60, 0xc6, 0x75, 0xc9, 0x8c, 0x30,
+	0xcf, 0xc0, 0x5d, 0x68, 0x2a, 0xd3, 0xbc, 0x8c, 0x81, 0xe2, 0x84, 0x2f, 0x4f, 0xb8, 0x0e, 0x2d,
+	0x75, 0x2c, 0x95, 0xdd, 0x59, 0x32, 0xab, 0x9a, 0xa4, 0x7f, 0xf4, 0x1c, 0x66, 0xf5, 0x59, 0x11,
+	0xba, 0xa0, 0x48, 0x59, 0x18, 0x5a, 0xd9, 0xef, 0x4d, 0x80, 0x66, 0x8e, 0x04, 0xe3, 0x01, 0x45,
+	0xe6, 0x8b, 0x85, 0x99, 0x85, 0x9d, 0xeb, 0xda, 0x29, 0xdd, 0x66, 0x91, 0x6e, 0xf3, 0x50, 0xba,
+	0x4d, 0x68, 0xa9, 0x73, 0x02, 0xa4, 0x9a, 0x28, 0x37, 0x81, 0xb0, 0xcf, 0x97, 0xc2, 0x04, 0xe3,
+	0x3f, 0x81, 0x59, 0x7d, 0x58, 0x90, 0xe9, 0xa1, 0x74, 0x86, 0x50, 0x60, 0xe4, 0x11, 0xb4, 0xb5,
+	0x09, 0x00, 0x3a, 0xaf, 0x19, 0x23, 0x47, 0x3d, 0x29, 0x1a, 0x1e, 0x43, 0x6b, 0x9d, 0x9a, 0x7d,
+	0x9c, 0x44, 0xea, 0xbc, 0xdf, 0x47, 0x59, 0x39, 0xae, 0xb6, 0xff, 0x13, 0xcf, 0xf9, 0x5b, 0x15,
+	0x96, 0xb4, 0x8a, 0x5c, 0x1e, 0xb8, 0x0e, 0x6d, 0xad, 0xdb, 0xc9, 0xd8, 0x2c, 0xeb, 0x81, 0xec,
+	0xd2, 0x1e, 0x00, 0x3d, 0xe1, 0xff, 0x15, 0xb4, 0xbd, 0x8b, 0x4a, 0xb0, 0x95, 0x74, 0x02, 0x13,
+	0x4e, 0xfa, 0xa5, 0xf8, 0x27, 0xa0, 0x6e, 0xa6, 0x68, 0x59, 0x0d, 0xb3, 0xb2, 0xf6, 0xc7, 0xbe,
+	0x34, 0x05, 0x43, 0xd8, 0xf3, 0x05, 0x2c, 0x89, 0xbe, 0x40, 0xbf, 0x52, 0x92, 0x4e, 0xee, 0x5a,
+	0x26, 0xf0, 0xfa, 0x25, 0x2c, 0xba, 0xf8, 0x6b, 0xdc, 0x23, 0xef, 0xe8, 0xbc, 0xe7, 0x80, 0xd6,
+	0xe2, 0x78, 0x70, 0xf0, 0x8e, 0x8e, 0xbb, 0x0f, 0x2d, 0xb5, 0x69, 0xca, 0x02, 0xa1, 0xa4, 0x93,
+	0xb2, 0xe5, 0x93, 0x9f, 0x01, 0x56, 0xff, 0x68, 0xc2, 0x9c, 0xac, 0x73, 0xa5, 0xab, 0xac, 0xc1,
+	0xac, 0x5e, 0xb6, 0x67, 0x11, 0x51, 0x5a, 0xcd, 0xdb, 0xc5, 0xc2, 0x15, 0xdd, 0x83, 0xa6, 0x52,
+	0x7a, 0x67, 0x99, 0xad, 0x58, 0x8e, 0xdb, 0x73, 0xb9, 0xa3, 0xd1, 0x7d, 0x68, 0x53, 0xd6, 0xc7,
+	0x67, 0x75, 0x0a, 0xc7, 0x4f, 0xb9, 0xf8, 0x21, 0xcc, 0x6f, 0x78, 0x61, 0x0f, 0x0f, 0x4e, 0x78,
+	0xc0, 0xea, 0xbf, 0x0c, 0x68, 0xb2, 0xba, 0x41, 0x28, 0xe3, 0x2e, 0xb4, 0xd4, 0xda, 0x73, 0x9c,
+	0x6a, 0x8b, 0x05, 0xa9, 0xdd, 0x54, 0x60, 0xe8, 0x1e, 0xb4, 0xb5, 0xe2, 0x70, 0x9c, 0x17, 0x4a,
+	0x4a, 0x46, 0x9d, 0xf4, 0x36, 0x67, 0x21, 0xff, 0x30, 0x15, 0xcb, 0x3d, 0x8d, 0x6c, 0xbd, 0xfe,
+	0xab, 0xea, 0x5e, 0xb0, 0xbf, 0xba, 0x5d, 0x67, 0x39, 0xe1, 0xd6, 0xff, 0x06, 0x00, 0xc9, 0xc1,
+	0x43, 0xc7, 0x9e, 0x21, 0x00, 0x00,
+}
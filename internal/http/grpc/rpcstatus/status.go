@@ -0,0 +1,77 @@
+// Package rpcstatus maps service errors to gRPC status errors. It is shared by every version of the gRPC API.
+package rpcstatus
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/larwef/ki/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+)
+
+// kindCode maps the kinds of service errors to gRPC status codes. Unknown kinds are internal errors.
+var kindCode = map[domain.Kind]codes.Code{
+	domain.InvalidArgument:    codes.InvalidArgument,
+	domain.ValidationFailed:   codes.InvalidArgument,
+	domain.NotFound:           codes.NotFound,
+	domain.AlreadyExists:      codes.AlreadyExists,
+	domain.FailedPrecondition: codes.FailedPrecondition,
+	domain.Unauthenticated:    codes.Unauthenticated,
+	domain.PermissionDenied:   codes.PermissionDenied,
+}
+
+// Error maps an error returned by a service to a gRPC status error. The status carries google.rpc error details
+// describing the error: BadRequest for invalid fields, PreconditionFailure for failed preconditions and ResourceInfo for
+// missing or existing resources. Details of internal errors are logged but not exposed.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := domain.From(err)
+	code, ok := kindCode[e.Kind]
+	if !ok {
+		log.Printf("Error: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	var details []proto.Message
+	switch code {
+	case codes.InvalidArgument:
+		if len(e.Violations) > 0 {
+			br := &errdetails.BadRequest{}
+			for _, v := range e.Violations {
+				br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+			details = append(details, br)
+		}
+	case codes.FailedPrecondition:
+		pf := &errdetails.PreconditionFailure{}
+		for _, v := range e.Violations {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+				Type:        e.Resource,
+				Subject:     v.Field,
+				Description: v.Description,
+			})
+		}
+		if len(pf.Violations) == 0 {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{Type: e.Resource, Description: e.Message})
+		}
+		details = append(details, pf)
+	case codes.NotFound, codes.AlreadyExists:
+		if e.Resource != "" {
+			details = append(details, &errdetails.ResourceInfo{ResourceType: e.Resource, Description: e.Message})
+		}
+	}
+
+	st := status.New(code, e.Message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
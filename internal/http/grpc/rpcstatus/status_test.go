@@ -0,0 +1,32 @@
+package rpcstatus
+
+import (
+	"errors"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/test"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestError_Internal(t *testing.T) {
+	st, _ := status.FromError(Error(errors.New("disk full")))
+
+	test.AssertEqual(t, st.Code(), codes.Internal)
+	test.AssertEqual(t, st.Message(), "internal error")
+	test.AssertEqual(t, len(st.Details()), 0)
+}
+
+func TestError_NotFound(t *testing.T) {
+	st, _ := status.FromError(Error(listing.ErrConfigNotFound))
+
+	test.AssertEqual(t, st.Code(), codes.NotFound)
+	test.AssertEqual(t, st.Message(), listing.ErrConfigNotFound.Error())
+	test.AssertEqual(t, len(st.Details()), 1)
+	test.AssertEqual(t, st.Details()[0].(*errdetails.ResourceInfo).ResourceType, "config")
+}
+
+func TestError_Nil(t *testing.T) {
+	test.AssertNotError(t, Error(nil))
+}
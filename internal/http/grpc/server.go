@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"github.com/larwef/ki/internal/http/auth"
+	"github.com/larwef/ki/internal/http/grpc/kiv2"
+	"github.com/larwef/ki/internal/secret"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"log"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -16,12 +21,23 @@ type Server struct {
 	Server   *grpc.Server
 	Listener net.Listener
 	Handler  *Handler
+	// HandlerV2 serves the ki.v2 services alongside the original ones if set
+	HandlerV2 *kiv2.Handler
 }
 
 // Serve starts listening on the grpc server. Sends a signal on error.
 func (s *Server) Serve(signal chan bool) {
 	RegisterGroupServiceServer(s.Server, s.Handler)
 	RegisterConfigServiceServer(s.Server, s.Handler)
+	RegisterChangeServiceServer(s.Server, s.Handler)
+	if s.HandlerV2 != nil {
+		kiv2.RegisterGroupServiceServer(s.Server, s.HandlerV2)
+		kiv2.RegisterConfigServiceServer(s.Server, s.HandlerV2)
+		kiv2.RegisterBatchServiceServer(s.Server, s.HandlerV2)
+		kiv2.RegisterChangeRequestServiceServer(s.Server, s.HandlerV2)
+		kiv2.RegisterScheduleServiceServer(s.Server, s.HandlerV2)
+		kiv2.RegisterDiffServiceServer(s.Server, s.HandlerV2)
+	}
 	reflection.Register(s.Server)
 
 	log.Printf("Starting grpc server on %s\n", s.Listener.Addr().String())
@@ -37,7 +53,26 @@ func (s *Server) GracefulShutdown() {
 	s.Server.GracefulStop()
 }
 
-// InOutLoggingUnaryInterceptor provides a logging interceptor that can be attached to gRPC server
+// authorizationKey is the metadata key of the credentials of a call, given like the Authorization header of the CRUD API
+const authorizationKey = "authorization"
+
+// AuthenticatingUnaryInterceptor returns an interceptor authenticating calls with the credentials in their metadata before
+// passing them on to next with the user in the context. Calls without valid credentials are not refused, but handled without
+// a user, which cannot propose or review change requests.
+func AuthenticatingUnaryInterceptor(aut auth.Auth, next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && aut != nil && len(md.Get(authorizationKey)) > 0 {
+			if user, err := aut.Authenticate(md.Get(authorizationKey)[0]); err == nil {
+				ctx = auth.NewContext(ctx, user)
+			}
+		}
+
+		return next(ctx, req, info, handler)
+	}
+}
+
+// InOutLoggingUnaryInterceptor provides a logging interceptor that can be attached to gRPC server. The payloads of writes are
+// omitted.
 func InOutLoggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	start := time.Now()
 
@@ -45,8 +80,11 @@ func InOutLoggingUnaryInterceptor(ctx context.Context, req interface{}, info *gr
 	if err != nil {
 		reqPayload = bytes.NewBufferString("Error marshalling request").Bytes()
 	}
+	if isWrite(info.FullMethod) {
+		reqPayload = []byte(secret.Omitted)
+	}
 
-	log.Printf("Innbound gRPC request:\nMethod: %q\nPayload: %s\n", info.FullMethod, string(reqPayload))
+	log.Printf("Innbound gRPC request:\nMethod: %q\nPayload: %s\n", info.FullMethod, string(secret.MaskPayload(reqPayload)))
 
 	res, resErr := handler(ctx, req)
 
@@ -54,8 +92,27 @@ func InOutLoggingUnaryInterceptor(ctx context.Context, req interface{}, info *gr
 	if err != nil {
 		resPayload = bytes.NewBufferString("Error marshalling response").Bytes()
 	}
+	if isWrite(info.FullMethod) {
+		resPayload = []byte(secret.Omitted)
+	}
 
-	log.Printf("Outbound gRPC response:\nDuration: %s\nPayload: %s\nReturned with Error: %v\n", time.Since(start), string(resPayload), resErr)
+	log.Printf("Outbound gRPC response:\nDuration: %s\nPayload: %s\nReturned with Error: %v\n", time.Since(start), string(secret.MaskPayload(resPayload)), resErr)
 
 	return res, resErr
 }
+
+// readPrefixes are the prefixes of the names of the methods that only read
+var readPrefixes = []string{"Get", "List", "Retrieve", "Search", "Diff"}
+
+// isWrite reports whether a method, given by its full name like "/ki.v2.ConfigService/GetConfig", can write configs, groups
+// or what they are validated against
+func isWrite(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,24 @@
+package listing
+
+// Resource types used to identify what kind of resource a Change refers to.
+const (
+	GroupResource  = "group"
+	ConfigResource = "config"
+)
+
+// Change represents a single mutation of a resource in the repository. Deleted is set if the resource was deleted. For
+// overlays ID is the id of the config and Environment the environment of the overlay.
+type Change struct {
+	Revision    int64  `json:"revision"`
+	Resource    string `json:"resource"`
+	Group       string `json:"group"`
+	ID          string `json:"id"`
+	Environment string `json:"environment,omitempty"`
+	Deleted     bool   `json:"deleted,omitempty"`
+}
+
+// Changes represents all changes after a given revision together with the current revision of the repository
+type Changes struct {
+	Revision int64    `json:"revision"`
+	Changes  []Change `json:"changes"`
+}
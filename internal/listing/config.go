@@ -5,12 +5,17 @@ import (
 	"time"
 )
 
-// Config represents a config resource to be listed
+// Config represents a config resource to be listed. Properties are the effective properties of the config, merged onto the
+// defaults of its group and the properties of its ancestors, unless the config is retrieved raw.
 type Config struct {
 	ID           string          `json:"id"`
 	Name         string          `json:"name"`
 	LastModified time.Time       `json:"lastModified"`
 	Version      int             `json:"version"`
+	Revision     int64           `json:"revision"`
 	Group        string          `json:"group"`
+	Parent       string          `json:"parent,omitempty"`
 	Properties   json.RawMessage `json:"properties"`
+	// Environment is set when the config is read in an environment
+	Environment *Environment `json:"environment,omitempty"`
 }
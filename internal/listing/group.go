@@ -1,7 +1,77 @@
 package listing
 
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"strings"
+)
+
+// DefaultsResource identifies the defaults of a group in errors
+const DefaultsResource = "defaults"
+
+// RecipientsResource identifies the recipients of a group in errors
+const RecipientsResource = "recipients"
+
 // Group represents a group object to be listed
 type Group struct {
-	ID      string   `json:"id"`
-	Configs []string `json:"configs"`
+	ID         string          `json:"id"`
+	Revision   int64           `json:"revision"`
+	Configs    []string        `json:"configs"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	Defaults   *Defaults       `json:"defaults,omitempty"`
+	Recipients []string        `json:"recipients,omitempty"`
+	Approvers  []string        `json:"approvers,omitempty"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+// Defaults represents the default properties of the configs in a group and the rules for merging config properties onto them
+type Defaults struct {
+	Properties json.RawMessage       `json:"properties"`
+	Merge      properties.MergeRules `json:"merge"`
+}
+
+// GroupSummary represents a group in a list of groups. Config ids are left out and can be paged through by getting the group.
+type GroupSummary struct {
+	ID          string `json:"id"`
+	Revision    int64  `json:"revision"`
+	ConfigCount int    `json:"configCount"`
+}
+
+// GroupPage represents a page of groups to be listed
+type GroupPage struct {
+	Groups     []GroupSummary `json:"groups"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// GroupQuery specifies which groups to list and how. Only groups with ids starting with Prefix are listed. Sort is one of
+// "id" or "revision", optionally prefixed with "-" for descending order, and defaults to "id".
+type GroupQuery struct {
+	Prefix string
+	Sort   string
+	Page
+}
+
+// groupOrder returns a less function for the sort specified
+func groupOrder(sort string) (func(a, b GroupSummary) bool, error) {
+	desc := strings.HasPrefix(sort, "-")
+	var less func(a, b GroupSummary) bool
+	switch strings.TrimPrefix(sort, "-") {
+	case "", "id":
+		less = func(a, b GroupSummary) bool { return a.ID < b.ID }
+	case "revision":
+		less = func(a, b GroupSummary) bool {
+			if a.Revision == b.Revision {
+				return a.ID < b.ID
+			}
+			return a.Revision < b.Revision
+		}
+	default:
+		return nil, ErrInvalidSort
+	}
+
+	if desc {
+		return func(a, b GroupSummary) bool { return less(b, a) }, nil
+	}
+
+	return less, nil
 }
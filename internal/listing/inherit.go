@@ -0,0 +1,84 @@
+package listing
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/sealed"
+)
+
+// errBrokenInheritance is used when the ancestors of a stored config cannot be resolved. Writes prevent this, so it is
+// reported as an internal error.
+var errBrokenInheritance = domain.New(domain.Internal, ConfigResource, "ancestors of config cannot be resolved")
+
+// resolve returns a copy of a config with its effective properties
+func (s *service) resolve(conf *Config) (*Config, error) {
+	grp, err := s.repo.RetrieveGroup(conf.Group)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	props, err := s.effective(grp, conf)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	res := *conf
+	res.Properties = props
+	return &res, nil
+}
+
+// effective merges the properties of a config onto the defaults of its group and the properties of its ancestors, from the
+// root ancestor down, using the merge rules of the group. Overlays are merged onto the result in the order given. Empty and
+// null properties inherit everything. Sealed properties cannot be merged and are kept as they are.
+func (s *service) effective(grp *Group, conf *Config, overlays ...json.RawMessage) (json.RawMessage, error) {
+	if grp.Defaults == nil && conf.Parent == "" && len(overlays) == 0 || sealed.IsSealed(conf.Properties) {
+		return conf.Properties, nil
+	}
+
+	// The chain is ordered from the last overlay to the defaults and merged in reverse
+	var chain []json.RawMessage
+	for i := len(overlays) - 1; i >= 0; i-- {
+		chain = append(chain, overlays[i])
+	}
+	chain = append(chain, conf.Properties)
+	seen := map[string]bool{conf.ID: true}
+	for parent := conf.Parent; parent != ""; {
+		if seen[parent] {
+			return nil, errBrokenInheritance
+		}
+		seen[parent] = true
+
+		anc, err := s.repo.RetrieveConfig(conf.Group, parent)
+		if err == ErrConfigNotFound {
+			return nil, errBrokenInheritance
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, anc.Properties)
+		parent = anc.Parent
+	}
+
+	var base interface{}
+	var rules properties.MergeRules
+	if grp.Defaults != nil {
+		chain = append(chain, grp.Defaults.Properties)
+		rules = grp.Defaults.Merge
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		var doc interface{}
+		if len(chain[i]) > 0 {
+			if err := json.Unmarshal(chain[i], &doc); err != nil {
+				return nil, err
+			}
+		}
+		if doc != nil {
+			base = properties.Merge(base, doc, rules)
+		}
+	}
+
+	return json.Marshal(base)
+}
@@ -0,0 +1,84 @@
+package listing
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"sort"
+	"time"
+)
+
+// OverlayResource identifies the overlay of a config in an environment in errors and changes
+const OverlayResource = "overlay"
+
+// ErrOverlayNotFound is used when a config has no overlay in an environment.
+var ErrOverlayNotFound = domain.New(domain.NotFound, OverlayResource, "overlay not found")
+
+// Overlay represents the properties of a config in an environment to be listed
+type Overlay struct {
+	Group        string          `json:"group"`
+	Config       string          `json:"config"`
+	Environment  string          `json:"environment"`
+	Version      int             `json:"version"`
+	Revision     int64           `json:"revision"`
+	LastModified time.Time       `json:"lastModified"`
+	Properties   json.RawMessage `json:"properties"`
+	PromotedFrom *Promotion      `json:"promotedFrom,omitempty"`
+}
+
+// Promotion identifies the overlay another overlay was promoted from, at the revision it was promoted
+type Promotion struct {
+	Environment string `json:"environment"`
+	Revision    int64  `json:"revision"`
+}
+
+// Environment identifies the overlay merged onto a config read in an environment
+type Environment struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	Revision int64  `json:"revision"`
+}
+
+// GetEnvironmentConfig gets a config in an environment, with the overlay of the environment merged onto its effective
+// properties. Returns ErrOverlayNotFound if the config has no overlay in the environment.
+func (s *service) GetEnvironmentConfig(groupID string, id string, env string) (*Config, error) {
+	conf, err := s.repo.RetrieveConfig(groupID, id)
+	if err != nil {
+		return conf, err
+	}
+
+	ov, err := s.repo.RetrieveOverlay(groupID, id, env)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	grp, err := s.repo.RetrieveGroup(groupID)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	props, err := s.effective(grp, conf, ov.Properties)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	res := *conf
+	res.Properties = props
+	res.Environment = &Environment{Name: ov.Environment, Version: ov.Version, Revision: ov.Revision}
+	return &res, nil
+}
+
+// GetOverlay gets the overlay of a config in an environment
+func (s *service) GetOverlay(groupID string, id string, env string) (*Overlay, error) {
+	return s.repo.RetrieveOverlay(groupID, id, env)
+}
+
+// ListOverlays lists the overlays of a config ordered by environment
+func (s *service) ListOverlays(groupID string, id string) ([]Overlay, error) {
+	overlays, err := s.repo.ListOverlays(groupID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(overlays, func(i, j int) bool { return overlays[i].Environment < overlays[j].Environment })
+	return overlays, nil
+}
@@ -0,0 +1,89 @@
+package listing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"sort"
+)
+
+const (
+	// DefaultPageSize is the number of items returned when no limit is given.
+	DefaultPageSize = 100
+	// MaxPageSize is the maximum number of items returned in one page.
+	MaxPageSize = 1000
+)
+
+// ErrInvalidCursor is used when a cursor could not be decoded.
+var ErrInvalidCursor = domain.New(domain.InvalidArgument, "", "invalid cursor",
+	domain.Violation{Field: "cursor", Description: "has to be a cursor returned with a previous page"})
+
+// ErrInvalidSort is used when asking to sort by an unsupported field.
+var ErrInvalidSort = domain.New(domain.InvalidArgument, "", "invalid sort",
+	domain.Violation{Field: "sort", Description: `has to be "id" or "revision", optionally prefixed with "-"`})
+
+// Page specifies which part of a list to return. Cursor is the opaque NextCursor returned with the previous page and is empty
+// for the first page. A Limit of 0 or less means DefaultPageSize.
+type Page struct {
+	Cursor string
+	Limit  int
+}
+
+func (p Page) limit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageSize
+	case p.Limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return p.Limit
+	}
+}
+
+// cursor holds the sort key of the last item on a page. It is encoded as base64 JSON to keep it opaque to clients.
+type cursor struct {
+	Group    string `json:"group,omitempty"`
+	ID       string `json:"id"`
+	Revision int64  `json:"revision,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// paginate returns the bounds of the page within a list of n sorted items. after reports whether item i comes after the cursor
+// item, and key returns the cursor for item i. The returned next cursor is empty when there are no more items.
+func paginate(n int, p Page, after func(c cursor, i int) bool, key func(i int) cursor) (int, int, string, error) {
+	start := 0
+	if p.Cursor != "" {
+		c, err := decodeCursor(p.Cursor)
+		if err != nil {
+			return 0, 0, "", err
+		}
+
+		// Lists are sorted, so everything after the first item following the cursor is also after it
+		start = sort.Search(n, func(i int) bool { return after(c, i) })
+	}
+
+	end := start + p.limit()
+	if end >= n {
+		return start, n, "", nil
+	}
+
+	return start, end, encodeCursor(key(end - 1)), nil
+}
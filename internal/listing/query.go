@@ -0,0 +1,208 @@
+package listing
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"regexp"
+	"strings"
+)
+
+// Operator defines how a property value is compared in a Condition
+type Operator string
+
+// Supported operators
+const (
+	Equal              Operator = "eq"
+	NotEqual           Operator = "ne"
+	GreaterThan        Operator = "gt"
+	GreaterThanOrEqual Operator = "gte"
+	LessThan           Operator = "lt"
+	LessThanOrEqual    Operator = "lte"
+	Exists             Operator = "exists"
+	Matches            Operator = "regex"
+)
+
+// operatorSymbols maps the symbols used in condition expressions to operators. Longer symbols has to come before the symbols
+// they start with.
+var operatorSymbols = []struct {
+	symbol   string
+	operator Operator
+}{
+	{"==", Equal},
+	{"!=", NotEqual},
+	{">=", GreaterThanOrEqual},
+	{"<=", LessThanOrEqual},
+	{"=~", Matches},
+	{">", GreaterThan},
+	{"<", LessThan},
+}
+
+// InvalidQueryError is used when a query or one of its conditions is invalid.
+type InvalidQueryError string
+
+func (i InvalidQueryError) Error() string {
+	return fmt.Sprintf("invalid query: %s", string(i))
+}
+
+// Describe describes the error as an invalid argument
+func (i InvalidQueryError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, "query", i.Error())
+}
+
+// Condition is a predicate on the value found at Path within a config's properties. Value is a decoded JSON value and is
+// ignored for Exists. Matches requires Value to be a regular expression string.
+type Condition struct {
+	Path     string
+	Operator Operator
+	Value    interface{}
+
+	pointer properties.Pointer
+	regex   *regexp.Regexp
+}
+
+// ParseCondition parses a condition expression on the form <path><op><value>, where op is one of ==, !=, >, >=, <, <= and =~,
+// or just <path> to check for existence. The expression is split at the leftmost operator, so the value can contain operator
+// symbols. The value is read as JSON if possible and as a string otherwise.
+// Example: database.port>=5432
+func ParseCondition(expr string) (Condition, error) {
+	for i := range expr {
+		for _, sym := range operatorSymbols {
+			if !strings.HasPrefix(expr[i:], sym.symbol) {
+				continue
+			}
+
+			raw := expr[i+len(sym.symbol):]
+			var value interface{}
+			if err := json.Unmarshal([]byte(raw), &value); err != nil {
+				value = raw
+			}
+
+			return Condition{Path: expr[:i], Operator: sym.operator, Value: value}, nil
+		}
+	}
+
+	return Condition{Path: expr, Operator: Exists}, nil
+}
+
+// Pointer returns the parsed path of the condition. Only valid after the condition has been compiled by the service.
+func (c *Condition) Pointer() properties.Pointer {
+	return c.pointer
+}
+
+// compile validates the condition and prepares it for matching
+func (c *Condition) compile() error {
+	p, err := properties.ParsePath(c.Path)
+	if err != nil {
+		return InvalidQueryError(fmt.Sprintf("invalid path %q", c.Path))
+	}
+	c.pointer = p
+
+	switch c.Operator {
+	case Equal, NotEqual, Exists:
+	case GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+		switch c.Value.(type) {
+		case float64, string:
+		default:
+			return InvalidQueryError(fmt.Sprintf("operator %q requires a number or a string", c.Operator))
+		}
+	case Matches:
+		expr, ok := c.Value.(string)
+		if !ok {
+			return InvalidQueryError(fmt.Sprintf("operator %q requires a string", c.Operator))
+		}
+		if c.regex, err = regexp.Compile(expr); err != nil {
+			return InvalidQueryError(fmt.Sprintf("invalid regular expression %q", expr))
+		}
+	default:
+		return InvalidQueryError(fmt.Sprintf("unknown operator %q", c.Operator))
+	}
+
+	return nil
+}
+
+// Match reports whether a value found at the conditions path satisfies the condition. Objects and arrays never equal a value,
+// so they only satisfy Exists and NotEqual.
+func (c *Condition) Match(v interface{}) bool {
+	switch c.Operator {
+	case Exists:
+		return true
+	case Equal:
+		return isScalar(v) && v == c.Value
+	case NotEqual:
+		return !isScalar(v) || v != c.Value
+	case Matches:
+		s, ok := v.(string)
+		return ok && c.regex != nil && c.regex.MatchString(s)
+	}
+
+	cmp, ok := compare(v, c.Value)
+	if !ok {
+		return false
+	}
+
+	switch c.Operator {
+	case GreaterThan:
+		return cmp > 0
+	case GreaterThanOrEqual:
+		return cmp >= 0
+	case LessThan:
+		return cmp < 0
+	case LessThanOrEqual:
+		return cmp <= 0
+	}
+
+	return false
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, bool, float64, string:
+		return true
+	}
+
+	return false
+}
+
+// compare compares two numbers or two strings. Returns false if the values are not comparable.
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+
+	return 0, false
+}
+
+// ConfigRef identifies a config
+type ConfigRef struct {
+	Group string `json:"group"`
+	ID    string `json:"id"`
+}
+
+// Query specifies which configs to search for. A config matches if it satisfies all conditions. If Group is set, only configs
+// within that group are searched.
+type Query struct {
+	Group      string
+	Conditions []Condition
+	Page
+}
+
+// ConfigPage represents a page of configs to be listed
+type ConfigPage struct {
+	Configs    []Config `json:"configs"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
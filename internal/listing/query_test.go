@@ -0,0 +1,78 @@
+package listing_test
+
+import (
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expr     string
+		path     string
+		operator listing.Operator
+		value    interface{}
+	}{
+		{"database.host==db1", "database.host", listing.Equal, "db1"},
+		{`database.host!="db1"`, "database.host", listing.NotEqual, "db1"},
+		{"database.port>=5432", "database.port", listing.GreaterThanOrEqual, float64(5432)},
+		{"database.port<10", "database.port", listing.LessThan, float64(10)},
+		{"enabled==true", "enabled", listing.Equal, true},
+		{"database.host=~^db[0-9]$", "database.host", listing.Matches, "^db[0-9]$"},
+		{"database.host", "database.host", listing.Exists, nil},
+		// The expression is split at the leftmost operator, and longer symbols win at the same position
+		{"a!=x==y", "a", listing.NotEqual, "x==y"},
+		{"a==x!=y", "a", listing.Equal, "x!=y"},
+		{"a=~^x==y$", "a", listing.Matches, "^x==y$"},
+		{"a=~^(<|>)$", "a", listing.Matches, "^(<|>)$"},
+		{"a=~x>=y", "a", listing.Matches, "x>=y"},
+		{"a<=b>c", "a", listing.LessThanOrEqual, "b>c"},
+		{"a>b<=c", "a", listing.GreaterThan, "b<=c"},
+		{`a=="<b>"`, "a", listing.Equal, "<b>"},
+	}
+
+	for _, tc := range tests {
+		cond, err := listing.ParseCondition(tc.expr)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, cond.Path, tc.path)
+		test.AssertEqual(t, cond.Operator, tc.operator)
+		test.AssertEqual(t, cond.Value, tc.value)
+	}
+}
+
+func TestCondition_Match(t *testing.T) {
+	// Objects and arrays never equal a value
+	for _, v := range []interface{}{map[string]interface{}{"a": "x"}, []interface{}{"x"}} {
+		for _, tc := range []struct {
+			operator listing.Operator
+			expected bool
+		}{
+			{listing.Exists, true},
+			{listing.NotEqual, true},
+			{listing.Equal, false},
+			{listing.GreaterThan, false},
+			{listing.LessThanOrEqual, false},
+		} {
+			cond := listing.Condition{Path: "a", Operator: tc.operator, Value: "x"}
+			test.AssertEqual(t, cond.Match(v), tc.expected)
+		}
+	}
+}
+
+func TestSearchConfigs_InvalidQuery(t *testing.T) {
+	lst := listing.NewService(nil)
+
+	queries := []listing.Query{
+		{},
+		{Conditions: []listing.Condition{{Path: "a..b", Operator: listing.Exists}}},
+		{Conditions: []listing.Condition{{Path: "a", Operator: "unknown"}}},
+		{Conditions: []listing.Condition{{Path: "a", Operator: listing.GreaterThan, Value: true}}},
+		{Conditions: []listing.Condition{{Path: "a", Operator: listing.Matches, Value: "("}}},
+	}
+
+	for _, q := range queries {
+		_, err := lst.SearchConfigs(q)
+		_, ok := err.(listing.InvalidQueryError)
+		test.AssertEqual(t, ok, true)
+	}
+}
@@ -0,0 +1,301 @@
+package listing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// MaxReferenceDepth is the number of nested references followed from a property before resolving it fails
+const MaxReferenceDepth = 8
+
+// referencePattern matches references to the property of another config, like "${ref:shared/database#host}". The part
+// after "#" is a path into the properties of the referenced config, like "database.hosts[0]", and an empty path refers to
+// all of them. A reference escaped as "$${ref:...}" is kept as the literal "${ref:...}".
+var referencePattern = regexp.MustCompile(`\$?\$\{ref:([^/#}]+)/([^/#}]+)#([^}]*)\}`)
+
+// referencePrefix is what every reference starts with, used to skip properties without references
+var referencePrefix = []byte("${ref:")
+
+// UnresolvedReferenceError is used when a reference in the properties of a config cannot be resolved. Field is the JSON
+// Pointer to the property holding the reference in the config read.
+type UnresolvedReferenceError struct {
+	Field  string
+	Reason string
+}
+
+func (u UnresolvedReferenceError) Error() string {
+	return fmt.Sprintf("unresolved reference at %s: %s", u.Field, u.Reason)
+}
+
+// Describe describes the error as a failed precondition with the reference as the violation
+func (u UnresolvedReferenceError) Describe() *domain.Error {
+	return domain.New(domain.FailedPrecondition, ConfigResource, "unresolved reference", domain.Violation{Field: u.Field, Description: u.Reason})
+}
+
+// reference is a property of a config, either referenced or holding a reference
+type reference struct {
+	config ConfigRef
+	path   properties.Pointer
+}
+
+// ResolveReferences replaces the references in the property at p in the properties of a config with the referenced
+// properties. An empty pointer resolves all properties. A string that is a single reference is replaced by the referenced
+// value, while references within a longer string are interpolated and have to refer to strings, numbers, booleans or null.
+// Referenced configs are read with their effective properties, and references in them are resolved too, up to
+// MaxReferenceDepth nested references.
+func (s *service) ResolveReferences(ref ConfigRef, p properties.Pointer, value json.RawMessage) (json.RawMessage, error) {
+	if !bytes.Contains(value, referencePrefix) {
+		return value, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(value, &doc); err != nil {
+		return nil, err
+	}
+
+	r := &resolver{service: s, docs: make(map[ConfigRef]interface{})}
+	doc, err := r.value(reference{config: ref, path: p}, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// GetDependents gets the configs that reference a config, directly or through other configs, ordered by group and id
+func (s *service) GetDependents(groupID string, id string) ([]ConfigRef, error) {
+	start := ConfigRef{Group: groupID, ID: id}
+	seen := map[ConfigRef]bool{start: true}
+	queue := []ConfigRef{start}
+	dependents := []ConfigRef{}
+	for len(queue) > 0 {
+		refs, err := s.repo.RetrieveDependents(queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		for _, ref := range refs {
+			if !seen[ref] {
+				seen[ref] = true
+				queue = append(queue, ref)
+				dependents = append(dependents, ref)
+			}
+		}
+	}
+
+	sort.Slice(dependents, func(i, j int) bool {
+		if dependents[i].Group != dependents[j].Group {
+			return dependents[i].Group < dependents[j].Group
+		}
+		return dependents[i].ID < dependents[j].ID
+	})
+	return dependents, nil
+}
+
+// FindReferences returns the configs referenced from properties, in the order first referenced. Escaped references and
+// properties that are not valid JSON are ignored.
+func FindReferences(props json.RawMessage) []ConfigRef {
+	if !bytes.Contains(props, referencePrefix) {
+		return nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil
+	}
+
+	var refs []ConfigRef
+	seen := make(map[ConfigRef]bool)
+	properties.Walk(doc, func(p properties.Pointer, v interface{}) {
+		str, ok := v.(string)
+		if !ok {
+			return
+		}
+
+		for _, m := range referencePattern.FindAllStringSubmatch(str, -1) {
+			ref := ConfigRef{Group: m[1], ID: m[2]}
+			if m[0][1] != '$' && !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	})
+
+	return refs
+}
+
+// resolver resolves the references in the properties of one config
+type resolver struct {
+	service *service
+	// docs caches the effective properties of referenced configs
+	docs map[ConfigRef]interface{}
+	// stack holds the properties being resolved, from the property of the config read to the last referenced one
+	stack []reference
+	// field is the property of the config read being resolved
+	field string
+}
+
+// value resolves the references in a value found at a property
+func (r *resolver) value(at reference, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			c, err := r.value(reference{config: at.config, path: at.path.Child(k)}, child)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = c
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, child := range val {
+			c, err := r.value(reference{config: at.config, path: at.path.Child(strconv.Itoa(i))}, child)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = c
+		}
+		return res, nil
+	case string:
+		return r.str(at, val)
+	default:
+		return v, nil
+	}
+}
+
+// str resolves the references in a string
+func (r *resolver) str(at reference, s string) (interface{}, error) {
+	matches := referencePattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	if len(r.stack) == 0 {
+		r.field = "/properties" + at.path.String()
+	}
+	r.stack = append(r.stack, at)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	// A single reference is replaced by the referenced value as it is
+	if m := matches[0]; len(matches) == 1 && m[0] == 0 && m[1] == len(s) && s[1] != '$' {
+		return r.follow(s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]])
+	}
+
+	var buf bytes.Buffer
+	last := 0
+	for _, m := range matches {
+		buf.WriteString(s[last:m[0]])
+		last = m[1]
+
+		if s[m[0]+1] == '$' {
+			buf.WriteString(s[m[0]+1 : m[1]])
+			continue
+		}
+
+		v, err := r.follow(s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]])
+		if err != nil {
+			return nil, err
+		}
+
+		switch val := v.(type) {
+		case string:
+			buf.WriteString(val)
+		case map[string]interface{}, []interface{}:
+			return nil, r.unresolved("%s/%s#%s is not a string, number, boolean or null and cannot be interpolated", s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]])
+		default:
+			b, err := json.Marshal(val)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(b)
+		}
+	}
+	buf.WriteString(s[last:])
+
+	return buf.String(), nil
+}
+
+// follow returns the resolved value of the property a reference refers to
+func (r *resolver) follow(group string, id string, path string) (interface{}, error) {
+	p, err := properties.ParsePath(path)
+	if err != nil {
+		return nil, r.unresolved("invalid property path %q", path)
+	}
+	target := reference{config: ConfigRef{Group: group, ID: id}, path: p}
+
+	// The referenced property is being resolved if it holds one of the properties on the stack
+	for _, at := range r.stack {
+		if at.config == target.config && hasPrefix(at.path, target.path) {
+			return nil, r.unresolved("reference cycle through %s/%s#%s", group, id, path)
+		}
+	}
+
+	if len(r.stack) > MaxReferenceDepth {
+		return nil, r.unresolved("more than %d nested references", MaxReferenceDepth)
+	}
+
+	doc, err := r.doc(target.config)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := properties.Get(doc, p)
+	if !ok {
+		return nil, r.unresolved("property %q not found in %s/%s", path, group, id)
+	}
+
+	return r.value(target, v)
+}
+
+// doc returns the decoded effective properties of a referenced config
+func (r *resolver) doc(ref ConfigRef) (interface{}, error) {
+	if doc, ok := r.docs[ref]; ok {
+		return doc, nil
+	}
+
+	conf, err := r.service.GetConfig(ref.Group, ref.ID)
+	if err == ErrGroupNotFound || err == ErrConfigNotFound {
+		return nil, r.unresolved("config %s/%s not found", ref.Group, ref.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if len(conf.Properties) > 0 {
+		if err := json.Unmarshal(conf.Properties, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	r.docs[ref] = doc
+	return doc, nil
+}
+
+func (r *resolver) unresolved(format string, args ...interface{}) error {
+	return UnresolvedReferenceError{Field: r.field, Reason: fmt.Sprintf(format, args...)}
+}
+
+// hasPrefix reports whether prefix is p or one of its ancestors
+func hasPrefix(p properties.Pointer, prefix properties.Pointer) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
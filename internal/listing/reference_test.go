@@ -0,0 +1,113 @@
+package listing_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func newReferenceTestService(t *testing.T, configs ...adding.Config) listing.Service {
+	repo := memory.NewRepository()
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "shared"}))
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "database", Group: "shared", Properties: []byte(`{"host":"db1","port":5432,"hosts":["db1","db2"]}`)}))
+	for _, c := range configs {
+		test.AssertNotError(t, repo.StoreConfig(c))
+	}
+
+	return listing.NewService(repo)
+}
+
+func TestService_ResolveReferences(t *testing.T) {
+	service := newReferenceTestService(t,
+		adding.Config{ID: "pool", Group: "shared", Properties: []byte(`{"hosts":"${ref:shared/database#hosts}"}`)},
+	)
+	ref := listing.ConfigRef{Group: "someGroup", ID: "someId"}
+
+	tests := []struct {
+		props    string
+		expected string
+	}{
+		{`{"host":"${ref:shared/database#host}"}`, `{"host":"db1"}`},
+		{`{"port":"${ref:shared/database#port}"}`, `{"port":5432}`},
+		{`{"url":"postgres://${ref:shared/database#host}:${ref:shared/database#port}"}`, `{"url":"postgres://db1:5432"}`},
+		{`{"hosts":"${ref:shared/pool#hosts}"}`, `{"hosts":["db1","db2"]}`},
+		{`{"first":"${ref:shared/database#hosts[0]}"}`, `{"first":"db1"}`},
+		{`{"literal":"$${ref:shared/database#host}"}`, `{"literal":"${ref:shared/database#host}"}`},
+		{`{"plain":"no references"}`, `{"plain":"no references"}`},
+	}
+
+	for _, tc := range tests {
+		res, err := service.ResolveReferences(ref, nil, []byte(tc.props))
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, string(res), tc.expected)
+	}
+
+	// A single property is resolved at its pointer
+	res, err := service.ResolveReferences(ref, properties.Pointer{"database"}, []byte(`{"host":"${ref:shared/database#host}"}`))
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res), `{"host":"db1"}`)
+}
+
+func TestService_ResolveReferences_Unresolved(t *testing.T) {
+	service := newReferenceTestService(t,
+		adding.Config{ID: "a", Group: "someGroup", Properties: []byte(`{"x":"${ref:someGroup/b#x}","y":"${ref:someGroup/a#z}","z":1}`)},
+		adding.Config{ID: "b", Group: "someGroup", Properties: []byte(`{"x":"${ref:someGroup/a#x}"}`)},
+	)
+	ref := listing.ConfigRef{Group: "someGroup", ID: "someId"}
+
+	tests := []struct {
+		props  string
+		field  string
+		reason string
+	}{
+		{`{"host":"${ref:shared/other#host}"}`, "/properties/host", "config shared/other not found"},
+		{`{"db":{"user":"${ref:shared/database#user}"}}`, "/properties/db/user", `property "user" not found in shared/database`},
+		{`{"url":"db://${ref:shared/database#hosts}"}`, "/properties/url", "shared/database#hosts is not a string, number, boolean or null and cannot be interpolated"},
+		{`{"x":"${ref:someGroup/a#x}"}`, "/properties/x", "reference cycle through someGroup/a#x"},
+		{`{"host":"${ref:shared/database#hosts[}"}`, "/properties/host", `invalid property path "hosts["`},
+	}
+
+	for _, tc := range tests {
+		_, err := service.ResolveReferences(ref, nil, []byte(tc.props))
+		test.AssertEqual(t, err, listing.UnresolvedReferenceError{Field: tc.field, Reason: tc.reason})
+	}
+
+	// References to other properties of the same config are not cycles
+	res, err := service.ResolveReferences(listing.ConfigRef{Group: "someGroup", ID: "a"}, nil, []byte(`{"y":"${ref:someGroup/a#z}","z":1}`))
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res), `{"y":1,"z":1}`)
+}
+
+func TestService_ResolveReferences_Depth(t *testing.T) {
+	var configs []adding.Config
+	for i := 0; i <= listing.MaxReferenceDepth; i++ {
+		next := string(rune('a' + i + 1))
+		configs = append(configs, adding.Config{ID: string(rune('a' + i)), Group: "someGroup", Properties: []byte(`{"v":"${ref:someGroup/` + next + `#v}"}`)})
+	}
+	service := newReferenceTestService(t, configs...)
+
+	_, err := service.ResolveReferences(listing.ConfigRef{Group: "someGroup", ID: "someId"}, nil, []byte(`{"v":"${ref:someGroup/a#v}"}`))
+	test.AssertEqual(t, err, listing.UnresolvedReferenceError{Field: "/properties/v", Reason: "more than 8 nested references"})
+}
+
+func TestService_GetDependents(t *testing.T) {
+	service := newReferenceTestService(t,
+		adding.Config{ID: "pool", Group: "shared", Properties: []byte(`{"host":"${ref:shared/database#host}"}`)},
+		adding.Config{ID: "app", Group: "someGroup", Properties: []byte(`{"db":"${ref:shared/pool#host}","port":"${ref:shared/database#port}"}`)},
+		adding.Config{ID: "escaped", Group: "someGroup", Properties: []byte(`{"db":"$${ref:shared/database#host}"}`)},
+	)
+
+	dependents, err := service.GetDependents("shared", "database")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(dependents), 2)
+	test.AssertEqual(t, dependents[0], listing.ConfigRef{Group: "shared", ID: "pool"})
+	test.AssertEqual(t, dependents[1], listing.ConfigRef{Group: "someGroup", ID: "app"})
+
+	dependents, err = service.GetDependents("someGroup", "app")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(dependents), 0)
+}
@@ -0,0 +1,20 @@
+package listing
+
+import "github.com/larwef/ki/internal/schema"
+
+// SchemaResource identifies the schema of a group in errors
+const SchemaResource = "schema"
+
+// SchemaCheck is the result of validating every config in a group against a schema
+type SchemaCheck struct {
+	Group   string          `json:"group"`
+	Valid   bool            `json:"valid"`
+	Checked int             `json:"checked"`
+	Invalid []InvalidConfig `json:"invalid"`
+}
+
+// InvalidConfig represents a config not satisfying a schema and the violations found
+type InvalidConfig struct {
+	ID         string             `json:"id"`
+	Violations []schema.Violation `json:"violations"`
+}
@@ -1,38 +1,403 @@
 package listing
 
-import "errors"
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/schema"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/sealed"
+	"sort"
+	"strings"
+)
 
 // ErrGroupNotFound is used when a group resource could not be found.
-var ErrGroupNotFound = errors.New("group not found")
+var ErrGroupNotFound = domain.New(domain.NotFound, GroupResource, "group not found")
 
 // ErrConfigNotFound is used when a config resource could not be found.
-var ErrConfigNotFound = errors.New("config not found")
+var ErrConfigNotFound = domain.New(domain.NotFound, ConfigResource, "config not found")
+
+// ErrSchemaNotFound is used when a group has no schema.
+var ErrSchemaNotFound = domain.New(domain.NotFound, SchemaResource, "schema not found")
+
+// ErrDefaultsNotFound is used when a group has no defaults.
+var ErrDefaultsNotFound = domain.New(domain.NotFound, DefaultsResource, "defaults not found")
+
+// ErrRecipientsNotFound is used when a group has no recipients.
+var ErrRecipientsNotFound = domain.New(domain.NotFound, RecipientsResource, "recipients not found")
+
+// ErrNoSchema is used when checking configs against the schema of a group without a schema.
+var ErrNoSchema = domain.New(domain.FailedPrecondition, SchemaResource, "group has no schema to check against")
 
 // Service provides adding operations
 type Service interface {
 	GetGroup(id string) (*Group, error)
+	GetPagedGroup(id string, p Page) (*Group, error)
+	ListGroups(q GroupQuery) (*GroupPage, error)
 	GetConfig(groupID string, id string) (*Config, error)
+	GetRawConfig(groupID string, id string) (*Config, error)
+	GetEnvironmentConfig(groupID string, id string, env string) (*Config, error)
+	GetOverlay(groupID string, id string, env string) (*Overlay, error)
+	ListOverlays(groupID string, id string) ([]Overlay, error)
+	GetProperty(groupID string, id string, p properties.Pointer) (json.RawMessage, error)
+	ResolveReferences(ref ConfigRef, p properties.Pointer, value json.RawMessage) (json.RawMessage, error)
+	GetDependents(groupID string, id string) ([]ConfigRef, error)
+	SearchConfigs(q Query) (*ConfigPage, error)
+	SearchText(q TextQuery) (*TextMatches, error)
+	ListChanges(since int64) (*Changes, error)
+	GetSchema(groupID string) (json.RawMessage, error)
+	GetDefaults(groupID string) (*Defaults, error)
+	GetRecipients(groupID string) ([]string, error)
+	CheckSchema(groupID string, s json.RawMessage) (*SchemaCheck, error)
+	RevealSecrets(props json.RawMessage) (json.RawMessage, error)
+	ResolveSecrets(p properties.Pointer, props json.RawMessage) (json.RawMessage, error)
 }
 
 // Repository provides access to repository
 type Repository interface {
 	RetrieveGroup(id string) (*Group, error)
+	ListGroups(prefix string) ([]Group, error)
 	RetrieveConfig(groupID string, id string) (*Config, error)
+	SearchConfigs(conditions []Condition) ([]ConfigRef, error)
+	SearchText(text string) ([]TextMatch, error)
+	RetrieveChanges(since int64) (*Changes, error)
+	RetrieveOverlay(groupID string, id string, env string) (*Overlay, error)
+	// ListOverlays lists the overlays of a config in any order
+	ListOverlays(groupID string, id string) ([]Overlay, error)
+	// RetrieveDependents retrieves the configs whose own properties reference a config, in any order
+	RetrieveDependents(ref ConfigRef) ([]ConfigRef, error)
 }
 
 type service struct {
-	repo Repository
+	repo     Repository
+	keeper   *secret.Keeper
+	resolver *secret.Resolver
 }
 
+// Option sets options on the service
+type Option func(*service)
+
+// Secrets returns an Option that sets the Keeper secret values are decrypted with. Without it encrypted values cannot be
+// revealed.
+func Secrets(k *secret.Keeper) Option { return func(s *service) { s.keeper = k } }
+
+// Providers returns an Option that sets the Resolver placeholders for secret values kept outside of ki are resolved with.
+// Without it placeholders cannot be resolved.
+func Providers(r *secret.Resolver) Option { return func(s *service) { s.resolver = r } }
+
 // NewService created a new adding service
-func NewService(r Repository) Service {
-	return &service{repo: r}
+func NewService(r Repository, opts ...Option) Service {
+	s := &service{repo: r}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
 }
 
 func (s *service) GetGroup(id string) (*Group, error) {
 	return s.repo.RetrieveGroup(id)
 }
 
+// GetPagedGroup gets a group with only one page of its config ids. Config ids are sorted to give a stable order between pages.
+func (s *service) GetPagedGroup(id string, p Page) (*Group, error) {
+	grp, err := s.repo.RetrieveGroup(id)
+	if err != nil {
+		return grp, err
+	}
+
+	ids := make([]string, len(grp.Configs))
+	copy(ids, grp.Configs)
+	sort.Strings(ids)
+
+	start, end, next, err := paginate(len(ids), p,
+		func(c cursor, i int) bool { return ids[i] > c.ID },
+		func(i int) cursor { return cursor{ID: ids[i]} },
+	)
+	if err != nil {
+		return &Group{}, err
+	}
+
+	grp.Configs = ids[start:end]
+	grp.NextCursor = next
+	return grp, nil
+}
+
+func (s *service) ListGroups(q GroupQuery) (*GroupPage, error) {
+	less, err := groupOrder(q.Sort)
+	if err != nil {
+		return &GroupPage{}, err
+	}
+
+	grps, err := s.repo.ListGroups(q.Prefix)
+	if err != nil {
+		return &GroupPage{}, err
+	}
+
+	summaries := make([]GroupSummary, len(grps))
+	for i, g := range grps {
+		summaries[i] = GroupSummary{
+			ID:          g.ID,
+			Revision:    g.Revision,
+			ConfigCount: len(g.Configs),
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return less(summaries[i], summaries[j]) })
+
+	start, end, next, err := paginate(len(summaries), q.Page,
+		func(c cursor, i int) bool { return less(GroupSummary{ID: c.ID, Revision: c.Revision}, summaries[i]) },
+		func(i int) cursor { return cursor{ID: summaries[i].ID, Revision: summaries[i].Revision} },
+	)
+	if err != nil {
+		return &GroupPage{}, err
+	}
+
+	return &GroupPage{
+		Groups:     summaries[start:end],
+		NextCursor: next,
+	}, nil
+}
+
+// GetConfig gets a config with its effective properties
 func (s *service) GetConfig(groupID string, id string) (*Config, error) {
+	conf, err := s.repo.RetrieveConfig(groupID, id)
+	if err != nil {
+		return conf, err
+	}
+
+	return s.resolve(conf)
+}
+
+// GetRawConfig gets a config with only its own properties, as they were stored
+func (s *service) GetRawConfig(groupID string, id string) (*Config, error) {
 	return s.repo.RetrieveConfig(groupID, id)
 }
+
+// GetProperty gets the value of a single effective property of a config. The empty Pointer gets all properties. Returns
+// properties.ErrPropertyNotFound if there is no property at the Pointer.
+func (s *service) GetProperty(groupID string, id string, p properties.Pointer) (json.RawMessage, error) {
+	conf, err := s.GetConfig(groupID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if len(conf.Properties) > 0 {
+		if err := json.Unmarshal(conf.Properties, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	v, ok := properties.Get(doc, p)
+	if !ok {
+		return nil, properties.ErrPropertyNotFound
+	}
+
+	return json.Marshal(v)
+}
+
+// SearchConfigs finds all configs satisfying every condition in the query. Conditions are matched against the properties of
+// the configs themselves, while the configs found have their effective properties. Results are ordered by group and id.
+func (s *service) SearchConfigs(q Query) (*ConfigPage, error) {
+	if len(q.Conditions) == 0 {
+		return &ConfigPage{}, InvalidQueryError("at least one condition is required")
+	}
+
+	for i := range q.Conditions {
+		if err := q.Conditions[i].compile(); err != nil {
+			return &ConfigPage{}, err
+		}
+	}
+
+	found, err := s.repo.SearchConfigs(q.Conditions)
+	if err != nil {
+		return &ConfigPage{}, err
+	}
+
+	refs := found[:0]
+	for _, ref := range found {
+		if q.Group == "" || ref.Group == q.Group {
+			refs = append(refs, ref)
+		}
+	}
+
+	less := func(a, b ConfigRef) bool {
+		if a.Group == b.Group {
+			return a.ID < b.ID
+		}
+		return a.Group < b.Group
+	}
+	sort.Slice(refs, func(i, j int) bool { return less(refs[i], refs[j]) })
+
+	start, end, next, err := paginate(len(refs), q.Page,
+		func(c cursor, i int) bool { return less(ConfigRef{Group: c.Group, ID: c.ID}, refs[i]) },
+		func(i int) cursor { return cursor{Group: refs[i].Group, ID: refs[i].ID} },
+	)
+	if err != nil {
+		return &ConfigPage{}, err
+	}
+
+	page := &ConfigPage{Configs: []Config{}, NextCursor: next}
+	for _, ref := range refs[start:end] {
+		conf, err := s.GetConfig(ref.Group, ref.ID)
+		if err != nil {
+			return &ConfigPage{}, err
+		}
+		page.Configs = append(page.Configs, *conf)
+	}
+
+	return page, nil
+}
+
+// SearchText finds the configs best matching the text, ordered by descending score
+func (s *service) SearchText(q TextQuery) (*TextMatches, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return &TextMatches{}, InvalidQueryError("text is required")
+	}
+
+	found, err := s.repo.SearchText(q.Text)
+	if err != nil {
+		return &TextMatches{}, err
+	}
+
+	matches := []TextMatch{}
+	for _, m := range found {
+		if q.Group == "" || m.Group == q.Group {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return a.ID < b.ID
+	})
+
+	if limit := (Page{Limit: q.Limit}).limit(); len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return &TextMatches{Matches: matches}, nil
+}
+
+func (s *service) ListChanges(since int64) (*Changes, error) {
+	return s.repo.RetrieveChanges(since)
+}
+
+func (s *service) GetSchema(groupID string) (json.RawMessage, error) {
+	grp, err := s.repo.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(grp.Schema) == 0 {
+		return nil, ErrSchemaNotFound
+	}
+
+	return grp.Schema, nil
+}
+
+func (s *service) GetDefaults(groupID string) (*Defaults, error) {
+	grp, err := s.repo.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if grp.Defaults == nil {
+		return nil, ErrDefaultsNotFound
+	}
+
+	return grp.Defaults, nil
+}
+
+// GetRecipients gets the public keys configs in a group are sealed to
+func (s *service) GetRecipients(groupID string) ([]string, error) {
+	grp, err := s.repo.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(grp.Recipients) == 0 {
+		return nil, ErrRecipientsNotFound
+	}
+
+	return grp.Recipients, nil
+}
+
+// CheckSchema validates the effective properties of every config in a group against a schema, typically before changing the
+// schema of the group. The current schema of the group is used if s is empty. Returns ErrNoSchema if neither is set. Sealed
+// configs cannot be read, so they are not checked.
+func (s *service) CheckSchema(groupID string, raw json.RawMessage) (*SchemaCheck, error) {
+	grp, err := s.repo.RetrieveGroup(groupID)
+	if err != nil {
+		return &SchemaCheck{}, err
+	}
+
+	if len(raw) == 0 {
+		raw = grp.Schema
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return &SchemaCheck{}, ErrNoSchema
+	}
+
+	sch, err := schema.Compile(raw)
+	if err != nil {
+		return &SchemaCheck{}, err
+	}
+
+	ids := make([]string, len(grp.Configs))
+	copy(ids, grp.Configs)
+	sort.Strings(ids)
+
+	check := &SchemaCheck{Group: groupID, Valid: true, Invalid: []InvalidConfig{}}
+	for _, id := range ids {
+		conf, err := s.repo.RetrieveConfig(groupID, id)
+		if err != nil {
+			return &SchemaCheck{}, err
+		}
+
+		if sealed.IsSealed(conf.Properties) {
+			continue
+		}
+
+		props, err := s.effective(grp, conf)
+		if err != nil {
+			return &SchemaCheck{}, err
+		}
+
+		if props, err = s.keeper.Open(props); err != nil {
+			return &SchemaCheck{}, err
+		}
+
+		check.Checked++
+		if err := sch.Validate(props); err != nil {
+			validationErr, ok := err.(schema.ValidationError)
+			if !ok {
+				return &SchemaCheck{}, err
+			}
+
+			check.Valid = false
+			check.Invalid = append(check.Invalid, InvalidConfig{ID: id, Violations: validationErr.Violations})
+		}
+	}
+
+	return check, nil
+}
+
+// RevealSecrets decrypts the secret values of properties as read from any of the other operations, for callers allowed to
+// read them. Callers not allowed should mask them with secret.Masked instead.
+func (s *service) RevealSecrets(props json.RawMessage) (json.RawMessage, error) {
+	return s.keeper.Open(props)
+}
+
+// ResolveSecrets replaces the placeholders for secret values kept outside of ki in the property at p in the properties of a
+// config with the values, for callers allowed to read secrets. An empty pointer resolves all properties.
+func (s *service) ResolveSecrets(p properties.Pointer, props json.RawMessage) (json.RawMessage, error) {
+	return s.resolver.Resolve(p, props)
+}
@@ -0,0 +1,29 @@
+package listing
+
+// TextQuery specifies a full-text search. Configs containing every word in Text, in either its id, name or string property
+// values, are found. If Group is set, only configs within that group are searched. A Limit of 0 or less means DefaultPageSize.
+type TextQuery struct {
+	Text  string
+	Group string
+	Limit int
+}
+
+// Snippet is a piece of a matched field with the matches highlighted. Field is either "id", "name" or "properties" followed
+// by the JSON pointer to a property value.
+type Snippet struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+// TextMatch represents a config found by full-text search
+type TextMatch struct {
+	Group    string    `json:"group"`
+	ID       string    `json:"id"`
+	Score    float64   `json:"score"`
+	Snippets []Snippet `json:"snippets"`
+}
+
+// TextMatches represents the result of a full-text search, ordered by descending score
+type TextMatches struct {
+	Matches []TextMatch `json:"matches"`
+}
@@ -0,0 +1,91 @@
+package properties
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// DiffType is the kind of a Difference
+type DiffType string
+
+const (
+	// Added is used for values only in the new document
+	Added DiffType = "added"
+	// Removed is used for values only in the old document
+	Removed DiffType = "removed"
+	// Changed is used for values replaced in the new document
+	Changed DiffType = "changed"
+)
+
+// Difference is a value added, removed or changed between two documents. Path is the JSON Pointer of the value.
+type Difference struct {
+	Type DiffType    `json:"type"`
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Diff returns the differences between two decoded JSON documents. Objects are compared member by member, in the order of
+// their keys, and arrays element by element. Any other values, or values of different types, are changed as a whole.
+func Diff(from interface{}, to interface{}) []Difference {
+	var diffs []Difference
+	diff(Pointer{}, from, to, &diffs)
+	return diffs
+}
+
+func diff(p Pointer, from interface{}, to interface{}, diffs *[]Difference) {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(f)+len(t))
+			for k := range f {
+				keys = append(keys, k)
+			}
+			for k := range t {
+				if _, ok := f[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				diffMember(p.Child(k), f, t, k, diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			for i := 0; i < len(f) || i < len(t); i++ {
+				child := p.Child(strconv.Itoa(i))
+				switch {
+				case i >= len(t):
+					*diffs = append(*diffs, Difference{Type: Removed, Path: child.String(), From: f[i]})
+				case i >= len(f):
+					*diffs = append(*diffs, Difference{Type: Added, Path: child.String(), To: t[i]})
+				default:
+					diff(child, f[i], t[i], diffs)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*diffs = append(*diffs, Difference{Type: Changed, Path: p.String(), From: from, To: to})
+	}
+}
+
+func diffMember(p Pointer, from map[string]interface{}, to map[string]interface{}, key string, diffs *[]Difference) {
+	f, inFrom := from[key]
+	t, inTo := to[key]
+
+	switch {
+	case !inTo:
+		*diffs = append(*diffs, Difference{Type: Removed, Path: p.String(), From: f})
+	case !inFrom:
+		*diffs = append(*diffs, Difference{Type: Added, Path: p.String(), To: t})
+	default:
+		diff(p, f, t, diffs)
+	}
+}
@@ -0,0 +1,29 @@
+package properties_test
+
+import (
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		from, to, expected string
+	}{
+		{`{"a":1}`, `{"a":1}`, `null`},
+		{`{"a":1}`, `{"a":2}`, `[{"type":"changed","path":"/a","from":1,"to":2}]`},
+		{`{"a":1}`, `{"b":1}`, `[{"type":"removed","path":"/a","from":1},{"type":"added","path":"/b","to":1}]`},
+		{`{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"c","d":"f"}}`, `[{"type":"changed","path":"/a/d","from":"e","to":"f"}]`},
+		{`{"a":[1,2]}`, `{"a":[1,3,4]}`, `[{"type":"changed","path":"/a/1","from":2,"to":3},{"type":"added","path":"/a/2","to":4}]`},
+		{`{"a":[1,2]}`, `{"a":[1]}`, `[{"type":"removed","path":"/a/1","from":2}]`},
+		{`{"a":[1]}`, `{"a":{"0":1}}`, `[{"type":"changed","path":"/a","from":[1],"to":{"0":1}}]`},
+		{`{"a":null}`, `{"a":{}}`, `[{"type":"changed","path":"/a","to":{}}]`},
+		{`{"a/b":1}`, `{"a/b":2}`, `[{"type":"changed","path":"/a~1b","from":1,"to":2}]`},
+		{`"a"`, `"b"`, `[{"type":"changed","path":"","from":"a","to":"b"}]`},
+	}
+
+	for _, tc := range tests {
+		res := properties.Diff(decode(t, tc.from), decode(t, tc.to))
+		test.AssertJSONEqual(t, encode(t, res), tc.expected)
+	}
+}
@@ -0,0 +1,138 @@
+package properties
+
+import (
+	"errors"
+	"github.com/larwef/ki/internal/domain"
+	"strconv"
+)
+
+// ErrPathNotFound is used when a Pointer does not refer to a value within a document.
+var ErrPathNotFound = errors.New("path not found")
+
+// ErrPropertyNotFound is used by the services when a Pointer does not refer to a property of a config.
+var ErrPropertyNotFound = domain.New(domain.NotFound, "property", "property not found")
+
+// Get returns the value p refers to within a decoded JSON document.
+func Get(doc interface{}, p Pointer) (interface{}, bool) {
+	v := doc
+	for _, token := range p {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			child, exists := val[token]
+			if !exists {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, ok := index(token, len(val))
+			if !ok {
+				return nil, false
+			}
+			v = val[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// Set sets the value p refers to within a decoded JSON document and returns the updated document. Objects missing along the
+// way are created. An array element can be replaced, or appended by using the length of the array or "-" as index. Returns
+// ErrPathNotFound if p passes through a scalar or an index out of range.
+func Set(doc interface{}, p Pointer, v interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+
+	token := p[0]
+	switch val := doc.(type) {
+	case nil:
+		child, err := Set(nil, p[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{token: child}, nil
+	case map[string]interface{}:
+		child, err := Set(val[token], p[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		val[token] = child
+		return val, nil
+	case []interface{}:
+		if token == "-" || token == strconv.Itoa(len(val)) {
+			child, err := Set(nil, p[1:], v)
+			if err != nil {
+				return nil, err
+			}
+			return append(val, child), nil
+		}
+
+		i, ok := index(token, len(val))
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		child, err := Set(val[i], p[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		val[i] = child
+		return val, nil
+	}
+
+	return nil, ErrPathNotFound
+}
+
+// Remove removes the value p refers to from a decoded JSON document and returns the updated document. Removing an array
+// element shifts the elements after it. Returns ErrPathNotFound if there is no value to remove.
+func Remove(doc interface{}, p Pointer) (interface{}, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	token := p[0]
+	switch val := doc.(type) {
+	case map[string]interface{}:
+		child, exists := val[token]
+		if !exists {
+			return nil, ErrPathNotFound
+		}
+		if len(p) == 1 {
+			delete(val, token)
+			return val, nil
+		}
+		child, err := Remove(child, p[1:])
+		if err != nil {
+			return nil, err
+		}
+		val[token] = child
+		return val, nil
+	case []interface{}:
+		i, ok := index(token, len(val))
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		if len(p) == 1 {
+			return append(val[:i:i], val[i+1:]...), nil
+		}
+		child, err := Remove(val[i], p[1:])
+		if err != nil {
+			return nil, err
+		}
+		val[i] = child
+		return val, nil
+	}
+
+	return nil, ErrPathNotFound
+}
+
+// index parses an array index token and checks that it is within an array of length n
+func index(token string, n int) (int, bool) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= n || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+
+	return i, true
+}
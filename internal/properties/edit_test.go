@@ -0,0 +1,89 @@
+package properties_test
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func encode(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestGet(t *testing.T) {
+	doc := decode(t, `{"database":{"hosts":["a","b"]}}`)
+
+	v, ok := properties.Get(doc, properties.Pointer{"database", "hosts", "1"})
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, v, "b")
+
+	for _, p := range []properties.Pointer{{"missing"}, {"database", "hosts", "2"}, {"database", "hosts", "01"}, {"database", "hosts", "0", "x"}} {
+		_, ok := properties.Get(doc, p)
+		test.AssertEqual(t, ok, false)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		doc      string
+		pointer  properties.Pointer
+		value    interface{}
+		expected string
+	}{
+		{`{"a":1}`, properties.Pointer{"a"}, "x", `{"a":"x"}`},
+		{`{"a":1}`, properties.Pointer{"b", "c"}, true, `{"a":1,"b":{"c":true}}`},
+		{`{"a":[1,2]}`, properties.Pointer{"a", "0"}, 3.0, `{"a":[3,2]}`},
+		{`{"a":[1,2]}`, properties.Pointer{"a", "2"}, 3.0, `{"a":[1,2,3]}`},
+		{`{"a":[1,2]}`, properties.Pointer{"a", "-"}, 3.0, `{"a":[1,2,3]}`},
+		{`null`, properties.Pointer{"a"}, 1.0, `{"a":1}`},
+		{`{"a":1}`, properties.Pointer{}, "x", `"x"`},
+	}
+
+	for _, tc := range tests {
+		res, err := properties.Set(decode(t, tc.doc), tc.pointer, tc.value)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, encode(t, res), tc.expected)
+	}
+}
+
+func TestSet_PathNotFound(t *testing.T) {
+	for _, p := range []properties.Pointer{{"a", "b"}, {"list", "3"}, {"list", "x"}} {
+		_, err := properties.Set(decode(t, `{"a":1,"list":[1]}`), p, 1.0)
+		test.AssertEqual(t, err, properties.ErrPathNotFound)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		doc      string
+		pointer  properties.Pointer
+		expected string
+	}{
+		{`{"a":1,"b":2}`, properties.Pointer{"a"}, `{"b":2}`},
+		{`{"a":{"b":1,"c":2}}`, properties.Pointer{"a", "b"}, `{"a":{"c":2}}`},
+		{`{"a":[1,2,3]}`, properties.Pointer{"a", "1"}, `{"a":[1,3]}`},
+		{`{"a":1}`, properties.Pointer{}, `null`},
+	}
+
+	for _, tc := range tests {
+		res, err := properties.Remove(decode(t, tc.doc), tc.pointer)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, encode(t, res), tc.expected)
+	}
+
+	_, err := properties.Remove(decode(t, `{"a":1}`), properties.Pointer{"b"})
+	test.AssertEqual(t, err, properties.ErrPathNotFound)
+}
@@ -0,0 +1,66 @@
+package properties
+
+// ArrayMerge is how an array is merged with the array it overrides
+type ArrayMerge string
+
+const (
+	// ReplaceArrays replaces the inherited array, and is the default
+	ReplaceArrays ArrayMerge = "replace"
+	// AppendArrays appends the elements of the overriding array to the inherited array
+	AppendArrays ArrayMerge = "append"
+)
+
+// NullMerge is how null overrides an inherited value
+type NullMerge string
+
+const (
+	// KeepNulls overrides the inherited value with null, and is the default
+	KeepNulls NullMerge = "keep"
+	// DeleteNulls removes the inherited value, like in a JSON Merge Patch
+	DeleteNulls NullMerge = "delete"
+)
+
+// MergeRules are the rules for merging a document with the document it overrides. The zero value has the default rules.
+type MergeRules struct {
+	Arrays ArrayMerge `json:"arrays,omitempty"`
+	Nulls  NullMerge  `json:"nulls,omitempty"`
+}
+
+// Valid checks if the rules are known
+func (m MergeRules) Valid() bool {
+	return (m.Arrays == "" || m.Arrays == ReplaceArrays || m.Arrays == AppendArrays) &&
+		(m.Nulls == "" || m.Nulls == KeepNulls || m.Nulls == DeleteNulls)
+}
+
+// Merge deep merges a decoded JSON document onto a base document it overrides and returns the merged document. Objects are
+// merged member by member, arrays and nulls as given by the rules, and any other value replaces the base value. Neither
+// document is modified.
+func Merge(base, override interface{}, rules MergeRules) interface{} {
+	switch o := override.(type) {
+	case map[string]interface{}:
+		b, _ := base.(map[string]interface{})
+		merged := make(map[string]interface{}, len(b)+len(o))
+		for k, v := range b {
+			merged[k] = v
+		}
+
+		for k, v := range o {
+			if v == nil && rules.Nulls == DeleteNulls {
+				delete(merged, k)
+			} else {
+				merged[k] = Merge(merged[k], v, rules)
+			}
+		}
+		return merged
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok || rules.Arrays != AppendArrays {
+			return o
+		}
+
+		merged := make([]interface{}, 0, len(b)+len(o))
+		return append(append(merged, b...), o...)
+	}
+
+	return override
+}
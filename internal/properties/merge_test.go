@@ -0,0 +1,51 @@
+package properties_test
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := `{"db":{"host":"db1","port":5432,"tags":["a"]},"debug":false,"list":[1,2]}`
+
+	tests := []struct {
+		override string
+		rules    properties.MergeRules
+		expected string
+	}{
+		{`{"db":{"host":"db2"}}`, properties.MergeRules{}, `{"db":{"host":"db2","port":5432,"tags":["a"]},"debug":false,"list":[1,2]}`},
+		{`{"list":[3],"db":{"tags":["b"]}}`, properties.MergeRules{}, `{"db":{"host":"db1","port":5432,"tags":["b"]},"debug":false,"list":[3]}`},
+		{`{"list":[3],"db":{"tags":["b"]}}`, properties.MergeRules{Arrays: properties.AppendArrays}, `{"db":{"host":"db1","port":5432,"tags":["a","b"]},"debug":false,"list":[1,2,3]}`},
+		{`{"db":{"port":null},"debug":null}`, properties.MergeRules{}, `{"db":{"host":"db1","port":null,"tags":["a"]},"debug":null,"list":[1,2]}`},
+		{`{"db":{"port":null},"debug":null,"new":{"a":null}}`, properties.MergeRules{Nulls: properties.DeleteNulls}, `{"db":{"host":"db1","tags":["a"]},"list":[1,2],"new":{}}`},
+		{`{"db":"none"}`, properties.MergeRules{}, `{"db":"none","debug":false,"list":[1,2]}`},
+		{`[1]`, properties.MergeRules{}, `[1]`},
+	}
+
+	for _, tc := range tests {
+		var b, o interface{}
+		test.AssertNotError(t, json.Unmarshal([]byte(base), &b))
+		test.AssertNotError(t, json.Unmarshal([]byte(tc.override), &o))
+
+		merged, err := json.Marshal(properties.Merge(b, o, tc.rules))
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, string(merged), tc.expected)
+	}
+
+	// The base document is not modified
+	var b interface{}
+	test.AssertNotError(t, json.Unmarshal([]byte(base), &b))
+	properties.Merge(b, map[string]interface{}{"db": map[string]interface{}{"host": "db2"}}, properties.MergeRules{})
+	actual, err := json.Marshal(b)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(actual), base)
+}
+
+func TestMergeRules_Valid(t *testing.T) {
+	test.AssertEqual(t, properties.MergeRules{}.Valid(), true)
+	test.AssertEqual(t, properties.MergeRules{Arrays: properties.AppendArrays, Nulls: properties.DeleteNulls}.Valid(), true)
+	test.AssertEqual(t, properties.MergeRules{Arrays: "merge"}.Valid(), false)
+	test.AssertEqual(t, properties.MergeRules{Nulls: "ignore"}.Valid(), false)
+}
@@ -0,0 +1,231 @@
+package properties
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// InvalidPatchError is used when an operation of a JSON Patch is malformed or cannot be applied.
+type InvalidPatchError struct {
+	// Index is the index of the operation in the patch
+	Index  int
+	Reason string
+}
+
+func (i InvalidPatchError) Error() string {
+	return fmt.Sprintf("invalid patch operation %d: %s", i.Index, i.Reason)
+}
+
+// Describe describes the error as an invalid argument
+func (i InvalidPatchError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, "patch", i.Error(), domain.Violation{Field: "/" + strconv.Itoa(i.Index), Description: i.Reason})
+}
+
+// TestFailedError is used when a test operation of a JSON Patch does not match the document.
+type TestFailedError struct {
+	// Index is the index of the operation in the patch
+	Index int
+	Path  string
+}
+
+func (t TestFailedError) Error() string {
+	return fmt.Sprintf("test operation %d failed: value at %q does not match", t.Index, t.Path)
+}
+
+// Describe describes the error as a failed precondition
+func (t TestFailedError) Describe() *domain.Error {
+	return domain.New(domain.FailedPrecondition, "patch", t.Error(), domain.Violation{Field: t.Path, Description: "value does not match"})
+}
+
+// Operation is an operation of a JSON Patch as defined in RFC 6902. Value is nil if the operation has no value, and "null"
+// if the value is null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch applies a JSON Merge Patch as defined in RFC 7396 to a decoded JSON document and returns the patched document.
+// Objects in the patch are merged into the document, where null removes a member, and any other value replaces the value in
+// the document.
+func MergePatch(doc, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		obj = make(map[string]interface{})
+	}
+
+	for k, v := range patchObj {
+		if v == nil {
+			delete(obj, k)
+		} else {
+			obj[k] = MergePatch(obj[k], v)
+		}
+	}
+
+	return obj
+}
+
+// ApplyPatch applies the operations of a JSON Patch as defined in RFC 6902 to a decoded JSON document and returns the patched
+// document. Operations are applied in order and the patch fails as a whole on the first operation failing, returning an
+// InvalidPatchError or a TestFailedError. The document may be partially modified if the patch fails.
+func ApplyPatch(doc interface{}, ops []Operation) (interface{}, error) {
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			if _, ok := err.(testFailed); ok {
+				return nil, TestFailedError{Index: i, Path: op.Path}
+			}
+			return nil, InvalidPatchError{Index: i, Reason: err.Error()}
+		}
+	}
+
+	return doc, nil
+}
+
+// patchReason describes why an operation is invalid, and is turned into an InvalidPatchError by ApplyPatch
+type patchReason string
+
+func (p patchReason) Error() string {
+	return string(p)
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, patchReason(fmt.Sprintf("invalid path %q", op.Path))
+	}
+
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, patchReason(fmt.Sprintf("%s requires a value", op.Op))
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, patchReason("value is not valid JSON")
+		}
+	}
+
+	var from Pointer
+	switch op.Op {
+	case "move", "copy":
+		if from, err = ParsePointer(op.From); err != nil {
+			return nil, patchReason(fmt.Sprintf("invalid from %q", op.From))
+		}
+		v, ok := Get(doc, from)
+		if !ok {
+			return nil, patchReason(fmt.Sprintf("from %q does not exist", op.From))
+		}
+		value = clone(v)
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "remove":
+		return remove(doc, path)
+	case "replace":
+		if _, ok := Get(doc, path); !ok {
+			return nil, patchReason(fmt.Sprintf("path %q does not exist", op.Path))
+		}
+		return Set(doc, path, value)
+	case "move":
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, patchReason("cannot move a value into itself")
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "copy":
+		return add(doc, path, value)
+	case "test":
+		if v, ok := Get(doc, path); !ok || !reflect.DeepEqual(v, value) {
+			return nil, testFailed{}
+		}
+		return doc, nil
+	}
+
+	return nil, patchReason(fmt.Sprintf("unknown op %q", op.Op))
+}
+
+// testFailed is returned by applyOperation when a test operation does not match, and is turned into a TestFailedError by
+// ApplyPatch
+type testFailed struct{}
+
+func (testFailed) Error() string {
+	return "test failed"
+}
+
+// add adds a value as specified by the add operation. A value added to an array is inserted before the element at the index.
+func add(doc interface{}, p Pointer, v interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+
+	parentPath, token := p[:len(p)-1], p[len(p)-1]
+	parent, ok := Get(doc, parentPath)
+	if !ok {
+		return nil, patchReason(fmt.Sprintf("parent of %q does not exist", p.String()))
+	}
+
+	switch val := parent.(type) {
+	case map[string]interface{}:
+		val[token] = v
+		return doc, nil
+	case []interface{}:
+		i := len(val)
+		if token != "-" {
+			var err error
+			if i, err = strconv.Atoi(token); err != nil || i < 0 || i > len(val) || (len(token) > 1 && token[0] == '0') {
+				return nil, patchReason(fmt.Sprintf("index of %q is out of range", p.String()))
+			}
+		}
+		arr := append(val[:i:i], append([]interface{}{v}, val[i:]...)...)
+		return Set(doc, parentPath, arr)
+	}
+
+	return nil, patchReason(fmt.Sprintf("parent of %q is not an object or array", p.String()))
+}
+
+func remove(doc interface{}, p Pointer) (interface{}, error) {
+	res, err := Remove(doc, p)
+	if err == ErrPathNotFound {
+		return nil, patchReason(fmt.Sprintf("path %q does not exist", p.String()))
+	}
+
+	return res, err
+}
+
+// clone returns a deep copy of a decoded JSON value
+func clone(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			res[k] = clone(child)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, child := range val {
+			res[i] = clone(child)
+		}
+		return res
+	}
+
+	return v
+}
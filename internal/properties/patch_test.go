@@ -0,0 +1,102 @@
+package properties_test
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7396 appendix A
+	tests := []struct {
+		doc, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range tests {
+		res := properties.MergePatch(decode(t, tc.doc), decode(t, tc.patch))
+		test.AssertJSONEqual(t, encode(t, res), tc.expected)
+	}
+}
+
+func applyPatch(t *testing.T, doc, patch string) (interface{}, error) {
+	var ops []properties.Operation
+	if err := json.Unmarshal([]byte(patch), &ops); err != nil {
+		t.Fatal(err)
+	}
+	return properties.ApplyPatch(decode(t, doc), ops)
+}
+
+func TestApplyPatch(t *testing.T) {
+	// Examples from RFC 6902 appendix A
+	tests := []struct {
+		doc, patch, expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`, `{"a":{"b":[1]},"c":{"b":[1,2]}}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+
+	for _, tc := range tests {
+		res, err := applyPatch(t, tc.doc, tc.patch)
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, encode(t, res), tc.expected)
+	}
+}
+
+func TestApplyPatch_Invalid(t *testing.T) {
+	tests := []struct {
+		doc, patch, reason string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, `parent of "/baz/bat" does not exist`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `path "/baz" does not exist`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, `path "/baz" does not exist`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":1}]`, `index of "/foo/2" is out of range`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]`, `add requires a value`},
+		{`{"foo":"bar"}`, `[{"op":"move","from":"/missing","path":"/baz"}]`, `from "/missing" does not exist`},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`, `cannot move a value into itself`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"baz","value":1}]`, `invalid path "baz"`},
+		{`{"foo":"bar"}`, `[{"op":"test","path":"/foo","value":"bar"},{"op":"rename","path":"/foo"}]`, `unknown op "rename"`},
+	}
+
+	for _, tc := range tests {
+		_, err := applyPatch(t, tc.doc, tc.patch)
+		patchErr, ok := err.(properties.InvalidPatchError)
+		test.AssertEqual(t, ok, true)
+		test.AssertEqual(t, patchErr.Reason, tc.reason)
+	}
+}
+
+func TestApplyPatch_TestFailed(t *testing.T) {
+	_, err := applyPatch(t, `{"baz":"qux"}`, `[{"op":"replace","path":"/baz","value":"boo"},{"op":"test","path":"/baz","value":"qux"}]`)
+	test.AssertEqual(t, err, properties.TestFailedError{Index: 1, Path: "/baz"})
+
+	_, err = applyPatch(t, `{"baz":"qux"}`, `[{"op":"test","path":"/missing","value":null}]`)
+	test.AssertEqual(t, err, properties.TestFailedError{Index: 0, Path: "/missing"})
+}
@@ -0,0 +1,96 @@
+// Package properties provides operations on the arbitrary JSON documents stored as config properties.
+package properties
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath is used when a path or pointer expression cannot be parsed.
+var ErrInvalidPath = errors.New("invalid path")
+
+// Pointer identifies a value within a JSON document as a list of object keys and array indices. Its string form is a JSON
+// Pointer as defined in RFC 6901. The empty Pointer refers to the whole document.
+type Pointer []string
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// ParsePointer parses a JSON Pointer such as "/database/hosts/0".
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+
+	if s[0] != '/' {
+		return nil, ErrInvalidPath
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+
+	return Pointer(tokens), nil
+}
+
+// ParsePath parses a JSONPath style expression such as "$.database.hosts[0]" or "database.hosts[0]". The leading "$" is
+// optional and refers to the root of the properties.
+func ParsePath(s string) (Pointer, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	if s == "" {
+		return Pointer{}, nil
+	}
+
+	var p Pointer
+	for _, segment := range strings.Split(s, ".") {
+		key := segment
+		var indices []string
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			key = segment[:i]
+			rest := segment[i:]
+			for rest != "" {
+				end := strings.IndexByte(rest, ']')
+				if rest[0] != '[' || end < 0 {
+					return nil, ErrInvalidPath
+				}
+				if _, err := strconv.Atoi(rest[1:end]); err != nil {
+					return nil, ErrInvalidPath
+				}
+				indices = append(indices, rest[1:end])
+				rest = rest[end+1:]
+			}
+		}
+
+		if key == "" && len(indices) == 0 {
+			return nil, ErrInvalidPath
+		}
+		if key != "" {
+			p = append(p, key)
+		}
+		p = append(p, indices...)
+	}
+
+	return p, nil
+}
+
+// String returns the JSON Pointer representation of p.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, t := range p {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(t))
+	}
+
+	return sb.String()
+}
+
+// Child returns a new Pointer referring to the token within the value p refers to.
+func (p Pointer) Child(token string) Pointer {
+	child := make(Pointer, len(p), len(p)+1)
+	copy(child, p)
+	return append(child, token)
+}
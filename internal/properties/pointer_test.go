@@ -0,0 +1,46 @@
+package properties_test
+
+import (
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"$":                      "",
+		"database.host":          "/database/host",
+		"$.database.host":        "/database/host",
+		"servers[1].host":        "/servers/1/host",
+		"matrix[0][2]":           "/matrix/0/2",
+		"some/key.with~tilde":    "/some~1key/with~0tilde",
+		"$.servers[10].ports[0]": "/servers/10/ports/0",
+	}
+
+	for expr, expected := range tests {
+		p, err := properties.ParsePath(expr)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, p.String(), expected)
+	}
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	for _, expr := range []string{"database..host", "servers[a]", "servers[0", "servers]0["} {
+		_, err := properties.ParsePath(expr)
+		test.AssertEqual(t, err, properties.ErrInvalidPath)
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	p, err := properties.ParsePointer("/some~1key/with~0tilde/0")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(p), 3)
+	test.AssertEqual(t, p[0], "some/key")
+	test.AssertEqual(t, p[1], "with~tilde")
+	test.AssertEqual(t, p[2], "0")
+	test.AssertEqual(t, p.String(), "/some~1key/with~0tilde/0")
+
+	_, err = properties.ParsePointer("noSlash")
+	test.AssertEqual(t, err, properties.ErrInvalidPath)
+}
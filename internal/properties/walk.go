@@ -0,0 +1,24 @@
+package properties
+
+import "strconv"
+
+// Walk calls fn for every value in a decoded JSON document, including the root and every object and array, with the Pointer
+// referring to it. Parents are visited before their children.
+func Walk(v interface{}, fn func(p Pointer, v interface{})) {
+	walk(Pointer{}, v, fn)
+}
+
+func walk(p Pointer, v interface{}, fn func(p Pointer, v interface{})) {
+	fn(p, v)
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			walk(p.Child(k), child, fn)
+		}
+	case []interface{}:
+		for i, child := range val {
+			walk(p.Child(strconv.Itoa(i)), child, fn)
+		}
+	}
+}
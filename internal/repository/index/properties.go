@@ -0,0 +1,115 @@
+// Package index provides secondary indexes over stored configs that repositories maintain on write.
+package index
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"sync"
+)
+
+// compound is stored in place of objects and arrays, which can only be matched by existence.
+type compound struct{}
+
+// Properties indexes the values of config properties by their path. Searching only has to look at the configs that has a value
+// at the paths in the conditions.
+type Properties struct {
+	lock sync.RWMutex
+	// values maps a JSON pointer to the value at that pointer for every config having it
+	values map[string]map[listing.ConfigRef]interface{}
+	// paths maps a config to all JSON pointers indexed for it, so they can be removed on update
+	paths map[listing.ConfigRef][]string
+}
+
+// NewProperties returns a new empty Properties index
+func NewProperties() *Properties {
+	return &Properties{
+		values: make(map[string]map[listing.ConfigRef]interface{}),
+		paths:  make(map[listing.ConfigRef][]string),
+	}
+}
+
+// Put indexes the properties of a config, replacing anything previously indexed for it. Properties that are not valid JSON
+// are not indexed.
+func (p *Properties) Put(ref listing.ConfigRef, props json.RawMessage) {
+	var doc interface{}
+	if len(props) > 0 {
+		if err := json.Unmarshal(props, &doc); err != nil {
+			doc = nil
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.remove(ref)
+	if doc == nil {
+		return
+	}
+
+	var paths []string
+	properties.Walk(doc, func(ptr properties.Pointer, v interface{}) {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			v = compound{}
+		}
+
+		key := ptr.String()
+		if p.values[key] == nil {
+			p.values[key] = make(map[listing.ConfigRef]interface{})
+		}
+		p.values[key][ref] = v
+		paths = append(paths, key)
+	})
+	p.paths[ref] = paths
+}
+
+// Remove removes everything indexed for a config
+func (p *Properties) Remove(ref listing.ConfigRef) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.remove(ref)
+}
+
+func (p *Properties) remove(ref listing.ConfigRef) {
+	for _, key := range p.paths[ref] {
+		delete(p.values[key], ref)
+		if len(p.values[key]) == 0 {
+			delete(p.values, key)
+		}
+	}
+	delete(p.paths, ref)
+}
+
+// Search returns all configs satisfying every condition. The conditions has to be compiled by the listing service. The
+// returned configs are not ordered.
+func (p *Properties) Search(conditions []listing.Condition) []listing.ConfigRef {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var candidates map[listing.ConfigRef]bool
+	for _, c := range conditions {
+		matches := make(map[listing.ConfigRef]bool)
+		for ref, v := range p.values[c.Pointer().String()] {
+			if candidates != nil && !candidates[ref] {
+				continue
+			}
+			if c.Match(v) {
+				matches[ref] = true
+			}
+		}
+
+		candidates = matches
+		if len(candidates) == 0 {
+			break
+		}
+	}
+
+	refs := []listing.ConfigRef{}
+	for ref := range candidates {
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
@@ -0,0 +1,75 @@
+package index
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/listing"
+	"sync"
+)
+
+// References indexes the configs referenced from the properties of configs, so the configs depending on a config can be
+// found when it changes.
+type References struct {
+	lock sync.RWMutex
+	// dependents maps a config to the configs referencing it
+	dependents map[listing.ConfigRef]map[listing.ConfigRef]bool
+	// referenced maps a config to the configs it references, so they can be removed on update
+	referenced map[listing.ConfigRef][]listing.ConfigRef
+}
+
+// NewReferences returns a new empty References index
+func NewReferences() *References {
+	return &References{
+		dependents: make(map[listing.ConfigRef]map[listing.ConfigRef]bool),
+		referenced: make(map[listing.ConfigRef][]listing.ConfigRef),
+	}
+}
+
+// Put indexes the references in the properties of a config, replacing anything previously indexed for it
+func (r *References) Put(ref listing.ConfigRef, props json.RawMessage) {
+	refs := listing.FindReferences(props)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.remove(ref)
+	for _, referenced := range refs {
+		if r.dependents[referenced] == nil {
+			r.dependents[referenced] = make(map[listing.ConfigRef]bool)
+		}
+		r.dependents[referenced][ref] = true
+	}
+	if len(refs) > 0 {
+		r.referenced[ref] = refs
+	}
+}
+
+// Remove removes everything indexed for a config. Configs referencing it are kept as its dependents.
+func (r *References) Remove(ref listing.ConfigRef) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.remove(ref)
+}
+
+func (r *References) remove(ref listing.ConfigRef) {
+	for _, referenced := range r.referenced[ref] {
+		delete(r.dependents[referenced], ref)
+		if len(r.dependents[referenced]) == 0 {
+			delete(r.dependents, referenced)
+		}
+	}
+	delete(r.referenced, ref)
+}
+
+// Dependents returns the configs directly referencing a config. The returned configs are not ordered.
+func (r *References) Dependents(ref listing.ConfigRef) []listing.ConfigRef {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	dependents := []listing.ConfigRef{}
+	for dependent := range r.dependents[ref] {
+		dependents = append(dependents, dependent)
+	}
+
+	return dependents
+}
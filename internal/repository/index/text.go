@@ -0,0 +1,240 @@
+package index
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// idBoost and nameBoost weights matches in the id and name over matches in property values
+	idBoost   = 2.0
+	nameBoost = 2.0
+
+	// snippetContext is the number of characters kept on each side of the first match in a snippet
+	snippetContext = 40
+	// maxSnippets is the maximum number of snippets returned per config
+	maxSnippets = 5
+
+	highlightStart = "<em>"
+	highlightEnd   = "</em>"
+)
+
+// field is a piece of indexed text belonging to a config
+type field struct {
+	name  string
+	text  string
+	boost float64
+}
+
+// Text is an inverted index over the id, name and string property values of configs.
+type Text struct {
+	lock sync.RWMutex
+	// postings maps a token to the weighted number of occurrences in each config
+	postings map[string]map[listing.ConfigRef]float64
+	// fields holds the indexed text of every config, used for removal and snippets
+	fields map[listing.ConfigRef][]field
+}
+
+// NewText returns a new empty Text index
+func NewText() *Text {
+	return &Text{
+		postings: make(map[string]map[listing.ConfigRef]float64),
+		fields:   make(map[listing.ConfigRef][]field),
+	}
+}
+
+// Put indexes a config, replacing anything previously indexed for it. Properties that are not valid JSON are not indexed.
+func (t *Text) Put(ref listing.ConfigRef, name string, props json.RawMessage) {
+	fields := []field{
+		{name: "id", text: ref.ID, boost: idBoost},
+		{name: "name", text: name, boost: nameBoost},
+	}
+
+	var doc interface{}
+	if len(props) > 0 && json.Unmarshal(props, &doc) == nil {
+		var propFields []field
+		properties.Walk(doc, func(p properties.Pointer, v interface{}) {
+			if s, ok := v.(string); ok {
+				propFields = append(propFields, field{name: "properties" + p.String(), text: s, boost: 1})
+			}
+		})
+		sort.Slice(propFields, func(i, j int) bool { return propFields[i].name < propFields[j].name })
+		fields = append(fields, propFields...)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.remove(ref)
+	for _, f := range fields {
+		for _, s := range tokenize(f.text) {
+			token := strings.ToLower(f.text[s.start:s.end])
+			if t.postings[token] == nil {
+				t.postings[token] = make(map[listing.ConfigRef]float64)
+			}
+			t.postings[token][ref] += f.boost
+		}
+	}
+	t.fields[ref] = fields
+}
+
+// Remove removes everything indexed for a config
+func (t *Text) Remove(ref listing.ConfigRef) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.remove(ref)
+}
+
+func (t *Text) remove(ref listing.ConfigRef) {
+	for _, f := range t.fields[ref] {
+		for _, s := range tokenize(f.text) {
+			token := strings.ToLower(f.text[s.start:s.end])
+			delete(t.postings[token], ref)
+			if len(t.postings[token]) == 0 {
+				delete(t.postings, token)
+			}
+		}
+	}
+	delete(t.fields, ref)
+}
+
+// Search returns all configs containing every token in text. Each match is scored by the weighted number of occurrences of the
+// tokens, where rare tokens weigh more, and has snippets of the fields matched with the tokens highlighted. The returned
+// matches are not ordered.
+func (t *Text) Search(text string) []listing.TextMatch {
+	tokens := map[string]bool{}
+	for _, s := range tokenize(text) {
+		tokens[strings.ToLower(text[s.start:s.end])] = true
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var scores map[listing.ConfigRef]float64
+	for token := range tokens {
+		postings := t.postings[token]
+		idf := math.Log(1 + float64(len(t.fields))/float64(len(postings)+1))
+
+		matches := make(map[listing.ConfigRef]float64)
+		for ref, tf := range postings {
+			if scores == nil {
+				matches[ref] = tf * idf
+			} else if score, ok := scores[ref]; ok {
+				matches[ref] = score + tf*idf
+			}
+		}
+
+		scores = matches
+		if len(scores) == 0 {
+			break
+		}
+	}
+
+	res := []listing.TextMatch{}
+	for ref, score := range scores {
+		res = append(res, listing.TextMatch{
+			Group:    ref.Group,
+			ID:       ref.ID,
+			Score:    score,
+			Snippets: t.snippets(ref, tokens),
+		})
+	}
+
+	return res
+}
+
+// snippets highlights the tokens in the fields of a config containing them
+func (t *Text) snippets(ref listing.ConfigRef, tokens map[string]bool) []listing.Snippet {
+	var snippets []listing.Snippet
+	for _, f := range t.fields[ref] {
+		if len(snippets) == maxSnippets {
+			break
+		}
+
+		var hits []span
+		for _, s := range tokenize(f.text) {
+			if tokens[strings.ToLower(f.text[s.start:s.end])] {
+				hits = append(hits, s)
+			}
+		}
+
+		if len(hits) > 0 {
+			snippets = append(snippets, listing.Snippet{Field: f.name, Text: highlight(f.text, hits)})
+		}
+	}
+
+	return snippets
+}
+
+// highlight marks the hits in text, cutting away text further than snippetContext from the first and last hit
+func highlight(text string, hits []span) string {
+	start := hits[0].start - snippetContext
+	end := hits[len(hits)-1].end + snippetContext
+
+	var sb strings.Builder
+	if start <= 0 {
+		start = 0
+	} else {
+		start = runeStart(text, start)
+		sb.WriteString("…")
+	}
+
+	pos := start
+	for _, h := range hits {
+		sb.WriteString(text[pos:h.start])
+		sb.WriteString(highlightStart)
+		sb.WriteString(text[h.start:h.end])
+		sb.WriteString(highlightEnd)
+		pos = h.end
+	}
+
+	if end >= len(text) {
+		sb.WriteString(text[pos:])
+	} else {
+		sb.WriteString(text[pos:runeStart(text, end)])
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
+
+// runeStart moves i back to the start of the UTF-8 encoded rune it is within
+func runeStart(s string, i int) int {
+	for i > 0 && s[i]&0xC0 == 0x80 {
+		i--
+	}
+	return i
+}
+
+// span is the byte offsets of a token within a text
+type span struct {
+	start, end int
+}
+
+// tokenize splits text into tokens of letters and digits
+func tokenize(text string) []span {
+	var spans []span
+	start := -1
+	for i, r := range text {
+		isPart := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isPart && start < 0 {
+			start = i
+		} else if !isPart && start >= 0 {
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+
+	return spans
+}
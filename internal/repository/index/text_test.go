@@ -0,0 +1,34 @@
+package index
+
+import (
+	"github.com/larwef/ki/test"
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text     string
+		token    string
+		expected string
+	}{
+		{text: "some text", token: "text", expected: "some <em>text</em>"},
+		{text: "Text, text and TEXT", token: "text", expected: "<em>Text</em>, <em>text</em> and <em>TEXT</em>"},
+		{
+			text:     strings.Repeat("a ", 30) + "needle" + strings.Repeat(" b", 30),
+			token:    "needle",
+			expected: "…" + strings.Repeat("a ", 20) + "<em>needle</em>" + strings.Repeat(" b", 20) + "…",
+		},
+		{text: "æøå needle", token: "needle", expected: "æøå <em>needle</em>"},
+	}
+
+	for _, tst := range tests {
+		var hits []span
+		for _, s := range tokenize(tst.text) {
+			if strings.ToLower(tst.text[s.start:s.end]) == tst.token {
+				hits = append(hits, s)
+			}
+		}
+		test.AssertEqual(t, highlight(tst.text, hits), tst.expected)
+	}
+}
@@ -0,0 +1,68 @@
+package local
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/scheduling"
+	"time"
+)
+
+// Activation represents a scheduled activation to be stored. The config it adds is stored as it will be added.
+type Activation struct {
+	ID           int64           `json:"id"`
+	State        string          `json:"state"`
+	ActivatesAt  time.Time       `json:"activatesAt"`
+	Created      time.Time       `json:"created"`
+	Activated    time.Time       `json:"activated"`
+	Revision     int64           `json:"revision,omitempty"`
+	BaseRevision int64           `json:"baseRevision,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Group        string          `json:"group"`
+	Config       string          `json:"config"`
+	Name         string          `json:"name"`
+	Version      int             `json:"version"`
+	Parent       string          `json:"parent,omitempty"`
+	Properties   json.RawMessage `json:"properties"`
+}
+
+// newActivation returns the activation to be stored
+func newActivation(a scheduling.Activation) Activation {
+	return Activation{
+		ID:           a.ID,
+		State:        string(a.State),
+		ActivatesAt:  a.ActivatesAt,
+		Created:      a.Created,
+		Activated:    a.Activated,
+		Revision:     a.Revision,
+		BaseRevision: a.BaseRevision,
+		Error:        a.Error,
+		Group:        a.Config.Group,
+		Config:       a.Config.ID,
+		Name:         a.Config.Name,
+		Version:      a.Config.Version,
+		Parent:       a.Config.Parent,
+		Properties:   a.Config.Properties,
+	}
+}
+
+// schedulingActivation returns the stored activation to be scheduled
+func schedulingActivation(a Activation) scheduling.Activation {
+	return scheduling.Activation{
+		ID:           a.ID,
+		State:        scheduling.State(a.State),
+		ActivatesAt:  a.ActivatesAt,
+		Created:      a.Created,
+		Activated:    a.Activated,
+		Revision:     a.Revision,
+		BaseRevision: a.BaseRevision,
+		Error:        a.Error,
+		Config: adding.Config{
+			ID:         a.Config,
+			Name:       a.Name,
+			Version:    a.Version,
+			Group:      a.Group,
+			Parent:     a.Parent,
+			Properties: a.Properties,
+		},
+	}
+}
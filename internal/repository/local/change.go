@@ -0,0 +1,17 @@
+package local
+
+// Change represents a mutation to be stored
+type Change struct {
+	Revision    int64  `json:"revision"`
+	Resource    string `json:"resource"`
+	Group       string `json:"group"`
+	ID          string `json:"id"`
+	Environment string `json:"environment,omitempty"`
+	Deleted     bool   `json:"deleted,omitempty"`
+}
+
+// changeOffset is where a change starts in the change log
+type changeOffset struct {
+	revision int64
+	offset   int64
+}
@@ -11,6 +11,8 @@ type Config struct {
 	Name         string          `json:"name"`
 	LastModified time.Time       `json:"lastModified"`
 	Version      int             `json:"version"`
+	Revision     int64           `json:"revision"`
 	Group        string          `json:"group"`
+	Parent       string          `json:"parent,omitempty"`
 	Properties   json.RawMessage `json:"properties"`
 }
@@ -1,7 +1,52 @@
 package local
 
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+)
+
 // Group represents a group object to be stored
 type Group struct {
-	ID      string   `json:"id"`
-	Configs []string `json:"configs"`
+	ID         string          `json:"id"`
+	Revision   int64           `json:"revision"`
+	Configs    []string        `json:"configs"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	Defaults   *Defaults       `json:"defaults,omitempty"`
+	Recipients []string        `json:"recipients,omitempty"`
+	Approvers  []string        `json:"approvers,omitempty"`
+}
+
+// Defaults represents the default properties of a group to be stored
+type Defaults struct {
+	Properties json.RawMessage       `json:"properties"`
+	Merge      properties.MergeRules `json:"merge"`
+}
+
+// newDefaults returns the defaults to be stored, or nil if there are none
+func newDefaults(d *adding.Defaults) *Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &Defaults{Properties: d.Properties, Merge: d.Merge}
+}
+
+// listingDefaults returns the stored defaults to be listed, or nil if there are none
+func listingDefaults(d *Defaults) *listing.Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &listing.Defaults{Properties: d.Properties, Merge: d.Merge}
+}
+
+// storedDefaults returns the listed defaults of a group to be stored again, or nil if there are none
+func storedDefaults(d *listing.Defaults) *Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &Defaults{Properties: d.Properties, Merge: d.Merge}
 }
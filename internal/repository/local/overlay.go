@@ -0,0 +1,24 @@
+package local
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Overlay represents the properties of a config in an environment to be stored
+type Overlay struct {
+	Group        string          `json:"group"`
+	Config       string          `json:"config"`
+	Environment  string          `json:"environment"`
+	Version      int             `json:"version"`
+	Revision     int64           `json:"revision"`
+	LastModified time.Time       `json:"lastModified"`
+	Properties   json.RawMessage `json:"properties"`
+	PromotedFrom *Promotion      `json:"promotedFrom,omitempty"`
+}
+
+// Promotion represents the overlay an overlay was promoted from to be stored
+type Promotion struct {
+	Environment string `json:"environment"`
+	Revision    int64  `json:"revision"`
+}
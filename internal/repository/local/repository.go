@@ -1,16 +1,58 @@
 package local
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/index"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/scheduling"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
+// changeLogFile is the name of the file, relative to the repository path, where all changes are appended as JSON lines.
+const changeLogFile = "changes.log"
+
+// changeRequestsDir is the name of the directory, relative to the repository path, where change requests are stored. Group
+// ids cannot start with an underscore, so it is never the directory of a group.
+const changeRequestsDir = "_requests"
+
+// historyDir is the name of the directory, relative to the repository path, where every version of each config stored is
+// appended as JSON lines, in a file per config within a directory per group
+const historyDir = "_history"
+
+// activationsDir is the name of the directory, relative to the repository path, where scheduled activations are stored
+const activationsDir = "_activations"
+
 // Repository representa a local storge object
 type Repository struct {
 	path string
+
+	// lock serializes mutations so revisions are assigned and logged in order
+	lock     sync.Mutex
+	revision int64
+	// The change log is indexed the first time it is needed, so changes are read from the first one wanted rather than from
+	// the start. offsets holds where every change starts, in the order they were appended, and changeLogSize the size of
+	// the log.
+	changesLoaded bool
+	offsets       []changeOffset
+	changeLogSize int64
+	// The indexes are built from the stored configs the first time they are needed and kept up to date on every store
+	index *index.Properties
+	text  *index.Text
+	refs  *index.References
 }
 
 // NewRepository returns a new Repository storage object
@@ -20,43 +62,64 @@ func NewRepository(path string) *Repository {
 
 // StoreGroup stores a config in the local storage. Will not overwrite existing Group object.
 func (r *Repository) StoreGroup(g adding.Group) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.createGroup(g)
+}
+
+// createGroup stores a new group. Has to be called while holding the lock.
+func (r *Repository) createGroup(g adding.Group) error {
 	fullPath := r.path + "/" + g.ID + ".json"
 	if _, err := os.Stat(fullPath); err == nil {
 		return adding.ErrGroupConflict
 	}
 
-	return r.storeGroup(g)
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	grp := Group{
+		ID:         g.ID,
+		Revision:   rev,
+		Configs:    g.Configs,
+		Schema:     g.Schema,
+		Recipients: g.Recipients,
+		Defaults:   newDefaults(g.Defaults),
+	}
+
+	if err := r.storeGroup(grp); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: g.ID, ID: g.ID})
 }
 
 // This store function will overwrite the group object
-func (r *Repository) storeGroup(g adding.Group) error {
+func (r *Repository) storeGroup(grp Group) error {
 	basePath := r.path + "/"
-	fullPath := basePath + g.ID + ".json"
+	fullPath := basePath + grp.ID + ".json"
 
 	err := os.MkdirAll(basePath, os.ModePerm)
 	if err != nil {
 		return err
 	}
 
-	file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-
-	grp := Group{
-		ID:      g.ID,
-		Configs: g.Configs,
-	}
-
-	return storeJSON(file, grp)
+	return storeFile(fullPath, grp)
 }
 
 // RetrieveGroup retrieves a group from the local storage specified by id
 func (r *Repository) RetrieveGroup(id string) (*listing.Group, error) {
+	if !isFileName(id) {
+		return nil, listing.ErrGroupNotFound
+	}
+
 	file, err := os.OpenFile(r.path+"/"+id+".json", os.O_RDONLY, 0644)
 	if err != nil {
 		return nil, listing.ErrGroupNotFound
 	}
+	defer file.Close()
 
 	var grp Group
 	if err := retrieveJSON(file, &grp); err != nil {
@@ -64,19 +127,302 @@ func (r *Repository) RetrieveGroup(id string) (*listing.Group, error) {
 	}
 
 	return &listing.Group{
-		ID:      grp.ID,
-		Configs: grp.Configs,
+		ID:         grp.ID,
+		Revision:   grp.Revision,
+		Configs:    grp.Configs,
+		Schema:     grp.Schema,
+		Recipients: grp.Recipients,
+		Approvers:  grp.Approvers,
+		Defaults:   listingDefaults(grp.Defaults),
 	}, nil
 
 }
 
+// ListGroups retrieves all groups with an id starting with prefix from the local storage. The groups are not ordered.
+func (r *Repository) ListGroups(prefix string) ([]listing.Group, error) {
+	grps := []listing.Group{}
+
+	files, err := ioutil.ReadDir(r.path)
+	if os.IsNotExist(err) {
+		return grps, nil
+	}
+	if err != nil {
+		return grps, err
+	}
+
+	for _, f := range files {
+		id := strings.TrimSuffix(f.Name(), ".json")
+		if f.IsDir() || id == f.Name() || !strings.HasPrefix(id, prefix) {
+			continue
+		}
+
+		grp, err := r.RetrieveGroup(id)
+		if err != nil {
+			return grps, err
+		}
+		grps = append(grps, *grp)
+	}
+
+	return grps, nil
+}
+
+// StoreSchema replaces the schema of a group in the local storage
+func (r *Repository) StoreSchema(groupID string, s json.RawMessage) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   rev,
+		Configs:    grp.Configs,
+		Schema:     s,
+		Recipients: grp.Recipients,
+		Approvers:  grp.Approvers,
+		Defaults:   storedDefaults(grp.Defaults),
+	}
+
+	if err := r.storeGroup(storeGrp); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: groupID, ID: groupID})
+}
+
+// RetrieveSchema retrieves the schema of a group from the local storage. Returns nil if the group has no schema.
+func (r *Repository) RetrieveSchema(groupID string) (json.RawMessage, error) {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return grp.Schema, nil
+}
+
+// StoreRecipients replaces the recipients of a group in the local storage
+func (r *Repository) StoreRecipients(groupID string, recipients []string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   rev,
+		Configs:    grp.Configs,
+		Schema:     grp.Schema,
+		Defaults:   storedDefaults(grp.Defaults),
+		Recipients: recipients,
+		Approvers:  grp.Approvers,
+	}
+
+	if err := r.storeGroup(storeGrp); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: groupID, ID: groupID})
+}
+
+// RetrieveRecipients retrieves the recipients of a group from the local storage. Returns nil if the group has none.
+func (r *Repository) RetrieveRecipients(groupID string) ([]string, error) {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return grp.Recipients, nil
+}
+
+// StoreApprovers replaces the approvers of a group in the local storage
+func (r *Repository) StoreApprovers(groupID string, approvers []string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   rev,
+		Configs:    grp.Configs,
+		Schema:     grp.Schema,
+		Defaults:   storedDefaults(grp.Defaults),
+		Recipients: grp.Recipients,
+		Approvers:  approvers,
+	}
+
+	if err := r.storeGroup(storeGrp); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: groupID, ID: groupID})
+}
+
+// RetrieveApprovers retrieves the approvers of a group from the local storage. Returns nil if the group has none.
+func (r *Repository) RetrieveApprovers(groupID string) ([]string, error) {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return grp.Approvers, nil
+}
+
+// StoreDefaults replaces the defaults of a group in the local storage
+func (r *Repository) StoreDefaults(groupID string, d *adding.Defaults) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.storeDefaults(groupID, d)
+}
+
+// UpdateDefaults replaces the defaults of a group in the local storage with the ones returned by update, which is given the
+// current defaults or nil if the group has none
+func (r *Repository) UpdateDefaults(groupID string, update func(d *adding.Defaults) (*adding.Defaults, error)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	d, err := r.RetrieveDefaults(groupID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := update(d)
+	if err != nil {
+		return err
+	}
+
+	return r.storeDefaults(groupID, updated)
+}
+
+// storeDefaults replaces the defaults of a group. Has to be called while holding the lock.
+func (r *Repository) storeDefaults(groupID string, d *adding.Defaults) error {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   rev,
+		Configs:    grp.Configs,
+		Schema:     grp.Schema,
+		Recipients: grp.Recipients,
+		Approvers:  grp.Approvers,
+		Defaults:   newDefaults(d),
+	}
+
+	if err := r.storeGroup(storeGrp); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: groupID, ID: groupID})
+}
+
+// RetrieveDefaults retrieves the defaults of a group from the local storage. Returns nil if the group has no defaults.
+func (r *Repository) RetrieveDefaults(groupID string) (*adding.Defaults, error) {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil || grp.Defaults == nil {
+		return nil, err
+	}
+
+	return &adding.Defaults{Properties: grp.Defaults.Properties, Merge: grp.Defaults.Merge}, nil
+}
+
+// RetrieveAncestor retrieves a config from the local storage as it is inherited from by other configs
+func (r *Repository) RetrieveAncestor(groupID string, id string) (*adding.Config, error) {
+	c, err := r.RetrieveConfig(groupID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adding.Config{
+		ID:         c.ID,
+		Group:      c.Group,
+		Parent:     c.Parent,
+		Properties: c.Properties,
+	}, nil
+}
+
 // StoreConfig stores a config in the local storage
 func (r *Repository) StoreConfig(c adding.Config) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.storeConfig(c)
+}
+
+// UpdateConfig replaces an existing config with the one returned by update while holding the lock
+func (r *Repository) UpdateConfig(groupID string, id string, update func(c adding.Config, revision int64) (adding.Config, error)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	c, err := r.RetrieveConfig(groupID, id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := update(adding.Config{
+		ID:           c.ID,
+		Name:         c.Name,
+		LastModified: c.LastModified,
+		Version:      c.Version,
+		Group:        c.Group,
+		Parent:       c.Parent,
+		Properties:   c.Properties,
+	}, c.Revision)
+	if err != nil {
+		return err
+	}
+
+	return r.storeConfig(updated)
+}
+
+// storeConfig stores a config. Has to be called while holding the lock.
+func (r *Repository) storeConfig(c adding.Config) error {
 	grp, err := r.RetrieveGroup(c.Group)
 	if err != nil {
 		return err
 	}
 
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	if err := r.backfillHistory(c.Group, c.ID); err != nil {
+		return err
+	}
+
 	basePath := r.path + "/" + c.Group + "/"
 
 	err = os.MkdirAll(basePath, os.ModePerm)
@@ -84,15 +430,27 @@ func (r *Repository) StoreConfig(c adding.Config) error {
 		return err
 	}
 
-	file, err := os.OpenFile(basePath+c.ID+".json", os.O_CREATE|os.O_WRONLY, 0644)
+	conf := Config{
+		ID:           c.ID,
+		Name:         c.Name,
+		LastModified: c.LastModified,
+		Version:      c.Version,
+		Revision:     rev,
+		Group:        c.Group,
+		Parent:       c.Parent,
+		Properties:   c.Properties,
+	}
+
+	// The config replaces the stored one only once the group lists it, so a failed group write leaves the config as it was
+	tmp, err := storeTemp(basePath+c.ID+".json", conf)
 	if err != nil {
 		return err
 	}
 
-	// TODO: There is a chance that the config will get created and storing the new group with config added will fail. Fix fix.
 	// TODO: Sort array?
 	if len(grp.Configs) == 0 {
 		grp.Configs = append(grp.Configs, c.ID)
+		grp.Revision = rev
 	} else {
 		for i := 0; i <= len(grp.Configs); i++ {
 			if grp.Configs[i] == c.ID {
@@ -101,31 +459,43 @@ func (r *Repository) StoreConfig(c adding.Config) error {
 
 			if i >= len(grp.Configs)-1 {
 				grp.Configs = append(grp.Configs, c.ID)
+				grp.Revision = rev
 				break
 			}
 		}
 	}
 
-	addGrp := adding.Group{
-		ID:      grp.ID,
-		Configs: grp.Configs,
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   grp.Revision,
+		Configs:    grp.Configs,
+		Schema:     grp.Schema,
+		Recipients: grp.Recipients,
+		Approvers:  grp.Approvers,
+		Defaults:   storedDefaults(grp.Defaults),
 	}
 
-	if err := r.storeGroup(addGrp); err != nil {
+	if err := r.storeGroup(storeGrp); err != nil {
 		log.Println("Failed persisting Group when new config was added. Config not added.")
+		os.Remove(tmp)
 		return err
 	}
 
-	conf := Config{
-		ID:           c.ID,
-		Name:         c.Name,
-		LastModified: c.LastModified,
-		Version:      c.Version,
-		Group:        c.Group,
-		Properties:   c.Properties,
+	if err := os.Rename(tmp, basePath+c.ID+".json"); err != nil {
+		return err
+	}
+
+	if err := r.appendHistory(conf); err != nil {
+		return err
+	}
+
+	if r.index != nil {
+		r.index.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Properties)
+		r.text.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Name, c.Properties)
+		r.refs.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Properties)
 	}
 
-	return storeJSON(file, conf)
+	return r.appendChange(Change{Revision: rev, Resource: listing.ConfigResource, Group: c.Group, ID: c.ID})
 }
 
 // RetrieveConfig retrieves a config from the local storage spesified by groupID and id of the config
@@ -134,10 +504,15 @@ func (r *Repository) RetrieveConfig(groupID string, id string) (*listing.Config,
 		return &listing.Config{}, err
 	}
 
+	if !isFileName(id) {
+		return nil, listing.ErrConfigNotFound
+	}
+
 	file, err := os.OpenFile(r.path+"/"+groupID+"/"+id+".json", os.O_RDONLY, 0644)
 	if err != nil {
 		return nil, listing.ErrConfigNotFound
 	}
+	defer file.Close()
 
 	var c Config
 	err = retrieveJSON(file, &c)
@@ -147,11 +522,1051 @@ func (r *Repository) RetrieveConfig(groupID string, id string) (*listing.Config,
 		Name:         c.Name,
 		LastModified: c.LastModified,
 		Version:      c.Version,
+		Revision:     c.Revision,
 		Group:        c.Group,
+		Parent:       c.Parent,
 		Properties:   c.Properties,
 	}, err
 }
 
+// RetrieveConfigRevision retrieves a config from the local storage as it was stored at a revision
+func (r *Repository) RetrieveConfigRevision(groupID string, id string, revision int64) (*listing.Config, error) {
+	c, err := r.retrieveHistory(groupID, id, func(c Config) bool { return c.Revision == revision })
+	if err == nil && c == nil {
+		return nil, diffing.ErrRevisionNotFound
+	}
+
+	return c, err
+}
+
+// RetrieveConfigVersion retrieves a config from the local storage as it was last stored with a version
+func (r *Repository) RetrieveConfigVersion(groupID string, id string, version int) (*listing.Config, error) {
+	c, err := r.retrieveHistory(groupID, id, func(c Config) bool { return c.Version == version })
+	if err == nil && c == nil {
+		return nil, diffing.ErrVersionNotFound
+	}
+
+	return c, err
+}
+
+// retrieveHistory retrieves the last version in the history of a config that matches, or nil if none do. Configs stored
+// before histories were kept have none until they are written again, so their current version is read as their history.
+func (r *Repository) retrieveHistory(groupID string, id string, match func(c Config) bool) (*listing.Config, error) {
+	if !isFileName(groupID) || !isFileName(id) {
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(r.historyPath(groupID, id), os.O_RDONLY, 0644)
+	if os.IsNotExist(err) {
+		return r.retrieveCurrentHistory(groupID, id, match)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var found *Config
+	// Configs are at most 1 MiB, so a line can be longer than the default buffer
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 2*1024*1024)
+	for scanner.Scan() {
+		var c Config
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, err
+		}
+		if match(c) {
+			found = &c
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if found == nil {
+		return nil, nil
+	}
+
+	return &listing.Config{
+		ID:           found.ID,
+		Name:         found.Name,
+		LastModified: found.LastModified,
+		Version:      found.Version,
+		Revision:     found.Revision,
+		Group:        found.Group,
+		Parent:       found.Parent,
+		Properties:   found.Properties,
+	}, nil
+}
+
+// retrieveCurrentHistory retrieves the current version of a config without history if it matches, or nil
+func (r *Repository) retrieveCurrentHistory(groupID string, id string, match func(c Config) bool) (*listing.Config, error) {
+	c, err := r.RetrieveConfig(groupID, id)
+	if err == listing.ErrGroupNotFound || err == listing.ErrConfigNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !match(Config{Version: c.Version, Revision: c.Revision}) {
+		return nil, nil
+	}
+
+	return c, nil
+}
+
+// backfillHistory starts the history of a config stored before histories were kept with its current version, so the
+// version is kept when the config is written again or deleted. Has to be called while holding the lock.
+func (r *Repository) backfillHistory(groupID string, id string) error {
+	if _, err := os.Stat(r.historyPath(groupID, id)); !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path+"/"+groupID+"/"+id+".json", os.O_RDONLY, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var c Config
+	if err := retrieveJSON(file, &c); err != nil {
+		return err
+	}
+
+	return r.appendHistory(c)
+}
+
+// appendHistory appends a version of a config to its history. Has to be called while holding the lock.
+func (r *Repository) appendHistory(c Config) error {
+	if err := os.MkdirAll(filepath.Dir(r.historyPath(c.Group, c.ID)), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.historyPath(c.Group, c.ID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return storeJSON(file, c)
+}
+
+// historyPath returns the path of the file holding the history of a config
+func (r *Repository) historyPath(groupID string, id string) string {
+	return r.path + "/" + historyDir + "/" + groupID + "/" + id + ".log"
+}
+
+// DeleteGroup deletes a group from the local storage. Only groups without configs can be deleted.
+func (r *Repository) DeleteGroup(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	grp, err := r.RetrieveGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if len(grp.Configs) > 0 {
+		return deleting.ErrGroupNotEmpty
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(r.path + "/" + id + ".json"); err != nil {
+		return err
+	}
+
+	// The directory of the group is left behind when its last config is deleted
+	if err := os.RemoveAll(r.path + "/" + id); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.GroupResource, Group: id, ID: id, Deleted: true})
+}
+
+// DeleteConfig deletes a config from the local storage and removes it from its group. Configs other configs inherit from
+// cannot be deleted.
+func (r *Repository) DeleteConfig(groupID string, id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.deleteConfig(groupID, id)
+}
+
+// deleteConfig deletes a config and its overlays. Has to be called while holding the lock.
+func (r *Repository) deleteConfig(groupID string, id string) error {
+	grp, err := r.RetrieveGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.RetrieveConfig(groupID, id); err != nil {
+		return err
+	}
+
+	for _, other := range grp.Configs {
+		c, err := r.RetrieveConfig(groupID, other)
+		if err != nil {
+			return err
+		}
+		if c.Parent == id {
+			return deleting.ErrConfigHasChildren
+		}
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	if err := r.backfillHistory(groupID, id); err != nil {
+		return err
+	}
+
+	storeGrp := Group{
+		ID:         grp.ID,
+		Revision:   rev,
+		Configs:    without(grp.Configs, id),
+		Schema:     grp.Schema,
+		Recipients: grp.Recipients,
+		Approvers:  grp.Approvers,
+		Defaults:   storedDefaults(grp.Defaults),
+	}
+
+	if err := r.storeGroup(storeGrp); err != nil {
+		return err
+	}
+
+	if err := os.Remove(r.path + "/" + groupID + "/" + id + ".json"); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(r.overlayPath(groupID, id)); err != nil {
+		return err
+	}
+
+	if r.index != nil {
+		r.index.Remove(listing.ConfigRef{Group: groupID, ID: id})
+		r.text.Remove(listing.ConfigRef{Group: groupID, ID: id})
+		r.refs.Remove(listing.ConfigRef{Group: groupID, ID: id})
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.ConfigResource, Group: groupID, ID: id, Deleted: true})
+}
+
+// StoreOverlay stores the overlay of a config in an environment in the local storage, replacing any existing overlay. Overlays
+// are stored in a directory named after the config.
+func (r *Repository) StoreOverlay(o adding.Overlay) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, err := r.RetrieveConfig(o.Group, o.Config); err != nil {
+		return err
+	}
+
+	return r.storeOverlay(o)
+}
+
+// UpdateOverlay replaces an existing overlay in the local storage with the one returned by update, which is given the current
+// overlay
+func (r *Repository) UpdateOverlay(groupID string, id string, env string, update func(o adding.Overlay) (adding.Overlay, error)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	o, err := r.RetrieveOverlay(groupID, id, env)
+	if err != nil {
+		return err
+	}
+
+	var promotedFrom *adding.Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &adding.Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	updated, err := update(adding.Overlay{
+		Group:        o.Group,
+		Config:       o.Config,
+		Environment:  o.Environment,
+		Version:      o.Version,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.storeOverlay(updated)
+}
+
+// storeOverlay stores an overlay of an existing config. Has to be called while holding the lock.
+func (r *Repository) storeOverlay(o adding.Overlay) error {
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	basePath := r.overlayPath(o.Group, o.Config)
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(basePath+o.Environment+".json", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var promotedFrom *Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	ov := Overlay{
+		Group:        o.Group,
+		Config:       o.Config,
+		Environment:  o.Environment,
+		Version:      o.Version,
+		Revision:     rev,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	}
+
+	if err := storeJSON(file, ov); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.OverlayResource, Group: o.Group, ID: o.Config, Environment: o.Environment})
+}
+
+// RetrieveOverlay retrieves the overlay of a config in an environment from the local storage
+func (r *Repository) RetrieveOverlay(groupID string, id string, env string) (*listing.Overlay, error) {
+	if _, err := r.RetrieveConfig(groupID, id); err != nil {
+		return &listing.Overlay{}, err
+	}
+
+	if !isFileName(env) {
+		return &listing.Overlay{}, listing.ErrOverlayNotFound
+	}
+
+	file, err := os.OpenFile(r.overlayPath(groupID, id)+env+".json", os.O_RDONLY, 0644)
+	if err != nil {
+		return &listing.Overlay{}, listing.ErrOverlayNotFound
+	}
+	defer file.Close()
+
+	var o Overlay
+	if err := retrieveJSON(file, &o); err != nil {
+		return &listing.Overlay{}, err
+	}
+
+	var promotedFrom *listing.Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &listing.Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	return &listing.Overlay{
+		Group:        o.Group,
+		Config:       o.Config,
+		Environment:  o.Environment,
+		Version:      o.Version,
+		Revision:     o.Revision,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	}, nil
+}
+
+// ListOverlays retrieves all overlays of a config from the local storage. The overlays are not ordered.
+func (r *Repository) ListOverlays(groupID string, id string) ([]listing.Overlay, error) {
+	if _, err := r.RetrieveConfig(groupID, id); err != nil {
+		return nil, err
+	}
+
+	overlays := []listing.Overlay{}
+	files, err := ioutil.ReadDir(r.overlayPath(groupID, id))
+	if os.IsNotExist(err) {
+		return overlays, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		env := strings.TrimSuffix(f.Name(), ".json")
+		if f.IsDir() || env == f.Name() {
+			continue
+		}
+
+		o, err := r.RetrieveOverlay(groupID, id, env)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, *o)
+	}
+
+	return overlays, nil
+}
+
+// RetrieveOverlayForPromotion retrieves an overlay and its revision from the local storage to be promoted
+func (r *Repository) RetrieveOverlayForPromotion(groupID string, id string, env string) (*adding.Overlay, int64, error) {
+	o, err := r.RetrieveOverlay(groupID, id, env)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &adding.Overlay{
+		Group:       o.Group,
+		Config:      o.Config,
+		Environment: o.Environment,
+		Version:     o.Version,
+		Properties:  o.Properties,
+	}, o.Revision, nil
+}
+
+// DeleteOverlay deletes the overlay of a config in an environment from the local storage
+func (r *Repository) DeleteOverlay(groupID string, id string, env string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, err := r.RetrieveOverlay(groupID, id, env); err != nil {
+		return err
+	}
+
+	rev, err := r.nextRevision()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(r.overlayPath(groupID, id) + env + ".json"); err != nil {
+		return err
+	}
+
+	return r.appendChange(Change{Revision: rev, Resource: listing.OverlayResource, Group: groupID, ID: id, Environment: env, Deleted: true})
+}
+
+// overlayPath returns the path of the directory holding the overlays of a config
+func (r *Repository) overlayPath(groupID string, id string) string {
+	return r.path + "/" + groupID + "/" + id + "/"
+}
+
+// SearchConfigs finds all configs satisfying every condition using the property index
+func (r *Repository) SearchConfigs(conditions []listing.Condition) ([]listing.ConfigRef, error) {
+	idx, _, _, err := r.loadIndexes()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Search(conditions), nil
+}
+
+// SearchText finds all configs containing every word in text using the full-text index
+func (r *Repository) SearchText(text string) ([]listing.TextMatch, error) {
+	_, idx, _, err := r.loadIndexes()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Search(text), nil
+}
+
+// RetrieveDependents retrieves the configs referencing a config using the reference index
+func (r *Repository) RetrieveDependents(ref listing.ConfigRef) ([]listing.ConfigRef, error) {
+	_, _, idx, err := r.loadIndexes()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Dependents(ref), nil
+}
+
+// loadIndexes builds the indexes by reading every stored config the first time it is called, and returns them. The indexes
+// returned are read under the lock, as restoring a failed batch drops them.
+func (r *Repository) loadIndexes() (*index.Properties, *index.Text, *index.References, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.index != nil {
+		return r.index, r.text, r.refs, nil
+	}
+
+	idx := index.NewProperties()
+	text := index.NewText()
+	refs := index.NewReferences()
+	grps, err := r.ListGroups("")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, grp := range grps {
+		for _, id := range grp.Configs {
+			conf, err := r.RetrieveConfig(grp.ID, id)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			idx.Put(listing.ConfigRef{Group: conf.Group, ID: conf.ID}, conf.Properties)
+			text.Put(listing.ConfigRef{Group: conf.Group, ID: conf.ID}, conf.Name, conf.Properties)
+			refs.Put(listing.ConfigRef{Group: conf.Group, ID: conf.ID}, conf.Properties)
+		}
+	}
+
+	r.index = idx
+	r.text = text
+	r.refs = refs
+	return idx, text, refs, nil
+}
+
+// RetrieveChanges retrieves all changes made after the since revision from the change log, ordered by revision
+func (r *Repository) RetrieveChanges(since int64) (*listing.Changes, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.loadChanges(); err != nil {
+		return nil, err
+	}
+
+	changes := &listing.Changes{Revision: r.revision, Changes: []listing.Change{}}
+	i := sort.Search(len(r.offsets), func(i int) bool { return r.offsets[i].revision > since })
+	if i == len(r.offsets) {
+		return changes, nil
+	}
+
+	err := r.readChanges(r.offsets[i].offset, func(c Change, _ int) {
+		changes.Changes = append(changes.Changes, listing.Change{
+			Revision:    c.Revision,
+			Resource:    c.Resource,
+			Group:       c.Group,
+			ID:          c.ID,
+			Environment: c.Environment,
+			Deleted:     c.Deleted,
+		})
+	})
+
+	return changes, err
+}
+
+// StoreChangeRequest stores a new change request in the local storage and returns the id assigned to it, which is one more
+// than the highest id stored
+func (r *Repository) StoreChangeRequest(cr reviewing.ChangeRequest) (int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	crs, err := r.ListChangeRequests("")
+	if err != nil {
+		return 0, err
+	}
+
+	cr.ID = 1
+	if len(crs) > 0 {
+		cr.ID = crs[len(crs)-1].ID + 1
+	}
+
+	return cr.ID, r.storeChangeRequest(newChangeRequest(cr))
+}
+
+// storeChangeRequest stores a change request, overwriting it if it exists
+func (r *Repository) storeChangeRequest(cr ChangeRequest) error {
+	basePath := r.path + "/" + changeRequestsDir + "/"
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(basePath+strconv.FormatInt(cr.ID, 10)+".json", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return storeJSON(file, cr)
+}
+
+// RetrieveChangeRequest retrieves a change request from the local storage
+func (r *Repository) RetrieveChangeRequest(id int64) (*reviewing.ChangeRequest, error) {
+	file, err := os.OpenFile(r.path+"/"+changeRequestsDir+"/"+strconv.FormatInt(id, 10)+".json", os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, reviewing.ErrChangeRequestNotFound
+	}
+	defer file.Close()
+
+	var cr ChangeRequest
+	if err := retrieveJSON(file, &cr); err != nil {
+		return nil, err
+	}
+
+	res := reviewingChangeRequest(cr)
+	return &res, nil
+}
+
+// ListChangeRequests retrieves the change requests of a group, or of every group if groupID is empty, from the local storage
+// ordered by id
+func (r *Repository) ListChangeRequests(groupID string) ([]reviewing.ChangeRequest, error) {
+	crs := []reviewing.ChangeRequest{}
+
+	files, err := ioutil.ReadDir(r.path + "/" + changeRequestsDir)
+	if os.IsNotExist(err) {
+		return crs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		id, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), ".json"), 10, 64)
+		if f.IsDir() || err != nil {
+			continue
+		}
+
+		cr, err := r.RetrieveChangeRequest(id)
+		if err != nil {
+			return nil, err
+		}
+		if groupID == "" || cr.Config.Group == groupID {
+			crs = append(crs, *cr)
+		}
+	}
+
+	sort.Slice(crs, func(i, j int) bool { return crs[i].ID < crs[j].ID })
+	return crs, nil
+}
+
+// UpdateChangeRequest replaces an existing change request in the local storage with the one returned by update, which is
+// given the current change request
+func (r *Repository) UpdateChangeRequest(id int64, update func(cr reviewing.ChangeRequest) (reviewing.ChangeRequest, error)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cr, err := r.RetrieveChangeRequest(id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := update(*cr)
+	if err != nil {
+		return err
+	}
+
+	updated.ID = id
+	return r.storeChangeRequest(newChangeRequest(updated))
+}
+
+// StoreActivation stores a new activation in the local storage and returns the id assigned to it, which is one more than the
+// highest id stored
+func (r *Repository) StoreActivation(a scheduling.Activation) (int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	as, err := r.ListActivations("", "")
+	if err != nil {
+		return 0, err
+	}
+
+	a.ID = 1
+	if len(as) > 0 {
+		a.ID = as[len(as)-1].ID + 1
+	}
+
+	return a.ID, r.storeActivation(newActivation(a))
+}
+
+// storeActivation stores an activation, overwriting it if it exists
+func (r *Repository) storeActivation(a Activation) error {
+	basePath := r.path + "/" + activationsDir + "/"
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(basePath+strconv.FormatInt(a.ID, 10)+".json", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return storeJSON(file, a)
+}
+
+// RetrieveActivation retrieves an activation from the local storage
+func (r *Repository) RetrieveActivation(id int64) (*scheduling.Activation, error) {
+	file, err := os.OpenFile(r.path+"/"+activationsDir+"/"+strconv.FormatInt(id, 10)+".json", os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, scheduling.ErrActivationNotFound
+	}
+	defer file.Close()
+
+	var a Activation
+	if err := retrieveJSON(file, &a); err != nil {
+		return nil, err
+	}
+
+	res := schedulingActivation(a)
+	return &res, nil
+}
+
+// ListActivations retrieves the activations of a config, or of every config if groupID and id are empty, from the local
+// storage ordered by id
+func (r *Repository) ListActivations(groupID string, id string) ([]scheduling.Activation, error) {
+	as := []scheduling.Activation{}
+
+	files, err := ioutil.ReadDir(r.path + "/" + activationsDir)
+	if os.IsNotExist(err) {
+		return as, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		activationID, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), ".json"), 10, 64)
+		if f.IsDir() || err != nil {
+			continue
+		}
+
+		a, err := r.RetrieveActivation(activationID)
+		if err != nil {
+			return nil, err
+		}
+		if groupID == "" && id == "" || a.Config.Group == groupID && a.Config.ID == id {
+			as = append(as, *a)
+		}
+	}
+
+	sort.Slice(as, func(i, j int) bool { return as[i].ID < as[j].ID })
+	return as, nil
+}
+
+// UpdateActivation replaces an existing activation in the local storage with the one returned by update, which is given the
+// current activation
+func (r *Repository) UpdateActivation(id int64, update func(a scheduling.Activation) (scheduling.Activation, error)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	a, err := r.RetrieveActivation(id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := update(*a)
+	if err != nil {
+		return err
+	}
+
+	updated.ID = id
+	return r.storeActivation(newActivation(updated))
+}
+
+// Commit applies the operations of a batch in order while holding the lock. If an operation cannot be applied, the files
+// touched by the batch are restored and the change log is truncated to where it was, so none of the changes are kept.
+func (r *Repository) Commit(ops []adding.Operation) ([]int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	before, err := r.snapshot(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]int64, len(ops))
+	for i, op := range ops {
+		if err := r.apply(op); err != nil {
+			if restoreErr := r.restore(before); restoreErr != nil {
+				log.Printf("Failed restoring the repository after a failed batch: %v", restoreErr)
+			}
+			return nil, &adding.OperationError{Index: i, Err: err}
+		}
+		// Every operation stores one change
+		revisions[i] = r.revision
+	}
+
+	return revisions, nil
+}
+
+// apply applies an operation of a batch. Has to be called while holding the lock.
+func (r *Repository) apply(op adding.Operation) error {
+	switch op.Type {
+	case adding.CreateGroup:
+		return r.createGroup(op.Group)
+	case adding.PutConfig:
+		if err := r.checkRevision(op.Config.Group, op.Config.ID, op.Revision); err != nil {
+			return err
+		}
+		return r.storeConfig(op.Config)
+	case adding.DeleteConfig:
+		if err := r.checkRevision(op.Config.Group, op.Config.ID, op.Revision); err != nil {
+			return err
+		}
+		return r.deleteConfig(op.Config.Group, op.Config.ID)
+	}
+
+	return fmt.Errorf("unknown operation type %d", op.Type)
+}
+
+// checkRevision checks that a config is at a revision, or does not exist if the revision is zero. Has to be called while
+// holding the lock.
+func (r *Repository) checkRevision(groupID string, id string, revision *int64) error {
+	if revision == nil {
+		return nil
+	}
+
+	var current int64
+	c, err := r.RetrieveConfig(groupID, id)
+	switch err {
+	case nil:
+		current = c.Revision
+	case listing.ErrGroupNotFound, listing.ErrConfigNotFound:
+	default:
+		return err
+	}
+
+	if current != *revision {
+		return adding.ErrRevisionMismatch
+	}
+
+	return nil
+}
+
+// snapshot is the state of the files touched by a batch before the batch is applied
+type snapshot struct {
+	revision int64
+	// changeLog is the size of the change log, and changes the number of changes in it
+	changeLog int64
+	changes   int
+	// files holds the content of every file touched by the batch, or nil for files that did not exist
+	files map[string][]byte
+	// groups are the groups created by the batch, which have their directories removed on restore
+	groups []string
+}
+
+// snapshot reads the files touched by a batch. Has to be called while holding the lock.
+func (r *Repository) snapshot(ops []adding.Operation) (snapshot, error) {
+	// The change log is loaded, so restoring it does not leave it unloaded
+	if err := r.loadChanges(); err != nil {
+		return snapshot{}, err
+	}
+
+	s := snapshot{revision: r.revision, changeLog: r.changeLogSize, changes: len(r.offsets), files: make(map[string][]byte)}
+
+	for _, op := range ops {
+		paths := []string{r.path + "/" + op.Group.ID + ".json"}
+		if op.Type == adding.CreateGroup {
+			if isFileName(op.Group.ID) {
+				s.groups = append(s.groups, op.Group.ID)
+			}
+		} else {
+			paths = []string{r.path + "/" + op.Config.Group + ".json", r.path + "/" + op.Config.Group + "/" + op.Config.ID + ".json", r.historyPath(op.Config.Group, op.Config.ID)}
+
+			files, err := ioutil.ReadDir(r.overlayPath(op.Config.Group, op.Config.ID))
+			if err != nil && !os.IsNotExist(err) {
+				return s, err
+			}
+			for _, f := range files {
+				paths = append(paths, r.overlayPath(op.Config.Group, op.Config.ID)+f.Name())
+			}
+		}
+
+		for _, path := range paths {
+			if _, read := s.files[path]; read {
+				continue
+			}
+
+			b, err := ioutil.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return s, err
+			}
+			s.files[path] = b
+		}
+	}
+
+	return s, nil
+}
+
+// restore restores the files of a snapshot and truncates the change log to its size. The indexes are built again the next
+// time they are needed. Has to be called while holding the lock.
+func (r *Repository) restore(s snapshot) error {
+	for path, b := range s.files {
+		if b == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range s.groups {
+		if s.files[r.path+"/"+id+".json"] == nil {
+			if err := os.RemoveAll(r.path + "/" + id); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.index, r.text, r.refs = nil, nil, nil
+	r.revision = s.revision
+	r.offsets = r.offsets[:s.changes]
+	r.changeLogSize = s.changeLog
+
+	if err := os.Truncate(r.path+"/"+changeLogFile, s.changeLog); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// nextRevision returns the revision to be assigned to the next change. Has to be called while holding the lock.
+func (r *Repository) nextRevision() (int64, error) {
+	if err := r.loadChanges(); err != nil {
+		return 0, err
+	}
+
+	return r.revision + 1, nil
+}
+
+// loadChanges reads the change log the first time it is needed to find the current revision and where every change starts.
+// Has to be called while holding the lock.
+func (r *Repository) loadChanges() error {
+	if r.changesLoaded {
+		return nil
+	}
+
+	var offsets []changeOffset
+	var revision, offset int64
+	err := r.readChanges(0, func(c Change, n int) {
+		offsets = append(offsets, changeOffset{revision: c.Revision, offset: offset})
+		revision = c.Revision
+		offset += int64(n)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.revision, r.offsets, r.changeLogSize = revision, offsets, offset
+	r.changesLoaded = true
+	return nil
+}
+
+// appendChange appends a change to the change log and makes its revision the current one. Has to be called while holding
+// the lock.
+func (r *Repository) appendChange(c Change) error {
+	if err := r.loadChanges(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path+"/"+changeLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	r.offsets = append(r.offsets, changeOffset{revision: c.Revision, offset: r.changeLogSize})
+	r.changeLogSize += int64(len(b) + 1)
+	r.revision = c.Revision
+	return nil
+}
+
+// readChanges calls fn for every change in the change log from offset in the order they were appended, with the length of
+// the line it was read from.
+func (r *Repository) readChanges(offset int64, fn func(c Change, n int)) error {
+	file, err := os.OpenFile(r.path+"/"+changeLogFile, os.O_RDONLY, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		var c Change
+		if err := json.Unmarshal(line, &c); err != nil {
+			return err
+		}
+		fn(c, len(line))
+	}
+}
+
+// isFileName checks that an id can be used as a file name without referring to a file outside the repository path. Ids are
+// validated by the adding service, but ids given to the retrieve functions are not.
+func isFileName(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+// without returns a copy of ids without id
+func without(ids []string, id string) []string {
+	res := make([]string, 0, len(ids))
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
+
+// storeFile stores v as JSON at path. The file is replaced at once, so it is never left partly written.
+func storeFile(path string, v interface{}) error {
+	tmp, err := storeTemp(path, v)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// storeTemp stores v as JSON in a temporary file beside path, and returns the temporary file to be renamed to path. Has to be
+// called while holding the lock, as the temporary file of a path is always the same.
+func storeTemp(path string, v interface{}) (string, error) {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	err = storeJSON(file, v)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	return tmp, nil
+}
+
 func storeJSON(file *os.File, v interface{}) error {
 	return json.NewEncoder(file).Encode(v)
 }
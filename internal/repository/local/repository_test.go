@@ -1,8 +1,13 @@
 package local
 
 import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/diffing"
+	"github.com/larwef/ki/internal/listing"
 	"github.com/larwef/ki/test"
+	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
 )
 
@@ -40,6 +45,235 @@ func TestRepository_RetrieveConfig_ConfigNotExist(t *testing.T) {
 	test.RetrieveConfigWhenConfigNotExist(t, NewRepository(testDir), clean)
 }
 
+func TestRepository_StoreAndRetrieveRevision(t *testing.T) {
+	test.StoreAndRetrieveRevision(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveChanges(t *testing.T) {
+	test.StoreAndRetrieveChanges(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_ListGroups(t *testing.T) {
+	test.ListGroupsWithPrefix(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_SearchConfigs(t *testing.T) {
+	test.SearchConfigsByProperties(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_SearchText(t *testing.T) {
+	test.SearchConfigsByText(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveSchema(t *testing.T) {
+	test.StoreAndRetrieveSchema(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveRecipients(t *testing.T) {
+	test.StoreAndRetrieveRecipients(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	test.DeleteConfigAndGroup(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_SameConfigIDInDifferentGroups(t *testing.T) {
+	test.StoreConfigsWithSameIDInDifferentGroups(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_UpdateConfig(t *testing.T) {
+	test.UpdateExistingConfig(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveDefaults(t *testing.T) {
+	test.StoreAndRetrieveDefaults(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreConfigsWithParent(t *testing.T) {
+	test.StoreConfigsWithParent(t, NewRepository(testDir), clean)
+}
+
 func clean() {
 	os.RemoveAll(testDir)
 }
+
+func TestRepository_RetrieveOutsidePath(t *testing.T) {
+	defer clean()
+
+	repo := NewRepository(testDir + "/repo")
+	err := repo.StoreGroup(adding.Group{ID: "someGroup"})
+	test.AssertNotError(t, err)
+
+	err = ioutil.WriteFile(testDir+"/outside.json", []byte(`{"id": "outside"}`), 0644)
+	test.AssertNotError(t, err)
+
+	_, err = repo.RetrieveGroup("../outside")
+	test.AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	// Would otherwise read the group file as a config
+	_, err = repo.RetrieveConfig("someGroup", "../someGroup")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
+
+func TestRepository_ReopenChanges(t *testing.T) {
+	defer clean()
+
+	repo := NewRepository(testDir)
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someOtherId", Group: "someGroup"}))
+
+	// A repository opened on the same path continues the revisions, and changes are read from the first one wanted
+	repo = NewRepository(testDir)
+	changes, err := repo.RetrieveChanges(2)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, changes.Revision, int64(3))
+	test.AssertEqual(t, len(changes.Changes), 1)
+	test.AssertEqual(t, changes.Changes[0].ID, "someOtherId")
+
+	// The changes of a failed batch are forgotten with the lines they appended
+	_, err = repo.Commit([]adding.Operation{
+		{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "someGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "missingGroup"}},
+	})
+	test.AssertEqual(t, err == nil, false)
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "lastId", Group: "someGroup"}))
+
+	for _, r := range []*Repository{repo, NewRepository(testDir)} {
+		changes, err = r.RetrieveChanges(3)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, changes.Revision, int64(4))
+		test.AssertEqual(t, len(changes.Changes), 1)
+		test.AssertEqual(t, changes.Changes[0].ID, "lastId")
+
+		changes, err = r.RetrieveChanges(4)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, changes.Revision, int64(4))
+		test.AssertEqual(t, len(changes.Changes), 0)
+	}
+}
+
+func TestRepository_StoreConfig_GroupWriteFails(t *testing.T) {
+	defer clean()
+
+	repo := NewRepository(testDir)
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+
+	// The group file cannot be replaced while its temporary file is a directory
+	test.AssertNotError(t, os.Mkdir(testDir+"/someGroup.json.tmp", os.ModePerm))
+	err := repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db2"}`)})
+	test.AssertEqual(t, err == nil, false)
+
+	// The stored config is left as it was
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db1"}`)
+	_, err = os.Stat(testDir + "/someGroup/someId.json.tmp")
+	test.AssertEqual(t, os.IsNotExist(err), true)
+}
+
+func TestRepository_BackfillHistory(t *testing.T) {
+	defer clean()
+
+	repo := NewRepository(testDir)
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 1, Properties: []byte(`{"host":"db1"}`)}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someOtherId", Group: "someGroup", Version: 1, Properties: []byte(`{}`)}))
+
+	// Configs stored before histories were kept have none
+	test.AssertNotError(t, os.RemoveAll(testDir+"/"+historyDir))
+
+	// The current version is read as the history
+	conf, err := repo.RetrieveConfigVersion("someGroup", "someId", 1)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Revision, int64(2))
+	_, err = repo.RetrieveConfigRevision("someGroup", "someId", 1)
+	test.AssertEqual(t, err, diffing.ErrRevisionNotFound)
+
+	// and kept when the config is written again or deleted
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"db2"}`)}))
+	test.AssertNotError(t, repo.DeleteConfig("someGroup", "someOtherId"))
+
+	conf, err = repo.RetrieveConfigVersion("someGroup", "someId", 1)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db1"}`)
+	conf, err = repo.RetrieveConfigRevision("someGroup", "someId", 4)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Version, 2)
+	conf, err = repo.RetrieveConfigRevision("someGroup", "someOtherId", 3)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Version, 1)
+}
+
+func TestRepository_SearchWhileBatchFails(t *testing.T) {
+	defer clean()
+
+	repo := NewRepository(testDir)
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+
+	// Restoring a failed batch drops the indexes, which searches running meanwhile have to not see. Run with -race.
+	done := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				refs, err := repo.SearchConfigs([]listing.Condition{{Path: "host", Operator: listing.Exists}})
+				test.AssertNotError(t, err)
+				test.AssertEqual(t, len(refs) > 0, true)
+				_, err = repo.SearchText("db1")
+				test.AssertNotError(t, err)
+				_, err = repo.RetrieveDependents(listing.ConfigRef{Group: "someGroup", ID: "someId"})
+				test.AssertNotError(t, err)
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		_, err := repo.Commit([]adding.Operation{
+			{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "someGroup"}},
+			{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "missingGroup"}},
+		})
+		test.AssertEqual(t, err == nil, false)
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestRepository_StoreAndRetrieveOverlays(t *testing.T) {
+	test.StoreAndRetrieveOverlays(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_RetrieveDependentsOfReferencedConfig(t *testing.T) {
+	test.RetrieveDependentsOfReferencedConfig(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_UpdateDefaultsAndOverlays(t *testing.T) {
+	test.UpdateDefaultsAndOverlays(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_CommitBatch(t *testing.T) {
+	test.CommitBatch(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveChangeRequests(t *testing.T) {
+	test.StoreAndRetrieveChangeRequests(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveActivations(t *testing.T) {
+	test.StoreAndRetrieveActivations(t, NewRepository(testDir), clean)
+}
+
+func TestRepository_StoreAndRetrieveConfigRevisions(t *testing.T) {
+	test.StoreAndRetrieveConfigRevisions(t, NewRepository(testDir), clean)
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/scheduling"
+	"time"
+)
+
+// Activation represents a scheduled activation to be stored. The config it adds is stored as it will be added.
+type Activation struct {
+	ID           int64
+	State        string
+	ActivatesAt  time.Time
+	Created      time.Time
+	Activated    time.Time
+	Revision     int64
+	BaseRevision int64
+	Error        string
+	Group        string
+	Config       string
+	Name         string
+	Version      int
+	Parent       string
+	Properties   json.RawMessage
+}
+
+// newActivation returns the activation to be stored
+func newActivation(a scheduling.Activation) Activation {
+	return Activation{
+		ID:           a.ID,
+		State:        string(a.State),
+		ActivatesAt:  a.ActivatesAt,
+		Created:      a.Created,
+		Activated:    a.Activated,
+		Revision:     a.Revision,
+		BaseRevision: a.BaseRevision,
+		Error:        a.Error,
+		Group:        a.Config.Group,
+		Config:       a.Config.ID,
+		Name:         a.Config.Name,
+		Version:      a.Config.Version,
+		Parent:       a.Config.Parent,
+		Properties:   a.Config.Properties,
+	}
+}
+
+// schedulingActivation returns the stored activation to be scheduled
+func schedulingActivation(a Activation) scheduling.Activation {
+	return scheduling.Activation{
+		ID:           a.ID,
+		State:        scheduling.State(a.State),
+		ActivatesAt:  a.ActivatesAt,
+		Created:      a.Created,
+		Activated:    a.Activated,
+		Revision:     a.Revision,
+		BaseRevision: a.BaseRevision,
+		Error:        a.Error,
+		Config: adding.Config{
+			ID:         a.Config,
+			Name:       a.Name,
+			Version:    a.Version,
+			Group:      a.Group,
+			Parent:     a.Parent,
+			Properties: a.Properties,
+		},
+	}
+}
@@ -0,0 +1,11 @@
+package memory
+
+// Change represents a mutation to be stored
+type Change struct {
+	Revision    int64
+	Resource    string
+	Group       string
+	ID          string
+	Environment string
+	Deleted     bool
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/reviewing"
+	"time"
+)
+
+// ChangeRequest represents a change request to be stored. The config it changes is stored as it will be added.
+type ChangeRequest struct {
+	ID           int64
+	Author       string
+	Description  string
+	State        string
+	BaseRevision int64
+	Group        string
+	Config       string
+	Name         string
+	Version      int
+	Parent       string
+	Properties   json.RawMessage
+	Diff         []properties.Difference
+	History      []Transition
+}
+
+// Transition represents a state transition of a change request to be stored
+type Transition struct {
+	State   string
+	User    string
+	Time    time.Time
+	Comment string
+}
+
+// newChangeRequest returns the change request to be stored
+func newChangeRequest(cr reviewing.ChangeRequest) ChangeRequest {
+	history := make([]Transition, len(cr.History))
+	for i, t := range cr.History {
+		history[i] = Transition{State: string(t.State), User: t.User, Time: t.Time, Comment: t.Comment}
+	}
+
+	return ChangeRequest{
+		ID:           cr.ID,
+		Author:       cr.Author,
+		Description:  cr.Description,
+		State:        string(cr.State),
+		BaseRevision: cr.BaseRevision,
+		Group:        cr.Config.Group,
+		Config:       cr.Config.ID,
+		Name:         cr.Config.Name,
+		Version:      cr.Config.Version,
+		Parent:       cr.Config.Parent,
+		Properties:   cr.Config.Properties,
+		Diff:         cr.Diff,
+		History:      history,
+	}
+}
+
+// reviewingChangeRequest returns the stored change request to be reviewed
+func reviewingChangeRequest(cr ChangeRequest) reviewing.ChangeRequest {
+	history := make([]reviewing.Transition, len(cr.History))
+	for i, t := range cr.History {
+		history[i] = reviewing.Transition{State: reviewing.State(t.State), User: t.User, Time: t.Time, Comment: t.Comment}
+	}
+
+	return reviewing.ChangeRequest{
+		ID:           cr.ID,
+		Author:       cr.Author,
+		Description:  cr.Description,
+		State:        reviewing.State(cr.State),
+		BaseRevision: cr.BaseRevision,
+		Config: adding.Config{
+			ID:         cr.Config,
+			Name:       cr.Name,
+			Version:    cr.Version,
+			Group:      cr.Group,
+			Parent:     cr.Parent,
+			Properties: cr.Properties,
+		},
+		Diff:    cr.Diff,
+		History: history,
+	}
+}
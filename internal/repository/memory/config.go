@@ -11,6 +11,8 @@ type Config struct {
 	Name         string
 	LastModified time.Time
 	Version      int
+	Revision     int64
 	Group        string
+	Parent       string
 	Properties   json.RawMessage
 }
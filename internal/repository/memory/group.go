@@ -1,7 +1,43 @@
 package memory
 
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+)
+
 // Group represents a group object to be stored
 type Group struct {
-	ID      string
-	Configs []string
+	ID         string
+	Revision   int64
+	Configs    []string
+	Schema     json.RawMessage
+	Defaults   *Defaults
+	Recipients []string
+	Approvers  []string
+}
+
+// Defaults represents the default properties of a group to be stored
+type Defaults struct {
+	Properties json.RawMessage
+	Merge      properties.MergeRules
+}
+
+// newDefaults returns the defaults to be stored, or nil if there are none
+func newDefaults(d *adding.Defaults) *Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &Defaults{Properties: d.Properties, Merge: d.Merge}
+}
+
+// listingDefaults returns the stored defaults to be listed, or nil if there are none
+func listingDefaults(d *Defaults) *listing.Defaults {
+	if d == nil {
+		return nil
+	}
+
+	return &listing.Defaults{Properties: d.Properties, Merge: d.Merge}
 }
@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Overlay represents the properties of a config in an environment to be stored
+type Overlay struct {
+	Environment  string
+	Version      int
+	Revision     int64
+	LastModified time.Time
+	Properties   json.RawMessage
+	PromotedFrom *Promotion
+}
+
+// Promotion represents the overlay an overlay was promoted from to be stored
+type Promotion struct {
+	Environment string
+	Revision    int64
+}
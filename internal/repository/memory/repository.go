@@ -1,23 +1,53 @@
 package memory
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/index"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/scheduling"
+	"sort"
+	"strings"
 	"sync"
 )
 
 // Repository representa a in memory storge object
 type Repository struct {
-	rwLock  sync.RWMutex
-	groups  map[string]Group
-	configs map[string]Config
+	rwLock   sync.RWMutex
+	groups   map[string]Group
+	configs  map[listing.ConfigRef]Config
+	overlays map[listing.ConfigRef]map[string]Overlay
+	revision int64
+	changes  []Change
+	index    *index.Properties
+	text     *index.Text
+	refs     *index.References
+	// history holds every version of each config stored, oldest first, and is kept when the config is deleted
+	history map[listing.ConfigRef][]Config
+	// changeRequests are not versioned with the groups and configs, and get their ids from lastChangeRequest
+	changeRequests    map[int64]ChangeRequest
+	lastChangeRequest int64
+	// activations are not versioned either, and get their ids from lastActivation
+	activations    map[int64]Activation
+	lastActivation int64
 }
 
 // NewRepository returns a new Repository storage object
 func NewRepository() *Repository {
 	return &Repository{
-		groups:  make(map[string]Group),
-		configs: make(map[string]Config),
+		groups:         make(map[string]Group),
+		configs:        make(map[listing.ConfigRef]Config),
+		overlays:       make(map[listing.ConfigRef]map[string]Overlay),
+		index:          index.NewProperties(),
+		text:           index.NewText(),
+		refs:           index.NewReferences(),
+		history:        make(map[listing.ConfigRef][]Config),
+		changeRequests: make(map[int64]ChangeRequest),
+		activations:    make(map[int64]Activation),
 	}
 }
 
@@ -26,13 +56,23 @@ func (r *Repository) StoreGroup(g adding.Group) error {
 	r.rwLock.Lock()
 	defer r.rwLock.Unlock()
 
+	return r.storeGroup(g)
+}
+
+// storeGroup stores a new group. Has to be called while holding the write lock.
+func (r *Repository) storeGroup(g adding.Group) error {
 	if _, exists := r.groups[g.ID]; exists {
 		return adding.ErrGroupConflict
 	}
 
+	rev := r.commit(Change{Resource: listing.GroupResource, Group: g.ID, ID: g.ID})
 	r.groups[g.ID] = Group{
-		ID:      g.ID,
-		Configs: g.Configs,
+		ID:         g.ID,
+		Revision:   rev,
+		Configs:    g.Configs,
+		Schema:     g.Schema,
+		Recipients: g.Recipients,
+		Defaults:   newDefaults(g.Defaults),
 	}
 
 	return nil
@@ -45,26 +85,266 @@ func (r *Repository) RetrieveGroup(id string) (*listing.Group, error) {
 
 	if val, exists := r.groups[id]; exists {
 		return &listing.Group{
-			ID:      val.ID,
-			Configs: val.Configs,
+			ID:         val.ID,
+			Revision:   val.Revision,
+			Configs:    val.Configs,
+			Schema:     val.Schema,
+			Recipients: val.Recipients,
+			Approvers:  val.Approvers,
+			Defaults:   listingDefaults(val.Defaults),
 		}, nil
 	}
 
 	return &listing.Group{}, listing.ErrGroupNotFound
 }
 
+// ListGroups retrieves all groups with an id starting with prefix from the memory storage. The groups are not ordered.
+func (r *Repository) ListGroups(prefix string) ([]listing.Group, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	grps := []listing.Group{}
+	for _, g := range r.groups {
+		if strings.HasPrefix(g.ID, prefix) {
+			grps = append(grps, listing.Group{
+				ID:         g.ID,
+				Revision:   g.Revision,
+				Configs:    g.Configs,
+				Schema:     g.Schema,
+				Recipients: g.Recipients,
+				Approvers:  g.Approvers,
+				Defaults:   listingDefaults(g.Defaults),
+			})
+		}
+	}
+
+	return grps, nil
+}
+
+// StoreSchema replaces the schema of a group in the memory storage
+func (r *Repository) StoreSchema(groupID string, s json.RawMessage) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.GroupResource, Group: groupID, ID: groupID})
+	grp.Schema = s
+	r.groups[groupID] = grp
+
+	return nil
+}
+
+// RetrieveSchema retrieves the schema of a group from the memory storage. Returns nil if the group has no schema.
+func (r *Repository) RetrieveSchema(groupID string) (json.RawMessage, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return nil, listing.ErrGroupNotFound
+	}
+
+	return grp.Schema, nil
+}
+
+// StoreRecipients replaces the recipients of a group in the memory storage
+func (r *Repository) StoreRecipients(groupID string, recipients []string) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.GroupResource, Group: groupID, ID: groupID})
+	grp.Recipients = recipients
+	r.groups[groupID] = grp
+
+	return nil
+}
+
+// RetrieveRecipients retrieves the recipients of a group from the memory storage. Returns nil if the group has none.
+func (r *Repository) RetrieveRecipients(groupID string) ([]string, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return nil, listing.ErrGroupNotFound
+	}
+
+	return grp.Recipients, nil
+}
+
+// StoreApprovers replaces the approvers of a group in the memory storage
+func (r *Repository) StoreApprovers(groupID string, approvers []string) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.GroupResource, Group: groupID, ID: groupID})
+	grp.Approvers = approvers
+	r.groups[groupID] = grp
+
+	return nil
+}
+
+// RetrieveApprovers retrieves the approvers of a group from the memory storage. Returns nil if the group has none.
+func (r *Repository) RetrieveApprovers(groupID string) ([]string, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return nil, listing.ErrGroupNotFound
+	}
+
+	return grp.Approvers, nil
+}
+
+// StoreDefaults replaces the defaults of a group in the memory storage
+func (r *Repository) StoreDefaults(groupID string, d *adding.Defaults) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.GroupResource, Group: groupID, ID: groupID})
+	grp.Defaults = newDefaults(d)
+	r.groups[groupID] = grp
+
+	return nil
+}
+
+// UpdateDefaults replaces the defaults of a group in the memory storage with the ones returned by update, which is given the
+// current defaults or nil if the group has none
+func (r *Repository) UpdateDefaults(groupID string, update func(d *adding.Defaults) (*adding.Defaults, error)) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	var current *adding.Defaults
+	if grp.Defaults != nil {
+		current = &adding.Defaults{Properties: grp.Defaults.Properties, Merge: grp.Defaults.Merge}
+	}
+
+	updated, err := update(current)
+	if err != nil {
+		return err
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.GroupResource, Group: groupID, ID: groupID})
+	grp.Defaults = newDefaults(updated)
+	r.groups[groupID] = grp
+
+	return nil
+}
+
+// RetrieveDefaults retrieves the defaults of a group from the memory storage. Returns nil if the group has no defaults.
+func (r *Repository) RetrieveDefaults(groupID string) (*adding.Defaults, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return nil, listing.ErrGroupNotFound
+	}
+
+	if grp.Defaults == nil {
+		return nil, nil
+	}
+
+	return &adding.Defaults{Properties: grp.Defaults.Properties, Merge: grp.Defaults.Merge}, nil
+}
+
+// RetrieveAncestor retrieves a config from the memory storage as it is inherited from by other configs
+func (r *Repository) RetrieveAncestor(groupID string, id string) (*adding.Config, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	if _, exists := r.groups[groupID]; !exists {
+		return nil, listing.ErrGroupNotFound
+	}
+
+	c, exists := r.configs[listing.ConfigRef{Group: groupID, ID: id}]
+	if !exists {
+		return nil, listing.ErrConfigNotFound
+	}
+
+	return &adding.Config{
+		ID:         c.ID,
+		Group:      c.Group,
+		Parent:     c.Parent,
+		Properties: c.Properties,
+	}, nil
+}
+
 // StoreConfig stores a config in the memory storage
 func (r *Repository) StoreConfig(c adding.Config) error {
 	r.rwLock.Lock()
 	defer r.rwLock.Unlock()
 
+	return r.storeConfig(c)
+}
+
+// UpdateConfig replaces an existing config with the one returned by update while holding the write lock
+func (r *Repository) UpdateConfig(groupID string, id string, update func(c adding.Config, revision int64) (adding.Config, error)) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	if _, exists := r.groups[groupID]; !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	c, exists := r.configs[listing.ConfigRef{Group: groupID, ID: id}]
+	if !exists {
+		return listing.ErrConfigNotFound
+	}
+
+	updated, err := update(adding.Config{
+		ID:           c.ID,
+		Name:         c.Name,
+		LastModified: c.LastModified,
+		Version:      c.Version,
+		Group:        c.Group,
+		Parent:       c.Parent,
+		Properties:   c.Properties,
+	}, c.Revision)
+	if err != nil {
+		return err
+	}
+
+	return r.storeConfig(updated)
+}
+
+// storeConfig stores a config. Has to be called while holding the write lock.
+func (r *Repository) storeConfig(c adding.Config) error {
 	grp, exists := r.groups[c.Group]
 	if !exists {
 		return listing.ErrGroupNotFound
 	}
 
+	rev := r.commit(Change{Resource: listing.ConfigResource, Group: c.Group, ID: c.ID})
+
 	if len(grp.Configs) == 0 {
 		grp.Configs = append(grp.Configs, c.ID)
+		grp.Revision = rev
 	} else {
 		for i := 0; i <= len(grp.Configs); i++ {
 			if grp.Configs[i] == c.ID {
@@ -73,26 +353,34 @@ func (r *Repository) StoreConfig(c adding.Config) error {
 
 			if i >= len(grp.Configs)-1 {
 				grp.Configs = append(grp.Configs, c.ID)
+				grp.Revision = rev
 				break
 			}
 		}
 	}
 
 	r.groups[c.Group] = grp
-	r.configs[c.ID] = Config{
+	conf := Config{
 		ID:           c.ID,
 		Name:         c.Name,
 		LastModified: c.LastModified,
 		Version:      c.Version,
+		Revision:     rev,
 		Group:        c.Group,
+		Parent:       c.Parent,
 		Properties:   c.Properties,
 	}
+	ref := listing.ConfigRef{Group: c.Group, ID: c.ID}
+	r.configs[ref] = conf
+	r.history[ref] = append(r.history[ref], conf)
+	r.index.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Properties)
+	r.text.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Name, c.Properties)
+	r.refs.Put(listing.ConfigRef{Group: c.Group, ID: c.ID}, c.Properties)
 
 	return nil
 }
 
 // RetrieveConfig retrieves a config from the memory storage spesified by groupID and id of the config
-// TODO: Doesnt behave same way as local. Cant handle configs with same id and different group. Improve and make test.
 func (r *Repository) RetrieveConfig(groupID string, id string) (*listing.Config, error) {
 	r.rwLock.RLock()
 	defer r.rwLock.RUnlock()
@@ -101,7 +389,7 @@ func (r *Repository) RetrieveConfig(groupID string, id string) (*listing.Config,
 		return &listing.Config{}, listing.ErrGroupNotFound
 	}
 
-	c, exists := r.configs[id]
+	c, exists := r.configs[listing.ConfigRef{Group: groupID, ID: id}]
 	if !exists {
 		return &listing.Config{}, listing.ErrConfigNotFound
 	}
@@ -111,7 +399,639 @@ func (r *Repository) RetrieveConfig(groupID string, id string) (*listing.Config,
 		Name:         c.Name,
 		LastModified: c.LastModified,
 		Version:      c.Version,
+		Revision:     c.Revision,
 		Group:        c.Group,
+		Parent:       c.Parent,
 		Properties:   c.Properties,
 	}, nil
 }
+
+// RetrieveConfigRevision retrieves a config from the memory storage as it was stored at a revision
+func (r *Repository) RetrieveConfigRevision(groupID string, id string, revision int64) (*listing.Config, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	for _, c := range r.history[listing.ConfigRef{Group: groupID, ID: id}] {
+		if c.Revision == revision {
+			return &listing.Config{
+				ID:           c.ID,
+				Name:         c.Name,
+				LastModified: c.LastModified,
+				Version:      c.Version,
+				Revision:     c.Revision,
+				Group:        c.Group,
+				Parent:       c.Parent,
+				Properties:   c.Properties,
+			}, nil
+		}
+	}
+
+	return nil, diffing.ErrRevisionNotFound
+}
+
+// RetrieveConfigVersion retrieves a config from the memory storage as it was last stored with a version
+func (r *Repository) RetrieveConfigVersion(groupID string, id string, version int) (*listing.Config, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	history := r.history[listing.ConfigRef{Group: groupID, ID: id}]
+	for i := len(history) - 1; i >= 0; i-- {
+		if c := history[i]; c.Version == version {
+			return &listing.Config{
+				ID:           c.ID,
+				Name:         c.Name,
+				LastModified: c.LastModified,
+				Version:      c.Version,
+				Revision:     c.Revision,
+				Group:        c.Group,
+				Parent:       c.Parent,
+				Properties:   c.Properties,
+			}, nil
+		}
+	}
+
+	return nil, diffing.ErrVersionNotFound
+}
+
+// SearchConfigs finds all configs satisfying every condition using the property index
+func (r *Repository) SearchConfigs(conditions []listing.Condition) ([]listing.ConfigRef, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	return r.index.Search(conditions), nil
+}
+
+// SearchText finds all configs containing every word in text using the full-text index
+func (r *Repository) SearchText(text string) ([]listing.TextMatch, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	return r.text.Search(text), nil
+}
+
+// RetrieveDependents retrieves the configs referencing a config using the reference index
+func (r *Repository) RetrieveDependents(ref listing.ConfigRef) ([]listing.ConfigRef, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	return r.refs.Dependents(ref), nil
+}
+
+// DeleteGroup deletes a group from the memory storage. Only groups without configs can be deleted.
+func (r *Repository) DeleteGroup(id string) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	grp, exists := r.groups[id]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	if len(grp.Configs) > 0 {
+		return deleting.ErrGroupNotEmpty
+	}
+
+	r.commit(Change{Resource: listing.GroupResource, Group: id, ID: id, Deleted: true})
+	delete(r.groups, id)
+
+	return nil
+}
+
+// DeleteConfig deletes a config from the memory storage and removes it from its group. Configs other configs inherit from
+// cannot be deleted.
+func (r *Repository) DeleteConfig(groupID string, id string) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	return r.deleteConfig(groupID, id)
+}
+
+// deleteConfig deletes a config and its overlays. Has to be called while holding the write lock.
+func (r *Repository) deleteConfig(groupID string, id string) error {
+	grp, exists := r.groups[groupID]
+	if !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	ref := listing.ConfigRef{Group: groupID, ID: id}
+	if _, exists := r.configs[ref]; !exists {
+		return listing.ErrConfigNotFound
+	}
+
+	for _, other := range grp.Configs {
+		if r.configs[listing.ConfigRef{Group: groupID, ID: other}].Parent == id {
+			return deleting.ErrConfigHasChildren
+		}
+	}
+
+	grp.Revision = r.commit(Change{Resource: listing.ConfigResource, Group: groupID, ID: id, Deleted: true})
+	grp.Configs = without(grp.Configs, id)
+	r.groups[groupID] = grp
+
+	delete(r.configs, ref)
+	delete(r.overlays, ref)
+	r.index.Remove(ref)
+	r.text.Remove(ref)
+	r.refs.Remove(ref)
+
+	return nil
+}
+
+// StoreOverlay stores the overlay of a config in an environment in the memory storage, replacing any existing overlay
+func (r *Repository) StoreOverlay(o adding.Overlay) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	if err := r.configExists(listing.ConfigRef{Group: o.Group, ID: o.Config}); err != nil {
+		return err
+	}
+
+	r.storeOverlay(o)
+	return nil
+}
+
+// UpdateOverlay replaces an existing overlay in the memory storage with the one returned by update, which is given the current
+// overlay
+func (r *Repository) UpdateOverlay(groupID string, id string, env string, update func(o adding.Overlay) (adding.Overlay, error)) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	ref := listing.ConfigRef{Group: groupID, ID: id}
+	if err := r.configExists(ref); err != nil {
+		return err
+	}
+
+	o, exists := r.overlays[ref][env]
+	if !exists {
+		return listing.ErrOverlayNotFound
+	}
+
+	var promotedFrom *adding.Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &adding.Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	updated, err := update(adding.Overlay{
+		Group:        groupID,
+		Config:       id,
+		Environment:  env,
+		Version:      o.Version,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.storeOverlay(updated)
+	return nil
+}
+
+// storeOverlay stores an overlay of an existing config. Has to be called while holding the write lock.
+func (r *Repository) storeOverlay(o adding.Overlay) {
+	ref := listing.ConfigRef{Group: o.Group, ID: o.Config}
+	rev := r.commit(Change{Resource: listing.OverlayResource, Group: o.Group, ID: o.Config, Environment: o.Environment})
+
+	var promotedFrom *Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	if r.overlays[ref] == nil {
+		r.overlays[ref] = make(map[string]Overlay)
+	}
+	r.overlays[ref][o.Environment] = Overlay{
+		Environment:  o.Environment,
+		Version:      o.Version,
+		Revision:     rev,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	}
+}
+
+// RetrieveOverlay retrieves the overlay of a config in an environment from the memory storage
+func (r *Repository) RetrieveOverlay(groupID string, id string, env string) (*listing.Overlay, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	ref := listing.ConfigRef{Group: groupID, ID: id}
+	if err := r.configExists(ref); err != nil {
+		return &listing.Overlay{}, err
+	}
+
+	o, exists := r.overlays[ref][env]
+	if !exists {
+		return &listing.Overlay{}, listing.ErrOverlayNotFound
+	}
+
+	return listingOverlay(ref, o), nil
+}
+
+// ListOverlays retrieves all overlays of a config from the memory storage. The overlays are not ordered.
+func (r *Repository) ListOverlays(groupID string, id string) ([]listing.Overlay, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	ref := listing.ConfigRef{Group: groupID, ID: id}
+	if err := r.configExists(ref); err != nil {
+		return nil, err
+	}
+
+	overlays := []listing.Overlay{}
+	for _, o := range r.overlays[ref] {
+		overlays = append(overlays, *listingOverlay(ref, o))
+	}
+
+	return overlays, nil
+}
+
+// RetrieveOverlayForPromotion retrieves an overlay and its revision from the memory storage to be promoted
+func (r *Repository) RetrieveOverlayForPromotion(groupID string, id string, env string) (*adding.Overlay, int64, error) {
+	o, err := r.RetrieveOverlay(groupID, id, env)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &adding.Overlay{
+		Group:       o.Group,
+		Config:      o.Config,
+		Environment: o.Environment,
+		Version:     o.Version,
+		Properties:  o.Properties,
+	}, o.Revision, nil
+}
+
+// DeleteOverlay deletes the overlay of a config in an environment from the memory storage
+func (r *Repository) DeleteOverlay(groupID string, id string, env string) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	ref := listing.ConfigRef{Group: groupID, ID: id}
+	if err := r.configExists(ref); err != nil {
+		return err
+	}
+
+	if _, exists := r.overlays[ref][env]; !exists {
+		return listing.ErrOverlayNotFound
+	}
+
+	r.commit(Change{Resource: listing.OverlayResource, Group: groupID, ID: id, Environment: env, Deleted: true})
+	delete(r.overlays[ref], env)
+
+	return nil
+}
+
+// configExists checks that a config and its group exist. Has to be called while holding the lock.
+func (r *Repository) configExists(ref listing.ConfigRef) error {
+	if _, exists := r.groups[ref.Group]; !exists {
+		return listing.ErrGroupNotFound
+	}
+
+	if _, exists := r.configs[ref]; !exists {
+		return listing.ErrConfigNotFound
+	}
+
+	return nil
+}
+
+func listingOverlay(ref listing.ConfigRef, o Overlay) *listing.Overlay {
+	var promotedFrom *listing.Promotion
+	if o.PromotedFrom != nil {
+		promotedFrom = &listing.Promotion{Environment: o.PromotedFrom.Environment, Revision: o.PromotedFrom.Revision}
+	}
+
+	return &listing.Overlay{
+		Group:        ref.Group,
+		Config:       ref.ID,
+		Environment:  o.Environment,
+		Version:      o.Version,
+		Revision:     o.Revision,
+		LastModified: o.LastModified,
+		Properties:   o.Properties,
+		PromotedFrom: promotedFrom,
+	}
+}
+
+// RetrieveChanges retrieves all changes made after the since revision, ordered by revision
+func (r *Repository) RetrieveChanges(since int64) (*listing.Changes, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	changes := &listing.Changes{
+		Revision: r.revision,
+		Changes:  []listing.Change{},
+	}
+
+	// Changes are appended in revision order
+	i := sort.Search(len(r.changes), func(i int) bool { return r.changes[i].Revision > since })
+	for _, c := range r.changes[i:] {
+		changes.Changes = append(changes.Changes, listing.Change{
+			Revision:    c.Revision,
+			Resource:    c.Resource,
+			Group:       c.Group,
+			ID:          c.ID,
+			Environment: c.Environment,
+			Deleted:     c.Deleted,
+		})
+	}
+
+	return changes, nil
+}
+
+// StoreChangeRequest stores a new change request in the memory storage and returns the id assigned to it
+func (r *Repository) StoreChangeRequest(cr reviewing.ChangeRequest) (int64, error) {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	r.lastChangeRequest++
+	cr.ID = r.lastChangeRequest
+	r.changeRequests[cr.ID] = newChangeRequest(cr)
+
+	return cr.ID, nil
+}
+
+// RetrieveChangeRequest retrieves a change request from the memory storage
+func (r *Repository) RetrieveChangeRequest(id int64) (*reviewing.ChangeRequest, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	cr, exists := r.changeRequests[id]
+	if !exists {
+		return nil, reviewing.ErrChangeRequestNotFound
+	}
+
+	res := reviewingChangeRequest(cr)
+	return &res, nil
+}
+
+// ListChangeRequests retrieves the change requests of a group, or of every group if groupID is empty, from the memory
+// storage ordered by id
+func (r *Repository) ListChangeRequests(groupID string) ([]reviewing.ChangeRequest, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	crs := []reviewing.ChangeRequest{}
+	for _, cr := range r.changeRequests {
+		if groupID == "" || cr.Group == groupID {
+			crs = append(crs, reviewingChangeRequest(cr))
+		}
+	}
+
+	sort.Slice(crs, func(i, j int) bool { return crs[i].ID < crs[j].ID })
+	return crs, nil
+}
+
+// UpdateChangeRequest replaces an existing change request in the memory storage with the one returned by update, which is
+// given the current change request
+func (r *Repository) UpdateChangeRequest(id int64, update func(cr reviewing.ChangeRequest) (reviewing.ChangeRequest, error)) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	cr, exists := r.changeRequests[id]
+	if !exists {
+		return reviewing.ErrChangeRequestNotFound
+	}
+
+	updated, err := update(reviewingChangeRequest(cr))
+	if err != nil {
+		return err
+	}
+
+	updated.ID = id
+	r.changeRequests[id] = newChangeRequest(updated)
+	return nil
+}
+
+// StoreActivation stores a new activation in the memory storage and returns the id assigned to it
+func (r *Repository) StoreActivation(a scheduling.Activation) (int64, error) {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	r.lastActivation++
+	a.ID = r.lastActivation
+	r.activations[a.ID] = newActivation(a)
+
+	return a.ID, nil
+}
+
+// RetrieveActivation retrieves an activation from the memory storage
+func (r *Repository) RetrieveActivation(id int64) (*scheduling.Activation, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	a, exists := r.activations[id]
+	if !exists {
+		return nil, scheduling.ErrActivationNotFound
+	}
+
+	res := schedulingActivation(a)
+	return &res, nil
+}
+
+// ListActivations retrieves the activations of a config, or of every config if groupID and id are empty, from the memory
+// storage ordered by id
+func (r *Repository) ListActivations(groupID string, id string) ([]scheduling.Activation, error) {
+	r.rwLock.RLock()
+	defer r.rwLock.RUnlock()
+
+	as := []scheduling.Activation{}
+	for _, a := range r.activations {
+		if groupID == "" && id == "" || a.Group == groupID && a.Config == id {
+			as = append(as, schedulingActivation(a))
+		}
+	}
+
+	sort.Slice(as, func(i, j int) bool { return as[i].ID < as[j].ID })
+	return as, nil
+}
+
+// UpdateActivation replaces an existing activation in the memory storage with the one returned by update, which is given the
+// current activation
+func (r *Repository) UpdateActivation(id int64, update func(a scheduling.Activation) (scheduling.Activation, error)) error {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	a, exists := r.activations[id]
+	if !exists {
+		return scheduling.ErrActivationNotFound
+	}
+
+	updated, err := update(schedulingActivation(a))
+	if err != nil {
+		return err
+	}
+
+	updated.ID = id
+	r.activations[id] = newActivation(updated)
+	return nil
+}
+
+// Commit applies the operations of a batch in order while holding the write lock. If an operation cannot be applied, the
+// groups, configs and overlays touched by the batch are restored together with the revision, the changes and the indexes.
+func (r *Repository) Commit(ops []adding.Operation) ([]int64, error) {
+	r.rwLock.Lock()
+	defer r.rwLock.Unlock()
+
+	before := r.snapshot(ops)
+	revisions := make([]int64, len(ops))
+	for i, op := range ops {
+		if err := r.apply(op); err != nil {
+			r.restore(before)
+			return nil, &adding.OperationError{Index: i, Err: err}
+		}
+		// Every operation stores one change
+		revisions[i] = r.revision
+	}
+
+	return revisions, nil
+}
+
+// apply applies an operation of a batch. Has to be called while holding the write lock.
+func (r *Repository) apply(op adding.Operation) error {
+	ref := listing.ConfigRef{Group: op.Config.Group, ID: op.Config.ID}
+
+	switch op.Type {
+	case adding.CreateGroup:
+		return r.storeGroup(op.Group)
+	case adding.PutConfig:
+		if err := r.checkRevision(ref, op.Revision); err != nil {
+			return err
+		}
+		return r.storeConfig(op.Config)
+	case adding.DeleteConfig:
+		if err := r.checkRevision(ref, op.Revision); err != nil {
+			return err
+		}
+		return r.deleteConfig(ref.Group, ref.ID)
+	}
+
+	return fmt.Errorf("unknown operation type %d", op.Type)
+}
+
+// checkRevision checks that a config is at a revision, or does not exist if the revision is zero. Has to be called while
+// holding the lock.
+func (r *Repository) checkRevision(ref listing.ConfigRef, revision *int64) error {
+	if revision != nil && r.configs[ref].Revision != *revision {
+		return adding.ErrRevisionMismatch
+	}
+
+	return nil
+}
+
+// snapshot is the state of the repository touched by a batch before the batch is applied. Groups and configs that did not
+// exist are nil.
+type snapshot struct {
+	revision int64
+	changes  int
+	groups   map[string]*Group
+	configs  map[listing.ConfigRef]*Config
+	overlays map[listing.ConfigRef]map[string]Overlay
+	// history holds the number of versions in the history of each config
+	history map[listing.ConfigRef]int
+}
+
+// snapshot returns the state of the groups and configs touched by a batch. Has to be called while holding the write lock.
+func (r *Repository) snapshot(ops []adding.Operation) snapshot {
+	s := snapshot{
+		revision: r.revision,
+		changes:  len(r.changes),
+		groups:   make(map[string]*Group),
+		configs:  make(map[listing.ConfigRef]*Config),
+		overlays: make(map[listing.ConfigRef]map[string]Overlay),
+		history:  make(map[listing.ConfigRef]int),
+	}
+
+	for _, op := range ops {
+		ids := []string{op.Group.ID}
+		if op.Type != adding.CreateGroup {
+			ids = []string{op.Config.Group}
+
+			ref := listing.ConfigRef{Group: op.Config.Group, ID: op.Config.ID}
+			if c, exists := r.configs[ref]; exists {
+				s.configs[ref] = &c
+			} else {
+				s.configs[ref] = nil
+			}
+			s.overlays[ref] = r.overlays[ref]
+			s.history[ref] = len(r.history[ref])
+		}
+
+		for _, id := range ids {
+			if g, exists := r.groups[id]; exists {
+				s.groups[id] = &g
+			} else {
+				s.groups[id] = nil
+			}
+		}
+	}
+
+	return s
+}
+
+// restore restores the state of a snapshot. Has to be called while holding the write lock.
+func (r *Repository) restore(s snapshot) {
+	r.revision = s.revision
+	r.changes = r.changes[:s.changes]
+
+	for id, g := range s.groups {
+		if g == nil {
+			delete(r.groups, id)
+		} else {
+			r.groups[id] = *g
+		}
+	}
+
+	for ref, c := range s.configs {
+		if c == nil {
+			delete(r.configs, ref)
+			r.index.Remove(ref)
+			r.text.Remove(ref)
+			r.refs.Remove(ref)
+			continue
+		}
+
+		r.configs[ref] = *c
+		r.index.Put(ref, c.Properties)
+		r.text.Put(ref, c.Name, c.Properties)
+		r.refs.Put(ref, c.Properties)
+	}
+
+	for ref, o := range s.overlays {
+		if o == nil {
+			delete(r.overlays, ref)
+		} else {
+			r.overlays[ref] = o
+		}
+	}
+
+	for ref, n := range s.history {
+		if n == 0 {
+			delete(r.history, ref)
+		} else {
+			r.history[ref] = r.history[ref][:n]
+		}
+	}
+}
+
+// commit increments the revision and records the change with it. Has to be called while holding the write lock.
+func (r *Repository) commit(c Change) int64 {
+	r.revision++
+	c.Revision = r.revision
+	r.changes = append(r.changes, c)
+
+	return r.revision
+}
+
+// without returns a copy of ids without id
+func without(ids []string, id string) []string {
+	res := make([]string, 0, len(ids))
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
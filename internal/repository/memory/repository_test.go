@@ -1,7 +1,10 @@
 package memory
 
 import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/listing"
 	"github.com/larwef/ki/test"
+	"sync"
 	"testing"
 )
 
@@ -37,4 +40,124 @@ func TestRepository_RetrieveConfig_ConfigNotExist(t *testing.T) {
 	test.RetrieveConfigWhenConfigNotExist(t, NewRepository(), clean)
 }
 
+func TestRepository_StoreAndRetrieveRevision(t *testing.T) {
+	test.StoreAndRetrieveRevision(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveChanges(t *testing.T) {
+	test.StoreAndRetrieveChanges(t, NewRepository(), clean)
+}
+
+func TestRepository_ListGroups(t *testing.T) {
+	test.ListGroupsWithPrefix(t, NewRepository(), clean)
+}
+
+func TestRepository_SearchConfigs(t *testing.T) {
+	test.SearchConfigsByProperties(t, NewRepository(), clean)
+}
+
+func TestRepository_SearchText(t *testing.T) {
+	test.SearchConfigsByText(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveSchema(t *testing.T) {
+	test.StoreAndRetrieveSchema(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveRecipients(t *testing.T) {
+	test.StoreAndRetrieveRecipients(t, NewRepository(), clean)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	test.DeleteConfigAndGroup(t, NewRepository(), clean)
+}
+
+func TestRepository_SameConfigIDInDifferentGroups(t *testing.T) {
+	test.StoreConfigsWithSameIDInDifferentGroups(t, NewRepository(), clean)
+}
+
+func TestRepository_UpdateConfig(t *testing.T) {
+	test.UpdateExistingConfig(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveDefaults(t *testing.T) {
+	test.StoreAndRetrieveDefaults(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreConfigsWithParent(t *testing.T) {
+	test.StoreConfigsWithParent(t, NewRepository(), clean)
+}
+
 func clean() {}
+
+func TestRepository_StoreAndRetrieveOverlays(t *testing.T) {
+	test.StoreAndRetrieveOverlays(t, NewRepository(), clean)
+}
+
+func TestRepository_RetrieveDependentsOfReferencedConfig(t *testing.T) {
+	test.RetrieveDependentsOfReferencedConfig(t, NewRepository(), clean)
+}
+
+func TestRepository_UpdateDefaultsAndOverlays(t *testing.T) {
+	test.UpdateDefaultsAndOverlays(t, NewRepository(), clean)
+}
+
+func TestRepository_CommitBatch(t *testing.T) {
+	test.CommitBatch(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveChangeRequests(t *testing.T) {
+	test.StoreAndRetrieveChangeRequests(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveActivations(t *testing.T) {
+	test.StoreAndRetrieveActivations(t, NewRepository(), clean)
+}
+
+func TestRepository_StoreAndRetrieveConfigRevisions(t *testing.T) {
+	test.StoreAndRetrieveConfigRevisions(t, NewRepository(), clean)
+}
+
+func TestRepository_SearchWhileBatchFails(t *testing.T) {
+	repo := NewRepository()
+	test.AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+
+	// Searches running while a batch fails never see the configs it stored before it was undone. Run with -race.
+	done := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				refs, err := repo.SearchConfigs([]listing.Condition{{Path: "host", Operator: listing.Exists}})
+				test.AssertNotError(t, err)
+				test.AssertEqual(t, len(refs), 1)
+				matches, err := repo.SearchText("db2")
+				test.AssertNotError(t, err)
+				test.AssertEqual(t, len(matches), 0)
+				dependents, err := repo.RetrieveDependents(listing.ConfigRef{Group: "someGroup", ID: "someId"})
+				test.AssertNotError(t, err)
+				test.AssertEqual(t, len(dependents), 0)
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		_, err := repo.Commit([]adding.Operation{
+			{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "someGroup", Properties: []byte(`{"host":"db2","db":"${ref:someGroup/someId#host}"}`)}},
+			{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "missingGroup"}},
+		})
+		test.AssertEqual(t, err == nil, false)
+	}
+
+	close(done)
+	wg.Wait()
+}
@@ -2,11 +2,21 @@ package repository
 
 import (
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/rotating"
+	"github.com/larwef/ki/internal/scheduling"
 )
 
-// Repository has to satisfy adding and listing repository interfaces.
+// Repository has to satisfy adding, listing, deleting, rotating, reviewing, scheduling and diffing repository interfaces.
 type Repository interface {
 	adding.Repository
 	listing.Repository
+	deleting.Repository
+	rotating.Repository
+	reviewing.Repository
+	scheduling.Repository
+	diffing.Repository
 }
@@ -0,0 +1,380 @@
+// Package reviewing provides change requests, which stage a config change until another user approves it. Approvers are
+// set per group, and a change request in a group can only be approved by one of them who is not its author.
+package reviewing
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/secret"
+	"time"
+)
+
+// ChangeRequestResource identifies a change request in errors
+const ChangeRequestResource = "change request"
+
+// ApproversResource identifies the approvers of a group in errors
+const ApproversResource = "approvers"
+
+// ErrChangeRequestNotFound is used when a change request does not exist.
+var ErrChangeRequestNotFound = domain.New(domain.NotFound, ChangeRequestResource, "change request not found")
+
+// ErrNoApprovers is used when proposing a change to a config in a group without approvers.
+var ErrNoApprovers = domain.New(domain.FailedPrecondition, ChangeRequestResource, "group has no approvers")
+
+// ErrNotOpen is used when approving or rejecting a change request that is no longer open.
+var ErrNotOpen = domain.New(domain.FailedPrecondition, ChangeRequestResource, "change request is not open")
+
+// ErrNotApproved is used when applying a change request that is not approved.
+var ErrNotApproved = domain.New(domain.FailedPrecondition, ChangeRequestResource, "change request is not approved")
+
+// ErrConfigChanged is used when applying a change request to a config that changed after the change was proposed. The change
+// has to be proposed again against the current config.
+var ErrConfigChanged = domain.New(domain.FailedPrecondition, ChangeRequestResource, "config changed after the change was proposed")
+
+// ErrNotApprover is used when a user other than an approver of the group approves a change request, or rejects or applies a
+// change request proposed by someone else.
+var ErrNotApprover = domain.New(domain.PermissionDenied, ChangeRequestResource, "user is not an approver of the group")
+
+// ErrOwnChangeRequest is used when the author of a change request approves it.
+var ErrOwnChangeRequest = domain.New(domain.PermissionDenied, ChangeRequestResource, "change requests cannot be approved by their author")
+
+// State is the state of a change request
+type State string
+
+// Possible states of a change request. Open change requests are either approved or rejected, and approved ones applied.
+const (
+	Open     State = "open"
+	Approved State = "approved"
+	Rejected State = "rejected"
+	Applied  State = "applied"
+)
+
+// ChangeRequest is a change to a config waiting to be approved and applied. Config is the config as it will be added, with its
+// secret values encrypted, and BaseRevision the revision of the config the change was proposed against, or zero if the config
+// did not exist. Diff holds the differences from the properties at BaseRevision, with secret values masked.
+type ChangeRequest struct {
+	ID           int64                   `json:"id"`
+	Author       string                  `json:"author"`
+	Description  string                  `json:"description,omitempty"`
+	State        State                   `json:"state"`
+	BaseRevision int64                   `json:"baseRevision"`
+	Config       adding.Config           `json:"config"`
+	Diff         []properties.Difference `json:"diff"`
+	History      []Transition            `json:"history"`
+}
+
+// Transition records a change request entering State
+type Transition struct {
+	State   State     `json:"state"`
+	User    string    `json:"user"`
+	Time    time.Time `json:"time"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+// Proposal is a change to a config to be reviewed. Config replaces the config like when adding it.
+type Proposal struct {
+	Description string        `json:"description"`
+	Config      adding.Config `json:"config"`
+}
+
+// Query specifies which change requests to list. Empty fields match every change request.
+type Query struct {
+	Group string
+	State State
+}
+
+// Service provides change request operations. User is the name of the user doing the operation.
+type Service interface {
+	SetApprovers(groupID string, approvers []string) error
+	GetApprovers(groupID string) ([]string, error)
+	Propose(user string, p Proposal) (*ChangeRequest, error)
+	GetChangeRequest(id int64) (*ChangeRequest, error)
+	ListChangeRequests(q Query) ([]ChangeRequest, error)
+	Approve(user string, id int64, comment string) (*ChangeRequest, error)
+	Reject(user string, id int64, comment string) (*ChangeRequest, error)
+	Apply(user string, id int64) (*ChangeRequest, error)
+}
+
+// Repository provides access to repository
+type Repository interface {
+	StoreApprovers(groupID string, approvers []string) error
+	// RetrieveApprovers retrieves the approvers of a group. Returns nil if the group has none.
+	RetrieveApprovers(groupID string) ([]string, error)
+	RetrieveConfig(groupID string, id string) (*listing.Config, error)
+	// StoreChangeRequest stores a new change request and returns the id assigned to it
+	StoreChangeRequest(cr ChangeRequest) (int64, error)
+	RetrieveChangeRequest(id int64) (*ChangeRequest, error)
+	// ListChangeRequests lists the change requests of a group, or of every group if groupID is empty, ordered by id
+	ListChangeRequests(groupID string) ([]ChangeRequest, error)
+	// UpdateChangeRequest replaces an existing change request with the one returned by update, which is given the current
+	// change request. No other changes can be made to the change request while update runs, and update cannot use the
+	// repository.
+	UpdateChangeRequest(id int64, update func(cr ChangeRequest) (ChangeRequest, error)) error
+}
+
+type service struct {
+	repo   Repository
+	adding adding.Service
+}
+
+// NewService returns a new reviewing service applying approved changes with add
+func NewService(r Repository, add adding.Service) Service {
+	return &service{repo: r, adding: add}
+}
+
+// SetApprovers replaces the users who can approve change requests in a group. An empty list removes them.
+func (s *service) SetApprovers(groupID string, approvers []string) error {
+	seen := make(map[string]bool)
+	for _, a := range approvers {
+		if a == "" {
+			return invalidField(ApproversResource, "approvers", "has to be usernames")
+		}
+		if seen[a] {
+			return invalidField(ApproversResource, "approvers", "duplicate approver "+a)
+		}
+		seen[a] = true
+	}
+
+	if len(approvers) == 0 {
+		approvers = nil
+	}
+
+	return s.repo.StoreApprovers(groupID, approvers)
+}
+
+// GetApprovers gets the users who can approve change requests in a group
+func (s *service) GetApprovers(groupID string) ([]string, error) {
+	approvers, err := s.repo.RetrieveApprovers(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if approvers == nil {
+		approvers = []string{}
+	}
+
+	return approvers, nil
+}
+
+// Propose stores a change to a config as an open change request, if the config is valid like when adding it. Returns
+// ErrNoApprovers if nobody can approve changes in the group of the config.
+func (s *service) Propose(user string, p Proposal) (*ChangeRequest, error) {
+	if user == "" {
+		return nil, invalidField(ChangeRequestResource, "user", "is required")
+	}
+
+	approvers, err := s.repo.RetrieveApprovers(p.Config.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(approvers) == 0 {
+		return nil, ErrNoApprovers
+	}
+
+	var base int64
+	var current []byte
+	c, err := s.repo.RetrieveConfig(p.Config.Group, p.Config.ID)
+	switch err {
+	case nil:
+		base, current = c.Revision, c.Properties
+	case listing.ErrConfigNotFound:
+	default:
+		return nil, err
+	}
+
+	conf, err := s.adding.PrepareConfig(p.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := secret.Diff(current, conf.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := ChangeRequest{
+		Author:       user,
+		Description:  p.Description,
+		State:        Open,
+		BaseRevision: base,
+		Config:       conf,
+		Diff:         diff,
+		History:      []Transition{{State: Open, User: user, Time: time.Now()}},
+	}
+
+	if cr.ID, err = s.repo.StoreChangeRequest(cr); err != nil {
+		return nil, err
+	}
+
+	return &cr, nil
+}
+
+// GetChangeRequest gets a change request
+func (s *service) GetChangeRequest(id int64) (*ChangeRequest, error) {
+	return s.repo.RetrieveChangeRequest(id)
+}
+
+// ListChangeRequests lists the change requests matching a query, ordered by id
+func (s *service) ListChangeRequests(q Query) ([]ChangeRequest, error) {
+	switch q.State {
+	case "", Open, Approved, Rejected, Applied:
+	default:
+		return nil, invalidField(ChangeRequestResource, "state", "has to be one of open, approved, rejected and applied")
+	}
+
+	crs, err := s.repo.ListChangeRequests(q.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []ChangeRequest{}
+	for _, cr := range crs {
+		if q.State == "" || cr.State == q.State {
+			res = append(res, cr)
+		}
+	}
+
+	return res, nil
+}
+
+// Approve approves an open change request. Returns ErrNotApprover if the user is not an approver of the group of the config,
+// and ErrOwnChangeRequest if the user proposed the change.
+func (s *service) Approve(user string, id int64, comment string) (*ChangeRequest, error) {
+	return s.transition(id, func(cr ChangeRequest, approvers []string) (State, error) {
+		if cr.State != Open {
+			return "", ErrNotOpen
+		}
+
+		if !contains(approvers, user) {
+			return "", ErrNotApprover
+		}
+
+		if cr.Author == user {
+			return "", ErrOwnChangeRequest
+		}
+
+		return Approved, nil
+	}, user, comment)
+}
+
+// Reject rejects an open change request. Change requests can be rejected by the approvers of the group of the config, and
+// withdrawn by their author.
+func (s *service) Reject(user string, id int64, comment string) (*ChangeRequest, error) {
+	return s.transition(id, func(cr ChangeRequest, approvers []string) (State, error) {
+		if cr.State != Open {
+			return "", ErrNotOpen
+		}
+
+		if cr.Author != user && !contains(approvers, user) {
+			return "", ErrNotApprover
+		}
+
+		return Rejected, nil
+	}, user, comment)
+}
+
+// Apply adds the config of an approved change request, if the config has not changed since the change was proposed. Change
+// requests can be applied by their author and the approvers of the group of the config. Returns ErrConfigChanged if the config
+// changed, or an error from adding the config, like when it no longer satisfies the schema of its group.
+func (s *service) Apply(user string, id int64) (*ChangeRequest, error) {
+	cr, err := s.repo.RetrieveChangeRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cr.State != Approved {
+		return nil, ErrNotApproved
+	}
+
+	if err := s.checkApprover(*cr, user); err != nil {
+		return nil, err
+	}
+
+	conf := cr.Config
+	conf.LastModified = time.Now()
+	// The revision makes applying the change request twice fail, as the config changes the first time
+	_, err = s.adding.Commit([]adding.Operation{{Type: adding.PutConfig, Config: conf, Revision: &cr.BaseRevision}})
+	if oe, ok := err.(*adding.OperationError); ok {
+		err = oe.Err
+	}
+	if err == adding.ErrRevisionMismatch {
+		return nil, ErrConfigChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transition(id, func(cr ChangeRequest, approvers []string) (State, error) {
+		if cr.State != Approved {
+			return "", ErrNotApproved
+		}
+		return Applied, nil
+	}, user, "")
+}
+
+// checkApprover checks that a user is the author of a change request or an approver of its group
+func (s *service) checkApprover(cr ChangeRequest, user string) error {
+	if cr.Author == user {
+		return nil
+	}
+
+	approvers, err := s.repo.RetrieveApprovers(cr.Config.Group)
+	if err != nil {
+		return err
+	}
+
+	if !contains(approvers, user) {
+		return ErrNotApprover
+	}
+
+	return nil
+}
+
+// transition moves a change request to the state returned by next, which is given the change request and the current
+// approvers of its group, and records it in the history of the change request
+func (s *service) transition(id int64, next func(cr ChangeRequest, approvers []string) (State, error), user string, comment string) (*ChangeRequest, error) {
+	cr, err := s.repo.RetrieveChangeRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, err := s.repo.RetrieveApprovers(cr.Config.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated ChangeRequest
+	err = s.repo.UpdateChangeRequest(id, func(cr ChangeRequest) (ChangeRequest, error) {
+		state, err := next(cr, approvers)
+		if err != nil {
+			return cr, err
+		}
+
+		cr.State = state
+		cr.History = append(cr.History, Transition{State: state, User: user, Time: time.Now(), Comment: comment})
+		updated = cr
+		return cr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func invalidField(resource, field, reason string) error {
+	return adding.InvalidFieldError{Resource: resource, Field: field, Reason: reason}
+}
@@ -0,0 +1,202 @@
+package reviewing_test
+
+import (
+	"bytes"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/test"
+	"strings"
+	"testing"
+)
+
+func newService(t *testing.T) (*memory.Repository, adding.Service, reviewing.Service) {
+	keeper, err := secret.NewKeeper(bytes.Repeat([]byte{1}, secret.KeySize))
+	test.AssertNotError(t, err)
+
+	repo := memory.NewRepository()
+	add := adding.NewService(repo, adding.Secrets(keeper))
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup", Schema: []byte(`{"properties":{"port":{"type":"integer"}}}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1","port":5432}`)}))
+
+	return repo, add, reviewing.NewService(repo, adding.NewService(repo, adding.Secrets(keeper), adding.Reviewed()))
+}
+
+func TestService_ApproveAndApply(t *testing.T) {
+	repo, _, service := newService(t)
+
+	proposal := reviewing.Proposal{
+		Description: "Move to db2",
+		Config:      adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db2","port":5432,"password":{"$secret":"hunter2"}}`)},
+	}
+
+	_, err := service.Propose("alice", proposal)
+	test.AssertEqual(t, err, reviewing.ErrNoApprovers)
+
+	test.AssertNotError(t, service.SetApprovers("someGroup", []string{"bob", "carol"}))
+	approvers, err := service.GetApprovers("someGroup")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, strings.Join(approvers, ","), "bob,carol")
+
+	cr, err := service.Propose("alice", proposal)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, reviewing.Open)
+	test.AssertEqual(t, cr.BaseRevision, int64(2))
+	test.AssertEqual(t, len(cr.Diff), 2)
+	test.AssertEqual(t, cr.Diff[0], properties.Difference{Type: properties.Changed, Path: "/host", From: "db1", To: "db2"})
+	test.AssertEqual(t, cr.Diff[1], properties.Difference{Type: properties.Added, Path: "/password", To: secret.Mask})
+
+	// Secret values are encrypted while the change request waits
+	if strings.Contains(string(cr.Config.Properties), "hunter2") {
+		t.Fatalf("Change request holds the secret value: %s", cr.Config.Properties)
+	}
+
+	// Nothing is changed before the change request is applied
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db1","port":5432}`)
+
+	_, err = service.Apply("alice", cr.ID)
+	test.AssertEqual(t, err, reviewing.ErrNotApproved)
+
+	_, err = service.Approve("alice", cr.ID, "")
+	test.AssertEqual(t, err, reviewing.ErrNotApprover)
+
+	// Authors cannot approve their own change requests, even as approvers
+	test.AssertNotError(t, service.SetApprovers("someGroup", []string{"alice", "bob"}))
+	_, err = service.Approve("alice", cr.ID, "")
+	test.AssertEqual(t, err, reviewing.ErrOwnChangeRequest)
+
+	cr, err = service.Approve("bob", cr.ID, "Looks good")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, reviewing.Approved)
+
+	_, err = service.Reject("bob", cr.ID, "")
+	test.AssertEqual(t, err, reviewing.ErrNotOpen)
+
+	_, err = service.Apply("dave", cr.ID)
+	test.AssertEqual(t, err, reviewing.ErrNotApprover)
+
+	cr, err = service.Apply("alice", cr.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, reviewing.Applied)
+
+	conf, err = repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.LastModified.IsZero(), false)
+	test.AssertEqual(t, secret.Encrypted(conf.Properties), true)
+
+	// Every state transition is recorded
+	cr, err = service.GetChangeRequest(cr.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(cr.History), 3)
+	test.AssertEqual(t, cr.History[0].User, "alice")
+	test.AssertEqual(t, cr.History[1].State, reviewing.Approved)
+	test.AssertEqual(t, cr.History[1].User, "bob")
+	test.AssertEqual(t, cr.History[1].Comment, "Looks good")
+	test.AssertEqual(t, cr.History[2].State, reviewing.Applied)
+
+	_, err = service.Apply("alice", cr.ID)
+	test.AssertEqual(t, err, reviewing.ErrNotApproved)
+}
+
+func TestService_ConfigChanged(t *testing.T) {
+	repo, add, service := newService(t)
+	test.AssertNotError(t, service.SetApprovers("someGroup", []string{"bob"}))
+
+	first, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":1}`)}})
+	test.AssertNotError(t, err)
+	second, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":2}`)}})
+	test.AssertNotError(t, err)
+
+	for _, cr := range []*reviewing.ChangeRequest{first, second} {
+		_, err = service.Approve("bob", cr.ID, "")
+		test.AssertNotError(t, err)
+	}
+
+	_, err = service.Apply("bob", first.ID)
+	test.AssertNotError(t, err)
+
+	// The second change request was proposed against the config before the first was applied
+	_, err = service.Apply("bob", second.ID)
+	test.AssertEqual(t, err, reviewing.ErrConfigChanged)
+
+	// Configs in the group cannot be changed without a change request
+	err = add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":4}`)})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	// but changes made to the repository are detected as well
+	third, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":3}`)}})
+	test.AssertNotError(t, err)
+	_, err = service.Approve("bob", third.ID, "")
+	test.AssertNotError(t, err)
+	test.AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":4}`)}))
+	_, err = service.Apply("bob", third.ID)
+	test.AssertEqual(t, err, reviewing.ErrConfigChanged)
+
+	// New configs have to still not exist
+	fourth, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "newId", Group: "someGroup"}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, fourth.BaseRevision, int64(0))
+	_, err = service.Approve("bob", fourth.ID, "")
+	test.AssertNotError(t, err)
+	_, err = service.Apply("bob", fourth.ID)
+	test.AssertNotError(t, err)
+}
+
+func TestService_RejectAndList(t *testing.T) {
+	_, _, service := newService(t)
+	test.AssertNotError(t, service.SetApprovers("someGroup", []string{"bob"}))
+
+	// Proposals are validated like when adding the config
+	_, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":"5432"}`)}})
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+
+	_, err = service.Propose("", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup"}})
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		cr, err := service.Propose("alice", reviewing.Proposal{Config: adding.Config{ID: "someId", Group: "someGroup"}})
+		test.AssertNotError(t, err)
+		ids = append(ids, cr.ID)
+	}
+
+	_, err = service.Reject("carol", ids[0], "")
+	test.AssertEqual(t, err, reviewing.ErrNotApprover)
+
+	cr, err := service.Reject("bob", ids[0], "Wrong host")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, cr.State, reviewing.Rejected)
+
+	// Authors can withdraw their change requests
+	_, err = service.Reject("alice", ids[1], "")
+	test.AssertNotError(t, err)
+
+	_, err = service.Approve("bob", ids[1], "")
+	test.AssertEqual(t, err, reviewing.ErrNotOpen)
+
+	crs, err := service.ListChangeRequests(reviewing.Query{Group: "someGroup", State: reviewing.Rejected})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(crs), 2)
+
+	crs, err = service.ListChangeRequests(reviewing.Query{State: reviewing.Open})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(crs), 1)
+	test.AssertEqual(t, crs[0].ID, ids[2])
+
+	crs, err = service.ListChangeRequests(reviewing.Query{Group: "someOtherGroup"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(crs), 0)
+
+	_, err = service.ListChangeRequests(reviewing.Query{State: "merged"})
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	_, err = service.GetChangeRequest(100)
+	test.AssertEqual(t, err, reviewing.ErrChangeRequestNotFound)
+
+	test.AssertEqual(t, domain.From(service.SetApprovers("someGroup", []string{"bob", "bob"})).Kind, domain.InvalidArgument)
+}
@@ -0,0 +1,359 @@
+package rotating
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/secret"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RotationResource identifies a key rotation in errors
+const RotationResource = "rotation"
+
+// ErrRotationRunning is used when starting a key rotation while another one is running.
+var ErrRotationRunning = domain.New(domain.FailedPrecondition, RotationResource, "a key rotation is already running")
+
+// errUnchanged is returned by updates with nothing to rewrap, so nothing is stored
+var errUnchanged = errors.New("unchanged")
+
+// State is the state of a key rotation
+type State string
+
+// Possible states of a key rotation
+const (
+	Idle    State = "idle"
+	Running State = "running"
+	Done    State = "done"
+	Failed  State = "failed"
+	Stopped State = "stopped"
+)
+
+// Progress reports the progress of the last key rotation. Configs is the number of configs to rotate and Rotated how many
+// are done, together with their overlays and the defaults of their group. Rewrapped is the number of secret values rewrapped
+// with the primary key so far.
+type Progress struct {
+	State     State     `json:"state"`
+	Key       string    `json:"key"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Configs   int       `json:"configs"`
+	Rotated   int       `json:"rotated"`
+	Rewrapped int       `json:"rewrapped"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Verification lists the resources with secret values still encrypted with retired keys. Verified is set if there are none.
+type Verification struct {
+	Key      string              `json:"key"`
+	Versions []secret.KeyVersion `json:"versions"`
+	Verified bool                `json:"verified"`
+	Retired  []Retired           `json:"retired"`
+}
+
+// Retired is a resource with Values secret values encrypted with retired keys. Resource is the type of resource: the defaults
+// of a group, a config or the overlay of a config in Environment.
+type Retired struct {
+	Resource    string `json:"resource"`
+	Group       string `json:"group"`
+	ID          string `json:"id"`
+	Environment string `json:"environment,omitempty"`
+	Values      int    `json:"values"`
+}
+
+// Service provides key rotation operations
+type Service interface {
+	Rotate() error
+	Progress() Progress
+	Verify() (*Verification, error)
+}
+
+// Repository provides access to repository
+type Repository interface {
+	ListGroups(prefix string) ([]listing.Group, error)
+	RetrieveConfig(groupID string, id string) (*listing.Config, error)
+	// ListOverlays lists the overlays of a config in any order
+	ListOverlays(groupID string, id string) ([]listing.Overlay, error)
+	// UpdateDefaults replaces the defaults of a group with the ones returned by update, which is given the current defaults or
+	// nil. No other changes can be made to the defaults while update runs, and update cannot use the repository.
+	UpdateDefaults(groupID string, update func(d *adding.Defaults) (*adding.Defaults, error)) error
+	UpdateConfig(groupID string, id string, update func(c adding.Config, revision int64) (adding.Config, error)) error
+	// UpdateOverlay replaces an existing overlay with the one returned by update, which is given the current overlay. No other
+	// changes can be made to the overlay while update runs, and update cannot use the repository.
+	UpdateOverlay(groupID string, id string, env string, update func(o adding.Overlay) (adding.Overlay, error)) error
+}
+
+// Job is the Service, and rewraps the secret values in the repository with the primary key of a Keeper in the background when
+// run by a runner.Runner. Values are rewrapped by encrypting their data-encryption keys with the primary key, without
+// decrypting the values. Every resource is rewrapped on its own, so the repository can be used while a rotation runs.
+type Job struct {
+	repo   Repository
+	keeper *secret.Keeper
+
+	trigger chan bool
+	stop    chan bool
+
+	lock     sync.Mutex
+	progress Progress
+}
+
+// NewJob returns a new Job rewrapping secret values with the primary key of k
+func NewJob(r Repository, k *secret.Keeper) *Job {
+	return &Job{
+		repo:     r,
+		keeper:   k,
+		trigger:  make(chan bool, 1),
+		stop:     make(chan bool),
+		progress: Progress{State: Idle, Key: k.ID()},
+	}
+}
+
+// Serve runs key rotations when they are started, until shut down
+func (j *Job) Serve(signal chan bool) {
+	log.Println("Starting key rotation job")
+	for {
+		select {
+		case <-j.trigger:
+			j.run()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+// GracefulShutdown stops the job. A running key rotation is stopped after the config being rotated, and can be started again
+// to complete it.
+func (j *Job) GracefulShutdown() {
+	log.Println("Shutting down key rotation job")
+	close(j.stop)
+}
+
+// Rotate starts rewrapping every secret value with the primary key. Returns ErrRotationRunning if a rotation is already
+// running.
+func (j *Job) Rotate() error {
+	if j.keeper == nil {
+		return secret.ErrNoKey
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.progress.State == Running {
+		return ErrRotationRunning
+	}
+
+	j.progress = Progress{State: Running, Key: j.keeper.ID(), Started: time.Now()}
+	j.trigger <- true
+	return nil
+}
+
+// Progress returns the progress of the last key rotation
+func (j *Job) Progress() Progress {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.progress
+}
+
+// Verify checks every resource in the repository for secret values encrypted with other keys than the primary key
+func (j *Job) Verify() (*Verification, error) {
+	if j.keeper == nil {
+		return nil, secret.ErrNoKey
+	}
+
+	v := &Verification{Key: j.keeper.ID(), Versions: j.keeper.Versions(), Retired: []Retired{}}
+	err := j.walk(func(grp listing.Group) error {
+		if grp.Defaults != nil {
+			if n := j.keeper.Retired(grp.Defaults.Properties); n > 0 {
+				v.Retired = append(v.Retired, Retired{Resource: listing.DefaultsResource, Group: grp.ID, ID: grp.ID, Values: n})
+			}
+		}
+		return nil
+	}, func(grp listing.Group, id string) error {
+		conf, err := j.repo.RetrieveConfig(grp.ID, id)
+		if err == listing.ErrConfigNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n := j.keeper.Retired(conf.Properties); n > 0 {
+			v.Retired = append(v.Retired, Retired{Resource: listing.ConfigResource, Group: grp.ID, ID: id, Values: n})
+		}
+
+		overlays, err := j.repo.ListOverlays(grp.ID, id)
+		if err == listing.ErrConfigNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sort.Slice(overlays, func(a, b int) bool { return overlays[a].Environment < overlays[b].Environment })
+
+		for _, o := range overlays {
+			if n := j.keeper.Retired(o.Properties); n > 0 {
+				v.Retired = append(v.Retired, Retired{Resource: listing.OverlayResource, Group: grp.ID, ID: id, Environment: o.Environment, Values: n})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v.Verified = len(v.Retired) == 0
+	return v, nil
+}
+
+// run runs a key rotation and records how it went
+func (j *Job) run() {
+	log.Printf("Rotating secret values to key %s\n", j.keeper.ID())
+	err := j.walk(func(grp listing.Group) error {
+		j.lock.Lock()
+		j.progress.Configs += len(grp.Configs)
+		j.lock.Unlock()
+
+		return j.rewrapDefaults(grp.ID)
+	}, func(grp listing.Group, id string) error {
+		select {
+		case <-j.stop:
+			return errStopped
+		default:
+		}
+
+		if err := j.rewrapConfig(grp.ID, id); err != nil {
+			return err
+		}
+
+		j.lock.Lock()
+		j.progress.Rotated++
+		j.lock.Unlock()
+		return nil
+	})
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.progress.Finished = time.Now()
+	switch {
+	case err == errStopped:
+		j.progress.State = Stopped
+	case err != nil:
+		j.progress.State = Failed
+		j.progress.Error = err.Error()
+	default:
+		j.progress.State = Done
+	}
+	log.Printf("Key rotation %s, %d values rewrapped\n", j.progress.State, j.progress.Rewrapped)
+}
+
+// errStopped is used when a key rotation is stopped by shutting down the job
+var errStopped = errors.New("stopped")
+
+// walk calls grpFn for every group and then confFn for each of its configs, ordered by id. Configs are counted before any of
+// them is visited.
+func (j *Job) walk(grpFn func(grp listing.Group) error, confFn func(grp listing.Group, id string) error) error {
+	grps, err := j.repo.ListGroups("")
+	if err != nil {
+		return err
+	}
+	sort.Slice(grps, func(a, b int) bool { return grps[a].ID < grps[b].ID })
+
+	for _, grp := range grps {
+		if err := grpFn(grp); err != nil {
+			return err
+		}
+	}
+
+	for _, grp := range grps {
+		ids := make([]string, len(grp.Configs))
+		copy(ids, grp.Configs)
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			if err := confFn(grp, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (j *Job) rewrapDefaults(groupID string) error {
+	err := j.repo.UpdateDefaults(groupID, func(d *adding.Defaults) (*adding.Defaults, error) {
+		if d == nil {
+			return nil, errUnchanged
+		}
+
+		props, err := j.rewrap(d.Properties)
+		if err != nil {
+			return nil, err
+		}
+
+		return &adding.Defaults{Properties: props, Merge: d.Merge}, nil
+	})
+
+	return ignoreSkipped(err)
+}
+
+// rewrapConfig rewraps the secret values of a config and its overlays
+func (j *Job) rewrapConfig(groupID string, id string) error {
+	err := j.repo.UpdateConfig(groupID, id, func(c adding.Config, revision int64) (adding.Config, error) {
+		var err error
+		c.Properties, err = j.rewrap(c.Properties)
+		return c, err
+	})
+	if err := ignoreSkipped(err); err != nil {
+		return err
+	}
+
+	overlays, err := j.repo.ListOverlays(groupID, id)
+	if err != nil {
+		return ignoreSkipped(err)
+	}
+
+	for _, o := range overlays {
+		err := j.repo.UpdateOverlay(groupID, id, o.Environment, func(o adding.Overlay) (adding.Overlay, error) {
+			var err error
+			o.Properties, err = j.rewrap(o.Properties)
+			return o, err
+		})
+		if err := ignoreSkipped(err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewrap rewraps properties and counts the values rewrapped. Returns errUnchanged if there is nothing to rewrap.
+func (j *Job) rewrap(props json.RawMessage) (json.RawMessage, error) {
+	res, n, err := j.keeper.Rewrap(props)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errUnchanged
+	}
+
+	j.lock.Lock()
+	j.progress.Rewrapped += n
+	j.lock.Unlock()
+
+	return res, nil
+}
+
+// ignoreSkipped ignores resources with nothing to rewrap and resources deleted while rotating
+func ignoreSkipped(err error) error {
+	if err == errUnchanged || err == listing.ErrGroupNotFound || err == listing.ErrConfigNotFound || err == listing.ErrOverlayNotFound {
+		return nil
+	}
+
+	return err
+}
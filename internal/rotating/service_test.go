@@ -0,0 +1,125 @@
+package rotating_test
+
+import (
+	"bytes"
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/rotating"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/test"
+	"testing"
+	"time"
+)
+
+func newKeyring(t *testing.T, versions ...int) *secret.Keeper {
+	var keys []secret.Key
+	for _, v := range versions {
+		keys = append(keys, secret.Key{Version: v, Key: bytes.Repeat([]byte{byte(v)}, secret.KeySize)})
+	}
+
+	k, err := secret.NewKeyring(keys...)
+	test.AssertNotError(t, err)
+	return k
+}
+
+// waitFor waits for the rotation started to finish
+func waitFor(t *testing.T, job *rotating.Job) rotating.Progress {
+	for i := 0; i < 100; i++ {
+		if p := job.Progress(); p.State != rotating.Running {
+			return p
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Key rotation did not finish")
+	return rotating.Progress{}
+}
+
+func TestJob_Rotate(t *testing.T) {
+	repo := memory.NewRepository()
+	add := adding.NewService(repo, adding.Secrets(newKeyring(t, 1)))
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup", Defaults: &adding.Defaults{Properties: []byte(`{"token":{"$secret":"abc"}}`)}}))
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someOtherGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"user":"admin","password":{"$secret":"hunter2"}}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "plain", Group: "someOtherGroup", Properties: []byte(`{"user":"admin"}`)}))
+	test.AssertNotError(t, add.SetOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Properties: []byte(`{"password":{"$secret":"hunter3"}}`)}))
+
+	keyring := newKeyring(t, 1, 2)
+	job := rotating.NewJob(repo, keyring)
+	test.AssertEqual(t, job.Progress().State, rotating.Idle)
+
+	v, err := job.Verify()
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, v.Verified, false)
+	test.AssertEqual(t, len(v.Retired), 3)
+	test.AssertEqual(t, v.Retired[0], rotating.Retired{Resource: listing.DefaultsResource, Group: "someGroup", ID: "someGroup", Values: 1})
+	test.AssertEqual(t, v.Retired[1], rotating.Retired{Resource: listing.ConfigResource, Group: "someGroup", ID: "someId", Values: 1})
+	test.AssertEqual(t, v.Retired[2], rotating.Retired{Resource: listing.OverlayResource, Group: "someGroup", ID: "someId", Environment: "prod", Values: 1})
+
+	plain, err := repo.RetrieveConfig("someOtherGroup", "plain")
+	test.AssertNotError(t, err)
+
+	signal := make(chan bool, 1)
+	go job.Serve(signal)
+	defer job.GracefulShutdown()
+
+	test.AssertNotError(t, job.Rotate())
+	p := waitFor(t, job)
+	test.AssertEqual(t, p.State, rotating.Done)
+	test.AssertEqual(t, p.Key, keyring.ID())
+	test.AssertEqual(t, p.Configs, 2)
+	test.AssertEqual(t, p.Rotated, 2)
+	test.AssertEqual(t, p.Rewrapped, 3)
+
+	v, err = job.Verify()
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, v.Verified, true)
+	test.AssertEqual(t, len(v.Retired), 0)
+
+	// The values are only readable with the new key
+	lst := listing.NewService(repo, listing.Secrets(newKeyring(t, 2)))
+	conf, err := lst.GetEnvironmentConfig("someGroup", "someId", "prod")
+	test.AssertNotError(t, err)
+	props, err := lst.RevealSecrets(conf.Properties)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(props), `{"token":"abc","user":"admin","password":"hunter3"}`)
+
+	// Configs without secret values are not rewritten
+	unchanged, err := repo.RetrieveConfig("someOtherGroup", "plain")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, unchanged.Revision, plain.Revision)
+
+	// Rotating again rewraps nothing
+	test.AssertNotError(t, job.Rotate())
+	p = waitFor(t, job)
+	test.AssertEqual(t, p.State, rotating.Done)
+	test.AssertEqual(t, p.Rewrapped, 0)
+}
+
+func TestJob_Rotate_Errors(t *testing.T) {
+	repo := memory.NewRepository()
+
+	job := rotating.NewJob(repo, nil)
+	test.AssertEqual(t, job.Rotate(), secret.ErrNoKey)
+	_, err := job.Verify()
+	test.AssertEqual(t, err, secret.ErrNoKey)
+
+	// Only one rotation runs at a time
+	job = rotating.NewJob(repo, newKeyring(t, 1))
+	test.AssertNotError(t, job.Rotate())
+	test.AssertEqual(t, job.Rotate(), rotating.ErrRotationRunning)
+
+	// Values encrypted with unknown keys fail the rotation
+	add := adding.NewService(repo, adding.Secrets(newKeyring(t, 3)))
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup"}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"password":{"$secret":"hunter2"}}`)}))
+
+	signal := make(chan bool, 1)
+	go job.Serve(signal)
+	defer job.GracefulShutdown()
+
+	p := waitFor(t, job)
+	test.AssertEqual(t, p.State, rotating.Failed)
+	test.AssertEqual(t, p.Error, secret.ErrUndecryptable.Error())
+}
@@ -0,0 +1,367 @@
+// Package scheduling provides scheduled activations, which add a config prepared in advance at a given time. Activations are
+// stored in the repository, so a Scheduler started again after a restart activates the ones that became due meanwhile.
+package scheduling
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActivationResource identifies a scheduled activation in errors
+const ActivationResource = "activation"
+
+// ErrActivationNotFound is used when an activation does not exist.
+var ErrActivationNotFound = domain.New(domain.NotFound, ActivationResource, "activation not found")
+
+// ErrNotPending is used when cancelling an activation that is no longer pending.
+var ErrNotPending = domain.New(domain.FailedPrecondition, ActivationResource, "activation is not pending")
+
+// ErrConfigChanged is used when an activation is due for a config that was changed by other means than activations after
+// the activation was scheduled. The activation fails rather than overwrite the change.
+var ErrConfigChanged = domain.New(domain.FailedPrecondition, ActivationResource, "config changed after the activation was scheduled")
+
+// State is the state of an activation
+type State string
+
+// Possible states of an activation. Pending activations are either activated, cancelled or failed if the config could not be
+// added when it was due.
+const (
+	Pending   State = "pending"
+	Activated State = "activated"
+	Cancelled State = "cancelled"
+	Failed    State = "failed"
+)
+
+// Activation is a config to be added at ActivatesAt. Config is the config as it will be added, with its secret values
+// encrypted. BaseRevision is the revision of the config when the activation was scheduled, or zero if it did not exist.
+// Activated is when the activation left the pending state, and Revision the revision of the config it added.
+type Activation struct {
+	ID           int64         `json:"id"`
+	State        State         `json:"state"`
+	ActivatesAt  time.Time     `json:"activatesAt"`
+	Created      time.Time     `json:"created"`
+	Activated    time.Time     `json:"activated"`
+	BaseRevision int64         `json:"baseRevision"`
+	Revision     int64         `json:"revision,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	Config       adding.Config `json:"config"`
+}
+
+// Request is a config to be added at ActivatesAt
+type Request struct {
+	ActivatesAt time.Time     `json:"activatesAt"`
+	Config      adding.Config `json:"config"`
+}
+
+// Version is the version of a config that is active
+type Version struct {
+	Name         string    `json:"name"`
+	Version      int       `json:"version"`
+	Revision     int64     `json:"revision"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Schedule reports the active version of a config and its pending activations, ordered by when they are due. Active is nil
+// if the config does not exist yet, and Next the first pending activation or nil if there are none.
+type Schedule struct {
+	Active  *Version     `json:"active"`
+	Next    *Activation  `json:"next"`
+	Pending []Activation `json:"pending"`
+}
+
+// Service provides scheduling operations
+type Service interface {
+	Schedule(r Request) (*Activation, error)
+	GetActivation(groupID string, id string, activationID int64) (*Activation, error)
+	Cancel(groupID string, id string, activationID int64) (*Activation, error)
+	GetSchedule(groupID string, id string) (*Schedule, error)
+}
+
+// Repository provides access to repository
+type Repository interface {
+	RetrieveConfig(groupID string, id string) (*listing.Config, error)
+	// StoreActivation stores a new activation and returns the id assigned to it
+	StoreActivation(a Activation) (int64, error)
+	RetrieveActivation(id int64) (*Activation, error)
+	// ListActivations lists the activations of a config, or of every config if groupID and id are empty, ordered by id
+	ListActivations(groupID string, id string) ([]Activation, error)
+	// UpdateActivation replaces an existing activation with the one returned by update, which is given the current
+	// activation. No other changes can be made to the activation while update runs, and update cannot use the repository.
+	UpdateActivation(id int64, update func(a Activation) (Activation, error)) error
+}
+
+// Scheduler is the Service, and activates pending activations when they are due while run by a runner.Runner. Activations
+// due while the scheduler was not running are activated when it starts.
+type Scheduler struct {
+	repo   Repository
+	adding adding.Service
+
+	wake chan bool
+	stop chan bool
+
+	// lock serializes activating and cancelling, so an activation is never cancelled while it is being activated
+	lock sync.Mutex
+}
+
+// NewScheduler returns a new Scheduler adding configs with add
+func NewScheduler(r Repository, add adding.Service) *Scheduler {
+	return &Scheduler{
+		repo:   r,
+		adding: add,
+		wake:   make(chan bool, 1),
+		stop:   make(chan bool),
+	}
+}
+
+// Serve activates pending activations when they are due, until shut down. Like the key rotation job it never sends on signal,
+// and while the repository fails the due activations are retried every minute.
+func (s *Scheduler) Serve(signal chan bool) {
+	log.Println("Starting scheduler")
+	for {
+		next, err := s.ActivateDue(time.Now())
+		if err != nil {
+			log.Printf("Error activating scheduled configs: %v", err)
+			// Try again later rather than giving up on the activations
+			next = time.Now().Add(time.Minute)
+		}
+
+		// Without pending activations the scheduler sleeps until one is scheduled
+		var timer *time.Timer
+		var due <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			due = timer.C
+		}
+
+		select {
+		case <-due:
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// GracefulShutdown stops the scheduler. Activations due while it is stopped are activated when it is started again.
+func (s *Scheduler) GracefulShutdown() {
+	log.Println("Shutting down scheduler")
+	close(s.stop)
+}
+
+// Schedule stores a config to be added at a time in the future, if the config is valid like when adding it. The config is
+// validated again when it is added, and is only added if it was not changed by other means than activations meanwhile.
+func (s *Scheduler) Schedule(r Request) (*Activation, error) {
+	if r.ActivatesAt.IsZero() {
+		return nil, invalidField("activatesAt", "is required")
+	}
+
+	now := time.Now()
+	if !r.ActivatesAt.After(now) {
+		return nil, invalidField("activatesAt", "has to be in the future")
+	}
+
+	var base int64
+	current, err := s.repo.RetrieveConfig(r.Config.Group, r.Config.ID)
+	switch err {
+	case nil:
+		base = current.Revision
+	case listing.ErrGroupNotFound, listing.ErrConfigNotFound:
+	default:
+		return nil, err
+	}
+
+	conf, err := s.adding.PrepareConfig(r.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	a := Activation{
+		State:        Pending,
+		ActivatesAt:  r.ActivatesAt,
+		Created:      now,
+		BaseRevision: base,
+		Config:       conf,
+	}
+
+	if a.ID, err = s.repo.StoreActivation(a); err != nil {
+		return nil, err
+	}
+
+	s.reschedule()
+	return &a, nil
+}
+
+// GetActivation gets an activation of a config
+func (s *Scheduler) GetActivation(groupID string, id string, activationID int64) (*Activation, error) {
+	a, err := s.repo.RetrieveActivation(activationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Config.Group != groupID || a.Config.ID != id {
+		return nil, ErrActivationNotFound
+	}
+
+	return a, nil
+}
+
+// Cancel cancels a pending activation of a config. Returns ErrNotPending if it is activated, cancelled or failed already.
+func (s *Scheduler) Cancel(groupID string, id string, activationID int64) (*Activation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := s.GetActivation(groupID, id, activationID); err != nil {
+		return nil, err
+	}
+
+	var cancelled Activation
+	err := s.repo.UpdateActivation(activationID, func(a Activation) (Activation, error) {
+		if a.State != Pending {
+			return a, ErrNotPending
+		}
+
+		a.State = Cancelled
+		a.Activated = time.Now()
+		cancelled = a
+		return a, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reschedule()
+	return &cancelled, nil
+}
+
+// GetSchedule gets the active version of a config and its pending activations. Returns listing.ErrConfigNotFound if the
+// config neither exists nor has pending activations.
+func (s *Scheduler) GetSchedule(groupID string, id string) (*Schedule, error) {
+	as, err := s.repo.ListActivations(groupID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Schedule{Pending: pending(as)}
+	if len(res.Pending) > 0 {
+		res.Next = &res.Pending[0]
+	}
+
+	conf, err := s.repo.RetrieveConfig(groupID, id)
+	switch err {
+	case nil:
+		res.Active = &Version{Name: conf.Name, Version: conf.Version, Revision: conf.Revision, LastModified: conf.LastModified}
+	case listing.ErrConfigNotFound:
+		if res.Next == nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ActivateDue activates every pending activation due at now, in the order they are due, and returns when the next pending
+// activation is due, or the zero time if there are none. An activation whose config cannot be added fails, and does not stop
+// the others.
+func (s *Scheduler) ActivateDue(now time.Time) (time.Time, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	as, err := s.repo.ListActivations("", "")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, a := range pending(as) {
+		if a.ActivatesAt.After(now) {
+			return a.ActivatesAt, nil
+		}
+
+		if err := s.activate(a); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+// activate adds the config of an activation and records how it went. The config has to be at the revision it was scheduled
+// against, or at the revision added by the last activation of the config after that, or the activation fails with
+// ErrConfigChanged.
+func (s *Scheduler) activate(a Activation) error {
+	conf := a.Config
+	conf.LastModified = time.Now()
+
+	as, err := s.repo.ListActivations(conf.Group, conf.ID)
+	if err != nil {
+		return err
+	}
+
+	// Activations are added in the order they are due, so the last one added has the highest revision
+	expected := a.BaseRevision
+	for _, other := range as {
+		if other.State == Activated && other.Revision > expected {
+			expected = other.Revision
+		}
+	}
+
+	state, msg := Activated, ""
+	var revision int64
+	revisions, err := s.adding.Commit([]adding.Operation{{Type: adding.PutConfig, Config: conf, Revision: &expected}})
+	if oe, ok := err.(*adding.OperationError); ok {
+		err = oe.Err
+	}
+	if err == adding.ErrRevisionMismatch {
+		err = ErrConfigChanged
+	}
+	if err != nil {
+		state, msg = Failed, err.Error()
+		log.Printf("Activation %d of config %s/%s failed: %v", a.ID, conf.Group, conf.ID, err)
+	} else {
+		revision = revisions[0]
+		log.Printf("Activated config %s/%s at revision %d", conf.Group, conf.ID, revision)
+	}
+
+	return s.repo.UpdateActivation(a.ID, func(a Activation) (Activation, error) {
+		a.State = state
+		a.Activated = conf.LastModified
+		a.Revision = revision
+		a.Error = msg
+		return a, nil
+	})
+}
+
+// reschedule wakes the scheduler up to find when the next activation is due
+func (s *Scheduler) reschedule() {
+	select {
+	case s.wake <- true:
+	default:
+	}
+}
+
+// pending returns the pending activations ordered by when they are due
+func pending(as []Activation) []Activation {
+	res := []Activation{}
+	for _, a := range as {
+		if a.State == Pending {
+			res = append(res, a)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].ActivatesAt.Before(res[j].ActivatesAt) })
+	return res
+}
+
+func invalidField(field, reason string) error {
+	return adding.InvalidFieldError{Resource: ActivationResource, Field: field, Reason: reason}
+}
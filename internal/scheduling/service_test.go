@@ -0,0 +1,216 @@
+package scheduling_test
+
+import (
+	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/repository/memory"
+	"github.com/larwef/ki/internal/scheduling"
+	"github.com/larwef/ki/test"
+	"testing"
+	"time"
+)
+
+func newScheduler(t *testing.T) (*memory.Repository, adding.Service, *scheduling.Scheduler) {
+	repo := memory.NewRepository()
+	add := adding.NewService(repo)
+	test.AssertNotError(t, add.AddGroup(adding.Group{ID: "someGroup", Schema: []byte(`{"properties":{"port":{"type":"integer"}}}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 1, Properties: []byte(`{"host":"db1"}`)}))
+
+	return repo, add, scheduling.NewScheduler(repo, add)
+}
+
+func TestScheduler_ActivateDue(t *testing.T) {
+	repo, _, scheduler := newScheduler(t)
+	now := time.Now()
+
+	_, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(-time.Minute), Config: adding.Config{ID: "someId", Group: "someGroup"}})
+	test.AssertEqual(t, domain.From(err).Kind, domain.InvalidArgument)
+
+	// Configs are validated when they are scheduled
+	_, err = scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"port":"5432"}`)}})
+	test.AssertEqual(t, domain.From(err).Kind, domain.ValidationFailed)
+
+	later, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(2 * time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Version: 3, Properties: []byte(`{"host":"db3"}`)}})
+	test.AssertNotError(t, err)
+	first, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, first.State, scheduling.Pending)
+
+	schedule, err := scheduler.GetSchedule("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Active.Version, 1)
+	test.AssertEqual(t, schedule.Next.ID, first.ID)
+	test.AssertEqual(t, len(schedule.Pending), 2)
+	test.AssertEqual(t, schedule.Pending[1].ID, later.ID)
+
+	next, err := scheduler.ActivateDue(now)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, next.Equal(first.ActivatesAt), true)
+
+	next, err = scheduler.ActivateDue(now.Add(90 * time.Minute))
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, next.Equal(later.ActivatesAt), true)
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, conf.Version, 2)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db2"}`)
+
+	a, err := scheduler.GetActivation("someGroup", "someId", first.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, scheduling.Activated)
+	test.AssertEqual(t, a.Revision, conf.Revision)
+
+	schedule, err = scheduler.GetSchedule("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Active.Version, 2)
+	test.AssertEqual(t, schedule.Next.ID, later.ID)
+
+	_, err = scheduler.Cancel("someGroup", "someId", first.ID)
+	test.AssertEqual(t, err, scheduling.ErrNotPending)
+
+	_, err = scheduler.Cancel("someGroup", "someOtherId", later.ID)
+	test.AssertEqual(t, err, scheduling.ErrActivationNotFound)
+
+	a, err = scheduler.Cancel("someGroup", "someId", later.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, scheduling.Cancelled)
+
+	next, err = scheduler.ActivateDue(now.Add(3 * time.Hour))
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, next.IsZero(), true)
+
+	schedule, err = scheduler.GetSchedule("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Active.Version, 2)
+	test.AssertEqual(t, schedule.Next == nil, true)
+	test.AssertEqual(t, len(schedule.Pending), 0)
+
+	_, err = scheduler.GetSchedule("someGroup", "someOtherId")
+	test.AssertEqual(t, err, listing.ErrConfigNotFound)
+}
+
+func TestScheduler_Failed(t *testing.T) {
+	repo, add, scheduler := newScheduler(t)
+	now := time.Now()
+
+	created, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "newId", Group: "someGroup", Properties: []byte(`{"port":5432}`)}})
+	test.AssertNotError(t, err)
+	failing, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+
+	// Configs scheduled before the config exists report only the pending version
+	schedule, err := scheduler.GetSchedule("someGroup", "newId")
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, schedule.Active == nil, true)
+	test.AssertEqual(t, schedule.Next.ID, created.ID)
+
+	// The config no longer satisfies the schema when it is due
+	test.AssertNotError(t, add.SetSchema("someGroup", []byte(`{"required":["port"]}`)))
+
+	// A scheduler started after the activations were due activates them
+	_, err = scheduling.NewScheduler(repo, add).ActivateDue(now.Add(2 * time.Hour))
+	test.AssertNotError(t, err)
+
+	a, err := scheduler.GetActivation("someGroup", "someId", failing.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, scheduling.Failed)
+	test.AssertEqual(t, a.Error, "properties do not satisfy the schema of the group")
+
+	a, err = scheduler.GetActivation("someGroup", "newId", created.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, scheduling.Activated)
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db1"}`)
+}
+
+func TestScheduler_ConfigChanged(t *testing.T) {
+	repo, add, scheduler := newScheduler(t)
+	now := time.Now()
+
+	conflicting, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+	created, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "newId", Group: "someGroup", Properties: []byte(`{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+
+	// The configs are changed by other means than activations before they are due
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 5, Properties: []byte(`{"host":"db5"}`)}))
+	test.AssertNotError(t, add.AddConfig(adding.Config{ID: "newId", Group: "someGroup", Properties: []byte(`{"host":"db5"}`)}))
+
+	_, err = scheduler.ActivateDue(now.Add(2 * time.Hour))
+	test.AssertNotError(t, err)
+
+	for _, c := range []struct {
+		id         string
+		activation int64
+	}{{"someId", conflicting.ID}, {"newId", created.ID}} {
+		a, err := scheduler.GetActivation("someGroup", c.id, c.activation)
+		test.AssertNotError(t, err)
+		test.AssertEqual(t, a.State, scheduling.Failed)
+		test.AssertEqual(t, a.Error, scheduling.ErrConfigChanged.Error())
+
+		conf, err := repo.RetrieveConfig("someGroup", c.id)
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db5"}`)
+	}
+}
+
+func TestScheduler_ApprovalRequired(t *testing.T) {
+	repo, _, scheduler := newScheduler(t)
+	now := time.Now()
+
+	pending, err := scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db2"}`)}})
+	test.AssertNotError(t, err)
+
+	// Configs in a group with approvers are only changed by applying approved change requests, so they cannot be scheduled
+	test.AssertNotError(t, repo.StoreApprovers("someGroup", []string{"bob"}))
+	_, err = scheduler.Schedule(scheduling.Request{ActivatesAt: now.Add(time.Hour), Config: adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db3"}`)}})
+	test.AssertEqual(t, err, adding.ErrApprovalRequired)
+
+	// and activations scheduled before the group had approvers fail
+	_, err = scheduler.ActivateDue(now.Add(2 * time.Hour))
+	test.AssertNotError(t, err)
+
+	a, err := scheduler.GetActivation("someGroup", "someId", pending.ID)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, a.State, scheduling.Failed)
+	test.AssertEqual(t, a.Error, adding.ErrApprovalRequired.Error())
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(conf.Properties), `{"host":"db1"}`)
+}
+
+func TestScheduler_Serve(t *testing.T) {
+	repo, _, scheduler := newScheduler(t)
+
+	signal := make(chan bool, 1)
+	done := make(chan bool)
+	go func() {
+		scheduler.Serve(signal)
+		close(done)
+	}()
+
+	// The scheduler is woken up by activations scheduled while it waits
+	_, err := scheduler.Schedule(scheduling.Request{ActivatesAt: time.Now().Add(50 * time.Millisecond), Config: adding.Config{ID: "someId", Group: "someGroup", Version: 2}})
+	test.AssertNotError(t, err)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conf, err := repo.RetrieveConfig("someGroup", "someId")
+		test.AssertNotError(t, err)
+		if conf.Version == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Config was not activated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	scheduler.GracefulShutdown()
+	<-done
+}
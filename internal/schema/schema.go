@@ -0,0 +1,173 @@
+// Package schema implements validation of JSON documents against a subset of JSON Schema draft 2020-12. Supported keywords
+// are type, enum, const, required, properties, additionalProperties, items, minItems, maxItems, uniqueItems, pattern,
+// minLength, maxLength, minimum, maximum, exclusiveMinimum and exclusiveMaximum. Other keywords are ignored.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"regexp"
+)
+
+// InvalidSchemaError is used when a schema is not valid JSON or uses a supported keyword wrongly.
+type InvalidSchemaError string
+
+func (i InvalidSchemaError) Error() string {
+	return "invalid schema: " + string(i)
+}
+
+// Describe describes the error as an invalid argument
+func (i InvalidSchemaError) Describe() *domain.Error {
+	return domain.New(domain.InvalidArgument, "schema", i.Error())
+}
+
+var types = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	// allow is set for the boolean schemas true and false
+	allow *bool
+
+	types      []string
+	enum       []interface{}
+	constant   interface{}
+	hasConst   bool
+	required   []string
+	properties map[string]*Schema
+	additional *Schema
+	items      *Schema
+	minItems   *int
+	maxItems   *int
+	unique     bool
+	pattern    *regexp.Regexp
+	minLength  *int
+	maxLength  *int
+	minimum    *float64
+	maximum    *float64
+	exclMin    *float64
+	exclMax    *float64
+	// secret marks the values matched by the schema as secret
+	secret bool
+}
+
+// keywords is the supported part of a schema object as it is read from JSON
+type keywords struct {
+	Type                 json.RawMessage            `json:"type"`
+	Enum                 []interface{}              `json:"enum"`
+	Const                json.RawMessage            `json:"const"`
+	Required             []string                   `json:"required"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+	UniqueItems          bool                       `json:"uniqueItems"`
+	Pattern              *string                    `json:"pattern"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	ExclusiveMinimum     *float64                   `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64                   `json:"exclusiveMaximum"`
+	Secret               bool                       `json:"secret"`
+}
+
+// Compile parses a schema
+func Compile(raw json.RawMessage) (*Schema, error) {
+	return compile(raw, properties.Pointer{})
+}
+
+// compile parses the schema at location, which is used in error messages
+func compile(raw json.RawMessage, location properties.Pointer) (*Schema, error) {
+	var allow bool
+	if err := json.Unmarshal(raw, &allow); err == nil {
+		return &Schema{allow: &allow}, nil
+	}
+
+	at := location.String()
+	var k keywords
+	if err := json.Unmarshal(raw, &k); err != nil {
+		return nil, InvalidSchemaError(fmt.Sprintf("schema at %q is not a valid schema object: %v", at, err))
+	}
+
+	s := &Schema{
+		enum:      k.Enum,
+		required:  k.Required,
+		minItems:  k.MinItems,
+		maxItems:  k.MaxItems,
+		unique:    k.UniqueItems,
+		minLength: k.MinLength,
+		maxLength: k.MaxLength,
+		minimum:   k.Minimum,
+		maximum:   k.Maximum,
+		exclMin:   k.ExclusiveMinimum,
+		exclMax:   k.ExclusiveMaximum,
+		secret:    k.Secret,
+	}
+
+	if len(k.Type) > 0 {
+		var t string
+		if err := json.Unmarshal(k.Type, &t); err == nil {
+			s.types = []string{t}
+		} else if err := json.Unmarshal(k.Type, &s.types); err != nil {
+			return nil, InvalidSchemaError(fmt.Sprintf("type at %q has to be a string or an array of strings", at))
+		}
+
+		for _, t := range s.types {
+			if !types[t] {
+				return nil, InvalidSchemaError(fmt.Sprintf("unknown type %q at %q", t, at))
+			}
+		}
+	}
+
+	if k.Const != nil {
+		s.hasConst = true
+		if err := json.Unmarshal(k.Const, &s.constant); err != nil {
+			return nil, InvalidSchemaError(fmt.Sprintf("const at %q is not valid JSON", at))
+		}
+	}
+
+	if k.Pattern != nil {
+		var err error
+		if s.pattern, err = regexp.Compile(*k.Pattern); err != nil {
+			return nil, InvalidSchemaError(fmt.Sprintf("invalid pattern %q at %q", *k.Pattern, at))
+		}
+	}
+
+	if len(k.Properties) > 0 {
+		s.properties = make(map[string]*Schema)
+		for name, raw := range k.Properties {
+			sub, err := compile(raw, location.Child("properties").Child(name))
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = sub
+		}
+	}
+
+	if len(k.AdditionalProperties) > 0 {
+		var err error
+		if s.additional, err = compile(k.AdditionalProperties, location.Child("additionalProperties")); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(k.Items) > 0 {
+		var err error
+		if s.items, err = compile(k.Items, location.Child("items")); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
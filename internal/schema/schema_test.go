@@ -0,0 +1,132 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/schema"
+	"github.com/larwef/ki/test"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const databaseSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["database"],
+	"properties": {
+		"database": {
+			"type": "object",
+			"required": ["host", "port"],
+			"properties": {
+				"host": {"type": "string", "pattern": "^db", "minLength": 3},
+				"port": {"type": "integer", "minimum": 1, "exclusiveMaximum": 65536},
+				"driver": {"enum": ["postgres", "mysql"]}
+			},
+			"additionalProperties": false
+		},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true}
+	}
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	sch, err := schema.Compile([]byte(databaseSchema))
+	test.AssertNotError(t, err)
+
+	err = sch.Validate([]byte(`{"database": {"host": "db1", "port": 5432, "driver": "postgres"}, "tags": ["a", "b"]}`))
+	test.AssertNotError(t, err)
+
+	tests := map[string][]schema.Violation{
+		`[]`:      {{Path: "", Message: "expected object but got array"}},
+		`{}`:      {{Path: "", Message: `missing required property "database"`}},
+		`{"a": 1`: {{Path: "", Message: "document is not valid JSON"}},
+		`{"tags": ["a", "a", 1]}`: {
+			{Path: "", Message: `missing required property "database"`},
+			{Path: "/tags", Message: "expected at most 2 items but got 3"},
+			{Path: "/tags", Message: "items are not unique"},
+			{Path: "/tags/2", Message: "expected string but got integer"},
+		},
+		`{"database": {"host": "db1", "port": 5432.5}}`: {
+			{Path: "/database/port", Message: "expected integer but got number"},
+		},
+		`{"database": {"host": "x", "port": 65536, "driver": "sqlite", "user": "u"}}`: {
+			{Path: "/database/driver", Message: "value is not one of the allowed values"},
+			{Path: "/database/host", Message: "expected at least 3 characters but got 1"},
+			{Path: "/database/host", Message: `value does not match pattern "^db"`},
+			{Path: "/database/port", Message: "expected a value < 65536 but got 65536"},
+			{Path: "/database/user", Message: "value is not allowed"},
+		},
+	}
+
+	for doc, expected := range tests {
+		err := sch.Validate([]byte(doc))
+		validationErr, ok := err.(schema.ValidationError)
+		if !ok {
+			t.Fatalf("Expected validation error for %s, got %v", doc, err)
+		}
+
+		test.AssertEqual(t, len(validationErr.Violations), len(expected))
+		for i, v := range validationErr.Violations {
+			test.AssertEqual(t, v, expected[i])
+		}
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`{"type": "text"}`:                        `invalid schema: unknown type "text" at ""`,
+		`{"properties": {"a": {"pattern": "("}}}`: `invalid schema: invalid pattern "(" at "/properties/a"`,
+		`{"minLength": "1"}`:                      "",
+		`[]`:                                      "",
+	}
+
+	for raw, expected := range tests {
+		_, err := schema.Compile([]byte(raw))
+		test.AssertIsError(t, err)
+
+		if _, ok := err.(schema.InvalidSchemaError); !ok {
+			t.Fatalf("Expected invalid schema error for %s, got %v", raw, err)
+		}
+		if expected != "" {
+			test.AssertEqual(t, err.Error(), expected)
+		}
+	}
+}
+
+func TestCompile_BooleanSchema(t *testing.T) {
+	sch, err := schema.Compile([]byte(`true`))
+	test.AssertNotError(t, err)
+	test.AssertNotError(t, sch.Validate([]byte(`{"anything": 1}`)))
+
+	sch, err = schema.Compile([]byte(`false`))
+	test.AssertNotError(t, err)
+	test.AssertIsError(t, sch.Validate([]byte(`{}`)))
+}
+
+func TestSchema_Secrets(t *testing.T) {
+	sch, err := schema.Compile([]byte(`{
+		"properties": {
+			"password": {"type": "string", "secret": true},
+			"database": {"properties": {"token": {"secret": true}}},
+			"keys": {"type": "array", "items": {"secret": true}}
+		},
+		"additionalProperties": {"properties": {"apiKey": {"secret": true}}}
+	}`))
+	test.AssertNotError(t, err)
+
+	var doc interface{}
+	test.AssertNotError(t, json.Unmarshal([]byte(`{
+		"user": "admin",
+		"password": "hunter2",
+		"database": {"host": "db1", "token": {"a": 1}},
+		"keys": ["a", "b"],
+		"service": {"apiKey": "c"}
+	}`), &doc))
+
+	var secrets []string
+	for _, p := range sch.Secrets(doc) {
+		secrets = append(secrets, p.String())
+	}
+	sort.Strings(secrets)
+
+	test.AssertEqual(t, strings.Join(secrets, " "), "/database/token /keys/0 /keys/1 /password /service/apiKey")
+}
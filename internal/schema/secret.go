@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"github.com/larwef/ki/internal/properties"
+	"strconv"
+)
+
+// Secrets returns the pointers to the values in doc matched by a schema with the "secret" keyword set to true. Values within
+// a secret value are not looked at.
+func (s *Schema) Secrets(doc interface{}) []properties.Pointer {
+	var res []properties.Pointer
+	s.secrets(doc, properties.Pointer{}, &res)
+	return res
+}
+
+func (s *Schema) secrets(v interface{}, at properties.Pointer, res *[]properties.Pointer) {
+	if s == nil || s.allow != nil {
+		return
+	}
+
+	if s.secret {
+		*res = append(*res, at)
+		return
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for name, child := range val {
+			if sub, ok := s.properties[name]; ok {
+				sub.secrets(child, at.Child(name), res)
+			} else {
+				s.additional.secrets(child, at.Child(name), res)
+			}
+		}
+	case []interface{}:
+		for i, child := range val {
+			s.items.secrets(child, at.Child(strconv.Itoa(i)), res)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/properties"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation describes where and how a document does not satisfy a schema. Path is a JSON Pointer to the offending value.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is used when a document does not satisfy a schema. It holds every violation found.
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (v ValidationError) Error() string {
+	msgs := make([]string, len(v.Violations))
+	for i, violation := range v.Violations {
+		msgs[i] = fmt.Sprintf("%q: %s", violation.Path, violation.Message)
+	}
+
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+// Validate validates a JSON document against the schema. Returns a ValidationError if the document is not valid JSON or does
+// not satisfy the schema. An empty document is treated as null.
+func (s *Schema) Validate(doc json.RawMessage) error {
+	var v interface{}
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &v); err != nil {
+			return ValidationError{Violations: []Violation{{Path: "", Message: "document is not valid JSON"}}}
+		}
+	}
+
+	var violations []Violation
+	s.validate(properties.Pointer{}, v, &violations)
+	if len(violations) > 0 {
+		return ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (s *Schema) validate(p properties.Pointer, v interface{}, violations *[]Violation) {
+	violate := func(format string, args ...interface{}) {
+		*violations = append(*violations, Violation{Path: p.String(), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.allow != nil {
+		if !*s.allow {
+			violate("value is not allowed")
+		}
+		return
+	}
+
+	if len(s.types) > 0 && !hasType(v, s.types) {
+		violate("expected %s but got %s", strings.Join(s.types, " or "), typeOf(v))
+		return
+	}
+
+	if s.enum != nil && !contains(s.enum, v) {
+		violate("value is not one of the allowed values")
+	}
+
+	if s.hasConst && !reflect.DeepEqual(s.constant, v) {
+		violate("value is not equal to the constant")
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(p, val, violations, violate)
+	case []interface{}:
+		s.validateArray(p, val, violations, violate)
+	case string:
+		s.validateString(val, violate)
+	case float64:
+		s.validateNumber(val, violate)
+	}
+}
+
+func (s *Schema) validateObject(p properties.Pointer, obj map[string]interface{}, violations *[]Violation, violate func(string, ...interface{})) {
+	for _, name := range s.required {
+		if _, exists := obj[name]; !exists {
+			violate("missing required property %q", name)
+		}
+	}
+
+	// Properties are visited in order to give a stable list of violations
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if sub, exists := s.properties[name]; exists {
+			sub.validate(p.Child(name), obj[name], violations)
+		} else if s.additional != nil {
+			s.additional.validate(p.Child(name), obj[name], violations)
+		}
+	}
+}
+
+func (s *Schema) validateArray(p properties.Pointer, arr []interface{}, violations *[]Violation, violate func(string, ...interface{})) {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		violate("expected at least %d items but got %d", *s.minItems, len(arr))
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		violate("expected at most %d items but got %d", *s.maxItems, len(arr))
+	}
+
+	if s.unique {
+		for i := 1; i < len(arr); i++ {
+			if contains(arr[:i], arr[i]) {
+				violate("items are not unique")
+				break
+			}
+		}
+	}
+
+	if s.items != nil {
+		for i, item := range arr {
+			s.items.validate(p.Child(fmt.Sprint(i)), item, violations)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, violate func(string, ...interface{})) {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		violate("expected at least %d characters but got %d", *s.minLength, length)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		violate("expected at most %d characters but got %d", *s.maxLength, length)
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		violate("value does not match pattern %q", s.pattern.String())
+	}
+}
+
+func (s *Schema) validateNumber(n float64, violate func(string, ...interface{})) {
+	if s.minimum != nil && n < *s.minimum {
+		violate("expected a value >= %v but got %v", *s.minimum, n)
+	}
+	if s.maximum != nil && n > *s.maximum {
+		violate("expected a value <= %v but got %v", *s.maximum, n)
+	}
+	if s.exclMin != nil && n <= *s.exclMin {
+		violate("expected a value > %v but got %v", *s.exclMin, n)
+	}
+	if s.exclMax != nil && n >= *s.exclMax {
+		violate("expected a value < %v but got %v", *s.exclMax, n)
+	}
+}
+
+// hasType checks if the value is of one of the types. Numbers without a fractional part are integers.
+func hasType(v interface{}, types []string) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func contains(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package secret
+
+import (
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+)
+
+// hidden is a secret value compared by Diff as a whole, without revealing it
+type hidden struct {
+	value interface{}
+}
+
+// Diff returns the differences between two versions of properties with secret values masked. A secret value is changed when
+// it is written again, since it is then encrypted with a new data-encryption key. Empty properties are an empty object.
+func Diff(from json.RawMessage, to json.RawMessage) ([]properties.Difference, error) {
+	a, err := decodeForDiff(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := decodeForDiff(to)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := properties.Diff(a, b)
+	for i := range diffs {
+		diffs[i].From = reveal(diffs[i].From)
+		diffs[i].To = reveal(diffs[i].To)
+	}
+
+	return diffs, nil
+}
+
+func decodeForDiff(props json.RawMessage) (interface{}, error) {
+	if len(props) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil, err
+	}
+
+	return hide(doc), nil
+}
+
+// hide replaces every value wrapped as a secret or encrypted by a hidden value
+func hide(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if isWrapped(val, SecretKey) || isWrapped(val, EncryptedKey) {
+			return hidden{value: val}
+		}
+
+		for k, child := range val {
+			val[k] = hide(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = hide(child)
+		}
+	}
+
+	return v
+}
+
+// reveal replaces every hidden value by Mask
+func reveal(v interface{}) interface{} {
+	switch val := v.(type) {
+	case hidden:
+		return Mask
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = reveal(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = reveal(child)
+		}
+	}
+
+	return v
+}
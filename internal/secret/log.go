@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// Redacted replaces payloads with secrets that cannot be masked when logged
+const Redacted = "[redacted, has secrets]"
+
+// Omitted replaces the payloads of writes when logged. Properties the schema of their group marks secret are written as plain
+// values and only encrypted when stored, so they cannot be told apart from other values in the payload.
+const Omitted = "[omitted, write]"
+
+// MaskPayload returns a request or response payload to be logged with secret values masked. In JSON payloads every object
+// wrapping a secret or encrypted value is masked, and so are strings holding secrets, like JSON or base64 encoded properties
+// in gRPC messages. Other payloads holding secrets are redacted.
+func MaskPayload(payload []byte) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		if hasSecret(payload) {
+			return []byte(Redacted)
+		}
+		return payload
+	}
+
+	masked, changed := maskPayload(doc)
+	if !changed {
+		return payload
+	}
+
+	b, err := json.Marshal(masked)
+	if err != nil {
+		return []byte(Redacted)
+	}
+
+	return b
+}
+
+// maskPayload masks the secrets in a decoded payload and reports whether any was found
+func maskPayload(v interface{}) (interface{}, bool) {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if _, ok := val[SecretKey]; ok {
+			return Mask, true
+		}
+		if _, ok := val[EncryptedKey]; ok {
+			return Mask, true
+		}
+
+		for k, child := range val {
+			c, ch := maskPayload(child)
+			val[k] = c
+			changed = changed || ch
+		}
+	case []interface{}:
+		for i, child := range val {
+			c, ch := maskPayload(child)
+			val[i] = c
+			changed = changed || ch
+		}
+	case string:
+		if hasSecret([]byte(val)) {
+			return Mask, true
+		}
+
+		// Bytes fields are base64 encoded when marshalling messages to JSON
+		if b, err := base64.StdEncoding.DecodeString(val); err == nil && hasSecret(b) {
+			return Mask, true
+		}
+	}
+
+	return v, changed
+}
+
+func hasSecret(b []byte) bool {
+	s := string(b)
+	return strings.Contains(s, SecretKey) || strings.Contains(s, EncryptedKey)
+}
@@ -0,0 +1,243 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// placeholderPattern matches placeholders for secret values kept outside of ki, like "${secret:env:DB_PASS}". The part after
+// "secret:" is the provider and the name of the secret within it. A placeholder escaped as "$${secret:...}" is kept as the
+// literal "${secret:...}".
+var placeholderPattern = regexp.MustCompile(`\$?\$\{secret:([a-z]+):([^}]*)\}`)
+
+// placeholderPrefix is what every placeholder starts with, used to skip properties without placeholders
+var placeholderPrefix = []byte("${secret:")
+
+// ErrSecretNotFound is returned by a Provider that has no secret with a name.
+var ErrSecretNotFound = domain.New(domain.NotFound, "", "secret not found")
+
+// UnresolvedSecretError is used when a placeholder in the properties of a config cannot be resolved. Field is the JSON Pointer
+// to the property holding the placeholder in the config read.
+type UnresolvedSecretError struct {
+	Field  string
+	Reason string
+}
+
+func (u UnresolvedSecretError) Error() string {
+	return fmt.Sprintf("unresolved secret at %s: %s", u.Field, u.Reason)
+}
+
+// Describe describes the error as a failed precondition with the placeholder as the violation
+func (u UnresolvedSecretError) Describe() *domain.Error {
+	return domain.New(domain.FailedPrecondition, "config", "unresolved secret", domain.Violation{Field: u.Field, Description: u.Reason})
+}
+
+// Provider provides secret values kept outside of ki by name. Returns ErrSecretNotFound if it has no secret with the name.
+type Provider interface {
+	Secret(name string) (string, error)
+}
+
+// FileProvider provides the contents of the files in Dir as secrets, named by their path relative to Dir, like Docker and
+// Kubernetes secrets mounted at /run/secrets. A trailing newline is removed.
+type FileProvider struct {
+	Dir string
+}
+
+// Secret returns the contents of a file. Paths outside Dir are not found.
+func (f FileProvider) Secret(name string) (string, error) {
+	dir, err := filepath.Abs(f.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	// Names can be given absolute, like "/run/secrets/db", or relative to the directory
+	path := filepath.Clean(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", ErrSecretNotFound
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+}
+
+// EnvProvider provides environment variables starting with Prefix as secrets. An empty Prefix provides every variable.
+type EnvProvider struct {
+	Prefix string
+}
+
+// Secret returns the value of an environment variable
+func (e EnvProvider) Secret(name string) (string, error) {
+	if !strings.HasPrefix(name, e.Prefix) {
+		return "", ErrSecretNotFound
+	}
+
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+
+	return val, nil
+}
+
+// Resolver resolves placeholders for secret values in properties through the providers registered with it, caching the values
+// for a time to live. A nil Resolver has no providers.
+type Resolver struct {
+	ttl       time.Duration
+	providers map[string]Provider
+
+	lock  sync.Mutex
+	cache map[string]cached
+}
+
+type cached struct {
+	value   string
+	expires time.Time
+}
+
+// NewResolver returns a Resolver without providers caching values for ttl. Values are not cached if ttl is 0.
+func NewResolver(ttl time.Duration) *Resolver {
+	return &Resolver{ttl: ttl, providers: make(map[string]Provider), cache: make(map[string]cached)}
+}
+
+// Register registers a provider used for placeholders naming it, like "file" for "${secret:file:db}"
+func (r *Resolver) Register(name string, p Provider) {
+	r.providers[name] = p
+}
+
+// HasPlaceholders reports whether properties have placeholders for secret values
+func HasPlaceholders(props json.RawMessage) bool {
+	return bytes.Contains(props, placeholderPrefix)
+}
+
+// Resolve replaces the placeholders in the property at p in the properties of a config with the secret values. An empty
+// pointer resolves all properties. Secret values are strings, and placeholders within a longer string are interpolated.
+// Returns an UnresolvedSecretError for the first placeholder that cannot be resolved.
+func (r *Resolver) Resolve(p properties.Pointer, props json.RawMessage) (json.RawMessage, error) {
+	if !HasPlaceholders(props) {
+		return props, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := r.value(p, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// value resolves the placeholders in a value found at a property. Object members are resolved in order so the same
+// placeholder fails first every time.
+func (r *Resolver) value(at properties.Pointer, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			c, err := r.value(at.Child(k), val[k])
+			if err != nil {
+				return nil, err
+			}
+			val[k] = c
+		}
+	case []interface{}:
+		for i, child := range val {
+			c, err := r.value(at.Child(strconv.Itoa(i)), child)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = c
+		}
+	case string:
+		return r.str(at, val)
+	}
+
+	return v, nil
+}
+
+// str resolves the placeholders in a string
+func (r *Resolver) str(at properties.Pointer, s string) (string, error) {
+	var err error
+	res := placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if err != nil {
+			return m
+		}
+		if m[1] == '$' {
+			return m[1:]
+		}
+
+		sub := placeholderPattern.FindStringSubmatch(m)
+		var val string
+		if val, err = r.secret(sub[1], sub[2]); err != nil {
+			err = UnresolvedSecretError{Field: "/properties" + at.String(), Reason: err.Error()}
+		}
+		return val
+	})
+
+	return res, err
+}
+
+// secret returns a secret value from the cache or its provider
+func (r *Resolver) secret(provider string, name string) (string, error) {
+	var p Provider
+	if r != nil {
+		p = r.providers[provider]
+	}
+	if p == nil {
+		return "", fmt.Errorf("unknown secret provider %q", provider)
+	}
+
+	key := provider + ":" + name
+	r.lock.Lock()
+	c, ok := r.cache[key]
+	r.lock.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.value, nil
+	}
+
+	val, err := p.Secret(name)
+	if err == ErrSecretNotFound {
+		return "", fmt.Errorf("secret %s not found by provider %s", name, provider)
+	}
+	if err != nil {
+		return "", fmt.Errorf("provider %s failed", provider)
+	}
+
+	if r.ttl > 0 {
+		r.lock.Lock()
+		r.cache[key] = cached{value: val, expires: time.Now().Add(r.ttl)}
+		r.lock.Unlock()
+	}
+
+	return val, nil
+}
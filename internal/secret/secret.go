@@ -0,0 +1,512 @@
+// Package secret encrypts secret property values at rest and masks them for callers who cannot read them. Secret values are
+// marked by wrapping them as {"$secret": value} when written, or by the schema of the group, and stored as envelopes
+// {"$encrypted": {"kek": ..., "dek": ..., "data": ...}}. Each value is encrypted with AES-GCM using its own data-encryption
+// key, which is in turn encrypted with the key-encryption key of a Keeper. A Keeper holds versioned key-encryption keys: the
+// newest encrypts, while older, retired keys only decrypt until every value has been rewrapped with the newest one.
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/larwef/ki/internal/domain"
+	"github.com/larwef/ki/internal/properties"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SecretKey wraps a property value written as a secret, like {"$secret": "hunter2"}
+	SecretKey = "$secret"
+	// EncryptedKey wraps the envelope of an encrypted property value
+	EncryptedKey = "$encrypted"
+	// Mask replaces secret values for callers who cannot read them
+	Mask = "********"
+	// KeySize is the size in bytes of keys, which are AES-256 keys
+	KeySize = 32
+)
+
+// ErrNoKey is used when writing secret values or reading encrypted ones without a key-encryption key configured.
+var ErrNoKey = domain.New(domain.FailedPrecondition, "", "secrets are not enabled, no key-encryption key is configured")
+
+// ErrUndecryptable is used when an encrypted value cannot be decrypted with the key-encryption keys, like when it was encrypted
+// with an unknown key or has been tampered with.
+var ErrUndecryptable = domain.New(domain.Internal, "", "secret value cannot be decrypted")
+
+// Key is a version of the key-encryption key. The key with the highest version is the primary key.
+type Key struct {
+	Version int
+	Key     []byte
+}
+
+// KeyVersion describes a version of the key-encryption key without revealing it
+type KeyVersion struct {
+	Version int    `json:"version"`
+	ID      string `json:"id"`
+	Primary bool   `json:"primary"`
+}
+
+// Envelope is an encrypted value. DEK is the data-encryption key encrypted with the key-encryption key identified by KEK, and
+// Data the value encrypted with the data-encryption key. Both are base64 encoded with the nonce first.
+type Envelope struct {
+	KEK  string `json:"kek"`
+	DEK  string `json:"dek"`
+	Data string `json:"data"`
+}
+
+// Keeper encrypts secret values with its primary key-encryption key and decrypts them with any of its keys. A nil Keeper has
+// no key, and fails with ErrNoKey if values have to be encrypted or decrypted.
+type Keeper struct {
+	// id identifies the primary key
+	id   string
+	keys map[string]cipher.AEAD
+	// versions are ordered from the primary key to the oldest
+	versions []KeyVersion
+}
+
+// NewKeeper returns a Keeper using key as its only key-encryption key. The key has to be KeySize bytes.
+func NewKeeper(key []byte) (*Keeper, error) {
+	return NewKeyring(Key{Version: 1, Key: key})
+}
+
+// NewKeyring returns a Keeper using the key with the highest version as the primary key and the others as retired keys. Keys
+// have to be KeySize bytes, and versions unique.
+func NewKeyring(keys ...Key) (*Keeper, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key-encryption keys")
+	}
+
+	k := &Keeper{keys: make(map[string]cipher.AEAD)}
+	seen := make(map[int]bool)
+	for _, key := range keys {
+		if len(key.Key) != KeySize {
+			return nil, fmt.Errorf("key-encryption key version %d has to be %d bytes, was %d", key.Version, KeySize, len(key.Key))
+		}
+		if seen[key.Version] {
+			return nil, fmt.Errorf("key-encryption key version %d is given more than once", key.Version)
+		}
+		seen[key.Version] = true
+
+		kek, err := newAEAD(key.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(key.Key)
+		id := hex.EncodeToString(sum[:8])
+		k.keys[id] = kek
+		k.versions = append(k.versions, KeyVersion{Version: key.Version, ID: id})
+	}
+
+	sort.Slice(k.versions, func(i, j int) bool { return k.versions[i].Version > k.versions[j].Version })
+	k.versions[0].Primary = true
+	k.id = k.versions[0].ID
+
+	return k, nil
+}
+
+// LoadKeyFile returns a Keeper using the base64 encoded key-encryption keys in a file. Every line holds a version and a key,
+// like "2 c2VjcmV0...", and a file with a single key without a version has it as version 1. Empty lines and lines starting
+// with # are ignored.
+func LoadKeyFile(path string) (*Keeper, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		version, encoded := 1, line
+		if fields := strings.Fields(line); len(fields) == 2 {
+			if version, err = strconv.Atoi(fields[0]); err != nil {
+				return nil, fmt.Errorf("key file %s has an invalid version on line %d", path, i+1)
+			}
+			encoded = fields[1]
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key file %s is not base64 encoded on line %d: %v", path, i+1, err)
+		}
+		keys = append(keys, Key{Version: version, Key: key})
+	}
+
+	return NewKeyring(keys...)
+}
+
+// ID identifies the primary key-encryption key of the Keeper without revealing it
+func (k *Keeper) ID() string {
+	if k == nil {
+		return ""
+	}
+
+	return k.id
+}
+
+// Versions describes the key-encryption keys of the Keeper, from the primary key to the oldest
+func (k *Keeper) Versions() []KeyVersion {
+	if k == nil {
+		return []KeyVersion{}
+	}
+
+	versions := make([]KeyVersion, len(k.versions))
+	copy(versions, k.versions)
+	return versions
+}
+
+// Seal encrypts the secret values of properties, which are the values wrapped as secrets and the values at paths. Values
+// already encrypted are kept as they are.
+func (k *Keeper) Seal(props json.RawMessage, paths []properties.Pointer) (json.RawMessage, error) {
+	if len(paths) == 0 && !bytes.Contains(props, []byte(SecretKey)) {
+		return props, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil, err
+	}
+
+	for _, p := range paths {
+		v, ok := properties.Get(doc, p)
+		if !ok || isWrapped(v, SecretKey) || isWrapped(v, EncryptedKey) {
+			continue
+		}
+
+		var err error
+		if doc, err = properties.Set(doc, p, map[string]interface{}{SecretKey: v}); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := transform(doc, SecretKey, k.encrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// Open decrypts the encrypted values of properties and unwraps the values wrapped as secrets, giving the properties as they
+// are read by callers who can read secrets
+func (k *Keeper) Open(props json.RawMessage) (json.RawMessage, error) {
+	if !bytes.Contains(props, []byte(SecretKey)) && !bytes.Contains(props, []byte(EncryptedKey)) {
+		return props, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := transform(doc, SecretKey, func(v interface{}) (interface{}, error) { return v, nil })
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err = transform(doc, EncryptedKey, k.decrypt); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// Rewrap encrypts the data-encryption keys of values encrypted with retired key-encryption keys with the primary key instead,
+// and returns the properties with the number of values rewrapped. The values themselves are not decrypted.
+func (k *Keeper) Rewrap(props json.RawMessage) (json.RawMessage, int, error) {
+	if !Encrypted(props) {
+		return props, 0, nil
+	}
+	if k == nil {
+		return nil, 0, ErrNoKey
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return nil, 0, err
+	}
+
+	rewrapped := 0
+	doc, err := transform(doc, EncryptedKey, func(v interface{}) (interface{}, error) {
+		env, err := envelope(v)
+		if err != nil {
+			return nil, err
+		}
+		if env.KEK == k.id {
+			return map[string]interface{}{EncryptedKey: v}, nil
+		}
+
+		kek, ok := k.keys[env.KEK]
+		if !ok {
+			return nil, ErrUndecryptable
+		}
+
+		dek, err := open(kek, env.DEK)
+		if err != nil {
+			return nil, ErrUndecryptable
+		}
+
+		wrapped, err := seal(k.keys[k.id], dek)
+		if err != nil {
+			return nil, err
+		}
+
+		rewrapped++
+		return map[string]interface{}{
+			EncryptedKey: map[string]interface{}{"kek": k.id, "dek": wrapped, "data": env.Data},
+		}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if rewrapped == 0 {
+		return props, 0, nil
+	}
+
+	b, err := json.Marshal(doc)
+	return b, rewrapped, err
+}
+
+// Retired returns the number of values in properties encrypted with other key-encryption keys than the primary key of the
+// Keeper, which are the values to be rewrapped. Properties that are not valid JSON have none.
+func (k *Keeper) Retired(props json.RawMessage) int {
+	if !Encrypted(props) {
+		return 0
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return 0
+	}
+
+	retired := 0
+	transform(doc, EncryptedKey, func(v interface{}) (interface{}, error) {
+		if env, err := envelope(v); err != nil || env.KEK != k.ID() {
+			retired++
+		}
+		return v, nil
+	})
+
+	return retired
+}
+
+// Masked returns properties with secret values, encrypted or not, replaced by Mask. Properties that are not valid JSON are
+// returned as they are.
+func Masked(props json.RawMessage) json.RawMessage {
+	if !bytes.Contains(props, []byte(SecretKey)) && !bytes.Contains(props, []byte(EncryptedKey)) {
+		return props
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return props
+	}
+
+	b, err := json.Marshal(mask(doc))
+	if err != nil {
+		return props
+	}
+
+	return b
+}
+
+// Encrypted reports whether properties have encrypted values
+func Encrypted(props json.RawMessage) bool {
+	return bytes.Contains(props, []byte(EncryptedKey))
+}
+
+// encrypt encrypts a value with a new data-encryption key and returns it as an envelope
+func (k *Keeper) encrypt(v interface{}) (interface{}, error) {
+	if k == nil {
+		return nil, ErrNoKey
+	}
+
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dek := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := seal(aead, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := seal(k.keys[k.id], dek)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		EncryptedKey: map[string]interface{}{"kek": k.id, "dek": wrapped, "data": data},
+	}, nil
+}
+
+// envelope decodes the envelope of an encrypted value
+func envelope(v interface{}) (*Envelope, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	return &env, nil
+}
+
+// decrypt decrypts an envelope and returns the value
+func (k *Keeper) decrypt(v interface{}) (interface{}, error) {
+	if k == nil {
+		return nil, ErrNoKey
+	}
+
+	env, err := envelope(v)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, ok := k.keys[env.KEK]
+	if !ok {
+		return nil, ErrUndecryptable
+	}
+
+	dek, err := open(kek, env.DEK)
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	plaintext, err := open(aead, env.Data)
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(plaintext, &res); err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	return res, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce and returns the nonce and ciphertext base64 encoded
+func seal(aead cipher.AEAD, plaintext []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// open decrypts what seal returned
+func open(aead cipher.AEAD, s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < aead.NonceSize() {
+		return nil, ErrUndecryptable
+	}
+
+	return aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+}
+
+// isWrapped reports whether a value is an object with key as its only member
+func isWrapped(v interface{}, key string) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return false
+	}
+
+	_, ok = m[key]
+	return ok
+}
+
+// transform replaces every value wrapped with key by fn applied to the wrapped value
+func transform(v interface{}, key string, fn func(v interface{}) (interface{}, error)) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if isWrapped(val, key) {
+			return fn(val[key])
+		}
+
+		for k, child := range val {
+			c, err := transform(child, key, fn)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = c
+		}
+	case []interface{}:
+		for i, child := range val {
+			c, err := transform(child, key, fn)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = c
+		}
+	}
+
+	return v, nil
+}
+
+// mask replaces every value wrapped as a secret or encrypted by Mask
+func mask(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if isWrapped(val, SecretKey) || isWrapped(val, EncryptedKey) {
+			return Mask
+		}
+
+		for k, child := range val {
+			val[k] = mask(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = mask(child)
+		}
+	}
+
+	return v
+}
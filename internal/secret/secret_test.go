@@ -0,0 +1,228 @@
+package secret_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/larwef/ki/internal/properties"
+	"github.com/larwef/ki/internal/secret"
+	"github.com/larwef/ki/test"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestKeeper(t *testing.T, b byte) *secret.Keeper {
+	k, err := secret.NewKeeper(bytes.Repeat([]byte{b}, secret.KeySize))
+	test.AssertNotError(t, err)
+	return k
+}
+
+func TestKeeper_SealAndOpen(t *testing.T) {
+	keeper := newTestKeeper(t, 1)
+
+	props := []byte(`{"user":"admin","password":{"$secret":"hunter2"},"db":{"token":{"a":[1,2]}}}`)
+	sealed, err := keeper.Seal(props, []properties.Pointer{{"db", "token"}, {"missing"}})
+	test.AssertNotError(t, err)
+
+	if strings.Contains(string(sealed), "hunter2") {
+		t.Fatalf("Sealed properties hold the secret value: %s", sealed)
+	}
+	test.AssertEqual(t, secret.Encrypted(sealed), true)
+
+	var doc struct {
+		User     string                     `json:"user"`
+		Password map[string]secret.Envelope `json:"password"`
+	}
+	test.AssertNotError(t, json.Unmarshal(sealed, &doc))
+	test.AssertEqual(t, doc.User, "admin")
+	test.AssertEqual(t, doc.Password[secret.EncryptedKey].KEK, keeper.ID())
+
+	opened, err := keeper.Open(sealed)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(opened), `{"user":"admin","password":"hunter2","db":{"token":{"a":[1,2]}}}`)
+
+	// Encrypted values are kept as they are when sealed again
+	resealed, err := keeper.Seal(sealed, []properties.Pointer{{"password"}})
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(resealed), string(sealed))
+
+	test.AssertJSONEqual(t, string(secret.Masked(sealed)), `{"user":"admin","password":"********","db":{"token":"********"}}`)
+	test.AssertJSONEqual(t, string(secret.Masked(props)), `{"user":"admin","password":"********","db":{"token":{"a":[1,2]}}}`)
+}
+
+func TestKeeper_Errors(t *testing.T) {
+	var noKeeper *secret.Keeper
+
+	// Properties without secrets need no key
+	props, err := noKeeper.Seal([]byte(`{"user":"admin"}`), nil)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(props), `{"user":"admin"}`)
+
+	_, err = noKeeper.Seal([]byte(`{"password":{"$secret":"hunter2"}}`), nil)
+	test.AssertEqual(t, err, secret.ErrNoKey)
+
+	sealed, err := newTestKeeper(t, 1).Seal([]byte(`{"password":{"$secret":"hunter2"}}`), nil)
+	test.AssertNotError(t, err)
+
+	_, err = noKeeper.Open(sealed)
+	test.AssertEqual(t, err, secret.ErrNoKey)
+
+	_, err = newTestKeeper(t, 2).Open(sealed)
+	test.AssertEqual(t, err, secret.ErrUndecryptable)
+
+	tampered := bytes.Replace(sealed, []byte(`"data":"`), []byte(`"data":"AAAA`), 1)
+	_, err = newTestKeeper(t, 1).Open(tampered)
+	test.AssertEqual(t, err, secret.ErrUndecryptable)
+
+	_, err = secret.NewKeeper([]byte("too short"))
+	if err == nil {
+		t.Fatal("Expected error for short key")
+	}
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ki-secret")
+	test.AssertNotError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ki.key")
+	key := bytes.Repeat([]byte{1}, secret.KeySize)
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+
+	keeper, err := secret.LoadKeyFile(path)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, keeper.ID(), newTestKeeper(t, 1).ID())
+
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte("not base64!"), 0600))
+	if _, err = secret.LoadKeyFile(path); err == nil {
+		t.Fatal("Expected error for key file not base64 encoded")
+	}
+}
+
+func TestMaskPayload(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"id":"someId","properties":{"password":{"$secret":"hunter2"}}}`, `{"id":"someId","properties":{"password":"********"}}`},
+		{`{"properties":{"password":{"$encrypted":{"kek":"k","dek":"d","data":"x"}}}}`, `{"properties":{"password":"********"}}`},
+		{`{"properties":"{\"password\":{\"$secret\":\"hunter2\"}}"}`, `{"properties":"********"}`},
+		{`{"properties":"` + base64.StdEncoding.EncodeToString([]byte(`{"password":{"$secret":"hunter2"}}`)) + `"}`, `{"properties":"********"}`},
+		{`{"id":"someId"}`, `{"id":"someId"}`},
+	}
+
+	for _, tc := range tests {
+		test.AssertJSONEqual(t, string(secret.MaskPayload([]byte(tc.payload))), tc.expected)
+	}
+
+	test.AssertEqual(t, string(secret.MaskPayload([]byte(`password: {"$secret": hunter2`))), secret.Redacted)
+	test.AssertEqual(t, string(secret.MaskPayload([]byte("not json"))), "not json")
+}
+
+func TestKeeper_Rewrap(t *testing.T) {
+	old := newTestKeeper(t, 1)
+	sealed, err := old.Seal([]byte(`{"user":"admin","password":{"$secret":"hunter2"},"token":{"$secret":"abc"}}`), nil)
+	test.AssertNotError(t, err)
+
+	keyring, err := secret.NewKeyring(
+		secret.Key{Version: 1, Key: bytes.Repeat([]byte{1}, secret.KeySize)},
+		secret.Key{Version: 2, Key: bytes.Repeat([]byte{2}, secret.KeySize)},
+	)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, keyring.ID(), newTestKeeper(t, 2).ID())
+
+	versions := keyring.Versions()
+	test.AssertEqual(t, len(versions), 2)
+	test.AssertEqual(t, versions[0], secret.KeyVersion{Version: 2, ID: keyring.ID(), Primary: true})
+	test.AssertEqual(t, versions[1], secret.KeyVersion{Version: 1, ID: old.ID()})
+
+	// Values encrypted with retired keys are decrypted, and rewrapped with the primary key
+	opened, err := keyring.Open(sealed)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(opened), `{"user":"admin","password":"hunter2","token":"abc"}`)
+	test.AssertEqual(t, keyring.Retired(sealed), 2)
+
+	rewrapped, n, err := keyring.Rewrap(sealed)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, n, 2)
+	test.AssertEqual(t, keyring.Retired(rewrapped), 0)
+
+	_, err = old.Open(rewrapped)
+	test.AssertEqual(t, err, secret.ErrUndecryptable)
+	opened, err = newTestKeeper(t, 2).Open(rewrapped)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(opened), `{"user":"admin","password":"hunter2","token":"abc"}`)
+
+	// Rewrapping again changes nothing
+	again, n, err := keyring.Rewrap(rewrapped)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, n, 0)
+	test.AssertEqual(t, string(again), string(rewrapped))
+
+	// Values encrypted with unknown keys cannot be rewrapped
+	_, _, err = newTestKeeper(t, 3).Rewrap(sealed)
+	test.AssertEqual(t, err, secret.ErrUndecryptable)
+
+	_, err = secret.NewKeyring(secret.Key{Version: 1, Key: bytes.Repeat([]byte{1}, secret.KeySize)}, secret.Key{Version: 1, Key: bytes.Repeat([]byte{2}, secret.KeySize)})
+	if err == nil {
+		t.Fatal("Expected error for duplicate versions")
+	}
+}
+
+func TestLoadKeyFile_Versions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ki-secret")
+	test.AssertNotError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ki.key")
+	file := "# Retired\n1 " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.KeySize)) + "\n\n" +
+		"2 " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, secret.KeySize)) + "\n"
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte(file), 0600))
+
+	keeper, err := secret.LoadKeyFile(path)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, keeper.ID(), newTestKeeper(t, 2).ID())
+	test.AssertEqual(t, len(keeper.Versions()), 2)
+
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte("x "+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.KeySize))), 0600))
+	if _, err = secret.LoadKeyFile(path); err == nil {
+		t.Fatal("Expected error for invalid version")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	keeper := newTestKeeper(t, 1)
+
+	from, err := keeper.Seal([]byte(`{"user":"admin","password":{"$secret":"hunter2"},"token":{"$secret":"a"}}`), nil)
+	test.AssertNotError(t, err)
+
+	// The password is kept encrypted as it is, while the token is written again
+	var doc map[string]interface{}
+	test.AssertNotError(t, json.Unmarshal(from, &doc))
+	doc["user"] = "root"
+	doc["token"] = map[string]interface{}{secret.SecretKey: "a"}
+	doc["keys"] = []interface{}{map[string]interface{}{secret.SecretKey: "b"}}
+	b, err := json.Marshal(doc)
+	test.AssertNotError(t, err)
+	to, err := keeper.Seal(b, nil)
+	test.AssertNotError(t, err)
+
+	diffs, err := secret.Diff(from, to)
+	test.AssertNotError(t, err)
+	res, err := json.Marshal(diffs)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(res), `[
+		{"type":"added","path":"/keys","to":["********"]},
+		{"type":"changed","path":"/token","from":"********","to":"********"},
+		{"type":"changed","path":"/user","from":"admin","to":"root"}
+	]`)
+
+	diffs, err = secret.Diff(nil, []byte(`{"host":"db1"}`))
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, len(diffs), 1)
+	test.AssertEqual(t, diffs[0].Type, properties.Added)
+	test.AssertEqual(t, diffs[0].Path, "/host")
+}
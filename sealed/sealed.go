@@ -0,0 +1,308 @@
+// Package sealed encrypts the properties of configs on the client side, so ki only stores ciphertext it cannot read. The
+// properties are encrypted with a random data key, which is encrypted to each recipient public key registered on the group of
+// the config with NaCl box. Sealed properties are stored as a single "$sealed" member holding the Envelope.
+package sealed
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+	"io"
+)
+
+const (
+	// Key is the member sealed properties are stored under
+	Key = "$sealed"
+	// Version is the version of the envelope format
+	Version = 1
+	// KeySize is the size in bytes of public and private keys
+	KeySize = 32
+	// nonceSize is the size in bytes of the nonces used with NaCl box and secretbox
+	nonceSize = 24
+)
+
+// ErrNotRecipient is returned when opening sealed properties with a private key they are not sealed to.
+var ErrNotRecipient = errors.New("not a recipient of the sealed properties")
+
+// ErrUndecryptable is returned when sealed properties cannot be decrypted, because they have been tampered with.
+var ErrUndecryptable = errors.New("sealed properties cannot be decrypted")
+
+// InvalidEnvelopeError is returned when properties are not a valid envelope
+type InvalidEnvelopeError string
+
+func (i InvalidEnvelopeError) Error() string {
+	return "invalid sealed properties: " + string(i)
+}
+
+// Envelope holds properties encrypted with a data key, and the data key encrypted to each recipient
+type Envelope struct {
+	Version int `json:"version"`
+	// Nonce and Data are the nonce and the properties encrypted with secretbox using the data key
+	Nonce      []byte      `json:"nonce"`
+	Data       []byte      `json:"data"`
+	Recipients []Recipient `json:"recipients"`
+}
+
+// Recipient holds the data key encrypted to a recipient public key with box, using an ephemeral key pair
+type Recipient struct {
+	Key       string `json:"key"`
+	Ephemeral []byte `json:"ephemeral"`
+	Nonce     []byte `json:"nonce"`
+	DataKey   []byte `json:"dataKey"`
+}
+
+// GenerateKey generates a key pair for a recipient. Both keys are base64 encoded. The public key is registered on groups, while
+// the private key is kept by the recipient.
+func GenerateKey() (string, string, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return encodeKey(public), encodeKey(private), nil
+}
+
+// PublicKey returns the public key of a private key
+func PublicKey(privateKey string) (string, error) {
+	private, err := decodeKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	var public [KeySize]byte
+	curve25519.ScalarBaseMult(&public, private)
+	return encodeKey(&public), nil
+}
+
+// ValidateKey returns an error if a key is not a base64 encoded key
+func ValidateKey(key string) error {
+	_, err := decodeKey(key)
+	return err
+}
+
+// IsSealed reports whether properties are sealed. It does not validate the envelope.
+func IsSealed(props json.RawMessage) bool {
+	if !bytes.Contains(props, []byte(Key)) {
+		return false
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(props, &doc); err != nil {
+		return false
+	}
+
+	_, ok := doc[Key]
+	return ok
+}
+
+// Seal encrypts properties to recipient public keys
+func Seal(props json.RawMessage, recipients []string) (json.RawMessage, error) {
+	if !json.Valid(props) {
+		return nil, errors.New("properties are not valid JSON")
+	}
+
+	var dataKey [KeySize]byte
+	if _, err := io.ReadFull(rand.Reader, dataKey[:]); err != nil {
+		return nil, err
+	}
+
+	env := Envelope{Version: Version}
+	var nonce [nonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	env.Nonce = nonce[:]
+	env.Data = secretbox.Seal(nil, props, &nonce, &dataKey)
+
+	var err error
+	if env.Recipients, err = wrap(&dataKey, recipients); err != nil {
+		return nil, err
+	}
+
+	return marshal(env)
+}
+
+// Open decrypts sealed properties with the private key of one of their recipients. Returns ErrNotRecipient if they are not
+// sealed to the key.
+func Open(props json.RawMessage, privateKey string) (json.RawMessage, error) {
+	env, err := Parse(props)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := unwrap(env, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], env.Nonce)
+	data, ok := secretbox.Open(nil, env.Data, &nonce, dataKey)
+	if !ok {
+		return nil, ErrUndecryptable
+	}
+
+	return data, nil
+}
+
+// Rekey encrypts the data key of sealed properties to a new set of recipients, like after recipients are added to or removed
+// from a group. The private key has to be one of the current recipients. The encrypted properties are kept as they are.
+func Rekey(props json.RawMessage, privateKey string, recipients []string) (json.RawMessage, error) {
+	env, err := Parse(props)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := unwrap(env, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if env.Recipients, err = wrap(dataKey, recipients); err != nil {
+		return nil, err
+	}
+
+	return marshal(*env)
+}
+
+// Parse parses sealed properties and validates the format of the envelope, without decrypting anything. Returns an
+// InvalidEnvelopeError if the properties are not a valid envelope.
+func Parse(props json.RawMessage) (*Envelope, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(props, &doc); err != nil || len(doc) != 1 || doc[Key] == nil {
+		return nil, InvalidEnvelopeError(fmt.Sprintf("has to be an object with %s as the only member", Key))
+	}
+
+	var env Envelope
+	dec := json.NewDecoder(bytes.NewReader(doc[Key]))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&env); err != nil {
+		return nil, InvalidEnvelopeError("envelope is not valid")
+	}
+
+	if env.Version != Version {
+		return nil, InvalidEnvelopeError(fmt.Sprintf("unsupported version %d", env.Version))
+	}
+	if len(env.Nonce) != nonceSize {
+		return nil, InvalidEnvelopeError("nonce has to be 24 bytes")
+	}
+	if len(env.Data) < secretbox.Overhead {
+		return nil, InvalidEnvelopeError("data is too short")
+	}
+	if len(env.Recipients) == 0 {
+		return nil, InvalidEnvelopeError("at least one recipient is required")
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range env.Recipients {
+		switch {
+		case ValidateKey(r.Key) != nil:
+			return nil, InvalidEnvelopeError(fmt.Sprintf("key of recipient %d is not a valid public key", i))
+		case seen[r.Key]:
+			return nil, InvalidEnvelopeError(fmt.Sprintf("recipient %s is given more than once", r.Key))
+		case len(r.Ephemeral) != KeySize:
+			return nil, InvalidEnvelopeError(fmt.Sprintf("ephemeral key of recipient %d has to be 32 bytes", i))
+		case len(r.Nonce) != nonceSize:
+			return nil, InvalidEnvelopeError(fmt.Sprintf("nonce of recipient %d has to be 24 bytes", i))
+		case len(r.DataKey) != KeySize+box.Overhead:
+			return nil, InvalidEnvelopeError(fmt.Sprintf("data key of recipient %d has to be 48 bytes", i))
+		}
+		seen[r.Key] = true
+	}
+
+	return &env, nil
+}
+
+// wrap encrypts a data key to each recipient with a new ephemeral key pair
+func wrap(dataKey *[KeySize]byte, recipients []string) ([]Recipient, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+
+	wrapped := make([]Recipient, 0, len(recipients))
+	for _, key := range recipients {
+		public, err := decodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		ephemeralPublic, ephemeralPrivate, err := box.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		var nonce [nonceSize]byte
+		if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+			return nil, err
+		}
+
+		wrapped = append(wrapped, Recipient{
+			Key:       encodeKey(public),
+			Ephemeral: ephemeralPublic[:],
+			Nonce:     nonce[:],
+			DataKey:   box.Seal(nil, dataKey[:], &nonce, public, ephemeralPrivate),
+		})
+	}
+
+	return wrapped, nil
+}
+
+// unwrap decrypts the data key of an envelope with the private key of one of its recipients
+func unwrap(env *Envelope, privateKey string) (*[KeySize]byte, error) {
+	private, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var public [KeySize]byte
+	curve25519.ScalarBaseMult(&public, private)
+	id := encodeKey(&public)
+
+	for _, r := range env.Recipients {
+		if r.Key != id {
+			continue
+		}
+
+		var ephemeral [KeySize]byte
+		var nonce [nonceSize]byte
+		copy(ephemeral[:], r.Ephemeral)
+		copy(nonce[:], r.Nonce)
+
+		b, ok := box.Open(nil, r.DataKey, &nonce, &ephemeral, private)
+		if !ok {
+			return nil, ErrUndecryptable
+		}
+
+		var dataKey [KeySize]byte
+		copy(dataKey[:], b)
+		return &dataKey, nil
+	}
+
+	return nil, ErrNotRecipient
+}
+
+func marshal(env Envelope) (json.RawMessage, error) {
+	return json.Marshal(map[string]Envelope{Key: env})
+}
+
+func encodeKey(k *[KeySize]byte) string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// decodeKey decodes a base64 encoded key. Only the canonical encoding is accepted, so a key always has the same encoding.
+func decodeKey(s string) (*[KeySize]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) != KeySize || base64.StdEncoding.EncodeToString(b) != s {
+		return nil, fmt.Errorf("key has to be %d base64 encoded bytes", KeySize)
+	}
+
+	var k [KeySize]byte
+	copy(k[:], b)
+	return &k, nil
+}
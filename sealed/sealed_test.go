@@ -0,0 +1,132 @@
+package sealed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/larwef/ki/sealed"
+	"github.com/larwef/ki/test"
+	"testing"
+)
+
+func generateKey(t *testing.T) (string, string) {
+	public, private, err := sealed.GenerateKey()
+	test.AssertNotError(t, err)
+	return public, private
+}
+
+func TestSealAndOpen(t *testing.T) {
+	alicePublic, alicePrivate := generateKey(t)
+	bobPublic, bobPrivate := generateKey(t)
+	_, evePrivate := generateKey(t)
+
+	public, err := sealed.PublicKey(alicePrivate)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, public, alicePublic)
+
+	props, err := sealed.Seal([]byte(`{"password":"hunter2"}`), []string{alicePublic, bobPublic})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, sealed.IsSealed(props), true)
+	if bytes.Contains(props, []byte("hunter2")) {
+		t.Fatalf("Sealed properties hold the plaintext: %s", props)
+	}
+
+	env, err := sealed.Parse(props)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, env.Version, sealed.Version)
+	test.AssertEqual(t, len(env.Recipients), 2)
+
+	for _, key := range []string{alicePrivate, bobPrivate} {
+		opened, err := sealed.Open(props, key)
+		test.AssertNotError(t, err)
+		test.AssertJSONEqual(t, string(opened), `{"password":"hunter2"}`)
+	}
+
+	_, err = sealed.Open(props, evePrivate)
+	test.AssertEqual(t, err, sealed.ErrNotRecipient)
+
+	env.Data[0] ^= 1
+	tampered, err := json.Marshal(map[string]*sealed.Envelope{sealed.Key: env})
+	test.AssertNotError(t, err)
+	_, err = sealed.Open(tampered, alicePrivate)
+	test.AssertEqual(t, err, sealed.ErrUndecryptable)
+
+	_, err = sealed.Seal([]byte(`{"password":"hunter2"}`), nil)
+	if err == nil {
+		t.Fatal("Expected error for no recipients")
+	}
+
+	test.AssertEqual(t, sealed.IsSealed([]byte(`{"password":"hunter2"}`)), false)
+	test.AssertEqual(t, sealed.IsSealed([]byte(`{"nested":{"$sealed":{}}}`)), false)
+}
+
+func TestRekey(t *testing.T) {
+	alicePublic, alicePrivate := generateKey(t)
+	bobPublic, bobPrivate := generateKey(t)
+
+	props, err := sealed.Seal([]byte(`{"password":"hunter2"}`), []string{alicePublic})
+	test.AssertNotError(t, err)
+
+	_, err = sealed.Rekey(props, bobPrivate, []string{bobPublic})
+	test.AssertEqual(t, err, sealed.ErrNotRecipient)
+
+	// Only the data key is encrypted again, to the new recipients
+	rekeyed, err := sealed.Rekey(props, alicePrivate, []string{bobPublic})
+	test.AssertNotError(t, err)
+
+	before, err := sealed.Parse(props)
+	test.AssertNotError(t, err)
+	after, err := sealed.Parse(rekeyed)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, string(after.Data), string(before.Data))
+
+	opened, err := sealed.Open(rekeyed, bobPrivate)
+	test.AssertNotError(t, err)
+	test.AssertJSONEqual(t, string(opened), `{"password":"hunter2"}`)
+
+	_, err = sealed.Open(rekeyed, alicePrivate)
+	test.AssertEqual(t, err, sealed.ErrNotRecipient)
+}
+
+func TestParse(t *testing.T) {
+	public, _ := generateKey(t)
+	props, err := sealed.Seal([]byte(`{}`), []string{public})
+	test.AssertNotError(t, err)
+
+	var doc map[string]map[string]interface{}
+	test.AssertNotError(t, json.Unmarshal(props, &doc))
+	recipient := doc[sealed.Key]["recipients"].([]interface{})[0]
+
+	// edit returns the sealed properties with a member of the envelope replaced
+	edit := func(member string, v interface{}) []byte {
+		env := make(map[string]interface{})
+		for k, val := range doc[sealed.Key] {
+			env[k] = val
+		}
+		env[member] = v
+
+		b, err := json.Marshal(map[string]interface{}{sealed.Key: env})
+		test.AssertNotError(t, err)
+		return b
+	}
+
+	tests := []struct {
+		props  []byte
+		reason string
+	}{
+		{[]byte(`{"password":"hunter2"}`), "has to be an object with $sealed as the only member"},
+		{[]byte(`{"$sealed":{},"password":"hunter2"}`), "has to be an object with $sealed as the only member"},
+		{edit("unknown", 1), "envelope is not valid"},
+		{edit("nonce", "not base64!"), "envelope is not valid"},
+		{edit("version", 2), "unsupported version 2"},
+		{edit("nonce", "AAAA"), "nonce has to be 24 bytes"},
+		{edit("data", ""), "data is too short"},
+		{edit("recipients", []interface{}{}), "at least one recipient is required"},
+		{edit("recipients", []interface{}{map[string]interface{}{"key": "short"}}), "key of recipient 0 is not a valid public key"},
+		{edit("recipients", []interface{}{recipient, recipient}), "recipient " + public + " is given more than once"},
+	}
+
+	for _, tc := range tests {
+		_, err := sealed.Parse(tc.props)
+		test.AssertEqual(t, err, sealed.InvalidEnvelopeError(tc.reason))
+	}
+}
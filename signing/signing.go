@@ -0,0 +1,175 @@
+// Package signing signs configs on the server and verifies them on the client, so clients can check that a config was served
+// by ki and not changed on the way, like by a proxy terminating TLS. The server signs the canonical JSON of the id, group,
+// version and properties of each config it returns with an Ed25519 key, and publishes the public key at WellKnownPath.
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// Algorithm is the signature algorithm
+	Algorithm = "Ed25519"
+	// SignatureHeader is the HTTP header with the base64 encoded signature of a config
+	SignatureHeader = "Ki-Signature"
+	// KeyIDHeader is the HTTP header with the id of the key a config is signed with
+	KeyIDHeader = "Ki-Signature-Key"
+	// SignatureTrailer is the gRPC trailer with the base64 encoded signature of a config
+	SignatureTrailer = "ki-signature"
+	// KeyIDTrailer is the gRPC trailer with the id of the key a config is signed with
+	KeyIDTrailer = "ki-signature-key"
+	// WellKnownPath is the path of the crud API the PublicKey of the server is published at
+	WellKnownPath = "/.well-known/ki-signing-key"
+)
+
+// ErrInvalidSignature is returned when a signature does not match a config.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrUnknownKey is returned when a config is signed with another key than the one verified with.
+var ErrUnknownKey = errors.New("signed with an unknown key")
+
+// Config is the part of a config that is signed
+type Config struct {
+	ID         string          `json:"id"`
+	Group      string          `json:"group"`
+	Version    int             `json:"version"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+// PublicKey is the public key configs are verified with
+type PublicKey struct {
+	ID        string `json:"keyId"`
+	Algorithm string `json:"algorithm"`
+	Key       []byte `json:"key"`
+}
+
+// Canonical returns the canonical JSON of a config, which is what is signed. Object members are sorted, whitespace is
+// removed and numbers are formatted the same way whatever format the config was read in, like protobuf, so a config
+// decoded from any representation gives the same bytes.
+func Canonical(c Config) ([]byte, error) {
+	var props interface{}
+	if len(c.Properties) > 0 {
+		if err := json.Unmarshal(c.Properties, &props); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(map[string]interface{}{
+		"id":         c.ID,
+		"group":      c.Group,
+		"version":    c.Version,
+		"properties": props,
+	})
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), err
+}
+
+// Signer signs configs with an Ed25519 private key
+type Signer struct {
+	key ed25519.PrivateKey
+	id  string
+}
+
+// NewSigner returns a Signer for the private key generated from a 32 byte seed
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key has to be %d bytes", ed25519.SeedSize)
+	}
+
+	key := ed25519.NewKeyFromSeed(seed)
+	return &Signer{key: key, id: keyID(key.Public().(ed25519.PublicKey))}, nil
+}
+
+// LoadKeyFile returns a Signer for the base64 encoded seed in a file, like one created with
+// "head -c 32 /dev/urandom | base64 > signing.key".
+func LoadKeyFile(path string) (*Signer, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("signing key file is not base64 encoded: %v", err)
+	}
+
+	return NewSigner(seed)
+}
+
+// KeyID returns the id of the key, which is derived from the public key
+func (s *Signer) KeyID() string {
+	return s.id
+}
+
+// PublicKey returns the public key configs signed by the Signer are verified with
+func (s *Signer) PublicKey() PublicKey {
+	return PublicKey{ID: s.id, Algorithm: Algorithm, Key: s.key.Public().(ed25519.PublicKey)}
+}
+
+// Sign returns the base64 encoded signature of a config
+func (s *Signer) Sign(c Config) (string, error) {
+	b, err := Canonical(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, b)), nil
+}
+
+// Verify verifies the base64 encoded signature of a config, made with the key with an id. Returns ErrUnknownKey if the key
+// is not the public key and ErrInvalidSignature if the signature does not match.
+func Verify(key PublicKey, id string, c Config, signature string) error {
+	if key.Algorithm != Algorithm || len(key.Key) != ed25519.PublicKeySize {
+		return fmt.Errorf("unsupported key: %s", key.Algorithm)
+	}
+
+	if id != key.ID || id != keyID(key.Key) {
+		return ErrUnknownKey
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	b, err := Canonical(c)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key.Key), b, sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyResponse verifies a config read from the crud API with the signature in the headers of the response. Only configs
+// read in a format holding the full config, like JSON, can be verified.
+func VerifyResponse(key PublicKey, h http.Header, c Config) error {
+	sig := h.Get(SignatureHeader)
+	if sig == "" {
+		return ErrInvalidSignature
+	}
+
+	return Verify(key, h.Get(KeyIDHeader), c, sig)
+}
+
+// keyID returns the id of a public key, the first 8 bytes of its SHA-256 hash hex encoded
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
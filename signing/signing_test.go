@@ -0,0 +1,95 @@
+package signing_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/larwef/ki/signing"
+	"github.com/larwef/ki/test"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newSigner(t *testing.T, b byte) *signing.Signer {
+	s, err := signing.NewSigner(bytes.Repeat([]byte{b}, 32))
+	test.AssertNotError(t, err)
+	return s
+}
+
+func TestCanonical(t *testing.T) {
+	a, err := signing.Canonical(signing.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{ "port": 5432, "host": "<db1>" }`)})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, string(a), `{"group":"someGroup","id":"someId","properties":{"host":"<db1>","port":5432},"version":2}`)
+
+	// Numbers are formatted the same way as when read from protobuf
+	b, err := signing.Canonical(signing.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"<db1>","port":5432.0}`)})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, string(b), string(a))
+
+	c, err := signing.Canonical(signing.Config{ID: "someId", Group: "someGroup"})
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, string(c), `{"group":"someGroup","id":"someId","properties":null,"version":0}`)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer := newSigner(t, 1)
+	key := signer.PublicKey()
+	test.AssertEqual(t, key.ID, signer.KeyID())
+	test.AssertEqual(t, key.Algorithm, signing.Algorithm)
+
+	conf := signing.Config{ID: "someId", Group: "someGroup", Version: 1, Properties: []byte(`{"host":"db1"}`)}
+	sig, err := signer.Sign(conf)
+	test.AssertNotError(t, err)
+	test.AssertNotError(t, signing.Verify(key, signer.KeyID(), conf, sig))
+
+	// Whitespace and the order of members do not matter
+	reordered := conf
+	reordered.Properties = []byte(` { "host" : "db1" } `)
+	test.AssertNotError(t, signing.Verify(key, signer.KeyID(), reordered, sig))
+
+	changed := conf
+	changed.Properties = []byte(`{"host":"evil"}`)
+	test.AssertEqual(t, signing.Verify(key, signer.KeyID(), changed, sig), signing.ErrInvalidSignature)
+
+	changed = conf
+	changed.Version = 2
+	test.AssertEqual(t, signing.Verify(key, signer.KeyID(), changed, sig), signing.ErrInvalidSignature)
+
+	test.AssertEqual(t, signing.Verify(key, signer.KeyID(), conf, "not base64!"), signing.ErrInvalidSignature)
+
+	other := newSigner(t, 2)
+	otherSig, err := other.Sign(conf)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, signing.Verify(key, other.KeyID(), conf, otherSig), signing.ErrUnknownKey)
+
+	// A key published with another id is not trusted
+	forged := other.PublicKey()
+	forged.ID = signer.KeyID()
+	test.AssertEqual(t, signing.Verify(forged, signer.KeyID(), conf, otherSig), signing.ErrUnknownKey)
+
+	h := http.Header{}
+	test.AssertEqual(t, signing.VerifyResponse(key, h, conf), signing.ErrInvalidSignature)
+	h.Set(signing.SignatureHeader, sig)
+	h.Set(signing.KeyIDHeader, signer.KeyID())
+	test.AssertNotError(t, signing.VerifyResponse(key, h, conf))
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ki-signing")
+	test.AssertNotError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "signing.key")
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))+"\n"), 0600))
+
+	signer, err := signing.LoadKeyFile(path)
+	test.AssertNotError(t, err)
+	test.AssertEqual(t, signer.KeyID(), newSigner(t, 1).KeyID())
+
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString([]byte("too short"))), 0600))
+	if _, err = signing.LoadKeyFile(path); err == nil {
+		t.Fatal("Expected error for short key")
+	}
+}
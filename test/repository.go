@@ -1,10 +1,18 @@
 package test
 
 import (
+	"errors"
 	"github.com/larwef/ki/internal/adding"
+	"github.com/larwef/ki/internal/deleting"
+	"github.com/larwef/ki/internal/diffing"
 	"github.com/larwef/ki/internal/listing"
+	"github.com/larwef/ki/internal/properties"
 	"github.com/larwef/ki/internal/repository"
+	"github.com/larwef/ki/internal/reviewing"
+	"github.com/larwef/ki/internal/scheduling"
+	"strings"
 	"testing"
+	"time"
 )
 
 // StoreAndRetrieveGroup tests that a group object can be stored and subsequently retrieved
@@ -188,3 +196,943 @@ func RetrieveConfigWhenConfigNotExist(t *testing.T, repo repository.Repository,
 	_, err = repo.RetrieveConfig("someGroup", "someOtherConfig")
 	AssertEqual(t, err, listing.ErrConfigNotFound)
 }
+
+// StoreAndRetrieveRevision tests that every store is assigned a new and increasing revision which is returned on reads
+func StoreAndRetrieveRevision(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	err := repo.StoreGroup(adding.Group{ID: "someGroup"})
+	AssertNotError(t, err)
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, grp.Revision, int64(1))
+
+	err = repo.StoreConfig(adding.Config{ID: "someConfig", Group: "someGroup"})
+	AssertNotError(t, err)
+	err = repo.StoreConfig(adding.Config{ID: "someConfig", Group: "someGroup"})
+	AssertNotError(t, err)
+
+	conf, err := repo.RetrieveConfig("someGroup", "someConfig")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(3))
+
+	// The group was only changed when the config was added to it
+	grp, err = repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, grp.Revision, int64(2))
+}
+
+// StoreAndRetrieveChanges tests that all mutations are recorded and can be retrieved from a given revision
+func StoreAndRetrieveChanges(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	changes, err := repo.RetrieveChanges(0)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(0))
+	AssertEqual(t, len(changes.Changes), 0)
+
+	err = repo.StoreGroup(adding.Group{ID: "someGroup"})
+	AssertNotError(t, err)
+	err = repo.StoreConfig(adding.Config{ID: "someConfig", Group: "someGroup"})
+	AssertNotError(t, err)
+	err = repo.StoreConfig(adding.Config{ID: "someOtherConfig", Group: "someGroup"})
+	AssertNotError(t, err)
+
+	changes, err = repo.RetrieveChanges(0)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(3))
+	AssertEqual(t, len(changes.Changes), 3)
+	AssertEqual(t, changes.Changes[0], listing.Change{Revision: 1, Resource: listing.GroupResource, Group: "someGroup", ID: "someGroup"})
+	AssertEqual(t, changes.Changes[1], listing.Change{Revision: 2, Resource: listing.ConfigResource, Group: "someGroup", ID: "someConfig"})
+	AssertEqual(t, changes.Changes[2], listing.Change{Revision: 3, Resource: listing.ConfigResource, Group: "someGroup", ID: "someOtherConfig"})
+
+	changes, err = repo.RetrieveChanges(2)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(3))
+	AssertEqual(t, len(changes.Changes), 1)
+	AssertEqual(t, changes.Changes[0].ID, "someOtherConfig")
+
+	changes, err = repo.RetrieveChanges(3)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(3))
+	AssertEqual(t, len(changes.Changes), 0)
+}
+
+// ListGroupsWithPrefix tests listing all groups and only the groups with a given prefix
+func ListGroupsWithPrefix(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	grps, err := repo.ListGroups("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 0)
+
+	for _, id := range []string{"someGroup", "someOtherGroup", "anotherGroup"} {
+		err := repo.StoreGroup(adding.Group{ID: id})
+		AssertNotError(t, err)
+	}
+
+	err = repo.StoreConfig(adding.Config{ID: "someConfig", Group: "someGroup"})
+	AssertNotError(t, err)
+
+	grps, err = repo.ListGroups("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 3)
+
+	grps, err = repo.ListGroups("some")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 2)
+	for _, grp := range grps {
+		if grp.ID == "someGroup" {
+			AssertEqual(t, len(grp.Configs), 1)
+		} else {
+			AssertEqual(t, grp.ID, "someOtherGroup")
+		}
+	}
+}
+
+// SearchConfigsByProperties tests searching for configs by property values, both before and after configs are updated
+func SearchConfigsByProperties(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	lst := listing.NewService(repo)
+
+	err := repo.StoreGroup(adding.Group{ID: "someGroup"})
+	AssertNotError(t, err)
+	err = repo.StoreGroup(adding.Group{ID: "someOtherGroup"})
+	AssertNotError(t, err)
+
+	confs := []adding.Config{
+		{ID: "config1", Group: "someGroup", Properties: []byte(`{"database": {"host": "db1", "port": 5432}}`)},
+		{ID: "config2", Group: "someGroup", Properties: []byte(`{"database": {"host": "db2", "port": 3306}}`)},
+		{ID: "config3", Group: "someOtherGroup", Properties: []byte(`{"database": {"host": "db1"}, "tags": ["a", "b"]}`)},
+	}
+	for _, c := range confs {
+		err = repo.StoreConfig(c)
+		AssertNotError(t, err)
+	}
+
+	search := func(group string, exprs ...string) []string {
+		q := listing.Query{Group: group}
+		for _, expr := range exprs {
+			cond, err := listing.ParseCondition(expr)
+			AssertNotError(t, err)
+			q.Conditions = append(q.Conditions, cond)
+		}
+
+		page, err := lst.SearchConfigs(q)
+		AssertNotError(t, err)
+
+		var ids []string
+		for _, c := range page.Configs {
+			ids = append(ids, c.Group+"/"+c.ID)
+		}
+		return ids
+	}
+
+	res := search("", "database.host==db1")
+	AssertEqual(t, len(res), 2)
+	AssertEqual(t, res[0], "someGroup/config1")
+	AssertEqual(t, res[1], "someOtherGroup/config3")
+
+	res = search("someGroup", "database.host==db1")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0], "someGroup/config1")
+
+	res = search("", "database.port>4000")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0], "someGroup/config1")
+
+	res = search("", "database.host=~^db", "database.port")
+	AssertEqual(t, len(res), 2)
+
+	res = search("", "$.tags[1]==b")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0], "someOtherGroup/config3")
+
+	// Updating a config should replace its indexed values
+	err = repo.StoreConfig(adding.Config{ID: "config1", Group: "someGroup", Properties: []byte(`{"database": {"host": "db3"}}`)})
+	AssertNotError(t, err)
+
+	res = search("", "database.host==db1")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0], "someOtherGroup/config3")
+
+	res = search("", "database.port")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0], "someGroup/config2")
+}
+
+// SearchConfigsByText tests full-text search over config ids, names and property values, both before and after configs are
+// updated
+func SearchConfigsByText(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	lst := listing.NewService(repo)
+
+	err := repo.StoreGroup(adding.Group{ID: "someGroup"})
+	AssertNotError(t, err)
+	err = repo.StoreGroup(adding.Group{ID: "someOtherGroup"})
+	AssertNotError(t, err)
+
+	confs := []adding.Config{
+		{ID: "config1", Name: "Payment service", Group: "someGroup", Properties: []byte(`{"url": "https://payment.example.com"}`)},
+		{ID: "config2", Name: "Billing", Group: "someGroup", Properties: []byte(`{"description": "Sends payment reminders"}`)},
+		{ID: "config3", Name: "Shipping", Group: "someOtherGroup", Properties: []byte(`{"url": "https://shipping.example.com"}`)},
+	}
+	for _, c := range confs {
+		err = repo.StoreConfig(c)
+		AssertNotError(t, err)
+	}
+
+	search := func(group, text string) []listing.TextMatch {
+		matches, err := lst.SearchText(listing.TextQuery{Text: text, Group: group})
+		AssertNotError(t, err)
+		return matches.Matches
+	}
+
+	// Matches in the name weighs more than matches in properties
+	res := search("", "PAYMENT")
+	AssertEqual(t, len(res), 2)
+	AssertEqual(t, res[0].ID, "config1")
+	AssertEqual(t, res[1].ID, "config2")
+	AssertEqual(t, res[1].Snippets[0].Field, "properties/description")
+	AssertEqual(t, res[1].Snippets[0].Text, "Sends <em>payment</em> reminders")
+
+	res = search("", "example com")
+	AssertEqual(t, len(res), 2)
+
+	res = search("someOtherGroup", "example")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0].ID, "config3")
+
+	res = search("", "payment shipping")
+	AssertEqual(t, len(res), 0)
+
+	// Updating a config should replace its indexed text
+	err = repo.StoreConfig(adding.Config{ID: "config2", Name: "Billing", Group: "someGroup", Properties: []byte(`{"description": "Sends invoices"}`)})
+	AssertNotError(t, err)
+
+	res = search("", "payment")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0].ID, "config1")
+
+	res = search("", "invoices")
+	AssertEqual(t, len(res), 1)
+	AssertEqual(t, res[0].ID, "config2")
+}
+
+// StoreAndRetrieveSchema tests that a group keeps its schema when it is replaced and when configs are added to the group
+func StoreAndRetrieveSchema(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	err := repo.StoreGroup(adding.Group{ID: "someGroup", Schema: []byte(`{"type":"object"}`)})
+	AssertNotError(t, err)
+
+	sch, err := repo.RetrieveSchema("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(sch), `{"type":"object"}`)
+
+	err = repo.StoreSchema("someGroup", []byte(`{"required":["host"]}`))
+	AssertNotError(t, err)
+
+	err = repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)})
+	AssertNotError(t, err)
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(grp.Schema), `{"required":["host"]}`)
+	AssertEqual(t, grp.Revision, int64(3))
+
+	err = repo.StoreSchema("someGroup", nil)
+	AssertNotError(t, err)
+
+	sch, err = repo.RetrieveSchema("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, len(sch), 0)
+
+	err = repo.StoreSchema("someOtherGroup", []byte(`{}`))
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	_, err = repo.RetrieveSchema("someOtherGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+}
+
+// StoreAndRetrieveRecipients tests that a group keeps its recipients when they are replaced and when the group changes
+func StoreAndRetrieveRecipients(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	err := repo.StoreGroup(adding.Group{ID: "someGroup", Recipients: []string{"key1"}})
+	AssertNotError(t, err)
+
+	recipients, err := repo.RetrieveRecipients("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, strings.Join(recipients, ","), "key1")
+
+	err = repo.StoreRecipients("someGroup", []string{"key1", "key2"})
+	AssertNotError(t, err)
+
+	err = repo.StoreSchema("someGroup", []byte(`{"type":"object"}`))
+	AssertNotError(t, err)
+
+	err = repo.StoreDefaults("someGroup", &adding.Defaults{Properties: []byte(`{"port":5432}`)})
+	AssertNotError(t, err)
+
+	err = repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup"})
+	AssertNotError(t, err)
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, strings.Join(grp.Recipients, ","), "key1,key2")
+	AssertEqual(t, grp.Revision, int64(5))
+
+	err = repo.StoreRecipients("someGroup", nil)
+	AssertNotError(t, err)
+
+	recipients, err = repo.RetrieveRecipients("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, len(recipients), 0)
+
+	err = repo.StoreRecipients("someOtherGroup", []string{"key1"})
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	_, err = repo.RetrieveRecipients("someOtherGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+}
+
+// DeleteConfigAndGroup tests that configs and groups can be deleted, that a group with configs cannot be deleted and that
+// deletes are recorded as changes
+func DeleteConfigAndGroup(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someOtherId", Group: "someGroup"}))
+
+	err := repo.DeleteGroup("someGroup")
+	AssertEqual(t, err, deleting.ErrGroupNotEmpty)
+
+	err = repo.DeleteConfig("someGroup", "someId")
+	AssertNotError(t, err)
+
+	_, err = repo.RetrieveConfig("someGroup", "someId")
+	AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	err = repo.DeleteConfig("someGroup", "someId")
+	AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	refs, err := repo.SearchConfigs([]listing.Condition{{Path: "host", Operator: listing.Exists}})
+	AssertNotError(t, err)
+	AssertEqual(t, len(refs), 0)
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grp.Configs), 1)
+	AssertEqual(t, grp.Configs[0], "someOtherId")
+	AssertEqual(t, grp.Revision, int64(4))
+
+	AssertNotError(t, repo.DeleteConfig("someGroup", "someOtherId"))
+	AssertNotError(t, repo.DeleteGroup("someGroup"))
+
+	_, err = repo.RetrieveGroup("someGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	err = repo.DeleteGroup("someGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	err = repo.DeleteConfig("someGroup", "someOtherId")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	changes, err := repo.RetrieveChanges(3)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(6))
+	AssertEqual(t, len(changes.Changes), 3)
+	AssertEqual(t, changes.Changes[0], listing.Change{Revision: 4, Resource: listing.ConfigResource, Group: "someGroup", ID: "someId", Deleted: true})
+	AssertEqual(t, changes.Changes[2], listing.Change{Revision: 6, Resource: listing.GroupResource, Group: "someGroup", ID: "someGroup", Deleted: true})
+
+	// A deleted group can be created again
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+}
+
+// StoreConfigsWithSameIDInDifferentGroups tests that configs are identified by both group and id
+func StoreConfigsWithSameIDInDifferentGroups(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someOtherGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Name: "first", Group: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Name: "second", Group: "someOtherGroup"}))
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Name, "first")
+
+	conf, err = repo.RetrieveConfig("someOtherGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Name, "second")
+}
+
+// UpdateExistingConfig tests that a config can be updated from its current state, and that it is left unchanged if the update
+// fails
+func UpdateExistingConfig(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Name: "someName", Group: "someGroup"}))
+
+	err := repo.UpdateConfig("someGroup", "someId", func(c adding.Config, revision int64) (adding.Config, error) {
+		AssertEqual(t, c.Name, "someName")
+		AssertEqual(t, revision, int64(2))
+		c.Name = "someOtherName"
+		return c, nil
+	})
+	AssertNotError(t, err)
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Name, "someOtherName")
+	AssertEqual(t, conf.Revision, int64(3))
+
+	err = repo.UpdateConfig("someGroup", "someId", func(c adding.Config, revision int64) (adding.Config, error) {
+		return adding.Config{}, listing.ErrInvalidCursor
+	})
+	AssertEqual(t, err, listing.ErrInvalidCursor)
+
+	conf, err = repo.RetrieveConfig("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(3))
+
+	noUpdate := func(c adding.Config, revision int64) (adding.Config, error) {
+		t.Error("update called for a config that does not exist")
+		return c, nil
+	}
+	AssertEqual(t, repo.UpdateConfig("someGroup", "someOtherId", noUpdate), listing.ErrConfigNotFound)
+	AssertEqual(t, repo.UpdateConfig("someOtherGroup", "someId", noUpdate), listing.ErrGroupNotFound)
+}
+
+// StoreAndRetrieveDefaults tests that the defaults of a group are stored, kept when the group changes and can be removed
+func StoreAndRetrieveDefaults(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	defaults := &adding.Defaults{Properties: []byte(`{"port":5432}`), Merge: properties.MergeRules{Arrays: properties.AppendArrays}}
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup", Defaults: defaults}))
+
+	d, err := repo.RetrieveDefaults("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(d.Properties), `{"port":5432}`)
+	AssertEqual(t, d.Merge, properties.MergeRules{Arrays: properties.AppendArrays})
+
+	AssertNotError(t, repo.StoreSchema("someGroup", []byte(`{"type":"object"}`)))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup"}))
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(grp.Defaults.Properties), `{"port":5432}`)
+	AssertEqual(t, grp.Defaults.Merge, properties.MergeRules{Arrays: properties.AppendArrays})
+
+	AssertNotError(t, repo.StoreDefaults("someGroup", &adding.Defaults{Properties: []byte(`{"port":6432}`)}))
+
+	d, err = repo.RetrieveDefaults("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(d.Properties), `{"port":6432}`)
+	AssertEqual(t, d.Merge, properties.MergeRules{})
+
+	grp, err = repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, grp.Revision, int64(4))
+
+	AssertNotError(t, repo.StoreDefaults("someGroup", nil))
+
+	d, err = repo.RetrieveDefaults("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, d == nil, true)
+
+	err = repo.StoreDefaults("someOtherGroup", defaults)
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	_, err = repo.RetrieveDefaults("someOtherGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+}
+
+// StoreConfigsWithParent tests that the parent of a config is stored and that a config with children cannot be deleted
+func StoreConfigsWithParent(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "base", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "child", Group: "someGroup", Parent: "base"}))
+
+	conf, err := repo.RetrieveConfig("someGroup", "child")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Parent, "base")
+
+	anc, err := repo.RetrieveAncestor("someGroup", "base")
+	AssertNotError(t, err)
+	AssertEqual(t, anc.Parent, "")
+	AssertJSONEqual(t, string(anc.Properties), `{"host":"db1"}`)
+
+	anc, err = repo.RetrieveAncestor("someGroup", "child")
+	AssertNotError(t, err)
+	AssertEqual(t, anc.Parent, "base")
+
+	_, err = repo.RetrieveAncestor("someGroup", "someOtherId")
+	AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	_, err = repo.RetrieveAncestor("someOtherGroup", "base")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	// The parent is kept when the config is updated
+	err = repo.UpdateConfig("someGroup", "child", func(c adding.Config, revision int64) (adding.Config, error) {
+		c.Name = "Child"
+		return c, nil
+	})
+	AssertNotError(t, err)
+
+	conf, err = repo.RetrieveConfig("someGroup", "child")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Parent, "base")
+
+	err = repo.DeleteConfig("someGroup", "base")
+	AssertEqual(t, err, deleting.ErrConfigHasChildren)
+
+	AssertNotError(t, repo.DeleteConfig("someGroup", "child"))
+	AssertNotError(t, repo.DeleteConfig("someGroup", "base"))
+}
+
+// StoreAndRetrieveOverlays tests storing, listing and deleting the overlays of a config and that they are deleted with it
+func StoreAndRetrieveOverlays(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+
+	err := repo.StoreOverlay(adding.Overlay{Group: "someGroup", Config: "someOtherId", Environment: "prod"})
+	AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	AssertNotError(t, repo.StoreOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "staging", Version: 1, Properties: []byte(`{"host":"db2"}`)}))
+	AssertNotError(t, repo.StoreOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Version: 1, Properties: []byte(`{"host":"db3"}`)}))
+
+	o, err := repo.RetrieveOverlay("someGroup", "someId", "staging")
+	AssertNotError(t, err)
+	AssertEqual(t, o.Group, "someGroup")
+	AssertEqual(t, o.Config, "someId")
+	AssertEqual(t, o.Environment, "staging")
+	AssertEqual(t, o.Version, 1)
+	AssertJSONEqual(t, string(o.Properties), `{"host":"db2"}`)
+
+	// Promotion keeps the revision of the overlay promoted from
+	promoted, revision, err := repo.RetrieveOverlayForPromotion("someGroup", "someId", "staging")
+	AssertNotError(t, err)
+	AssertEqual(t, revision, o.Revision)
+	promoted.Environment = "prod"
+	promoted.PromotedFrom = &adding.Promotion{Environment: "staging", Revision: revision}
+	AssertNotError(t, repo.StoreOverlay(*promoted))
+
+	o, err = repo.RetrieveOverlay("someGroup", "someId", "prod")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(o.Properties), `{"host":"db2"}`)
+	AssertEqual(t, *o.PromotedFrom, listing.Promotion{Environment: "staging", Revision: revision})
+
+	overlays, err := repo.ListOverlays("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, len(overlays), 2)
+
+	_, err = repo.RetrieveOverlay("someGroup", "someId", "dev")
+	AssertEqual(t, err, listing.ErrOverlayNotFound)
+
+	AssertNotError(t, repo.DeleteOverlay("someGroup", "someId", "staging"))
+	AssertEqual(t, repo.DeleteOverlay("someGroup", "someId", "staging"), listing.ErrOverlayNotFound)
+
+	changes, err := repo.RetrieveChanges(0)
+	AssertNotError(t, err)
+	last := changes.Changes[len(changes.Changes)-1]
+	AssertEqual(t, last.Resource, listing.OverlayResource)
+	AssertEqual(t, last.Environment, "staging")
+	AssertEqual(t, last.Deleted, true)
+
+	// Deleting the config deletes its overlays
+	AssertNotError(t, repo.DeleteConfig("someGroup", "someId"))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup"}))
+	overlays, err = repo.ListOverlays("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, len(overlays), 0)
+}
+
+// RetrieveDependentsOfReferencedConfig tests that the configs referencing a config are kept up to date as configs change
+func RetrieveDependentsOfReferencedConfig(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	database := listing.ConfigRef{Group: "shared", ID: "database"}
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "shared"}))
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "database", Group: "shared", Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"${ref:shared/database#host}"}`)}))
+
+	dependents, err := repo.RetrieveDependents(database)
+	AssertNotError(t, err)
+	AssertEqual(t, len(dependents), 1)
+	AssertEqual(t, dependents[0], listing.ConfigRef{Group: "someGroup", ID: "someId"})
+
+	err = repo.UpdateConfig("someGroup", "someId", func(c adding.Config, revision int64) (adding.Config, error) {
+		c.Properties = []byte(`{"host":"db2"}`)
+		return c, nil
+	})
+	AssertNotError(t, err)
+
+	dependents, err = repo.RetrieveDependents(database)
+	AssertNotError(t, err)
+	AssertEqual(t, len(dependents), 0)
+
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "otherId", Group: "someGroup", Properties: []byte(`{"url":"db://${ref:shared/database#host}"}`)}))
+	AssertNotError(t, repo.DeleteConfig("someGroup", "otherId"))
+
+	dependents, err = repo.RetrieveDependents(database)
+	AssertNotError(t, err)
+	AssertEqual(t, len(dependents), 0)
+}
+
+// UpdateDefaultsAndOverlays tests that defaults and overlays are replaced by what updates return, and kept if updates fail
+func UpdateDefaultsAndOverlays(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup", Defaults: &adding.Defaults{Properties: []byte(`{"port":5432}`)}}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Version: 2, Properties: []byte(`{"host":"db2"}`), PromotedFrom: &adding.Promotion{Environment: "staging", Revision: 1}}))
+
+	err := repo.UpdateDefaults("someGroup", func(d *adding.Defaults) (*adding.Defaults, error) {
+		AssertJSONEqual(t, string(d.Properties), `{"port":5432}`)
+		return &adding.Defaults{Properties: []byte(`{"port":6432}`), Merge: d.Merge}, nil
+	})
+	AssertNotError(t, err)
+
+	d, err := repo.RetrieveDefaults("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(d.Properties), `{"port":6432}`)
+
+	failed := errors.New("failed")
+	err = repo.UpdateDefaults("someGroup", func(d *adding.Defaults) (*adding.Defaults, error) { return nil, failed })
+	AssertEqual(t, err, failed)
+	d, err = repo.RetrieveDefaults("someGroup")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(d.Properties), `{"port":6432}`)
+
+	err = repo.UpdateDefaults("someOtherGroup", func(d *adding.Defaults) (*adding.Defaults, error) { return d, nil })
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	before, err := repo.RetrieveOverlay("someGroup", "someId", "prod")
+	AssertNotError(t, err)
+
+	err = repo.UpdateOverlay("someGroup", "someId", "prod", func(o adding.Overlay) (adding.Overlay, error) {
+		AssertEqual(t, o.Version, 2)
+		AssertEqual(t, *o.PromotedFrom, adding.Promotion{Environment: "staging", Revision: 1})
+		o.Properties = []byte(`{"host":"db3"}`)
+		return o, nil
+	})
+	AssertNotError(t, err)
+
+	o, err := repo.RetrieveOverlay("someGroup", "someId", "prod")
+	AssertNotError(t, err)
+	AssertJSONEqual(t, string(o.Properties), `{"host":"db3"}`)
+	AssertEqual(t, o.Version, 2)
+	AssertEqual(t, *o.PromotedFrom, listing.Promotion{Environment: "staging", Revision: 1})
+	AssertEqual(t, o.Revision > before.Revision, true)
+
+	err = repo.UpdateOverlay("someGroup", "someId", "prod", func(o adding.Overlay) (adding.Overlay, error) { return o, failed })
+	AssertEqual(t, err, failed)
+
+	err = repo.UpdateOverlay("someGroup", "someId", "dev", func(o adding.Overlay) (adding.Overlay, error) { return o, nil })
+	AssertEqual(t, err, listing.ErrOverlayNotFound)
+}
+
+// CommitBatch tests that the operations of a batch are applied in order with their revision preconditions checked, and that
+// none of them are applied when one fails
+func CommitBatch(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreOverlay(adding.Overlay{Group: "someGroup", Config: "someId", Environment: "prod", Properties: []byte(`{}`)}))
+
+	revision := func(r int64) *int64 { return &r }
+
+	revisions, err := repo.Commit([]adding.Operation{
+		{Type: adding.CreateGroup, Group: adding.Group{ID: "someOtherGroup"}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "someOtherGroup"}, Revision: revision(0)},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "someGroup", Version: 2, Properties: []byte(`{"host":"db2"}`)}, Revision: revision(2)},
+	})
+	AssertNotError(t, err)
+	AssertEqual(t, len(revisions), 3)
+	AssertEqual(t, revisions[0], int64(4))
+	AssertEqual(t, revisions[2], int64(6))
+
+	conf, err := repo.RetrieveConfig("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Version, 2)
+	AssertEqual(t, conf.Revision, int64(6))
+
+	_, err = repo.RetrieveConfig("someOtherGroup", "someId")
+	AssertNotError(t, err)
+
+	// The second operation is applied before the third fails, and is undone
+	_, err = repo.Commit([]adding.Operation{
+		{Type: adding.PutConfig, Config: adding.Config{ID: "newId", Group: "someGroup", Properties: []byte(`{"host":"db3"}`)}},
+		{Type: adding.DeleteConfig, Config: adding.Config{ID: "someId", Group: "someGroup"}, Revision: revision(6)},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "someOtherGroup"}, Revision: revision(0)},
+	})
+	opErr, ok := err.(*adding.OperationError)
+	AssertEqual(t, ok, true)
+	AssertEqual(t, opErr.Index, 2)
+	AssertEqual(t, opErr.Err, adding.ErrRevisionMismatch)
+
+	_, err = repo.RetrieveConfig("someGroup", "newId")
+	AssertEqual(t, err, listing.ErrConfigNotFound)
+
+	conf, err = repo.RetrieveConfig("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(6))
+
+	_, err = repo.RetrieveOverlay("someGroup", "someId", "prod")
+	AssertNotError(t, err)
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, strings.Join(grp.Configs, ","), "someId")
+
+	refs, err := repo.SearchConfigs([]listing.Condition{{Path: "host", Operator: listing.Exists}})
+	AssertNotError(t, err)
+	AssertEqual(t, len(refs), 1)
+	AssertEqual(t, refs[0], listing.ConfigRef{Group: "someGroup", ID: "someId"})
+
+	_, err = repo.Commit([]adding.Operation{
+		{Type: adding.CreateGroup, Group: adding.Group{ID: "newGroup"}},
+		{Type: adding.CreateGroup, Group: adding.Group{ID: "someGroup"}},
+	})
+	AssertEqual(t, err.(*adding.OperationError).Err, adding.ErrGroupConflict)
+
+	_, err = repo.RetrieveGroup("newGroup")
+	AssertEqual(t, err, listing.ErrGroupNotFound)
+
+	changes, err := repo.RetrieveChanges(0)
+	AssertNotError(t, err)
+	AssertEqual(t, changes.Revision, int64(6))
+	AssertEqual(t, len(changes.Changes), 6)
+
+	// Revisions continue from before the failed batches
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "newId", Group: "someGroup"}))
+	conf, err = repo.RetrieveConfig("someGroup", "newId")
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(7))
+}
+
+// StoreAndRetrieveChangeRequests tests that change requests are assigned increasing ids, listed by group and updated, and that
+// a group keeps its approvers when the group changes
+func StoreAndRetrieveChangeRequests(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someOtherGroup"}))
+	AssertNotError(t, repo.StoreApprovers("someGroup", []string{"alice", "bob"}))
+	AssertNotError(t, repo.StoreRecipients("someGroup", nil))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup"}))
+
+	grp, err := repo.RetrieveGroup("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, strings.Join(grp.Approvers, ","), "alice,bob")
+
+	approvers, err := repo.RetrieveApprovers("someOtherGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, len(approvers), 0)
+
+	AssertEqual(t, repo.StoreApprovers("missingGroup", []string{"alice"}), listing.ErrGroupNotFound)
+
+	conf := adding.Config{ID: "someId", Group: "someGroup", Name: "someName", Properties: []byte(`{"host":"db1"}`)}
+	diff := []properties.Difference{{Type: properties.Added, Path: "/host", To: "db1"}}
+	for _, group := range []string{"someGroup", "someOtherGroup", "someGroup"} {
+		conf.Group = group
+		id, err := repo.StoreChangeRequest(reviewing.ChangeRequest{
+			Author:       "carol",
+			State:        reviewing.Open,
+			BaseRevision: 5,
+			Config:       conf,
+			Diff:         diff,
+			History:      []reviewing.Transition{{State: reviewing.Open, User: "carol"}},
+		})
+		AssertNotError(t, err)
+		AssertEqual(t, id > 0, true)
+	}
+
+	crs, err := repo.ListChangeRequests("someGroup")
+	AssertNotError(t, err)
+	AssertEqual(t, len(crs), 2)
+	AssertEqual(t, crs[0].ID < crs[1].ID, true)
+
+	all, err := repo.ListChangeRequests("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(all), 3)
+	AssertEqual(t, all[2].ID, crs[1].ID)
+
+	cr, err := repo.RetrieveChangeRequest(crs[1].ID)
+	AssertNotError(t, err)
+	AssertEqual(t, cr.Author, "carol")
+	AssertEqual(t, cr.BaseRevision, int64(5))
+	AssertEqual(t, cr.Config.Name, "someName")
+	AssertEqual(t, cr.Diff[0].Path, "/host")
+	AssertJSONEqual(t, string(cr.Config.Properties), `{"host":"db1"}`)
+
+	errStop := errors.New("stop")
+	err = repo.UpdateChangeRequest(cr.ID, func(cr reviewing.ChangeRequest) (reviewing.ChangeRequest, error) {
+		cr.State = reviewing.Rejected
+		return cr, errStop
+	})
+	AssertEqual(t, err, errStop)
+
+	err = repo.UpdateChangeRequest(cr.ID, func(cr reviewing.ChangeRequest) (reviewing.ChangeRequest, error) {
+		cr.State = reviewing.Approved
+		cr.History = append(cr.History, reviewing.Transition{State: reviewing.Approved, User: "alice", Comment: "ok"})
+		return cr, nil
+	})
+	AssertNotError(t, err)
+
+	cr, err = repo.RetrieveChangeRequest(cr.ID)
+	AssertNotError(t, err)
+	AssertEqual(t, cr.State, reviewing.Approved)
+	AssertEqual(t, len(cr.History), 2)
+	AssertEqual(t, cr.History[1].Comment, "ok")
+
+	_, err = repo.RetrieveChangeRequest(100)
+	AssertEqual(t, err, reviewing.ErrChangeRequestNotFound)
+
+	// Change requests are not groups
+	grps, err := repo.ListGroups("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 2)
+}
+
+// StoreAndRetrieveActivations tests that activations are assigned increasing ids, listed by config and updated
+func StoreAndRetrieveActivations(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	at := time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC)
+	conf := adding.Config{ID: "someId", Group: "someGroup", Name: "someName", Version: 2, Properties: []byte(`{"host":"db2"}`)}
+	for _, id := range []string{"someId", "someOtherId", "someId"} {
+		conf.ID = id
+		activationID, err := repo.StoreActivation(scheduling.Activation{State: scheduling.Pending, ActivatesAt: at, Config: conf})
+		AssertNotError(t, err)
+		AssertEqual(t, activationID > 0, true)
+	}
+
+	as, err := repo.ListActivations("someGroup", "someId")
+	AssertNotError(t, err)
+	AssertEqual(t, len(as), 2)
+	AssertEqual(t, as[0].ID < as[1].ID, true)
+
+	all, err := repo.ListActivations("", "")
+	AssertNotError(t, err)
+	AssertEqual(t, len(all), 3)
+	AssertEqual(t, all[2].ID, as[1].ID)
+
+	a, err := repo.RetrieveActivation(as[1].ID)
+	AssertNotError(t, err)
+	AssertEqual(t, a.State, scheduling.Pending)
+	AssertEqual(t, a.ActivatesAt.Equal(at), true)
+	AssertEqual(t, a.Config.Name, "someName")
+	AssertEqual(t, a.Config.Version, 2)
+	AssertJSONEqual(t, string(a.Config.Properties), `{"host":"db2"}`)
+
+	errStop := errors.New("stop")
+	err = repo.UpdateActivation(a.ID, func(a scheduling.Activation) (scheduling.Activation, error) {
+		a.State = scheduling.Cancelled
+		return a, errStop
+	})
+	AssertEqual(t, err, errStop)
+
+	err = repo.UpdateActivation(a.ID, func(a scheduling.Activation) (scheduling.Activation, error) {
+		a.State = scheduling.Activated
+		a.Revision = 7
+		return a, nil
+	})
+	AssertNotError(t, err)
+
+	a, err = repo.RetrieveActivation(a.ID)
+	AssertNotError(t, err)
+	AssertEqual(t, a.State, scheduling.Activated)
+	AssertEqual(t, a.Revision, int64(7))
+
+	_, err = repo.RetrieveActivation(100)
+	AssertEqual(t, err, scheduling.ErrActivationNotFound)
+	AssertEqual(t, repo.UpdateActivation(100, func(a scheduling.Activation) (scheduling.Activation, error) { return a, nil }), scheduling.ErrActivationNotFound)
+
+	// Activations are not groups
+	grps, err := repo.ListGroups("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 0)
+}
+
+// StoreAndRetrieveConfigRevisions tests that every version of a config stored can be retrieved by its revision or version, also after
+// the config is deleted, and that versions stored by a failed batch are not kept
+func StoreAndRetrieveConfigRevisions(t *testing.T, repo repository.Repository, cleanup func()) {
+	defer cleanup()
+
+	AssertNotError(t, repo.StoreGroup(adding.Group{ID: "someGroup"}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 1, Properties: []byte(`{"host":"db1"}`)}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someOtherId", Group: "someGroup", Properties: []byte(`{}`)}))
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 2, Name: "someName", Properties: []byte(`{"host":"db2"}`)}))
+
+	conf, err := repo.RetrieveConfigRevision("someGroup", "someId", 2)
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Version, 1)
+	AssertEqual(t, conf.Revision, int64(2))
+	AssertJSONEqual(t, string(conf.Properties), `{"host":"db1"}`)
+
+	conf, err = repo.RetrieveConfigRevision("someGroup", "someId", 4)
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Version, 2)
+	AssertEqual(t, conf.Name, "someName")
+	AssertJSONEqual(t, string(conf.Properties), `{"host":"db2"}`)
+
+	// Revision 3 is a revision of another config
+	_, err = repo.RetrieveConfigRevision("someGroup", "someId", 3)
+	AssertEqual(t, err, diffing.ErrRevisionNotFound)
+
+	_, err = repo.RetrieveConfigRevision("someGroup", "missingId", 2)
+	AssertEqual(t, err, diffing.ErrRevisionNotFound)
+
+	// Versions are found like revisions, and a version stored more than once is found as it was stored last
+	AssertNotError(t, repo.StoreConfig(adding.Config{ID: "someId", Group: "someGroup", Version: 2, Name: "someName", Properties: []byte(`{"host":"db3"}`)}))
+	conf, err = repo.RetrieveConfigVersion("someGroup", "someId", 2)
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(5))
+	AssertJSONEqual(t, string(conf.Properties), `{"host":"db3"}`)
+
+	conf, err = repo.RetrieveConfigVersion("someGroup", "someId", 1)
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Revision, int64(2))
+
+	_, err = repo.RetrieveConfigVersion("someGroup", "someId", 3)
+	AssertEqual(t, err, diffing.ErrVersionNotFound)
+
+	_, err = repo.RetrieveConfigVersion("someGroup", "missingId", 1)
+	AssertEqual(t, err, diffing.ErrVersionNotFound)
+
+	_, err = repo.Commit([]adding.Operation{
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "someGroup", Version: 3}},
+		{Type: adding.PutConfig, Config: adding.Config{ID: "someId", Group: "missingGroup"}},
+	})
+	AssertEqual(t, err.(*adding.OperationError).Err, listing.ErrGroupNotFound)
+
+	_, err = repo.RetrieveConfigRevision("someGroup", "someId", 6)
+	AssertEqual(t, err, diffing.ErrRevisionNotFound)
+
+	_, err = repo.RetrieveConfigVersion("someGroup", "someId", 3)
+	AssertEqual(t, err, diffing.ErrVersionNotFound)
+
+	AssertNotError(t, repo.DeleteConfig("someGroup", "someId"))
+	conf, err = repo.RetrieveConfigRevision("someGroup", "someId", 4)
+	AssertNotError(t, err)
+	AssertEqual(t, conf.Version, 2)
+
+	// The history is not a group
+	grps, err := repo.ListGroups("")
+	AssertNotError(t, err)
+	AssertEqual(t, len(grps), 1)
+}